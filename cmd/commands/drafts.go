@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"time"
+	"wikilite/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+// draftPruneInterval is how often the background job checks for stale drafts.
+const draftPruneInterval = 1 * time.Hour
+
+// newPruneDraftsCmd creates the "prune-drafts" command to delete abandoned drafts.
+func newPruneDraftsCmd(state *cliState) *cobra.Command {
+	var daysToKeep int
+
+	cmd := &cobra.Command{
+		Use:   "prune-drafts",
+		Short: "Delete drafts not updated in X days",
+		Run: func(cmd *cobra.Command, args []string) {
+			if daysToKeep < 0 {
+				log.Fatal("Days cannot be negative")
+			}
+
+			log.Printf("Pruning drafts older than %d days...", daysToKeep)
+
+			duration := time.Duration(daysToKeep) * 24 * time.Hour
+
+			count, err := state.DB.PruneStaleDrafts(context.Background(), duration)
+			if err != nil {
+				log.Fatalf("Failed to prune drafts: %v", err)
+			}
+
+			log.Printf("Success: Deleted %d stale drafts.", count)
+		},
+	}
+
+	cmd.Flags().IntVar(&daysToKeep, "days", 30, "Days of inactivity before a draft is removed")
+
+	return cmd
+}
+
+// startDraftPruner periodically removes drafts older than ttl until ctx is cancelled.
+func startDraftPruner(ctx context.Context, database *db.DB, ttl time.Duration) {
+	prune := func() {
+		count, err := database.PruneStaleDrafts(ctx, ttl)
+		if err != nil {
+			log.Printf("Failed to prune stale drafts: %v", err)
+			return
+		}
+
+		if count > 0 {
+			log.Printf("Pruned %d stale drafts.", count)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(draftPruneInterval)
+		defer ticker.Stop()
+
+		prune()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				prune()
+			}
+		}
+	}()
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"wikilite/internal/db"
+	"wikilite/internal/mediawiki"
+
+	"github.com/spf13/cobra"
+)
+
+// newImportMediaWikiCmd creates the "import-mediawiki" command to create articles from a
+// MediaWiki XML dump.
+func newImportMediaWikiCmd(state *cliState) *cobra.Command {
+	var filePath string
+
+	cmd := &cobra.Command{
+		Use:   "import-mediawiki",
+		Short: "Import articles and their history from a MediaWiki XML dump",
+		Long: "Import the articles of a MediaWiki XML export (Special:Export or dumpBackup.php).\n" +
+			"Every revision becomes a version of the article, converted from wikitext to markdown\n" +
+			"on a best-effort basis. Redirects, pages outside the main namespace and articles\n" +
+			"whose slug already exists are skipped.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if filePath == "" {
+				log.Fatal("Error: --file is required")
+			}
+
+			file, err := os.Open(filePath)
+			if err != nil {
+				log.Fatalf("Failed to open dump: %v", err)
+			}
+			defer file.Close()
+
+			imported, skipped, failed := 0, 0, 0
+			reader := mediawiki.NewReader(file)
+
+			for {
+				page, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				if err != nil {
+					log.Fatalf("Failed to read dump: %v", err)
+				}
+
+				reason := skipReason(page)
+				if reason != "" {
+					log.Printf("Skipped %q: %s", page.Title, reason)
+					skipped++
+
+					continue
+				}
+
+				article, err := state.DB.ImportArticle(context.Background(), page.Title, importRevisions(page))
+				if errors.Is(err, db.ErrArticleExists) {
+					log.Printf("Skipped %q: an article with the same slug already exists", page.Title)
+					skipped++
+
+					continue
+				}
+
+				if err != nil {
+					log.Printf("Failed %q: %v", page.Title, err)
+					failed++
+
+					continue
+				}
+
+				log.Printf("Imported %q as /wiki/%s (%d versions)", page.Title, article.Slug, article.Version)
+				imported++
+			}
+
+			log.Printf("Done: %d imported, %d skipped, %d failed.", imported, skipped, failed)
+		},
+	}
+
+	cmd.Flags().StringVar(&filePath, "file", "", "Path of the MediaWiki XML dump (required)")
+
+	return cmd
+}
+
+// skipReason explains why a page of a dump is not imported, or returns "" to import it.
+func skipReason(page *mediawiki.Page) string {
+	switch {
+	case page.Namespace != mediawiki.MainNamespace:
+		return "not in the main namespace"
+	case page.Redirect != "":
+		return "redirect to " + page.Redirect
+	case len(page.Revisions) == 0:
+		return "no revisions"
+	default:
+		return ""
+	}
+}
+
+// importRevisions converts the revisions of a page to markdown.
+func importRevisions(page *mediawiki.Page) []db.ImportRevision {
+	revisions := make([]db.ImportRevision, 0, len(page.Revisions))
+	for _, revision := range page.Revisions {
+		revisions = append(revisions, db.ImportRevision{
+			CreatedAt: revision.Timestamp,
+			CreatedBy: revision.Contributor,
+			Content:   mediawiki.ToMarkdown(revision.Text),
+		})
+	}
+
+	return revisions
+}
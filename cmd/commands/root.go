@@ -5,7 +5,10 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 	"wikilite/internal/api"
+	"wikilite/internal/cache"
 	"wikilite/internal/db"
 
 	"github.com/joho/godotenv"
@@ -20,20 +23,61 @@ type cliState struct {
 
 // config holds the environment configuration.
 type config struct {
-	DBPath            string
-	LogDBPath         string
-	JWTSecret         string
-	JWKSURL           string
-	JWTIssuer         string
-	JWTEmailClaim     string
-	WikiName          string
-	PluginPath        string
-	PluginStoragePath string
-	JSPkgsPath        string
-	Production        bool
-	TrustProxyHeaders bool
-	InsecureCookies   bool
-	Port              int
+	DBPath              string
+	LogDBPath           string
+	JWTSecret           string
+	JWKSURL             string
+	JWTIssuer           string
+	JWTEmailClaim       string
+	EndSessionEndpoint  string
+	PostLogoutRedirect  string
+	SSOLoginURL         string
+	WikiName            string
+	PluginPath          string
+	PluginStoragePath   string
+	JSPkgsPath          string
+	PluginMemoryLimitMB int
+	Production          bool
+	TrustProxyHeaders   bool
+	TrustedProxyHops    int
+	InsecureCookies     bool
+	Port                int
+	DraftTTLDays        int
+	MaxDraftsPerUser    int
+	WriteRateLimit      int
+	AdminWriteRateLimit int
+	MaxHistoryVersions  int
+	MaxSlugLength       int
+	StubWordCount       int
+	MaxContentSize      int
+	MaxRequestBodySize  int
+	MaxMultipartMemory  int
+	DefaultPageSize     int
+	MaxPageSize         int
+	AdminEmail          string
+	AdminPassword       string
+	SeedPath            string
+	PasswordMinLength   int
+	LogQueueSize        int
+	LogWorkers          int
+	SQLLogging          db.SQLLogMode
+	PasswordComplex     bool
+	OTPIssuer           string
+	OTPPeriod           int
+	OTPDigits           int
+	OTPAlgorithm        string
+	CompressHistory     bool
+	EmojiShortcodes     bool
+	DefinitionLists     bool
+	Abbreviations       bool
+	HardWraps           bool
+	LazyImages          bool
+	Suggestions         bool
+	ArticleAST          bool
+	CustomArticleCode   bool
+	Cache               cache.Config
+	OTPCache            cache.Config
+	ArticleTemplatePath string
 }
 
 // NewRootCmd creates the entire command tree and returns the root command.
@@ -60,21 +104,311 @@ func NewRootCmd() *cobra.Command {
 				portNumber = api.DefaultPort
 			}
 
+			var draftTTLDays int
+			draftTTL := os.Getenv("DRAFT_TTL_DAYS")
+			if draftTTL != "" {
+				cnvTTL, err := strconv.Atoi(draftTTL)
+				if err != nil || cnvTTL < 0 {
+					log.Fatalf("Invalid DRAFT_TTL_DAYS value: %s", draftTTL)
+				}
+
+				draftTTLDays = cnvTTL
+			}
+
+			var maxDraftsPerUser int
+			maxDrafts := os.Getenv("MAX_DRAFTS_PER_USER")
+			if maxDrafts != "" {
+				cnvMax, err := strconv.Atoi(maxDrafts)
+				if err != nil || cnvMax < 0 {
+					log.Fatalf("Invalid MAX_DRAFTS_PER_USER value: %s", maxDrafts)
+				}
+
+				maxDraftsPerUser = cnvMax
+			}
+
+			var writeRateLimit int
+			writeRate := os.Getenv("WRITE_RATE_LIMIT")
+			if writeRate != "" {
+				cnvRate, err := strconv.Atoi(writeRate)
+				if err != nil || cnvRate < 0 {
+					log.Fatalf("Invalid WRITE_RATE_LIMIT value: %s", writeRate)
+				}
+
+				writeRateLimit = cnvRate
+			}
+
+			var adminWriteRateLimit int
+			adminWriteRate := os.Getenv("ADMIN_WRITE_RATE_LIMIT")
+			if adminWriteRate != "" {
+				cnvRate, err := strconv.Atoi(adminWriteRate)
+				if err != nil || cnvRate < 0 {
+					log.Fatalf("Invalid ADMIN_WRITE_RATE_LIMIT value: %s", adminWriteRate)
+				}
+
+				adminWriteRateLimit = cnvRate
+			}
+
+			var maxHistoryVersions int
+			maxHistory := os.Getenv("MAX_HISTORY_VERSIONS")
+			if maxHistory != "" {
+				cnvHistory, err := strconv.Atoi(maxHistory)
+				if err != nil || cnvHistory < 0 {
+					log.Fatalf("Invalid MAX_HISTORY_VERSIONS value: %s", maxHistory)
+				}
+
+				maxHistoryVersions = cnvHistory
+			}
+
+			maxSlugLength := db.DefaultMaxSlugLength
+			maxSlug := os.Getenv("MAX_SLUG_LENGTH")
+			if maxSlug != "" {
+				cnvSlug, err := strconv.Atoi(maxSlug)
+				if err != nil || cnvSlug < 0 {
+					log.Fatalf("Invalid MAX_SLUG_LENGTH value: %s", maxSlug)
+				}
+
+				maxSlugLength = cnvSlug
+			}
+
+			var stubWordCount int
+			stubWords := os.Getenv("STUB_WORD_COUNT")
+			if stubWords != "" {
+				cnvStub, err := strconv.Atoi(stubWords)
+				if err != nil || cnvStub <= 0 {
+					log.Fatalf("Invalid STUB_WORD_COUNT value: %s", stubWords)
+				}
+
+				stubWordCount = cnvStub
+			}
+
+			var maxContentSize int
+			maxContent := os.Getenv("MAX_CONTENT_SIZE")
+			if maxContent != "" {
+				cnvSize, err := strconv.Atoi(maxContent)
+				if err != nil || cnvSize <= 0 {
+					log.Fatalf("Invalid MAX_CONTENT_SIZE value: %s", maxContent)
+				}
+
+				maxContentSize = cnvSize
+			}
+
+			var maxRequestBodySize int
+			maxBody := os.Getenv("MAX_REQUEST_BODY_SIZE")
+			if maxBody != "" {
+				cnvSize, err := strconv.Atoi(maxBody)
+				if err != nil || cnvSize <= 0 {
+					log.Fatalf("Invalid MAX_REQUEST_BODY_SIZE value: %s", maxBody)
+				}
+
+				maxRequestBodySize = cnvSize
+			}
+
+			var maxMultipartMemory int
+			multipartMemory := os.Getenv("MAX_MULTIPART_MEMORY")
+			if multipartMemory != "" {
+				cnvSize, err := strconv.Atoi(multipartMemory)
+				if err != nil || cnvSize <= 0 {
+					log.Fatalf("Invalid MAX_MULTIPART_MEMORY value: %s", multipartMemory)
+				}
+
+				maxMultipartMemory = cnvSize
+			}
+
+			var defaultPageSize int
+			pageSize := os.Getenv("DEFAULT_PAGE_SIZE")
+			if pageSize != "" {
+				cnvSize, err := strconv.Atoi(pageSize)
+				if err != nil || cnvSize <= 0 {
+					log.Fatalf("Invalid DEFAULT_PAGE_SIZE value: %s", pageSize)
+				}
+
+				defaultPageSize = cnvSize
+			}
+
+			var maxPageSize int
+			maxPage := os.Getenv("MAX_PAGE_SIZE")
+			if maxPage != "" {
+				cnvSize, err := strconv.Atoi(maxPage)
+				if err != nil || cnvSize <= 0 {
+					log.Fatalf("Invalid MAX_PAGE_SIZE value: %s", maxPage)
+				}
+
+				maxPageSize = cnvSize
+			}
+
+			var pluginMemoryLimitMB int
+			pluginMemory := os.Getenv("PLUGIN_MEMORY_LIMIT_MB")
+			if pluginMemory != "" {
+				cnvLimit, err := strconv.Atoi(pluginMemory)
+				if err != nil || cnvLimit <= 0 {
+					log.Fatalf("Invalid PLUGIN_MEMORY_LIMIT_MB value: %s", pluginMemory)
+				}
+
+				pluginMemoryLimitMB = cnvLimit
+			}
+
+			var trustedProxyHops int
+			proxyHops := os.Getenv("TRUSTED_PROXY_HOPS")
+			if proxyHops != "" {
+				cnvHops, err := strconv.Atoi(proxyHops)
+				if err != nil || cnvHops <= 0 {
+					log.Fatalf("Invalid TRUSTED_PROXY_HOPS value: %s", proxyHops)
+				}
+
+				trustedProxyHops = cnvHops
+			}
+
+			var passwordMinLength int
+			minLength := os.Getenv("PASSWORD_MIN_LENGTH")
+			if minLength != "" {
+				cnvLength, err := strconv.Atoi(minLength)
+				if err != nil || cnvLength <= 0 {
+					log.Fatalf("Invalid PASSWORD_MIN_LENGTH value: %s", minLength)
+				}
+
+				passwordMinLength = cnvLength
+			}
+
+			var logQueueSize int
+			logQueue := os.Getenv("LOG_QUEUE_SIZE")
+			if logQueue != "" {
+				cnvSize, err := strconv.Atoi(logQueue)
+				if err != nil || cnvSize <= 0 {
+					log.Fatalf("Invalid LOG_QUEUE_SIZE value: %s", logQueue)
+				}
+
+				logQueueSize = cnvSize
+			}
+
+			var logWorkers int
+			workers := os.Getenv("LOG_WORKERS")
+			if workers != "" {
+				cnvWorkers, err := strconv.Atoi(workers)
+				if err != nil || cnvWorkers <= 0 {
+					log.Fatalf("Invalid LOG_WORKERS value: %s", workers)
+				}
+
+				logWorkers = cnvWorkers
+			}
+
+			sqlLogging := db.SQLLogMode(strings.ToLower(os.Getenv("SQL_LOGGING")))
+			switch sqlLogging {
+			case "", db.SQLLogAll, db.SQLLogErrors, db.SQLLogOff:
+			default:
+				log.Fatalf("Invalid SQL_LOGGING value: %s", sqlLogging)
+			}
+
+			var otpPeriod int
+			period := os.Getenv("OTP_PERIOD")
+			if period != "" {
+				cnvPeriod, err := strconv.Atoi(period)
+				if err != nil || cnvPeriod <= 0 {
+					log.Fatalf("Invalid OTP_PERIOD value: %s", period)
+				}
+
+				otpPeriod = cnvPeriod
+			}
+
+			var otpDigits int
+			digits := os.Getenv("OTP_DIGITS")
+			if digits != "" {
+				cnvDigits, err := strconv.Atoi(digits)
+				if err != nil || (cnvDigits != 6 && cnvDigits != 8) {
+					log.Fatalf("Invalid OTP_DIGITS value: %s", digits)
+				}
+
+				otpDigits = cnvDigits
+			}
+
+			var cacheTTL time.Duration
+			cacheMinutes := os.Getenv("CACHE_TTL_MINUTES")
+			if cacheMinutes != "" {
+				cnvMinutes, err := strconv.Atoi(cacheMinutes)
+				if err != nil || cnvMinutes <= 0 {
+					log.Fatalf("Invalid CACHE_TTL_MINUTES value: %s", cacheMinutes)
+				}
+
+				cacheTTL = time.Duration(cnvMinutes) * time.Minute
+			}
+
+			var cacheSize uint64
+			cacheEntries := os.Getenv("CACHE_SIZE")
+			if cacheEntries != "" {
+				cnvSize, err := strconv.ParseUint(cacheEntries, 10, 64)
+				if err != nil || cnvSize == 0 {
+					log.Fatalf("Invalid CACHE_SIZE value: %s", cacheEntries)
+				}
+
+				cacheSize = cnvSize
+			}
+
+			var otpCacheTTL time.Duration
+			otpCacheMinutes := os.Getenv("OTP_CACHE_TTL_MINUTES")
+			if otpCacheMinutes != "" {
+				cnvMinutes, err := strconv.Atoi(otpCacheMinutes)
+				if err != nil || cnvMinutes <= 0 {
+					log.Fatalf("Invalid OTP_CACHE_TTL_MINUTES value: %s", otpCacheMinutes)
+				}
+
+				otpCacheTTL = time.Duration(cnvMinutes) * time.Minute
+			}
+
 			state.Config = config{
-				DBPath:            os.Getenv("DB_PATH"),
-				LogDBPath:         os.Getenv("LOG_DB_PATH"),
-				JWTSecret:         os.Getenv("JWT_SECRET"),
-				JWKSURL:           os.Getenv("JWKS_URL"),
-				JWTIssuer:         os.Getenv("JWT_ISSUER"),
-				JWTEmailClaim:     os.Getenv("JWT_EMAIL_CLAIM"),
-				WikiName:          os.Getenv("WIKI_NAME"),
-				PluginPath:        os.Getenv("PLUGIN_PATH"),
-				PluginStoragePath: os.Getenv("PLUGIN_STORAGE_PATH"),
-				JSPkgsPath:        os.Getenv("JSPKGS_PATH"),
-				Production:        !(os.Getenv("IS_DEVELOPMENT") == "true"),
-				TrustProxyHeaders: os.Getenv("TRUST_PROXY_HEADERS") == "true",
-				InsecureCookies:   os.Getenv("INSECURE_COOKIES") == "true",
-				Port:              portNumber,
+				DBPath:              os.Getenv("DB_PATH"),
+				LogDBPath:           os.Getenv("LOG_DB_PATH"),
+				JWTSecret:           os.Getenv("JWT_SECRET"),
+				JWKSURL:             os.Getenv("JWKS_URL"),
+				JWTIssuer:           os.Getenv("JWT_ISSUER"),
+				JWTEmailClaim:       os.Getenv("JWT_EMAIL_CLAIM"),
+				EndSessionEndpoint:  os.Getenv("END_SESSION_ENDPOINT"),
+				PostLogoutRedirect:  os.Getenv("POST_LOGOUT_REDIRECT_URL"),
+				SSOLoginURL:         os.Getenv("SSO_LOGIN_URL"),
+				WikiName:            os.Getenv("WIKI_NAME"),
+				PluginPath:          os.Getenv("PLUGIN_PATH"),
+				PluginStoragePath:   os.Getenv("PLUGIN_STORAGE_PATH"),
+				JSPkgsPath:          os.Getenv("JSPKGS_PATH"),
+				PluginMemoryLimitMB: pluginMemoryLimitMB,
+				Production:          !(os.Getenv("IS_DEVELOPMENT") == "true"),
+				TrustProxyHeaders:   os.Getenv("TRUST_PROXY_HEADERS") == "true",
+				TrustedProxyHops:    trustedProxyHops,
+				InsecureCookies:     os.Getenv("INSECURE_COOKIES") == "true",
+				Port:                portNumber,
+				DraftTTLDays:        draftTTLDays,
+				MaxDraftsPerUser:    maxDraftsPerUser,
+				WriteRateLimit:      writeRateLimit,
+				AdminWriteRateLimit: adminWriteRateLimit,
+				MaxHistoryVersions:  maxHistoryVersions,
+				MaxSlugLength:       maxSlugLength,
+				StubWordCount:       stubWordCount,
+				MaxContentSize:      maxContentSize,
+				MaxRequestBodySize:  maxRequestBodySize,
+				MaxMultipartMemory:  maxMultipartMemory,
+				DefaultPageSize:     defaultPageSize,
+				MaxPageSize:         maxPageSize,
+				AdminEmail:          os.Getenv("ADMIN_EMAIL"),
+				AdminPassword:       os.Getenv("ADMIN_PASSWORD"),
+				SeedPath:            os.Getenv("SEED_PATH"),
+				PasswordMinLength:   passwordMinLength,
+				LogQueueSize:        logQueueSize,
+				LogWorkers:          logWorkers,
+				SQLLogging:          sqlLogging,
+				PasswordComplex:     os.Getenv("PASSWORD_REQUIRE_COMPLEXITY") == "true",
+				OTPIssuer:           os.Getenv("OTP_ISSUER"),
+				OTPPeriod:           otpPeriod,
+				OTPDigits:           otpDigits,
+				OTPAlgorithm:        os.Getenv("OTP_ALGORITHM"),
+				CompressHistory:     os.Getenv("COMPRESS_HISTORY") == "true",
+				EmojiShortcodes:     os.Getenv("EMOJI_SHORTCODES") == "true",
+				DefinitionLists:     os.Getenv("DEFINITION_LISTS") == "true",
+				Abbreviations:       os.Getenv("ABBREVIATIONS") == "true",
+				HardWraps:           os.Getenv("HARD_WRAPS") == "true",
+				LazyImages:          os.Getenv("LAZY_IMAGES") != "false",
+				Suggestions:         os.Getenv("SUGGESTIONS_ENABLED") == "true",
+				ArticleAST:          os.Getenv("ARTICLE_AST_ENABLED") == "true",
+				CustomArticleCode:   os.Getenv("CUSTOM_ARTICLE_CODE_ENABLED") == "true",
+				Cache:               cache.Config{TTL: cacheTTL, Size: cacheSize},
+				OTPCache:            cache.Config{TTL: otpCacheTTL},
+				ArticleTemplatePath: os.Getenv("ARTICLE_TEMPLATE_PATH"),
 			}
 
 			if state.Config.JWTSecret == "" && state.Config.JWKSURL == "" {
@@ -97,11 +431,28 @@ func NewRootCmd() *cobra.Command {
 			state.DB, err = db.New(
 				"file:"+wikiDbPath+"?cache=shared",
 				"file:"+logDbPath+"?cache=shared",
+				db.WithLogQueueSize(state.Config.LogQueueSize),
+				db.WithLogWorkers(state.Config.LogWorkers),
+				db.WithSQLLogging(state.Config.SQLLogging),
+				db.WithArticleCache(state.Config.Cache),
 			)
 			if err != nil {
 				return fmt.Errorf("failed to connect to database: %w", err)
 			}
 
+			state.DB.SetCompression(state.Config.CompressHistory)
+			state.DB.SetMaxHistory(state.Config.MaxHistoryVersions)
+			state.DB.SetMaxSlugLength(state.Config.MaxSlugLength)
+
+			if state.Config.ArticleTemplatePath != "" {
+				tmpl, err := os.ReadFile(state.Config.ArticleTemplatePath)
+				if err != nil {
+					return fmt.Errorf("failed to read article template: %w", err)
+				}
+
+				state.DB.SetArticleTemplate(string(tmpl))
+			}
+
 			return nil
 		},
 		// PersistentPostRun ensures the DB is closed after the command finishes.
@@ -117,9 +468,11 @@ func NewRootCmd() *cobra.Command {
 
 	rootCmd.AddCommand(newServerCmd(state))
 	rootCmd.AddCommand(newPruneLogsCmd(state))
+	rootCmd.AddCommand(newPruneDraftsCmd(state))
 	rootCmd.AddCommand(newAddUserCmd(state))
 	rootCmd.AddCommand(newRemoveUserCmd(state))
 	rootCmd.AddCommand(newUpdateUserCmd(state))
+	rootCmd.AddCommand(newImportMediaWikiCmd(state))
 
 	return rootCmd
 }
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"wikilite/internal/db"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+)
+
+const (
+	defaultAdminEmail = "admin@example.com"
+	// generatedPasswordLength is the length of the bootstrap admin password generated when
+	// ADMIN_PASSWORD is not set.
+	generatedPasswordLength = 20
+	// generatePasswordAttempts is how many passwords are generated looking for one that
+	// meets the password policy.
+	generatePasswordAttempts = 100
+)
+
+// seedDatabase provisions the bootstrap admin when the database has no users, and the
+// initial articles when it is empty. Nothing is done once any user exists.
+// The bootstrap password must meet the password policy, so the admin can keep it until
+// they choose a new one.
+func seedDatabase(ctx context.Context, state *cliState, policy utils.PasswordPolicy) error {
+	userCount, err := state.DB.CountUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check seed status: %w", err)
+	}
+
+	if userCount > 0 {
+		return nil
+	}
+
+	adminEmail := defaultAdminEmail
+	if state.Config.AdminEmail != "" {
+		adminEmail = state.Config.AdminEmail
+	}
+
+	adminPassword := state.Config.AdminPassword
+	generated := adminPassword == ""
+	if generated {
+		adminPassword, err = generateAdminPassword(policy)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = utils.ValidatePassword(adminPassword, policy)
+		if err != nil {
+			return fmt.Errorf("ADMIN_PASSWORD does not meet the password policy: %w", err)
+		}
+	}
+
+	hash, err := utils.HashPassword(adminPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash seed password: %w", err)
+	}
+
+	adminUser := &models.User{
+		Name:       "System Admin",
+		Email:      adminEmail,
+		Hash:       hash,
+		Role:       models.ADMIN,
+		IsExternal: false,
+	}
+
+	empty, err := state.DB.IsEmpty(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check seed status: %w", err)
+	}
+
+	if empty {
+		var articles []db.SeedArticle
+		if state.Config.SeedPath != "" {
+			articles, err = loadSeedArticles(state.Config.SeedPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Seeding database with Admin user and %d imported articles...", len(articles))
+
+		err = state.DB.Seed(ctx, adminUser, "Home", articles...)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = state.DB.BootstrapAdmin(ctx, adminUser)
+		if err != nil {
+			return err
+		}
+	}
+
+	if generated {
+		log.Printf("WARNING: ADMIN_PASSWORD not set. Bootstrap admin %s was created with the generated password: %s",
+			adminUser.Email, adminPassword)
+	} else {
+		log.Printf("WARNING: Bootstrap admin %s was created with the password from ADMIN_PASSWORD.", adminUser.Email)
+	}
+
+	log.Println("WARNING: Change the bootstrap admin password after the first login.")
+
+	return nil
+}
+
+// generateAdminPassword returns a random bootstrap admin password satisfying the policy.
+func generateAdminPassword(policy utils.PasswordPolicy) (string, error) {
+	length := max(generatedPasswordLength, policy.MinLength)
+
+	// A random password can miss a required character class, so draw again until it has
+	// them all. Generated passwords contain no symbols, so that requirement is never met.
+	for range generatePasswordAttempts {
+		password, err := utils.GeneratePassword(length)
+		if err != nil {
+			return "", err
+		}
+
+		if utils.ValidatePassword(password, policy) == nil {
+			return password, nil
+		}
+	}
+
+	return "", errors.New("failed to generate a bootstrap admin password meeting the password policy, set ADMIN_PASSWORD")
+}
+
+// loadSeedArticles reads every markdown file in dir as a seed article.
+// The slug is derived from the file name and the title from the first level-one heading,
+// falling back to the file name.
+func loadSeedArticles(dir string) ([]db.SeedArticle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed directory %s: %w", dir, err)
+	}
+
+	articles := make([]db.SeedArticle, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read seed article %s: %w", entry.Name(), err)
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		slug := utils.ToKebabCase(name)
+		if slug == "" {
+			continue
+		}
+
+		articles = append(articles, db.SeedArticle{
+			Title:   seedTitle(string(content), name),
+			Slug:    slug,
+			Content: string(content),
+		})
+	}
+
+	return articles, nil
+}
+
+// seedTitle returns the text of the first level-one heading in content, or a title
+// built from the file name if there is none.
+func seedTitle(content, fileName string) string {
+	for line := range strings.Lines(content) {
+		heading, ok := strings.CutPrefix(strings.TrimSpace(line), "# ")
+		if ok && strings.TrimSpace(heading) != "" {
+			return strings.TrimSpace(heading)
+		}
+	}
+
+	words := strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(fileName))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+
+	return strings.Join(words, " ")
+}
@@ -7,10 +7,10 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 	"wikilite/internal/api"
-	"wikilite/pkg/models"
 	"wikilite/pkg/utils"
 
 	"github.com/spf13/cobra"
@@ -24,53 +24,74 @@ func newServerCmd(state *cliState) *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
 
-			seeded, err := state.DB.IsSeeded(ctx)
-			if err != nil {
-				log.Printf("Warning: Failed to check seed status: %v", err)
+			wikiName := api.DefaultWikiName
+			if state.Config.WikiName != "" {
+				wikiName = state.Config.WikiName
 			}
 
-			if !seeded {
-				log.Println("Seeding database with default Admin user...")
-				hash, err := utils.HashPassword("admin")
-				if err != nil {
-					log.Fatalf("Failed to hash seed password: %v", err)
-				}
-
-				adminUser := &models.User{
-					Name:       "System Admin",
-					Email:      "admin@example.com",
-					Hash:       hash,
-					Role:       models.ADMIN,
-					IsExternal: false,
-				}
+			serverConfig := api.ServerConfig{
+				Database:           state.DB,
+				JwtSecret:          state.Config.JWTSecret,
+				JwksURL:            state.Config.JWKSURL,
+				JwtIssuer:          state.Config.JWTIssuer,
+				JwtEmailClaim:      state.Config.JWTEmailClaim,
+				WikiName:           wikiName,
+				PluginPath:         state.Config.PluginPath,
+				PluginStoragePath:  state.Config.PluginStoragePath,
+				JsPkgsPath:         state.Config.JSPkgsPath,
+				PluginMemoryLimit:  state.Config.PluginMemoryLimitMB << 20,
+				Production:         state.Config.Production,
+				TrustProxyHeaders:  state.Config.TrustProxyHeaders,
+				TrustedProxyHops:   state.Config.TrustedProxyHops,
+				InsecureCookies:    state.Config.InsecureCookies,
+				Port:               state.Config.Port,
+				MaxContentSize:     state.Config.MaxContentSize,
+				MaxRequestBodySize: state.Config.MaxRequestBodySize,
+				MaxMultipartMemory: state.Config.MaxMultipartMemory,
+				DefaultPageSize:    state.Config.DefaultPageSize,
+				MaxPageSize:        state.Config.MaxPageSize,
+				MaxDraftsPerUser:   state.Config.MaxDraftsPerUser,
+				EmojiShortcodes:    state.Config.EmojiShortcodes,
+				DefinitionLists:    state.Config.DefinitionLists,
+				Abbreviations:      state.Config.Abbreviations,
+				HardWraps:          state.Config.HardWraps,
+				LazyImages:         state.Config.LazyImages,
+				PasswordPolicy: utils.PasswordPolicy{
+					MinLength:    state.Config.PasswordMinLength,
+					RequireUpper: state.Config.PasswordComplex,
+					RequireLower: state.Config.PasswordComplex,
+					RequireDigit: state.Config.PasswordComplex,
+				},
+				OTPIssuer:             state.Config.OTPIssuer,
+				OTPPeriod:             uint(state.Config.OTPPeriod),
+				OTPDigits:             state.Config.OTPDigits,
+				OTPAlgorithm:          state.Config.OTPAlgorithm,
+				EndSessionEndpoint:    state.Config.EndSessionEndpoint,
+				PostLogoutRedirectURL: state.Config.PostLogoutRedirect,
+				SSOLoginURL:           state.Config.SSOLoginURL,
+				StubWordCount:         state.Config.StubWordCount,
+				Suggestions:           state.Config.Suggestions,
+				ArticleAST:            state.Config.ArticleAST,
+				CustomArticleCode:     state.Config.CustomArticleCode,
+				WriteRateLimit:        state.Config.WriteRateLimit,
+				AdminWriteRateLimit:   state.Config.AdminWriteRateLimit,
+				Cache:                 state.Config.Cache,
+				OTPCache:              state.Config.OTPCache,
+			}
 
-				err = state.DB.Seed(ctx, adminUser, "Home")
-				if err != nil {
-					log.Fatalf("Failed to seed database: %v", err)
-				}
-				log.Println("Seeding complete. Login with admin@example.com / admin")
+			// Problems such as an unreachable JWKS endpoint or a port in use are reported
+			// before anything is written to the database.
+			err := serverConfig.Validate(ctx)
+			if err != nil {
+				log.Fatalf("Invalid configuration:\n  - %s", strings.ReplaceAll(err.Error(), "\n", "\n  - "))
 			}
 
-			wikiName := api.DefaultWikiName
-			if state.Config.WikiName != "" {
-				wikiName = state.Config.WikiName
+			err = seedDatabase(ctx, state, serverConfig.PasswordPolicy)
+			if err != nil {
+				log.Fatalf("Failed to seed database: %v", err)
 			}
 
-			server, err := api.NewServer(api.ServerConfig{
-				Database:          state.DB,
-				JwtSecret:         state.Config.JWTSecret,
-				JwksURL:           state.Config.JWKSURL,
-				JwtIssuer:         state.Config.JWTIssuer,
-				JwtEmailClaim:     state.Config.JWTEmailClaim,
-				WikiName:          wikiName,
-				PluginPath:        state.Config.PluginPath,
-				PluginStoragePath: state.Config.PluginStoragePath,
-				JsPkgsPath:        state.Config.JSPkgsPath,
-				Production:        state.Config.Production,
-				TrustProxyHeaders: state.Config.TrustProxyHeaders,
-				InsecureCookies:   state.Config.InsecureCookies,
-				Port:              state.Config.Port,
-			})
+			server, err := api.NewServer(serverConfig)
 			if err != nil {
 				log.Fatalf("Failed to create server: %v", err)
 			}
@@ -84,6 +105,15 @@ func newServerCmd(state *cliState) *cobra.Command {
 				log.Printf("Auth Mode: Local HMAC")
 			}
 
+			pruneCtx, stopPruner := context.WithCancel(context.Background())
+			defer stopPruner()
+
+			if state.Config.DraftTTLDays > 0 {
+				log.Printf("Draft Expiry: %d days", state.Config.DraftTTLDays)
+				ttl := time.Duration(state.Config.DraftTTLDays) * 24 * time.Hour
+				startDraftPruner(pruneCtx, state.DB, ttl)
+			}
+
 			stop := make(chan os.Signal, 1)
 			signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -98,6 +128,8 @@ func newServerCmd(state *cliState) *cobra.Command {
 			<-stop
 			log.Println("Shutdown signal received...")
 
+			stopPruner()
+
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
@@ -120,6 +152,10 @@ func newServerCmd(state *cliState) *cobra.Command {
 
 // logAuthModeDetails logs authentication mode details.
 func logAuthModeDetails(config *config) {
+	if config.EndSessionEndpoint != "" {
+		log.Printf("Logout: RP-initiated via %s", config.EndSessionEndpoint)
+	}
+
 	if config.JWTEmailClaim != "" {
 		log.Printf("Email Claim: Explicitly set to '%s'", config.JWTEmailClaim)
 		return
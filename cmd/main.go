@@ -1,12 +1,16 @@
 package main
 
-import "wikilite/cmd/commands"
+import (
+	"os"
+	"wikilite/cmd/commands"
+)
 
 func main() {
 	cli := commands.NewRootCmd()
 
+	// Cobra has already printed the error, so only the exit code is left to set.
 	err := cli.Execute()
 	if err != nil {
-		panic(err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"wikilite/pkg/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RecentChange represents a single published edit in the activity feed.
+type RecentChange struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Author    *string   `json:"author,omitempty"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	ArticleId int       `json:"articleId"`
+	Version   int       `json:"version"`
+}
+
+// ActivityInput represents the input for the recent changes feed.
+type ActivityInput struct {
+	Page   int    `default:"1"                                    doc:"Page number" minimum:"1" query:"page"`
+	Limit  int    `doc:"Items per page (capped by server config)" query:"limit"`
+	Author string `doc:"Filter by author email (Admin only, or your own email)" query:"author" required:"false"`
+}
+
+// ActivityOutput represents the output for the recent changes feed.
+type ActivityOutput struct {
+	Body struct {
+		Changes []*RecentChange `json:"changes"`
+		Author  string          `json:"author,omitempty"`
+		Page    int             `json:"page"`
+		Limit   int             `json:"limit"`
+	}
+}
+
+// registerActivityRoutes registers the activity feed routes with the API.
+func (s *Server) registerActivityRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-recent-changes",
+		Method:      http.MethodGet,
+		Path:        "/api/activity",
+		Summary:     "List Recent Changes",
+		Description: "Get recently published versions across all articles, newest first. " +
+			"Authors are only included for admins. Filter by author with ?author=email.",
+		Tags:     []string{"Articles"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleGetRecentChanges)
+}
+
+// handleGetRecentChanges handles the request to get the recent changes feed.
+func (s *Server) handleGetRecentChanges(
+	ctx context.Context,
+	input *ActivityInput,
+) (*ActivityOutput, error) {
+	var offset int
+	input.Page, input.Limit, offset = s.paginate(input.Page, input.Limit)
+
+	isAdmin := getAdminUserFromContext(ctx) != nil
+	author := utils.NormalizeEmail(input.Author)
+
+	if author != "" && !isAdmin {
+		user := getUserFromContext(ctx)
+		if user == nil || utils.NormalizeEmail(user.Email) != author {
+			return nil, huma.Error403Forbidden("Only admins can view other authors' changes")
+		}
+	}
+
+	history, err := s.db.GetRecentChanges(ctx, author, input.Limit, offset)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	changes := make([]*RecentChange, 0, len(history))
+	for _, h := range history {
+		if h.Article == nil {
+			continue
+		}
+
+		change := &RecentChange{
+			ArticleId: h.ArticleId,
+			Title:     h.Article.Title,
+			Slug:      h.Article.Slug,
+			Version:   h.Version,
+			CreatedAt: h.CreatedAt,
+		}
+
+		if (isAdmin || author != "") && h.CreatedBy != "" {
+			author := h.CreatedBy
+			change.Author = &author
+		}
+
+		changes = append(changes, change)
+	}
+
+	resp := &ActivityOutput{}
+	resp.Body.Changes = changes
+	resp.Body.Author = author
+	resp.Body.Page = input.Page
+	resp.Body.Limit = input.Limit
+
+	return resp, nil
+}
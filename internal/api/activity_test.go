@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetRecentChanges(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	article, err := db.GetArticleBySlug(ctx, "home")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(ctx, article.Id, "# Home v1", "writer@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	resp, err := server.handleGetRecentChanges(ctx, &ActivityInput{})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Changes, 1)
+	assert.Equal(t, "home", resp.Body.Changes[0].Slug)
+	assert.Equal(t, 1, resp.Body.Changes[0].Version)
+	assert.Nil(t, resp.Body.Changes[0].Author, "authors are hidden from non-admins")
+	assert.Equal(t, 1, resp.Body.Page)
+	assert.Equal(t, DefaultPageSize, resp.Body.Limit)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	resp, err = server.handleGetRecentChanges(contextWithUser(admin), &ActivityInput{})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Changes, 1)
+	require.NotNil(t, resp.Body.Changes[0].Author)
+	assert.Equal(t, "writer@example.com", *resp.Body.Changes[0].Author)
+}
+
+func TestHandleGetRecentChanges_ByAuthor(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	article, err := db.GetArticleBySlug(ctx, "home")
+	require.NoError(t, err)
+
+	for _, author := range []string{"writer@example.com", "other@example.com"} {
+		draft, err := db.CreateDraft(ctx, article.Id, "# Edit by "+author, author)
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+	}
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	input := &ActivityInput{Author: "Writer@Example.com"}
+	resp, err := server.handleGetRecentChanges(contextWithUser(writer), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Changes, 1)
+	assert.Equal(t, "writer@example.com", resp.Body.Author)
+	assert.Equal(t, "writer@example.com", *resp.Body.Changes[0].Author)
+
+	input = &ActivityInput{Author: "other@example.com"}
+	_, err = server.handleGetRecentChanges(contextWithUser(writer), input)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	_, err = server.handleGetRecentChanges(ctx, &ActivityInput{Author: "writer@example.com"})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	input = &ActivityInput{Author: "other@example.com"}
+	resp, err = server.handleGetRecentChanges(contextWithUser(admin), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Changes, 1)
+	assert.Equal(t, 2, resp.Body.Changes[0].Version)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jellydator/ttlcache/v3"
+)
+
+const (
+	// adminStatsTtl is how long the aggregated admin statistics are reused before recomputing.
+	adminStatsTtl = 30 * time.Second
+	// adminStatsErrorWindow is how far back errors are counted in the admin statistics.
+	adminStatsErrorWindow = 24 * time.Hour
+	adminStatsKey         = "stats"
+)
+
+// UserRoleCounts breaks the user total down by role.
+type UserRoleCounts struct {
+	Read  int `json:"read"`
+	Write int `json:"write"`
+	Admin int `json:"admin"`
+}
+
+// AdminStats is an overview of the wiki's contents and health.
+type AdminStats struct {
+	GeneratedAt  time.Time      `json:"generatedAt"`
+	UsersByRole  UserRoleCounts `json:"usersByRole"`
+	Articles     int            `json:"articles"`
+	Users        int            `json:"users"`
+	Drafts       int            `json:"drafts"`
+	Orphans      int            `json:"orphans"`
+	RecentErrors int            `json:"recentErrors"`
+	DatabaseSize int64          `json:"databaseSize"`
+	// DroppedLogs counts log entries discarded since startup because the log queue was full.
+	DroppedLogs int64 `json:"droppedLogs"`
+}
+
+// AdminStatsOutput represents the output of the admin statistics endpoint.
+type AdminStatsOutput struct {
+	Body *AdminStats
+}
+
+// newAdminStatsCache creates the cache holding the most recent admin statistics.
+func newAdminStatsCache() *ttlcache.Cache[string, *AdminStats] {
+	return ttlcache.New[string, *AdminStats](
+		ttlcache.WithTTL[string, *AdminStats](adminStatsTtl),
+		ttlcache.WithCapacity[string, *AdminStats](1),
+	)
+}
+
+// registerAdminStatsRoutes registers the admin statistics route with the API.
+func (s *Server) registerAdminStatsRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-admin-stats",
+		Method:      http.MethodGet,
+		Path:        "/api/admin/stats",
+		Summary:     "Get Admin Statistics",
+		Description: "Get totals for articles, users by role, drafts and orphaned articles, " +
+			"the database size, the number of errors logged in the last 24 hours and the number of " +
+			"log entries dropped since startup because the log queue was full. " +
+			"Results are cached for 30 seconds. Admin only.",
+		Tags:     []string{"System"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleGetAdminStats)
+}
+
+// handleGetAdminStats handles fetching the admin statistics.
+func (s *Server) handleGetAdminStats(ctx context.Context, _ *struct{}) (*AdminStatsOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can view statistics")
+	}
+
+	if item := s.adminStatsCache.Get(adminStatsKey); item != nil {
+		return &AdminStatsOutput{Body: item.Value()}, nil
+	}
+
+	stats, err := s.collectAdminStats(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	s.adminStatsCache.Set(adminStatsKey, stats, ttlcache.DefaultTTL)
+
+	return &AdminStatsOutput{Body: stats}, nil
+}
+
+// collectAdminStats runs the queries behind the admin statistics.
+func (s *Server) collectAdminStats(ctx context.Context) (*AdminStats, error) {
+	now := time.Now()
+	stats := &AdminStats{GeneratedAt: now}
+
+	var err error
+
+	stats.Articles, err = s.db.CountArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Drafts, err = s.db.CountDrafts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.db.CountUsersByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.UsersByRole = UserRoleCounts{
+		Read:  roles[models.READ],
+		Write: roles[models.WRITE],
+		Admin: roles[models.ADMIN],
+	}
+
+	for _, count := range roles {
+		stats.Users += count
+	}
+
+	orphans, err := s.db.GetOrphanedArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Orphans = len(orphans)
+
+	stats.RecentErrors, err = s.db.CountLogsSince(
+		ctx,
+		now.Add(-adminStatsErrorWindow),
+		models.LevelError,
+		models.LevelSQLError,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.DatabaseSize, err = s.db.DatabaseSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.DroppedLogs = s.db.DroppedLogs()
+
+	return stats, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetAdminStats(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	admin, err := db.GetUserByEmail(ctx, "admin@test.com")
+	require.NoError(t, err)
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, writer))
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Lonely Page", writer.Email)
+	require.NoError(t, err)
+
+	resp, err := server.handleGetAdminStats(contextWithUser(admin), nil)
+	require.NoError(t, err)
+
+	stats := resp.Body
+	assert.Equal(t, 2, stats.Articles)
+	assert.Equal(t, 1, stats.Drafts)
+	assert.Equal(t, 1, stats.Orphans)
+	assert.Equal(t, 2, stats.Users)
+	assert.Equal(t, UserRoleCounts{Admin: 1, Write: 1}, stats.UsersByRole)
+	assert.Positive(t, stats.DatabaseSize)
+	assert.Zero(t, stats.DroppedLogs)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Another Page", writer.Email)
+	require.NoError(t, err)
+
+	cached, err := server.handleGetAdminStats(contextWithUser(admin), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cached.Body.Articles, "stats should be served from the cache")
+
+	server.adminStatsCache.DeleteAll()
+
+	fresh, err := server.handleGetAdminStats(contextWithUser(admin), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fresh.Body.Articles)
+}
+
+func TestHandleGetAdminStats_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), writer))
+
+	_, err := server.handleGetAdminStats(contextWithUser(writer), nil)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+}
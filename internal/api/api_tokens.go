@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// errInvalidApiToken is returned for unknown, expired or disabled personal access tokens.
+var errInvalidApiToken = errors.New("invalid or expired API token")
+
+// apiTokenTouchInterval is how stale the recorded last use of a personal access token may
+// get before it is updated again, so scripts making many requests do not write on each one.
+const apiTokenTouchInterval = time.Minute
+
+const (
+	// apiTokenTouchTimeout bounds the background write recording the last use of a token.
+	apiTokenTouchTimeout = 5 * time.Second
+	// apiTokenTouchRetryDelay is how long a failed write waits before it is tried again.
+	apiTokenTouchRetryDelay = 20 * time.Millisecond
+)
+
+// apiTokenContextKey is the key used to store/retrieve the personal access token from context.
+const apiTokenContextKey contextKey = "apiToken"
+
+// ApiTokenIDInput represents the input for addressing a personal access token by ID.
+type ApiTokenIDInput struct {
+	ID int `doc:"The ID of the token" path:"id"`
+}
+
+// CreateApiTokenInput represents the input for creating a personal access token.
+type CreateApiTokenInput struct {
+	Body struct {
+		Name      string     `doc:"Label to recognize the token by"                       json:"name"      maxLength:"100" minLength:"1" required:"true"`
+		Scopes    []string   `doc:"Scopes granted to the token: read or write"            json:"scopes"    required:"true"`
+		ExpiresAt *time.Time `doc:"When the token stops working. Omit for no expiry." json:"expiresAt" required:"false"`
+	}
+}
+
+// ApiTokenCreatedOutput represents the output of creating a personal access token.
+type ApiTokenCreatedOutput struct {
+	Body struct {
+		Token    string           `doc:"The token. It is only shown once." json:"token"`
+		ApiToken *models.ApiToken `json:"apiToken"`
+	}
+	Status int
+}
+
+// ApiTokenListOutput represents the output for a list of personal access tokens.
+type ApiTokenListOutput struct {
+	Body struct {
+		Tokens []*models.ApiToken `json:"tokens"`
+	}
+}
+
+// registerApiTokenRoutes registers the personal access token routes with the API.
+func (s *Server) registerApiTokenRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-my-api-tokens",
+		Method:      http.MethodGet,
+		Path:        "/api/me/tokens",
+		Summary:     "List Personal Access Tokens",
+		Description: "List the current user's personal access tokens. Token secrets are never returned.",
+		Tags:        []string{"Auth"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleListApiTokens)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "create-my-api-token",
+		Method:      http.MethodPost,
+		Path:        "/api/me/tokens",
+		Summary:     "Create Personal Access Token",
+		Description: "Create a token for scripts, sent as a Bearer token like a session. " +
+			"The token is only returned in this response. Read tokens may only make GET requests.",
+		Tags:     []string{"Auth"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleCreateApiToken)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "revoke-my-api-token",
+		Method:      http.MethodDelete,
+		Path:        "/api/me/tokens/{id}",
+		Summary:     "Revoke Personal Access Token",
+		Tags:        []string{"Auth"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleRevokeApiToken)
+}
+
+// parseApiTokenScopes validates and converts scope names.
+func parseApiTokenScopes(raw []string) ([]models.ApiTokenScope, error) {
+	if len(raw) == 0 {
+		return nil, huma.Error400BadRequest("At least one scope is required")
+	}
+
+	scopes := make([]models.ApiTokenScope, 0, len(raw))
+
+	for _, sc := range raw {
+		scope := models.ApiTokenScope(sc)
+		if !slices.Contains(models.ApiTokenScopes, scope) {
+			return nil, huma.Error400BadRequest("Unknown token scope: " + sc)
+		}
+
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
+
+// handleListApiTokens handles the request to list the current user's personal access tokens.
+func (s *Server) handleListApiTokens(ctx context.Context, _ *struct{}) (*ApiTokenListOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	tokens, err := s.db.GetApiTokensByUser(ctx, user.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	resp := &ApiTokenListOutput{}
+	resp.Body.Tokens = tokens
+	if resp.Body.Tokens == nil {
+		resp.Body.Tokens = []*models.ApiToken{}
+	}
+
+	return resp, nil
+}
+
+// handleCreateApiToken handles the creation of a personal access token.
+func (s *Server) handleCreateApiToken(
+	ctx context.Context,
+	input *CreateApiTokenInput,
+) (*ApiTokenCreatedOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if getImpersonatorFromContext(ctx) != nil {
+		return nil, huma.Error403Forbidden("Tokens cannot be created while impersonating a user")
+	}
+
+	scopes, err := parseApiTokenScopes(input.Body.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Body.ExpiresAt != nil && !input.Body.ExpiresAt.After(time.Now()) {
+		return nil, huma.Error400BadRequest("Token expiry must be in the future")
+	}
+
+	secret, err := utils.GenerateApiToken()
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to generate token", err)
+	}
+
+	token := &models.ApiToken{
+		UserId:      user.Id,
+		Name:        input.Body.Name,
+		HashedToken: utils.HashApiToken(secret),
+		Scopes:      scopes,
+		ExpiresAt:   input.Body.ExpiresAt,
+	}
+
+	err = s.db.CreateApiToken(ctx, token)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create token", err)
+	}
+
+	resp := &ApiTokenCreatedOutput{Status: http.StatusCreated}
+	resp.Body.Token = secret
+	resp.Body.ApiToken = token
+
+	return resp, nil
+}
+
+// handleRevokeApiToken handles the request to revoke one of the current user's tokens.
+func (s *Server) handleRevokeApiToken(
+	ctx context.Context,
+	input *ApiTokenIDInput,
+) (*struct{ Status int }, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.db.DeleteApiToken(ctx, input.ID, user.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to revoke token", err)
+	}
+
+	if !deleted {
+		return nil, huma.Error404NotFound("Token not found")
+	}
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
+
+// resolveApiToken authenticates a personal access token, returning its owner and the token.
+// The last use of the token is recorded in the background at most once per
+// apiTokenTouchInterval.
+func (s *Server) resolveApiToken(
+	ctx context.Context,
+	secret string,
+) (*models.User, *models.ApiToken, error) {
+	token, err := s.db.GetApiTokenByHash(ctx, utils.HashApiToken(secret))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	if token == nil || token.Expired(now) {
+		return nil, nil, errInvalidApiToken
+	}
+
+	user, err := s.db.GetUserByID(ctx, token.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user == nil || user.Disabled {
+		return nil, nil, errInvalidApiToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenTouchInterval {
+		s.touchApiToken(token.Id, now)
+	}
+
+	return user, token, nil
+}
+
+// touchApiToken records the last use of a token in the background, so the request does not
+// wait for the write. Only one write per token is in flight at a time, and failures are
+// logged rather than failing the request.
+func (s *Server) touchApiToken(id int, usedAt time.Time) {
+	_, touching := s.touchingApiTokens.LoadOrStore(id, struct{}{})
+	if touching {
+		return
+	}
+
+	s.apiTokenTouches.Go(func() {
+		defer s.touchingApiTokens.Delete(id)
+
+		ctx, cancel := context.WithTimeout(context.Background(), apiTokenTouchTimeout)
+		defer cancel()
+
+		// SQLite rejects a write while another one is in progress, such as the write made by
+		// the request itself, so failed writes are retried until the timeout.
+		err := s.db.TouchApiToken(ctx, id, usedAt)
+		for err != nil && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(apiTokenTouchRetryDelay):
+				err = s.db.TouchApiToken(ctx, id, usedAt)
+			}
+		}
+
+		if err != nil {
+			_ = s.db.CreateLogEntry(
+				context.Background(),
+				models.LevelWarning,
+				"AUTH",
+				"Failed to record API token use",
+				fmt.Sprintf("Token: %d | Error: %v", id, err),
+			)
+		}
+	})
+}
+
+// getApiTokenFromContext retrieves the personal access token the request was authenticated
+// with, or nil when it was authenticated another way.
+func getApiTokenFromContext(ctx context.Context) *models.ApiToken {
+	token, ok := ctx.Value(apiTokenContextKey).(*models.ApiToken)
+	if !ok {
+		return nil
+	}
+
+	return token
+}
+
+// requireScope rejects requests authenticated with a personal access token that lacks the
+// given scope. Session and JWT logins are not scoped and always pass.
+func requireScope(ctx context.Context, scope models.ApiTokenScope) error {
+	token := getApiTokenFromContext(ctx)
+	if token == nil || token.HasScope(scope) {
+		return nil
+	}
+
+	return huma.Error403Forbidden("This token does not have the " + string(scope) + " scope")
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newApiTokenFixture creates a writer and a personal access token for them with the given scopes.
+func newApiTokenFixture(
+	t *testing.T,
+	server *Server,
+	scopes ...string,
+) (*models.User, *ApiTokenCreatedOutput) {
+	t.Helper()
+
+	user := &models.User{Name: "Script", Email: "script@example.com", Role: models.WRITE}
+	require.NoError(t, server.db.CreateUser(context.Background(), user))
+
+	input := &CreateApiTokenInput{}
+	input.Body.Name = "CI"
+	input.Body.Scopes = scopes
+
+	resp, err := server.handleCreateApiToken(contextWithUser(user), input)
+	require.NoError(t, err)
+
+	return user, resp
+}
+
+// serveWithToken sends a request without a body through the authenticated router using a
+// Bearer token.
+func serveWithToken(server *Server, method, path, token string) *httptest.ResponseRecorder {
+	return serveJSONWithToken(server, method, path, token, "")
+}
+
+// serveJSONWithToken sends a request with a JSON body through the authenticated router using
+// a Bearer token.
+func serveJSONWithToken(server *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rr := httptest.NewRecorder()
+	server.authMiddleware(server.router).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestHandleCreateApiToken(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	user, resp := newApiTokenFixture(t, server, "write", "write")
+
+	assert.Equal(t, http.StatusCreated, resp.Status)
+	assert.True(t, strings.HasPrefix(resp.Body.Token, utils.ApiTokenPrefix))
+	assert.Equal(t, []models.ApiTokenScope{models.ScopeWrite}, resp.Body.ApiToken.Scopes)
+
+	stored, err := db.GetApiTokenByHash(context.Background(), utils.HashApiToken(resp.Body.Token))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, user.Id, stored.UserId)
+
+	body, err := json.Marshal(resp.Body.ApiToken)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), stored.HashedToken, "the hash is never returned")
+
+	list, err := server.handleListApiTokens(contextWithUser(user), nil)
+	require.NoError(t, err)
+	require.Len(t, list.Body.Tokens, 1)
+	assert.Equal(t, "CI", list.Body.Tokens[0].Name)
+}
+
+func TestHandleCreateApiToken_Invalid(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		{"no scopes", nil, nil},
+		{"unknown scope", []string{"admin"}, nil},
+		{"expiry in the past", []string{"read"}, &past},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &CreateApiTokenInput{}
+			input.Body.Name = "CI"
+			input.Body.Scopes = tt.scopes
+			input.Body.ExpiresAt = tt.expiresAt
+
+			_, err := server.handleCreateApiToken(contextWithUser(user), input)
+			require.Error(t, err)
+
+			var humaErr *huma.ErrorModel
+			require.True(t, errors.As(err, &humaErr))
+			assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+		})
+	}
+}
+
+func TestApiTokenAuthentication(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	user, resp := newApiTokenFixture(t, server, "write")
+
+	rr := serveWithToken(server, http.MethodGet, "/api/me/tokens", resp.Body.Token)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), `"name":"CI"`)
+
+	rr = serveWithToken(server, http.MethodPost, "/api/articles/home/watch", resp.Body.Token)
+	assert.Equal(t, http.StatusNoContent, rr.Code, "write tokens may make write requests")
+
+	server.apiTokenTouches.Wait()
+
+	tokens, err := db.GetApiTokensByUser(context.Background(), user.Id)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].LastUsedAt, "last use is recorded")
+
+	rr = serveWithToken(server, http.MethodGet, "/api/me/tokens", utils.ApiTokenPrefix+"unknown")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestResolveApiToken_TouchOutlivesRequest(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	user, resp := newApiTokenFixture(t, server, "read")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, err := server.resolveApiToken(ctx, resp.Body.Token)
+	require.NoError(t, err)
+	cancel()
+
+	server.apiTokenTouches.Wait()
+
+	tokens, err := db.GetApiTokensByUser(context.Background(), user.Id)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].LastUsedAt, "the write does not use the request context")
+}
+
+func TestApiTokenAuthentication_ReadScope(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	_, resp := newApiTokenFixture(t, server, "read")
+
+	rr := serveWithToken(server, http.MethodGet, "/api/me/watches", resp.Body.Token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveWithToken(server, http.MethodPost, "/api/articles/home/watch", resp.Body.Token)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "does not have the write scope")
+}
+
+func TestRequireScope_WriteHandlers(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	user, writeResp := newApiTokenFixture(t, server, "write")
+
+	input := &CreateApiTokenInput{}
+	input.Body.Name = "Read only"
+	input.Body.Scopes = []string{"read"}
+
+	readResp, err := server.handleCreateApiToken(contextWithUser(user), input)
+	require.NoError(t, err)
+
+	readToken := readResp.Body.Token
+	writeToken := writeResp.Body.Token
+
+	rr := serveJSONWithToken(server, http.MethodPost, "/api/articles", readToken, `{"title":"Scripted"}`)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "does not have the write scope")
+
+	rr = serveJSONWithToken(server, http.MethodPost, "/api/articles", writeToken, `{"title":"Scripted"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var created struct {
+		DraftID int `json:"draftId"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+	publish := "/api/drafts/" + strconv.Itoa(created.DraftID) + "/publish"
+
+	rr = serveWithToken(server, http.MethodPost, publish, readToken)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = serveWithToken(server, http.MethodPost, publish, writeToken)
+	assert.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+	rr = serveJSONWithToken(server, http.MethodPost, "/api/graphql", readToken, `{"query":"{ article(slug: \"scripted\") { title } }"}`)
+	assert.Equal(t, http.StatusOK, rr.Code, "read-only endpoints accept read tokens on any method")
+	assert.Contains(t, rr.Body.String(), `"title":"Scripted"`)
+
+	assert.NoError(t, requireScope(contextWithUser(user), models.ScopeWrite), "logins are not scoped")
+}
+
+func TestApiTokenAuthentication_Expired(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	_, resp := newApiTokenFixture(t, server, "read")
+
+	past := time.Now().Add(-time.Minute)
+	_, err := db.NewUpdate().
+		Model((*models.ApiToken)(nil)).
+		Set("expires_at = ?", past).
+		Where("id = ?", resp.Body.ApiToken.Id).
+		Exec(context.Background())
+	require.NoError(t, err)
+
+	rr := serveWithToken(server, http.MethodGet, "/api/me/tokens", resp.Body.Token)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestHandleRevokeApiToken(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	user, resp := newApiTokenFixture(t, server, "write")
+
+	other := &models.User{Email: "other@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), other))
+
+	_, err := server.handleRevokeApiToken(contextWithUser(other), &ApiTokenIDInput{ID: resp.Body.ApiToken.Id})
+	require.Error(t, err, "tokens of other users cannot be revoked")
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+
+	path := "/api/me/tokens/" + strconv.Itoa(resp.Body.ApiToken.Id)
+	rr := serveWithToken(server, http.MethodDelete, path, resp.Body.Token)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = serveWithToken(server, http.MethodGet, "/api/me/tokens", resp.Body.Token)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "revoked tokens no longer authenticate")
+
+	list, err := server.handleListApiTokens(contextWithUser(user), nil)
+	require.NoError(t, err)
+	assert.Empty(t, list.Body.Tokens)
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// TemplateIDInput represents the input for addressing a template by ID.
+type TemplateIDInput struct {
+	ID int `doc:"The ID of the template" path:"id"`
+}
+
+// CreateTemplateInput represents the input for creating a template.
+type CreateTemplateInput struct {
+	Body struct {
+		Name    string `doc:"Unique name shown in the template picker" json:"name"    maxLength:"100" required:"true"`
+		Content string `doc:"Markdown content. {{title}} and {{date}} are substituted." json:"content" required:"true"`
+	}
+}
+
+// UpdateTemplateInput represents the input for updating a template.
+type UpdateTemplateInput struct {
+	Body struct {
+		Name    *string `json:"name,omitempty"    maxLength:"100"`
+		Content *string `json:"content,omitempty"`
+	}
+	ID int `doc:"The ID of the template" path:"id"`
+}
+
+// TemplateOutput represents the output for a single template.
+type TemplateOutput struct {
+	Body struct {
+		Template *models.Template `json:"template"`
+	}
+}
+
+// TemplateListOutput represents the output for the template library.
+type TemplateListOutput struct {
+	Body struct {
+		Templates []*models.Template `json:"templates"`
+	}
+}
+
+// registerTemplateRoutes registers the article template library routes with the API.
+func (s *Server) registerTemplateRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-templates",
+		Method:      http.MethodGet,
+		Path:        "/api/templates",
+		Summary:     "List Templates",
+		Description: "List the templates available when creating an article.",
+		Tags:        []string{"Templates"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleListTemplates)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "create-template",
+		Method:      http.MethodPost,
+		Path:        "/api/templates",
+		Summary:     "Create Template",
+		Description: "Add a template to the library. Admin only.",
+		Tags:        []string{"Templates"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleCreateTemplate)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-template",
+		Method:      http.MethodGet,
+		Path:        "/api/templates/{id}",
+		Summary:     "Get Template",
+		Tags:        []string{"Templates"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleGetTemplate)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "update-template",
+		Method:      http.MethodPatch,
+		Path:        "/api/templates/{id}",
+		Summary:     "Update Template",
+		Description: "Admin only.",
+		Tags:        []string{"Templates"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleUpdateTemplate)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "delete-template",
+		Method:      http.MethodDelete,
+		Path:        "/api/templates/{id}",
+		Summary:     "Delete Template",
+		Description: "Admin only.",
+		Tags:        []string{"Templates"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleDeleteTemplate)
+}
+
+// validateTemplate checks a template's name and content against the server limits.
+// It returns the trimmed name.
+func (s *Server) validateTemplate(ctx context.Context, id int, name, content string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", huma.Error400BadRequest("Template name is required")
+	}
+
+	if len(content) > s.maxContentSize {
+		return "", errContentTooLarge(s.maxContentSize)
+	}
+
+	existing, err := s.db.GetTemplateByName(ctx, name)
+	if err != nil {
+		return "", huma.Error500InternalServerError("Database error", err)
+	}
+
+	if existing != nil && existing.Id != id {
+		return "", huma.Error409Conflict("A template with this name already exists")
+	}
+
+	return name, nil
+}
+
+// handleListTemplates handles the request to list templates.
+func (s *Server) handleListTemplates(ctx context.Context, _ *struct{}) (*TemplateListOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	templates, err := s.db.GetTemplates(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if templates == nil {
+		templates = []*models.Template{}
+	}
+
+	resp := &TemplateListOutput{}
+	resp.Body.Templates = templates
+
+	return resp, nil
+}
+
+// handleCreateTemplate handles the creation of a new template.
+func (s *Server) handleCreateTemplate(
+	ctx context.Context,
+	input *CreateTemplateInput,
+) (*TemplateOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage templates")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.validateTemplate(ctx, 0, input.Body.Name, input.Body.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &models.Template{
+		Name:    name,
+		Content: input.Body.Content,
+	}
+
+	err = s.db.CreateTemplate(ctx, tmpl)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create template", err)
+	}
+
+	resp := &TemplateOutput{}
+	resp.Body.Template = tmpl
+
+	return resp, nil
+}
+
+// handleGetTemplate handles the request to get a single template.
+func (s *Server) handleGetTemplate(
+	ctx context.Context,
+	input *TemplateIDInput,
+) (*TemplateOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	tmpl, err := s.db.GetTemplateByID(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if tmpl == nil {
+		return nil, huma.Error404NotFound("Template not found")
+	}
+
+	resp := &TemplateOutput{}
+	resp.Body.Template = tmpl
+
+	return resp, nil
+}
+
+// handleUpdateTemplate handles updating a template.
+func (s *Server) handleUpdateTemplate(
+	ctx context.Context,
+	input *UpdateTemplateInput,
+) (*TemplateOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage templates")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.db.GetTemplateByID(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if tmpl == nil {
+		return nil, huma.Error404NotFound("Template not found")
+	}
+
+	var cols []string
+
+	name := tmpl.Name
+	if input.Body.Name != nil {
+		name = *input.Body.Name
+
+		cols = append(cols, "name")
+	}
+
+	content := tmpl.Content
+	if input.Body.Content != nil {
+		content = *input.Body.Content
+
+		cols = append(cols, "content")
+	}
+
+	if len(cols) > 0 {
+		name, err = s.validateTemplate(ctx, tmpl.Id, name, content)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl.Name = name
+		tmpl.Content = content
+
+		err = s.db.UpdateTemplate(ctx, tmpl, cols...)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to update template", err)
+		}
+	}
+
+	resp := &TemplateOutput{}
+	resp.Body.Template = tmpl
+
+	return resp, nil
+}
+
+// handleDeleteTemplate handles deleting a template.
+func (s *Server) handleDeleteTemplate(
+	ctx context.Context,
+	input *TemplateIDInput,
+) (*struct{ Status int }, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage templates")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.db.GetTemplateByID(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if tmpl == nil {
+		return nil, huma.Error404NotFound("Template not found")
+	}
+
+	err = s.db.DeleteTemplate(ctx, tmpl.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete template", err)
+	}
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
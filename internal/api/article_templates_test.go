@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTemplates_CRUD(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	ctx := contextWithUser(admin)
+
+	input := &CreateTemplateInput{}
+	input.Body.Name = "  Meeting Notes "
+	input.Body.Content = "# {{title}}"
+
+	created, err := server.handleCreateTemplate(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, "Meeting Notes", created.Body.Template.Name)
+
+	_, err = server.handleCreateTemplate(ctx, input)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusConflict, humaErr.Status)
+
+	update := &UpdateTemplateInput{ID: created.Body.Template.Id}
+	content := "# {{title}}\n\n## Attendees\n"
+	update.Body.Content = &content
+
+	updated, err := server.handleUpdateTemplate(ctx, update)
+	require.NoError(t, err)
+	assert.Equal(t, content, updated.Body.Template.Content)
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	list, err := server.handleListTemplates(contextWithUser(writer), nil)
+	require.NoError(t, err)
+	require.Len(t, list.Body.Templates, 1)
+	assert.Equal(t, content, list.Body.Templates[0].Content)
+
+	_, err = server.handleDeleteTemplate(ctx, &TemplateIDInput{ID: created.Body.Template.Id})
+	require.NoError(t, err)
+
+	_, err = server.handleGetTemplate(ctx, &TemplateIDInput{ID: created.Body.Template.Id})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestHandleTemplates_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	tmpl := &models.Template{Name: "How-To", Content: "# How to"}
+	require.NoError(t, db.CreateTemplate(context.Background(), tmpl))
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(writer)
+
+	input := &CreateTemplateInput{}
+	input.Body.Name = "Sneaky"
+	input.Body.Content = "content"
+
+	_, err := server.handleCreateTemplate(ctx, input)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	_, err = server.handleDeleteTemplate(ctx, &TemplateIDInput{ID: tmpl.Id})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	_, err = server.handleListTemplates(context.Background(), nil)
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusUnauthorized, humaErr.Status)
+}
+
+func TestHandleCreateArticle_FromTemplate(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	tmpl := &models.Template{Name: "Meeting Notes", Content: "# {{title}}\n\nDate: {{date}}\n"}
+	require.NoError(t, db.CreateTemplate(context.Background(), tmpl))
+
+	user := &models.User{Email: "test@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	input := &CreateArticleInput{}
+	input.Body.Title = "Weekly Sync"
+	input.Body.TemplateID = tmpl.Id
+
+	resp, err := server.handleCreateArticle(ctx, input)
+	require.NoError(t, err)
+
+	_, content, err := db.GetDraftByID(context.Background(), resp.Body.DraftID)
+	require.NoError(t, err)
+	assert.Equal(t, "# Weekly Sync\n\nDate: "+time.Now().Format(time.DateOnly)+"\n", content)
+
+	input.Body.Title = "Another Sync"
+	input.Body.TemplateID = 999
+
+	_, err = server.handleCreateArticle(ctx, input)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+}
@@ -2,10 +2,12 @@ package api
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
+	"wikilite/internal/db"
 	"wikilite/pkg/models"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -20,15 +22,32 @@ const articleTemplateStr = `
 {{.Content}}
 </article>`
 
+// maxBatchSlugs is the maximum number of slugs that can be requested in a single batch.
+const maxBatchSlugs = 50
+
+// defaultBatchHistoryLimit and maxBatchHistoryLimit bound the versions returned per article
+// by the batch history endpoint.
+const (
+	defaultBatchHistoryLimit = 10
+	maxBatchHistoryLimit     = 50
+)
+
 // ArticleSlugInput represents the input for getting an article by slug.
 type ArticleSlugInput struct {
 	Slug string `doc:"The URL slug of the article" path:"slug"`
 }
 
+// ArticleViewInput represents the input for getting an article, optionally rendered.
+type ArticleViewInput struct {
+	Slug   string `doc:"The URL slug of the article"                                                               path:"slug"`
+	Render string `doc:"Set to 'html' to also return the HTML rendered by the plugin pipeline, as the UI shows it" enum:"markdown,html" query:"render"`
+}
+
 // ArticleContentInput represents the input for getting an article's content.
 type ArticleContentInput struct {
-	Slug   string `doc:"The URL slug of the article"   path:"slug"`
-	Format string `doc:"Output format: 'html' or 'md'"             default:"html" enum:"html,md" query:"format"`
+	Slug   string `doc:"The URL slug of the article"                                     path:"slug"`
+	Format string `doc:"Output format: 'html' or 'md'. Overrides the Accept header if set." enum:"html,md" query:"format"`
+	Accept string `doc:"Preferred media type: text/html or text/markdown"                 header:"Accept"`
 }
 
 // ArticleVersionInput represents the input for getting a specific version of an article.
@@ -38,11 +57,43 @@ type ArticleVersionInput struct {
 	Version int    `doc:"The specific version number to retrieve" path:"version"`
 }
 
+// ArticleBatchInput represents the input for fetching several articles by slug.
+type ArticleBatchInput struct {
+	Body struct {
+		Slugs []string `doc:"Slugs of the articles to fetch" json:"slugs" maxItems:"50" minItems:"1" required:"true"`
+	}
+}
+
+// ArticleHistoryBatchInput represents the input for fetching the history of several articles.
+type ArticleHistoryBatchInput struct {
+	Body struct {
+		Slugs []string `doc:"Slugs of the articles to fetch the history of"     json:"slugs"           maxItems:"50" minItems:"1" required:"true"`
+		Limit int      `doc:"Maximum number of versions per article, 10 if unset" json:"limit,omitempty" maximum:"50"  minimum:"1" required:"false"`
+	}
+}
+
 // CreateArticleInput represents the input for creating a new article.
 type CreateArticleInput struct {
+	Body struct {
+		Title      string `doc:"Title of the new article"                  json:"title"                required:"true"`
+		TemplateID int    `doc:"ID of a template to start the draft from" json:"templateId,omitempty" required:"false"`
+	}
+}
+
+// CloneArticleInput represents the input for cloning an existing article.
+type CloneArticleInput struct {
 	Body struct {
 		Title string `doc:"Title of the new article" json:"title" required:"true"`
 	}
+	Slug string `doc:"The URL slug of the article to clone" path:"slug"`
+}
+
+// CloneArticleOutput represents the output after cloning an article.
+type CloneArticleOutput struct {
+	Body struct {
+		ArticleSlug string `json:"articleSlug"`
+		ArticleId   int    `json:"articleId"`
+	}
 }
 
 // CreateArticleOutput represents the output after creating a new article.
@@ -61,19 +112,33 @@ type ArticleListInput struct {
 
 // ArticlePaginationInput represents the input for paginating articles.
 type ArticlePaginationInput struct {
-	Page  int `default:"1"  doc:"Page number"    minimum:"1" query:"page"`
-	Limit int `default:"10" doc:"Items per page" minimum:"1" query:"limit" maximum:"100"`
+	Page          int    `default:"1"                                                       doc:"Page number" minimum:"1" query:"page"`
+	Limit         int    `doc:"Items per page (capped by server config)"                    query:"limit"`
+	Sort          string `default:"updated"                                                 doc:"Sort order"  enum:"created,updated,title,popular" query:"sort"`
+	CreatedAfter  string `doc:"Only articles created at or after this time (RFC 3339)"  query:"createdAfter"`
+	CreatedBefore string `doc:"Only articles created at or before this time (RFC 3339)" query:"createdBefore"`
+	LinkCounts    bool   `doc:"Include inbound and outbound link counts"                query:"linkCounts"`
 }
 
 // PublicArticle is a sanitized version of models.Article for API responses.
 type PublicArticle struct {
 	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 	Author    *string   `json:"author,omitempty"`
 	Title     string    `json:"title"`
 	Slug      string    `json:"slug"`
 	Data      string    `json:"data,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
 	Id        int       `json:"id"`
 	Version   int       `json:"version"`
+
+	LastEditor   *string    `json:"lastEditor,omitempty"`
+	LastEditedAt *time.Time `json:"lastEditedAt,omitempty"`
+
+	ReconstructionDegraded bool `json:"reconstructionDegraded,omitempty"`
+
+	InboundLinks  *int `json:"inboundLinks,omitempty"`
+	OutboundLinks *int `json:"outboundLinks,omitempty"`
 }
 
 // ArticleListOutput represents the output for a list of articles.
@@ -90,10 +155,56 @@ type ArticleHistoryOutput struct {
 	}
 }
 
+// ArticleHistoryBatchOutput represents the history of several articles keyed by slug.
+type ArticleHistoryBatchOutput struct {
+	Body struct {
+		History map[string][]*models.History `json:"history"`
+	}
+}
+
 // ArticleOutput represents the output for a single article.
 type ArticleOutput struct {
 	Body struct {
 		*PublicArticle
+		HasDraft  bool   `doc:"Whether the current user (or anyone, for admins) has an open draft"                 json:"hasDraft"`
+		DraftID   int    `doc:"ID of the most recently updated open draft"                                         json:"draftId,omitempty"`
+		Watching  bool   `doc:"Whether the current user is watching the article"                                   json:"watching"`
+		IsStub    bool   `doc:"Whether the article is shorter than the stub word count"                            json:"isStub"`
+		HTML      string `doc:"The article rendered to HTML, including plugins. Only set with render=html"         json:"html,omitempty"`
+		CustomCSS string `doc:"CSS added to the article page. Only set when custom article code is enabled"        json:"customCss,omitempty"`
+		CustomJS  string `doc:"JavaScript added to the article page. Only set when custom article code is enabled" json:"customJs,omitempty"`
+	}
+}
+
+// ArticleBatchOutput represents the output for a batch of articles keyed by slug.
+type ArticleBatchOutput struct {
+	Body struct {
+		Articles map[string]*PublicArticle `json:"articles"`
+	}
+}
+
+// ArticleSourceOutput represents the raw markdown of an article split into lines.
+type ArticleSourceOutput struct {
+	Body struct {
+		Slug    string   `json:"slug"`
+		Version int      `json:"version"`
+		Lines   []string `doc:"Markdown source lines without line endings; line N is Lines[N-1]" json:"lines"`
+	}
+}
+
+// ArticlePreview is lightweight article metadata used for link previews.
+type ArticlePreview struct {
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	Excerpt     string `doc:"Plain text of the first paragraph" json:"excerpt"`
+	ReadingTime int    `doc:"Estimated reading time in minutes" json:"readingTime"`
+	Version     int    `json:"version"`
+}
+
+// ArticlePreviewOutput represents the output for an article preview.
+type ArticlePreviewOutput struct {
+	Body struct {
+		*ArticlePreview
 	}
 }
 
@@ -104,6 +215,7 @@ type PaginatedArticleListOutput struct {
 		Total    int64            `json:"total"`
 		Page     int              `json:"page"`
 		Limit    int              `json:"limit"`
+		Sort     string           `json:"sort"`
 	}
 }
 
@@ -119,20 +231,64 @@ func (s *Server) registerArticleRoutes() {
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handleCreateArticle)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "clone-article",
+		Method:      http.MethodPost,
+		Path:        "/api/articles/{slug}/clone",
+		Summary:     "Clone Article",
+		Description: "Creates a new article under a new title, published with the current " +
+			"content of an existing article.",
+		Tags:     []string{"Articles"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleCloneArticle)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-articles-batch",
+		Method:      http.MethodPost,
+		Path:        "/api/articles/batch",
+		Summary:     "Get Articles (Batch)",
+		Description: "Fetch several articles by slug in one request. Missing slugs are omitted.",
+		Tags:        []string{"Articles"},
+	}, s.handleGetArticlesBatch)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-articles-history-batch",
+		Method:      http.MethodPost,
+		Path:        "/api/articles/history/batch",
+		Summary:     "Get Article History (Batch)",
+		Description: "Fetch the most recent versions of several articles by slug in one request, " +
+			"e.g. to build a combined changelog. Missing slugs are omitted.",
+		Tags: []string{"Articles"},
+	}, s.handleGetArticlesHistoryBatch)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "get-article",
 		Method:      http.MethodGet,
 		Path:        "/api/articles/{slug}",
 		Summary:     "Get Article (JSON)",
+		Description: "Get an article with its markdown in data. With render=html, html holds the " +
+			"same rendered and plugin-processed HTML as the article page in the UI.",
+		Tags: []string{"Articles"},
+	}, s.handleGetArticle)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-article-preview",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/{slug}/preview",
+		Summary:     "Get Article Preview",
+		Description: "Get the title, a short plain text excerpt, and reading time for link previews.",
 		Tags:        []string{"Articles"},
-	}, s.handleGetArticleJSON)
+	}, s.handleGetArticlePreview)
 
 	huma.Register(s.api, huma.Operation{
 		OperationID: "get-article-content",
 		Method:      http.MethodGet,
 		Path:        "/api/articles/{slug}/content",
 		Summary:     "Get Article Content",
-		Tags:        []string{"Articles"},
+		Description: "Get the article as HTML or markdown. The format is chosen by the format " +
+			"query parameter, or negotiated from the Accept header when it is not set. " +
+			"{{include:slug}} tokens are replaced by the content of the included article.",
+		Tags: []string{"Articles"},
 	}, s.handleGetArticleContent)
 
 	huma.Register(s.api, huma.Operation{
@@ -144,16 +300,39 @@ func (s *Server) registerArticleRoutes() {
 		Tags:        []string{"Articles"},
 	}, s.handleGetArticleVersion)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-article-source",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/{slug}/source",
+		Summary:     "Get Article Source",
+		Description: "Get the raw markdown of the published article as numbered lines, for linking " +
+			"to a specific line. Line endings are normalized and {{include:slug}} tokens are kept.",
+		Tags: []string{"Articles"},
+	}, s.handleGetArticleSource)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "list-orphaned-articles",
 		Method:      http.MethodGet,
 		Path:        "/api/articles/orphans",
 		Summary:     "List Orphaned Articles",
-		Description: "Get a list of articles that are not linked to by any other article.",
-		Tags:        []string{"Articles"},
-		Security:    []map[string][]string{{"bearer": {}}},
+		Description: "Get a list of articles that are not linked to by any other article. " +
+			"Results are cached for 60 seconds or until an article is published or deleted; " +
+			"pass fresh=true to recompute.",
+		Tags:     []string{"Articles"},
+		Security: []map[string][]string{{"bearer": {}}},
 	}, s.handleGetOrphans)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-stub-articles",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/stubs",
+		Summary:     "List Stub Articles",
+		Description: "Get a list of published articles shorter than the stub word count, " +
+			"so contributors can find pages to expand. Results are cached like the orphan list.",
+		Tags:     []string{"Articles"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleGetStubs)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "get-user-articles",
 		Method:      http.MethodGet,
@@ -206,8 +385,10 @@ func sanitizeArticle(a *models.Article, isAdmin bool) *PublicArticle {
 		Slug:      a.Slug,
 		Version:   a.Version,
 		Data:      a.Data,
+		Tags:      a.Tags,
 		Author:    author,
 		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
 	}
 }
 
@@ -221,9 +402,55 @@ func (s *Server) handleCreateArticle(
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
-	article, draft, err := s.db.CreateArticleWithDraft(ctx, input.Body.Title, user.Email)
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkWriteLimit(user)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmpl *models.Template
+	if input.Body.TemplateID != 0 {
+		found, err := s.db.GetTemplateByID(ctx, input.Body.TemplateID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Database error", err)
+		}
+
+		if found == nil {
+			return nil, huma.Error400BadRequest("Template not found")
+		}
+
+		tmpl = found
+	}
+
+	err = s.checkDraftLimit(ctx, user, 0)
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Failed to create article", err)
+		return nil, err
+	}
+
+	var article *models.Article
+	var draft *models.Draft
+
+	if tmpl != nil {
+		content := tmpl.Render(input.Body.Title, time.Now())
+		article, draft, err = s.db.CreateArticleWithContent(
+			ctx,
+			input.Body.Title,
+			content,
+			user.Email,
+		)
+	} else {
+		article, draft, err = s.db.CreateArticleWithDraft(ctx, input.Body.Title, user.Email)
+	}
+
+	if err != nil {
+		if errors.Is(err, db.ErrContentTooLarge) {
+			return nil, errContentTooLarge(s.maxContentSize)
+		}
+		return nil, dbError(err, "Article", "Failed to create article")
 	}
 
 	resp := &CreateArticleOutput{}
@@ -234,6 +461,91 @@ func (s *Server) handleCreateArticle(
 	return resp, nil
 }
 
+// handleCloneArticle handles the request to fork an article under a new title.
+// The clone starts its own history at version 1 and is attributed to the requester.
+func (s *Server) handleCloneArticle(
+	ctx context.Context,
+	input *CloneArticleInput,
+) (*CloneArticleOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role < models.WRITE {
+		return nil, huma.Error403Forbidden("You do not have permission to publish")
+	}
+
+	err = s.checkWriteLimit(user)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if source == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	title := strings.TrimSpace(input.Body.Title)
+	if title == "" {
+		return nil, huma.Error400BadRequest("Title is required")
+	}
+
+	article, draft, err := s.db.CreateArticleWithContent(ctx, title, source.Data, user.Email)
+	if err != nil {
+		return nil, dbError(err, "Article", "Failed to create article")
+	}
+
+	err = s.db.PublishDraft(ctx, draft.Id)
+	if err != nil {
+		_ = s.db.DeleteArticle(ctx, article.Id)
+		return nil, huma.Error500InternalServerError("Failed to publish clone", err)
+	}
+
+	s.invalidateArticleReports()
+
+	s.emitWebhook(models.EventArticlePublished, map[string]any{
+		"id":          article.Id,
+		"slug":        article.Slug,
+		"title":       article.Title,
+		"version":     1,
+		"publishedBy": user.Email,
+	})
+
+	resp := &CloneArticleOutput{}
+	resp.Body.ArticleId = article.Id
+	resp.Body.ArticleSlug = article.Slug
+
+	return resp, nil
+}
+
+// handleGetArticle handles the request to get an article in JSON format, rendering it to
+// HTML when requested.
+func (s *Server) handleGetArticle(ctx context.Context, input *ArticleViewInput) (*ArticleOutput, error) {
+	resp, err := s.handleGetArticleJSON(ctx, &ArticleSlugInput{Slug: input.Slug})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Render == "html" {
+		resp.Body.HTML, err = s.renderArticleHTML(ctx, resp.Body.PublicArticle)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to render article", err)
+		}
+	}
+
+	return resp, nil
+}
+
 // handleGetArticleJSON handles the request to get an article in JSON format.
 func (s *Server) handleGetArticleJSON(
 	ctx context.Context,
@@ -256,6 +568,118 @@ func (s *Server) handleGetArticleJSON(
 
 	resp := &ArticleOutput{}
 	resp.Body.PublicArticle = sanitizeArticle(article, isAdmin)
+	resp.Body.IsStub = s.isStub(article)
+
+	if s.customArticleCode {
+		resp.Body.CustomCSS = article.CustomCSS
+		resp.Body.CustomJS = article.CustomJS
+	}
+
+	lastEdit, err := s.db.GetLastEdit(ctx, article.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if lastEdit != nil {
+		resp.Body.LastEditedAt = &lastEdit.CreatedAt
+
+		if isAdmin && lastEdit.CreatedBy != "" {
+			resp.Body.LastEditor = &lastEdit.CreatedBy
+		}
+	}
+
+	draft, err := s.findOpenDraft(ctx, article.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if draft != nil {
+		resp.Body.HasDraft = true
+		resp.Body.DraftID = draft.Id
+	}
+
+	if viewer := getUserFromContext(ctx); viewer != nil {
+		resp.Body.Watching, err = s.db.IsWatching(ctx, viewer.Id, article.Id)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Database error", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// findOpenDraft returns the most recently updated draft of an article visible to the current user.
+// Admins see drafts from any user, writers only their own. Anonymous users never see drafts.
+func (s *Server) findOpenDraft(ctx context.Context, articleID int) (*models.Draft, error) {
+	user := getUserFromContext(ctx)
+	if user == nil || user.Role < models.WRITE {
+		return nil, nil
+	}
+
+	var filter []string
+	if user.Role != models.ADMIN {
+		filter = append(filter, user.Email)
+	}
+
+	drafts, err := s.db.GetDraftsByArticle(ctx, articleID, filter...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(drafts) == 0 {
+		return nil, nil
+	}
+
+	return drafts[0], nil
+}
+
+// handleGetArticlesBatch handles the request to get several articles by slug.
+func (s *Server) handleGetArticlesBatch(
+	ctx context.Context,
+	input *ArticleBatchInput,
+) (*ArticleBatchOutput, error) {
+	if len(input.Body.Slugs) > maxBatchSlugs {
+		return nil, huma.Error400BadRequest(
+			fmt.Sprintf("A maximum of %d slugs can be requested at once", maxBatchSlugs),
+		)
+	}
+
+	articles, err := s.db.GetArticlesBySlugs(ctx, input.Body.Slugs)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	isAdmin := false
+	user := getAdminUserFromContext(ctx)
+	if user != nil {
+		isAdmin = true
+	}
+
+	resp := &ArticleBatchOutput{}
+	resp.Body.Articles = make(map[string]*PublicArticle, len(articles))
+	for _, a := range articles {
+		resp.Body.Articles[a.Slug] = sanitizeArticle(a, isAdmin)
+	}
+
+	return resp, nil
+}
+
+// handleGetArticlePreview handles the request to get an article's preview metadata.
+func (s *Server) handleGetArticlePreview(
+	ctx context.Context,
+	input *ArticleSlugInput,
+) (*ArticlePreviewOutput, error) {
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	resp := &ArticlePreviewOutput{}
+	resp.Body.ArticlePreview = s.getArticlePreview(article)
 
 	return resp, nil
 }
@@ -265,6 +689,19 @@ func (s *Server) handleGetArticleContent(
 	ctx context.Context,
 	input *ArticleContentInput,
 ) (*huma.StreamResponse, error) {
+	format := input.Format
+	if format == "" {
+		var ok bool
+
+		format, ok = negotiateContentFormat(input.Accept)
+		if !ok {
+			return nil, huma.NewError(
+				http.StatusNotAcceptable,
+				"Supported media types are text/html and text/markdown",
+			)
+		}
+	}
+
 	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Database error", err)
@@ -282,7 +719,12 @@ func (s *Server) handleGetArticleContent(
 
 	safeArticle := sanitizeArticle(article, isAdmin)
 
-	if input.Format == "md" {
+	if format == "md" {
+		safeArticle.Data, err = s.resolveIncludes(ctx, safeArticle.Slug, safeArticle.Data)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Database error", err)
+		}
+
 		return s.streamMarkdown(safeArticle), nil
 	}
 
@@ -292,13 +734,18 @@ func (s *Server) handleGetArticleContent(
 }
 
 // handleGetOrphans handles the request to get orphaned articles.
-func (s *Server) handleGetOrphans(ctx context.Context, _ *struct{}) (*ArticleListOutput, error) {
+func (s *Server) handleGetOrphans(ctx context.Context, input *ArticleReportInput) (*ArticleListOutput, error) {
 	user := getAdminUserFromContext(ctx)
 	if user == nil {
 		return nil, huma.Error403Forbidden("Only admins can view orphaned articles")
 	}
 
-	articles, err := s.db.GetOrphanedArticles(ctx)
+	articles, err := s.cachedArticleReport(
+		ctx,
+		orphansReportKey,
+		input.Fresh,
+		s.db.GetOrphanedArticles,
+	)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Database error", err)
 	}
@@ -314,6 +761,96 @@ func (s *Server) handleGetOrphans(ctx context.Context, _ *struct{}) (*ArticleLis
 	return resp, nil
 }
 
+// handleGetArticleSource handles the request to get the markdown source of an article.
+func (s *Server) handleGetArticleSource(
+	ctx context.Context,
+	input *ArticleSlugInput,
+) (*ArticleSourceOutput, error) {
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	resp := &ArticleSourceOutput{}
+	resp.Body.Slug = article.Slug
+	resp.Body.Version = article.Version
+	resp.Body.Lines = sourceLines(article.Data)
+
+	return resp, nil
+}
+
+// sourceLines splits markdown into lines, normalizing CRLF and CR line endings so that
+// line numbers do not depend on the editor the content was written with.
+func sourceLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	content = strings.TrimSuffix(content, "\n")
+
+	if content == "" {
+		return []string{}
+	}
+
+	return strings.Split(content, "\n")
+}
+
+// handleGetStubs handles the request to get stub articles.
+func (s *Server) handleGetStubs(ctx context.Context, input *ArticleReportInput) (*ArticleListOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Login required to view stub articles")
+	}
+
+	articles, err := s.cachedArticleReport(
+		ctx,
+		stubsReportKey,
+		input.Fresh,
+		s.findStubs,
+	)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	isAdmin := getAdminUserFromContext(ctx) != nil
+
+	stubs := make([]*PublicArticle, 0, len(articles))
+	for _, a := range articles {
+		safe := sanitizeArticle(a, isAdmin)
+		safe.Data = ""
+		stubs = append(stubs, safe)
+	}
+
+	resp := &ArticleListOutput{}
+	resp.Body.Articles = stubs
+
+	return resp, nil
+}
+
+// findStubs returns the published articles that are stubs.
+func (s *Server) findStubs(ctx context.Context) ([]*models.Article, error) {
+	articles, err := s.db.GetPublishedArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stubs []*models.Article
+	for _, a := range articles {
+		if s.isStub(a) {
+			stubs = append(stubs, a)
+		}
+	}
+
+	return stubs, nil
+}
+
+// isStub reports whether a published article has fewer words than the stub word count.
+func (s *Server) isStub(article *models.Article) bool {
+	return article.Version > 0 && s.renderer.WordCount(article.Data) < s.stubWordCount
+}
+
 // handleGetArticleVersion handles the request to get a specific version of an article.
 func (s *Server) handleGetArticleVersion(
 	ctx context.Context,
@@ -328,12 +865,9 @@ func (s *Server) handleGetArticleVersion(
 		return nil, huma.Error404NotFound("Article not found")
 	}
 
-	content, err := s.db.GetArticleVersion(ctx, article.Id, input.Version)
+	content, degraded, err := s.db.GetArticleVersion(ctx, article.Id, input.Version)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, huma.Error404NotFound("Article version not found")
-		}
-		return nil, huma.Error500InternalServerError("Failed to reconstruct version", err)
+		return nil, dbError(err, "Article version", "Failed to reconstruct version")
 	}
 
 	versionedArticle := *article
@@ -347,6 +881,7 @@ func (s *Server) handleGetArticleVersion(
 	}
 
 	safeArticle := sanitizeArticle(&versionedArticle, isAdmin)
+	safeArticle.ReconstructionDegraded = degraded
 
 	if input.Format == "md" {
 		return s.streamMarkdown(safeArticle), nil
@@ -417,28 +952,100 @@ func (s *Server) handleGetArticleHistory(
 		return nil, huma.Error500InternalServerError("Failed to fetch history", err)
 	}
 
+	if getAdminUserFromContext(ctx) == nil {
+		for _, h := range history {
+			h.CreatedBy = ""
+		}
+	}
+
 	resp := &ArticleHistoryOutput{}
 	resp.Body.History = history
 
 	return resp, nil
 }
 
+// handleGetArticlesHistoryBatch handles the request to get the recent history of several
+// articles by slug.
+func (s *Server) handleGetArticlesHistoryBatch(
+	ctx context.Context,
+	input *ArticleHistoryBatchInput,
+) (*ArticleHistoryBatchOutput, error) {
+	if len(input.Body.Slugs) > maxBatchSlugs {
+		return nil, huma.Error400BadRequest(
+			fmt.Sprintf("A maximum of %d slugs can be requested at once", maxBatchSlugs),
+		)
+	}
+
+	limit := input.Body.Limit
+	if limit <= 0 {
+		limit = defaultBatchHistoryLimit
+	}
+
+	limit = min(limit, maxBatchHistoryLimit)
+
+	articles, err := s.db.GetArticlesBySlugs(ctx, input.Body.Slugs)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	ids := make([]int, len(articles))
+	for i, a := range articles {
+		ids[i] = a.Id
+	}
+
+	history, err := s.db.GetHistoryForArticles(ctx, ids, limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to fetch history", err)
+	}
+
+	isAdmin := getAdminUserFromContext(ctx) != nil
+
+	resp := &ArticleHistoryBatchOutput{}
+	resp.Body.History = make(map[string][]*models.History, len(articles))
+	for _, a := range articles {
+		entries := history[a.Id]
+		if entries == nil {
+			entries = []*models.History{}
+		}
+
+		if !isAdmin {
+			for _, h := range entries {
+				h.CreatedBy = ""
+			}
+		}
+
+		resp.Body.History[a.Slug] = entries
+	}
+
+	return resp, nil
+}
+
 // handleGetArticles handles the request to get a paginated list of articles.
 func (s *Server) handleGetArticles(
 	ctx context.Context,
 	input *ArticlePaginationInput,
 ) (*PaginatedArticleListOutput, error) {
-	if input.Page < 1 {
-		input.Page = 1
+	var offset int
+	input.Page, input.Limit, offset = s.paginate(input.Page, input.Limit)
+
+	if input.Sort == "" {
+		input.Sort = db.SortUpdated
 	}
 
-	if input.Limit < 1 {
-		input.Limit = 10
+	filter, err := articleFilterFromInput(input)
+	if err != nil {
+		return nil, err
 	}
 
-	offset := (input.Page - 1) * input.Limit
+	articles, total, err := s.db.GetArticlesFiltered(ctx, input.Sort, filter, input.Limit, offset)
+	if errors.Is(err, db.ErrInvalidSort) {
+		return nil, huma.Error400BadRequest("Invalid sort order", &huma.ErrorDetail{
+			Message:  err.Error(),
+			Location: "query.sort",
+			Value:    input.Sort,
+		})
+	}
 
-	articles, total, err := s.db.GetArticles(ctx, input.Limit, offset)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Database error", err)
 	}
@@ -454,15 +1061,90 @@ func (s *Server) handleGetArticles(
 		safeArticles[i] = sanitizeArticle(a, isAdmin)
 	}
 
+	if input.LinkCounts {
+		err = s.addLinkCounts(ctx, safeArticles)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to count links", err)
+		}
+	}
+
 	resp := &PaginatedArticleListOutput{}
 	resp.Body.Articles = safeArticles
 	resp.Body.Total = total
 	resp.Body.Page = input.Page
 	resp.Body.Limit = input.Limit
+	resp.Body.Sort = input.Sort
 
 	return resp, nil
 }
 
+// addLinkCounts sets the inbound and outbound link counts of each article.
+func (s *Server) addLinkCounts(ctx context.Context, articles []*PublicArticle) error {
+	ids := make([]int, len(articles))
+	for i, a := range articles {
+		ids[i] = a.Id
+	}
+
+	counts, err := s.db.GetLinkCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range articles {
+		c := counts[a.Id]
+		a.InboundLinks = &c.Inbound
+		a.OutboundLinks = &c.Outbound
+	}
+
+	return nil
+}
+
+// articleFilterFromInput parses the creation date range of an article listing request,
+// returning a 400 error for malformed or inverted ranges.
+func articleFilterFromInput(input *ArticlePaginationInput) (db.ArticleFilter, error) {
+	var filter db.ArticleFilter
+
+	parse := func(value, location string) (time.Time, error) {
+		if value == "" {
+			return time.Time{}, nil
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, huma.Error400BadRequest("Invalid date", &huma.ErrorDetail{
+				Message:  "expected an RFC 3339 date-time such as 2024-01-01T00:00:00Z",
+				Location: location,
+				Value:    value,
+			})
+		}
+
+		return t, nil
+	}
+
+	var err error
+
+	filter.CreatedAfter, err = parse(input.CreatedAfter, "query.createdAfter")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.CreatedBefore, err = parse(input.CreatedBefore, "query.createdBefore")
+	if err != nil {
+		return filter, err
+	}
+
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() &&
+		filter.CreatedAfter.After(filter.CreatedBefore) {
+		return filter, huma.Error400BadRequest("Invalid date range", &huma.ErrorDetail{
+			Message:  "createdAfter must not be later than createdBefore",
+			Location: "query.createdAfter",
+			Value:    input.CreatedAfter,
+		})
+	}
+
+	return filter, nil
+}
+
 // handleDeleteArticle handles the request to delete an article.
 func (s *Server) handleDeleteArticle(
 	ctx context.Context,
@@ -473,6 +1155,11 @@ func (s *Server) handleDeleteArticle(
 		return nil, huma.Error403Forbidden("Only admins can delete articles")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Database error", err)
@@ -487,5 +1174,14 @@ func (s *Server) handleDeleteArticle(
 		return nil, huma.Error500InternalServerError("Failed to delete article", err)
 	}
 
+	s.invalidateArticleReports()
+
+	s.emitWebhook(models.EventArticleDeleted, map[string]any{
+		"id":        article.Id,
+		"slug":      article.Slug,
+		"title":     article.Title,
+		"deletedBy": admin.Email,
+	})
+
 	return &struct{ Status int }{Status: http.StatusNoContent}, nil
 }
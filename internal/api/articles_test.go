@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 	"wikilite/pkg/models"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -63,6 +65,62 @@ func TestHandleCreateArticle_Unauthorized(t *testing.T) {
 	assert.Equal(t, 401, humaErr.Status)
 }
 
+func TestHandleCloneArticle(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	source, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+	require.NotNil(t, source)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	input := &CloneArticleInput{Slug: "home"}
+	input.Body.Title = "Home Fork"
+
+	resp, err := server.handleCloneArticle(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, "home-fork", resp.Body.ArticleSlug)
+
+	clone, err := db.GetArticleBySlug(context.Background(), "home-fork")
+	require.NoError(t, err)
+	require.NotNil(t, clone)
+	assert.Equal(t, source.Data, clone.Data)
+	assert.Equal(t, 1, clone.Version)
+	assert.NotEqual(t, source.Id, clone.Id)
+	assert.NotEqual(t, source.Slug, clone.Slug)
+	assert.Equal(t, user.Email, clone.CreatedBy)
+	assert.NotEqual(t, source.CreatedBy, clone.CreatedBy)
+
+	count, err := db.CountDraftsByUser(context.Background(), user.Email)
+	require.NoError(t, err)
+	assert.Zero(t, count, "the clone's draft should be published")
+
+	tests := []struct {
+		name   string
+		slug   string
+		title  string
+		status int
+	}{
+		{"duplicate title", "home", "Home Fork", http.StatusConflict},
+		{"unknown source", "missing", "Another Fork", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &CloneArticleInput{Slug: tt.slug}
+			input.Body.Title = tt.title
+
+			_, err := server.handleCloneArticle(ctx, input)
+
+			var humaErr *huma.ErrorModel
+			require.True(t, errors.As(err, &humaErr))
+			assert.Equal(t, tt.status, humaErr.Status)
+		})
+	}
+}
+
 func TestHandleGetArticleJSON_Success(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -79,6 +137,31 @@ func TestHandleGetArticleJSON_Success(t *testing.T) {
 	assert.Nil(t, resp.Body.PublicArticle.Author, "Author should be nil for non-admin users")
 }
 
+func TestHandleGetArticle_RenderHTML(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	ctx := context.Background()
+
+	resp, err := server.handleGetArticle(ctx, &ArticleViewInput{Slug: "home"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Body.HTML, "HTML is only rendered on request")
+	assert.Contains(t, resp.Body.Data, "Welcome to your Home")
+
+	resp, err = server.handleGetArticle(ctx, &ArticleViewInput{Slug: "home", Render: "html"})
+	require.NoError(t, err)
+
+	expected, err := server.getRenderedHTML(ctx, resp.Body.PublicArticle)
+	require.NoError(t, err)
+	assert.Equal(t, expected, resp.Body.HTML)
+	assert.Contains(t, resp.Body.HTML, "<h1")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/home?render=pdf", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
 func TestHandleGetArticleJSON_NotFound(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -116,6 +199,214 @@ func TestHandleGetArticleJSON_AdminView(t *testing.T) {
 	assert.Equal(t, "1", *resp.Body.PublicArticle.Author)
 }
 
+func TestHandleGetArticleJSON_DraftIndicator(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	other := &models.User{Email: "other@example.com", Role: models.WRITE}
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(context.Background(), article.Id, article.Data, writer.Email)
+	require.NoError(t, err)
+
+	input := &ArticleSlugInput{Slug: "home"}
+
+	resp, err := server.handleGetArticleJSON(contextWithUser(writer), input)
+	require.NoError(t, err)
+	assert.True(t, resp.Body.HasDraft)
+	assert.Equal(t, draft.Id, resp.Body.DraftID)
+
+	resp, err = server.handleGetArticleJSON(contextWithUser(other), input)
+	require.NoError(t, err)
+	assert.False(t, resp.Body.HasDraft, "writers should only see their own drafts")
+	assert.Zero(t, resp.Body.DraftID)
+
+	resp, err = server.handleGetArticleJSON(contextWithUser(admin), input)
+	require.NoError(t, err)
+	assert.True(t, resp.Body.HasDraft, "admins should see drafts from any user")
+	assert.Equal(t, draft.Id, resp.Body.DraftID)
+
+	resp, err = server.handleGetArticleJSON(context.Background(), input)
+	require.NoError(t, err)
+	assert.False(t, resp.Body.HasDraft)
+}
+
+func TestHandleGetArticleJSON_LastEditor(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	input := &ArticleSlugInput{Slug: "home"}
+
+	resp, err := server.handleGetArticleJSON(ctx, input)
+	require.NoError(t, err)
+	assert.Nil(t, resp.Body.LastEditedAt, "the seeded article has never been edited")
+
+	article, err := db.GetArticleBySlug(ctx, "home")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(ctx, article.Id, "# Home v1", "writer@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	resp, err = server.handleGetArticleJSON(ctx, input)
+	require.NoError(t, err)
+	require.NotNil(t, resp.Body.LastEditedAt)
+	assert.Nil(t, resp.Body.LastEditor, "the last editor is hidden from non-admins")
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	resp, err = server.handleGetArticleJSON(contextWithUser(admin), input)
+	require.NoError(t, err)
+	require.NotNil(t, resp.Body.LastEditor)
+	assert.Equal(t, "writer@example.com", *resp.Body.LastEditor)
+}
+
+func TestHandleGetArticlesBatch_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	_, _, err := db.CreateArticleWithDraft(context.Background(), "Second Page", "admin@test.com")
+	require.NoError(t, err)
+
+	input := &ArticleBatchInput{}
+	input.Body.Slugs = []string{"home", "second-page", "missing"}
+
+	resp, err := server.handleGetArticlesBatch(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Articles, 2)
+
+	assert.Equal(t, "Home", resp.Body.Articles["home"].Title)
+	assert.Equal(t, "Second Page", resp.Body.Articles["second-page"].Title)
+	assert.Nil(t, resp.Body.Articles["home"].Author, "Author should be nil for non-admin users")
+	assert.NotContains(t, resp.Body.Articles, "missing")
+}
+
+func TestHandleGetArticlesHistoryBatch(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	ctx := context.Background()
+
+	for _, title := range []string{"Guide", "Reference"} {
+		publishArticle(t, db, title, title+" v1")
+
+		article, err := db.GetArticleBySlug(ctx, strings.ToLower(title))
+		require.NoError(t, err)
+
+		for _, content := range []string{title + " v2", title + " v3"} {
+			draft, err := db.CreateDraft(ctx, article.Id, content, "writer@example.com")
+			require.NoError(t, err)
+			require.NoError(t, db.PublishDraft(ctx, draft.Id))
+		}
+	}
+
+	input := &ArticleHistoryBatchInput{}
+	input.Body.Slugs = []string{"guide", "reference", "missing"}
+	input.Body.Limit = 2
+
+	resp, err := server.handleGetArticlesHistoryBatch(ctx, input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.History, 2)
+	assert.NotContains(t, resp.Body.History, "missing")
+
+	for _, slug := range []string{"guide", "reference"} {
+		entries := resp.Body.History[slug]
+		require.Len(t, entries, 2)
+		assert.Equal(t, 3, entries[0].Version)
+		assert.Equal(t, 2, entries[1].Version)
+		assert.Empty(t, entries[0].CreatedBy, "editors are hidden from non-admins")
+	}
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	input.Body.Limit = 0
+
+	resp, err = server.handleGetArticlesHistoryBatch(contextWithUser(admin), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.History["guide"], 3)
+	assert.Equal(t, "writer@example.com", resp.Body.History["guide"][0].CreatedBy)
+}
+
+func TestHandleGetArticlesBatch_AdminView(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	ctx := contextWithUser(admin)
+
+	input := &ArticleBatchInput{}
+	input.Body.Slugs = []string{"home"}
+
+	resp, err := server.handleGetArticlesBatch(ctx, input)
+	require.NoError(t, err)
+	require.Contains(t, resp.Body.Articles, "home")
+	require.NotNil(t, resp.Body.Articles["home"].Author)
+}
+
+func TestHandleGetArticlesBatch_TooManySlugs(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	input := &ArticleBatchInput{}
+	for i := range maxBatchSlugs + 1 {
+		input.Body.Slugs = append(input.Body.Slugs, fmt.Sprintf("page-%d", i))
+	}
+
+	_, err := server.handleGetArticlesBatch(context.Background(), input)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+}
+
+func TestHandleGetArticlePreview_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "test@example.com", Role: models.WRITE}
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Preview Article", user.Email)
+	require.NoError(t, err)
+
+	content := "# Preview Article\n\nThis is the **first** paragraph.\n\nSecond paragraph."
+	draft, err := db.CreateDraft(context.Background(), article.Id, content, user.Email)
+	require.NoError(t, err)
+	err = db.PublishDraft(context.Background(), draft.Id)
+	require.NoError(t, err)
+
+	input := &ArticleSlugInput{Slug: article.Slug}
+	resp, err := server.handleGetArticlePreview(context.Background(), input)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, "Preview Article", resp.Body.Title)
+	assert.Equal(t, "This is the first paragraph.", resp.Body.Excerpt)
+	assert.Equal(t, 1, resp.Body.ReadingTime)
+	assert.Equal(t, 1, resp.Body.Version)
+
+	cached := server.previewCache.Get(fmt.Sprintf("%s-%d", article.Slug, 1))
+	require.NotNil(t, cached)
+	assert.Equal(t, resp.Body.ArticlePreview, cached.Value())
+}
+
+func TestHandleGetArticlePreview_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	input := &ArticleSlugInput{Slug: "non-existent-slug"}
+	_, err := server.handleGetArticlePreview(context.Background(), input)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
 func TestHandleGetArticleContent_HTML(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -170,6 +461,48 @@ func TestHandleGetArticleContent_Markdown(t *testing.T) {
 	assert.Contains(t, body, "# Welcome to your Home")
 }
 
+func TestHandleGetArticleContent_Negotiation(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	tests := []struct {
+		name        string
+		query       string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"no accept header", "", "", http.StatusOK, "text/html"},
+		{"accept markdown", "", "text/markdown", http.StatusOK, "text/markdown"},
+		{"accept html", "", "text/html", http.StatusOK, "text/html"},
+		{"accept anything", "", "*/*", http.StatusOK, "text/html"},
+		{"browser accept", "", "text/html,application/xhtml+xml,*/*;q=0.8", http.StatusOK, "text/html"},
+		{"weighted markdown", "", "text/html;q=0.5, text/markdown", http.StatusOK, "text/markdown"},
+		{"query overrides accept", "?format=html", "text/markdown", http.StatusOK, "text/html"},
+		{"query with unsupported accept", "?format=md", "application/pdf", http.StatusOK, "text/markdown"},
+		{"unsupported accept", "", "application/pdf", http.StatusNotAcceptable, ""},
+		{"markdown refused", "", "text/markdown;q=0", http.StatusNotAcceptable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/home/content"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, rr.Body.String())
+			if tt.contentType != "" {
+				assert.Contains(t, rr.Header().Get("Content-Type"), tt.contentType)
+				assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
 func TestHandleGetArticleContent_NotFound(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -267,7 +600,7 @@ func TestHandleGetOrphans_Success(t *testing.T) {
 	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
 	ctx := contextWithUser(admin)
 
-	resp, err := server.handleGetOrphans(ctx, &struct{}{})
+	resp, err := server.handleGetOrphans(ctx, &ArticleReportInput{})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
@@ -282,7 +615,7 @@ func TestHandleGetOrphans_Unauthorized(t *testing.T) {
 	user := &models.User{Email: "test@example.com", Role: models.WRITE}
 	ctx := contextWithUser(user)
 
-	resp, err := server.handleGetOrphans(ctx, &struct{}{})
+	resp, err := server.handleGetOrphans(ctx, &ArticleReportInput{})
 	require.Error(t, err)
 	require.Nil(t, resp)
 
@@ -292,6 +625,122 @@ func TestHandleGetOrphans_Unauthorized(t *testing.T) {
 	assert.Equal(t, 403, humaErr.Status)
 }
 
+func TestHandleGetStubs(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	user := &models.User{Email: "test@example.com", Role: models.WRITE}
+
+	publish := func(title, content string) {
+		article, _, err := db.CreateArticleWithDraft(ctx, title, user.Email)
+		require.NoError(t, err)
+
+		draft, err := db.CreateDraft(ctx, article.Id, content, user.Email)
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+	}
+
+	publish("Short Article", "# Short Article\n\nOnly a few words here.")
+	publish("Long Article", "# Long Article\n\n"+strings.Repeat("word ", DefaultStubWordCount))
+
+	_, _, err := db.CreateArticleWithDraft(ctx, "Unpublished Article", user.Email)
+	require.NoError(t, err)
+
+	resp, err := server.handleGetStubs(contextWithUser(user), &ArticleReportInput{})
+	require.NoError(t, err)
+
+	slugs := make([]string, len(resp.Body.Articles))
+	for i, a := range resp.Body.Articles {
+		slugs[i] = a.Slug
+		assert.Empty(t, a.Data, "stub listings omit content")
+	}
+
+	assert.Contains(t, slugs, "short-article")
+	assert.NotContains(t, slugs, "long-article")
+	assert.NotContains(t, slugs, "unpublished-article")
+
+	short, err := server.handleGetArticleJSON(ctx, &ArticleSlugInput{Slug: "short-article"})
+	require.NoError(t, err)
+	assert.True(t, short.Body.IsStub)
+
+	long, err := server.handleGetArticleJSON(ctx, &ArticleSlugInput{Slug: "long-article"})
+	require.NoError(t, err)
+	assert.False(t, long.Body.IsStub)
+
+	_, err = server.handleGetStubs(ctx, &ArticleReportInput{})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusUnauthorized, humaErr.Status)
+}
+
+func TestHandleGetOrphans_Cached(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := contextWithUser(&models.User{Email: "admin@test.com", Role: models.ADMIN})
+
+	publishArticle(t, db, "First", "First page")
+
+	first, err := server.handleGetOrphans(ctx, &ArticleReportInput{})
+	require.NoError(t, err)
+	require.Len(t, first.Body.Articles, 1)
+
+	// Created directly in the database, so nothing invalidates the cached listing.
+	publishArticle(t, db, "Second", "Second page")
+
+	cached, err := server.handleGetOrphans(ctx, &ArticleReportInput{})
+	require.NoError(t, err)
+	assert.Len(t, cached.Body.Articles, 1, "a second call within the TTL is served from the cache")
+
+	fresh, err := server.handleGetOrphans(ctx, &ArticleReportInput{Fresh: true})
+	require.NoError(t, err)
+	assert.Len(t, fresh.Body.Articles, 2)
+
+	publishArticle(t, db, "Third", "Third page")
+
+	_, err = server.handleDeleteArticle(ctx, &ArticleSlugInput{Slug: "first"})
+	require.NoError(t, err)
+
+	invalidated, err := server.handleGetOrphans(ctx, &ArticleReportInput{})
+	require.NoError(t, err)
+	require.Len(t, invalidated.Body.Articles, 2, "deleting an article invalidates the cache")
+	assert.Equal(t, "Second", invalidated.Body.Articles[0].Title)
+	assert.Equal(t, "Third", invalidated.Body.Articles[1].Title)
+}
+
+func TestHandleGetArticleSource(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Source Page", "test@example.com")
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(ctx, article.Id, "# Source Page\r\n\r\nSee {{include:home}}\r\n", "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	resp, err := server.handleGetArticleSource(ctx, &ArticleSlugInput{Slug: "source-page"})
+	require.NoError(t, err)
+	assert.Equal(t, "source-page", resp.Body.Slug)
+	assert.Equal(t, 1, resp.Body.Version)
+	assert.Equal(t, []string{"# Source Page", "", "See {{include:home}}"}, resp.Body.Lines)
+
+	_, err = server.handleGetArticleSource(ctx, &ArticleSlugInput{Slug: "missing"})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestSourceLines(t *testing.T) {
+	assert.Equal(t, []string{}, sourceLines(""))
+	assert.Equal(t, []string{"a", "b", "c"}, sourceLines("a\r\nb\rc\n"))
+	assert.Equal(t, []string{"a", ""}, sourceLines("a\n\n"))
+}
+
 func TestHandleGetArticlesByUser_Success(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -410,6 +859,45 @@ func TestHandleGetArticleHistory_Success(t *testing.T) {
 	assert.Equal(t, 1, resp.Body.History[0].Version)
 }
 
+func TestHandleGetArticleHistory_AuthorAdminOnly(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	article, _, err := db.CreateArticleWithDraft(
+		context.Background(),
+		"History Article",
+		"alice@example.com",
+	)
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(
+		context.Background(),
+		article.Id,
+		"History content",
+		"alice@example.com",
+	)
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(context.Background(), draft.Id))
+
+	input := &ArticleSlugInput{Slug: article.Slug}
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	resp, err := server.handleGetArticleHistory(contextWithUser(admin), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.History, 1)
+	assert.Equal(t, "alice@example.com", resp.Body.History[0].CreatedBy)
+
+	writer := &models.User{Email: "alice@example.com", Role: models.WRITE}
+	resp, err = server.handleGetArticleHistory(contextWithUser(writer), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.History, 1)
+	assert.Empty(t, resp.Body.History[0].CreatedBy)
+
+	resp, err = server.handleGetArticleHistory(context.Background(), input)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Body.History[0].CreatedBy)
+}
+
 func TestHandleGetArticleHistory_NotFound(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -474,6 +962,118 @@ func TestHandleGetArticles_Pagination(t *testing.T) {
 	assert.Equal(t, 5, resp.Body.Limit)
 }
 
+func TestHandleGetArticles_LinkCounts(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Target", "# Target")
+	publishArticle(t, db, "Source", "See [Target](/wiki/target) and [Home](/wiki/home).")
+	publishArticle(t, db, "Lonely", "# Lonely")
+
+	ctx := context.Background()
+
+	resp, err := server.handleGetArticles(ctx, &ArticlePaginationInput{Page: 1, Sort: "title"})
+	require.NoError(t, err)
+	for _, a := range resp.Body.Articles {
+		assert.Nil(t, a.InboundLinks, "counts are omitted unless requested")
+		assert.Nil(t, a.OutboundLinks)
+	}
+
+	resp, err = server.handleGetArticles(ctx, &ArticlePaginationInput{Page: 1, Sort: "title", LinkCounts: true})
+	require.NoError(t, err)
+
+	counts := make(map[string][2]int, len(resp.Body.Articles))
+	for _, a := range resp.Body.Articles {
+		require.NotNil(t, a.InboundLinks)
+		require.NotNil(t, a.OutboundLinks)
+		counts[a.Slug] = [2]int{*a.InboundLinks, *a.OutboundLinks}
+	}
+
+	assert.Equal(t, [2]int{1, 0}, counts["target"])
+	assert.Equal(t, [2]int{0, 2}, counts["source"])
+	assert.Equal(t, [2]int{0, 0}, counts["lonely"])
+	assert.Equal(t, 1, counts["home"][0])
+}
+
+func TestHandleGetArticles_Sort(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	_, _, err := db.CreateArticleWithDraft(ctx, "Another Page", "test@example.com")
+	require.NoError(t, err)
+
+	home, err := db.GetArticleBySlug(ctx, "home")
+	require.NoError(t, err)
+
+	db.RecordView(home.Id)
+	require.NoError(t, db.FlushViews(ctx))
+
+	resp, err := server.handleGetArticles(ctx, &ArticlePaginationInput{Page: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "updated", resp.Body.Sort)
+	require.Len(t, resp.Body.Articles, 2)
+	assert.Equal(t, "Another Page", resp.Body.Articles[0].Title)
+
+	resp, err = server.handleGetArticles(ctx, &ArticlePaginationInput{Page: 1, Sort: "popular"})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Articles, 2)
+	assert.Equal(t, "Home", resp.Body.Articles[0].Title, "viewed articles rank first")
+
+	_, err = server.handleGetArticles(ctx, &ArticlePaginationInput{Page: 1, Sort: "bogus"})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+}
+
+func TestHandleGetArticles_CreatedRange(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Old Page", "test@example.com")
+	require.NoError(t, err)
+
+	_, err = db.NewUpdate().
+		Model((*models.Article)(nil)).
+		Set("created_at = ?", time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC)).
+		Where("id = ?", article.Id).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	resp, err := server.handleGetArticles(ctx, &ArticlePaginationInput{
+		Page:          1,
+		CreatedAfter:  "2020-06-01T12:00:00Z",
+		CreatedBefore: "2020-12-31T23:59:59Z",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Articles, 1)
+	assert.Equal(t, "Old Page", resp.Body.Articles[0].Title)
+	assert.EqualValues(t, 1, resp.Body.Total)
+
+	resp, err = server.handleGetArticles(ctx, &ArticlePaginationInput{Page: 1, CreatedAfter: "2021-01-01T00:00:00+02:00"})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Articles, 1)
+	assert.Equal(t, "Home", resp.Body.Articles[0].Title)
+
+	invalid := []*ArticlePaginationInput{
+		{Page: 1, CreatedAfter: "2020-06-01"},
+		{Page: 1, CreatedBefore: "yesterday"},
+		{Page: 1, CreatedAfter: "2021-01-01T00:00:00Z", CreatedBefore: "2020-01-01T00:00:00Z"},
+	}
+
+	for _, input := range invalid {
+		_, err = server.handleGetArticles(ctx, input)
+		require.Error(t, err)
+
+		var humaErr *huma.ErrorModel
+		require.True(t, errors.As(err, &humaErr))
+		assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+	}
+}
+
 func TestHandleDeleteArticle_Success(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
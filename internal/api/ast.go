@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"wikilite/internal/markdown"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ArticleASTOutput represents the output for fetching the syntax tree of an article.
+type ArticleASTOutput struct {
+	Body *markdown.Node
+}
+
+// registerASTRoutes registers the article syntax tree routes with the API.
+func (s *Server) registerASTRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-article-ast",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/{slug}/ast",
+		Summary:     "Get Article AST",
+		Description: "Get the markdown syntax tree of an article as JSON, for clients that render " +
+			"articles with their own components. Includes are resolved, raw HTML is sanitized " +
+			"and unsafe link destinations are removed, as when rendering HTML.",
+		Tags: []string{"Articles"},
+	}, s.handleGetArticleAST)
+}
+
+// handleGetArticleAST handles the request to get the syntax tree of an article.
+func (s *Server) handleGetArticleAST(
+	ctx context.Context,
+	input *ArticleSlugInput,
+) (*ArticleASTOutput, error) {
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	content, err := s.resolveIncludes(ctx, article.Slug, article.Data)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	return &ArticleASTOutput{Body: s.renderer.AST(content)}, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikilite/internal/markdown"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArticleAST_HTTP(t *testing.T) {
+	db := newTestDB(t)
+	server, err := NewServer(ServerConfig{
+		Database:   db,
+		JwtSecret:  "test-secret",
+		WikiName:   "Test Wiki",
+		ArticleAST: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	page := publishArticle(t, db, "Guide", "# Install\n\nRead the [FAQ](/wiki/faq) first.")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/"+page.Slug+"/ast", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(context.Background()))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var doc markdown.Node
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	assert.Equal(t, "Document", doc.Type)
+	require.Len(t, doc.Children, 2)
+
+	heading := doc.Children[0]
+	assert.Equal(t, "Heading", heading.Type)
+	assert.EqualValues(t, 1, heading.Attributes["level"])
+	assert.Equal(t, "Install", heading.Children[0].Text)
+
+	link := doc.Children[1].Children[1]
+	assert.Equal(t, "Link", link.Type)
+	assert.Equal(t, "/wiki/faq", link.Attributes["destination"])
+	assert.Equal(t, "FAQ", link.Children[0].Text)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/articles/missing/ast", nil)
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetArticleAST_Disabled(t *testing.T) {
+	server := newTestServer(t, newTestDB(t))
+
+	assert.NotContains(t, server.api.OpenAPI().Paths, "/api/articles/{slug}/ast")
+}
@@ -3,19 +3,26 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/base32"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"image/png"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 	"wikilite/pkg/models"
 	"wikilite/pkg/utils"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/didip/tollbooth/v8"
+	"github.com/didip/tollbooth/v8/limiter"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 )
 
@@ -24,8 +31,22 @@ const (
 	CookieName = "wiki_session"
 	// SessionDuration is the duration of a user session.
 	SessionDuration = 10 * time.Hour
+	// loginCheckRate is the sustained number of login checks allowed per second per client.
+	loginCheckRate = 0.2
+	// loginCheckBurst is the number of login checks a client may make in quick succession.
+	loginCheckBurst = 5
+	// backupCodesDownloadTTL is how long after enrollment the backup codes can be downloaded.
+	backupCodesDownloadTTL = 10 * time.Minute
 )
 
+// dummyPasswordHash is compared against when there is no account to check a password
+// against, so that unknown emails take as long to reject as wrong passwords.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := utils.HashPassword("wikilite-dummy-password")
+
+	return hash
+})
+
 // LoginInput represents the input for a user login request.
 type LoginInput struct {
 	Body struct {
@@ -35,6 +56,14 @@ type LoginInput struct {
 	}
 }
 
+// LoginCheckInput represents the input for a pre-login OTP check.
+type LoginCheckInput struct {
+	Body struct {
+		Email    string `format:"email"  json:"email"    required:"true"`
+		Password string `json:"password" required:"true"`
+	}
+}
+
 // OTPStartEnrollmentInput represents the input for an OTP enrollment request.
 type OTPStartEnrollmentInput struct {
 	Body struct {
@@ -47,6 +76,20 @@ type OTPCompleteEnrollmentInput struct {
 	Code string `path:"code"`
 }
 
+// OTPBackupCodesDownloadOutput represents the backup codes file returned after OTP enrollment.
+type OTPBackupCodesDownloadOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+// OTPQRCodeOutput represents the QR code image of an OTP enrollment in progress.
+type OTPQRCodeOutput struct {
+	ContentType  string `header:"Content-Type"`
+	CacheControl string `header:"Cache-Control"`
+	Body         []byte
+}
+
 // OTPRemoveInput represents the input for an OTP enrollment removal request.
 type OTPRemoveInput struct {
 	Email string `query:"email"`
@@ -57,6 +100,14 @@ type AuthOutput struct {
 	Cookies []string `header:"Set-Cookie"`
 }
 
+// LogoutOutput represents the output of a logout request. When the session has to be ended
+// at the external IDP as well, it redirects to the IDP's end-session URL.
+type LogoutOutput struct {
+	Status   int
+	Cookies  []string `header:"Set-Cookie"`
+	Location string   `header:"Location"`
+}
+
 // AuthTokenOutput represents the output of a token creation request.
 type AuthTokenOutput struct {
 	Body struct {
@@ -66,6 +117,13 @@ type AuthTokenOutput struct {
 	}
 }
 
+// LoginCheckOutput represents the output of a pre-login OTP check.
+type LoginCheckOutput struct {
+	Body struct {
+		OTPRequired bool `json:"otpRequired"`
+	}
+}
+
 // OTPStartEnrollmentOutput represents the output of an OTP enrollment request.
 type OTPStartEnrollmentOutput struct {
 	Body struct {
@@ -103,13 +161,28 @@ func (s *Server) registerAuthRoutes() {
 		Tags:        []string{"Auth"},
 	}, s.handleLoginToken)
 
+	loginCheckLimiter := tollbooth.NewLimiter(loginCheckRate, nil)
+	loginCheckLimiter.SetBurst(loginCheckBurst)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "check-login",
+		Method:      http.MethodPost,
+		Path:        "/api/login/check",
+		Summary:     "Check Login",
+		Description: "Report whether a login with these credentials will require an OTP code. " +
+			"Invalid credentials always report false. No token is issued. Rate limited.",
+		Tags:        []string{"Auth"},
+		Middlewares: huma.Middlewares{s.rateLimitOperation(loginCheckLimiter)},
+	}, s.handleLoginCheck)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "logout",
 		Method:      http.MethodPost,
 		Path:        "/api/logout",
 		Summary:     "User Logout",
-		Description: "Clears the session.",
-		Tags:        []string{"Auth"},
+		Description: "Clears the session. When an external IDP end-session endpoint is configured, " +
+			"responds with 303 See Other to the IDP's logout URL so the IDP session ends as well.",
+		Tags: []string{"Auth"},
 	}, s.handleLogout)
 
 	huma.Register(s.api, huma.Operation{
@@ -122,6 +195,17 @@ func (s *Server) registerAuthRoutes() {
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handleStartOTPEnrollment)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-otp-qr",
+		Method:      http.MethodGet,
+		Path:        "/api/otp/qr",
+		Summary:     "Get OTP Enrollment QR Code",
+		Description: "Returns the QR code of the OTP enrollment in progress as a PNG image, " +
+			"for use as an <img> source. Returns 404 when no enrollment is in progress.",
+		Tags:     []string{"Auth"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleGetOTPQRCode)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "complete-otp-enrollment",
 		Method:      http.MethodPost,
@@ -132,6 +216,17 @@ func (s *Server) registerAuthRoutes() {
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handleCompleteOTPEnrollment)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "download-otp-backup-codes",
+		Method:      http.MethodGet,
+		Path:        "/api/otp/backup-codes/download",
+		Summary:     "Download OTP Backup Codes",
+		Description: "Download the backup codes from the last OTP enrollment as a text file. " +
+			"The codes can be downloaded once, within 10 minutes of completing enrollment.",
+		Tags:     []string{"Auth"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleDownloadBackupCodes)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "remove-otp",
 		Method:      http.MethodDelete,
@@ -145,12 +240,16 @@ func (s *Server) registerAuthRoutes() {
 
 // createUserToken creates a new JWT token for a user.
 func (s *Server) createUserToken(ctx context.Context, input *LoginInput) (string, error) {
+	input.Body.Email = utils.NormalizeEmail(input.Body.Email)
+
 	user, err := s.db.GetUserByEmail(ctx, input.Body.Email)
 	if err != nil {
 		return "", huma.Error500InternalServerError("Database error", err)
 	}
 
 	if user == nil {
+		utils.CheckPassword(input.Body.Password, dummyPasswordHash())
+
 		return "", huma.Error401Unauthorized("Invalid email or password")
 	}
 
@@ -177,11 +276,17 @@ func (s *Server) createUserToken(ctx context.Context, input *LoginInput) (string
 		}
 	}
 
+	return s.signSessionToken(user)
+}
+
+// signSessionToken signs a regular session token for an already authenticated user.
+func (s *Server) signSessionToken(user *models.User) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":   fmt.Sprintf("%d", user.Id),
 		"email": user.Email,
 		"name":  user.Name,
 		"role":  user.Role,
+		"tv":    user.TokenVersion,
 		"iss":   s.LocalIssuer,
 		"iat":   time.Now().Unix(),
 		"exp":   time.Now().Add(SessionDuration).Unix(),
@@ -192,9 +297,51 @@ func (s *Server) createUserToken(ctx context.Context, input *LoginInput) (string
 	return token.SignedString(s.jwtSecret)
 }
 
+// newTOTPOptions builds the TOTP parameters shared by enrollment and validation,
+// applying defaults for unset values.
+func newTOTPOptions(period uint, digits int, algorithm string) (totp.ValidateOpts, error) {
+	opts := totp.ValidateOpts{
+		Period:    period,
+		Skew:      1,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	if opts.Period == 0 {
+		opts.Period = DefaultOTPPeriod
+	}
+
+	switch digits {
+	case 0, 6:
+	case 8:
+		opts.Digits = otp.DigitsEight
+	default:
+		return opts, fmt.Errorf("invalid OTP digits %d: must be 6 or 8", digits)
+	}
+
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA1":
+	case "SHA256":
+		opts.Algorithm = otp.AlgorithmSHA256
+	case "SHA512":
+		opts.Algorithm = otp.AlgorithmSHA512
+	default:
+		return opts, fmt.Errorf("invalid OTP algorithm %q: must be SHA1, SHA256 or SHA512", algorithm)
+	}
+
+	return opts, nil
+}
+
+// validateTOTP checks a TOTP code against a secret using the configured parameters.
+func (s *Server) validateTOTP(code, secret string) bool {
+	valid, err := totp.ValidateCustom(code, secret, time.Now().UTC(), s.totpOptions)
+
+	return err == nil && valid
+}
+
 // validateOTP validates either a TOTP code or backup code for a user.
 func (s *Server) validateOTP(ctx context.Context, otpCode, otpSecret string, userID int) error {
-	if totp.Validate(otpCode, otpSecret) {
+	if s.validateTOTP(otpCode, otpSecret) {
 		return nil
 	}
 
@@ -261,8 +408,62 @@ func (s *Server) handleLoginToken(
 	return resp, nil
 }
 
+// handleLoginCheck reports whether the credentials belong to a user with OTP enabled, so the
+// login form can ask for the code up front.
+//
+// It never reveals more than /api/login already does: a caller only learns about OTP after
+// presenting a valid password, and every other outcome (unknown email, wrong password,
+// disabled or external account) is reported as otpRequired=false rather than as an error.
+// The remaining cost is that it is a second endpoint that verifies passwords without issuing
+// a token, which makes it attractive for password guessing; it is therefore rate limited per
+// client IP independently of the global limiter.
+func (s *Server) handleLoginCheck(
+	ctx context.Context,
+	input *LoginCheckInput,
+) (*LoginCheckOutput, error) {
+	resp := &LoginCheckOutput{}
+
+	if s.isExternalIDPEnabled() {
+		return resp, nil
+	}
+
+	user, err := s.db.GetUserByEmail(ctx, utils.NormalizeEmail(input.Body.Email))
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if user == nil || user.Disabled || user.IsExternal {
+		utils.CheckPassword(input.Body.Password, dummyPasswordHash())
+
+		return resp, nil
+	}
+
+	if !utils.CheckPassword(input.Body.Password, user.Hash) {
+		return resp, nil
+	}
+
+	resp.Body.OTPRequired = user.OTPSecret != ""
+
+	return resp, nil
+}
+
+// rateLimitOperation returns a huma middleware that limits an operation per client IP.
+func (s *Server) rateLimitOperation(lmt *limiter.Limiter) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		r, _ := humago.Unwrap(ctx)
+
+		httpError := tollbooth.LimitByKeys(lmt, []string{s.clientIP(r)})
+		if httpError != nil {
+			_ = huma.WriteErr(s.api, ctx, httpError.StatusCode, "Too many requests, try again later")
+			return
+		}
+
+		next(ctx)
+	}
+}
+
 // handleLogout handles a user logout request.
-func (s *Server) handleLogout(_ context.Context, _ *struct{}) (*AuthOutput, error) {
+func (s *Server) handleLogout(_ context.Context, _ *struct{}) (*LogoutOutput, error) {
 	cookie := http.Cookie{
 		Name:     CookieName,
 		Value:    "",
@@ -272,12 +473,59 @@ func (s *Server) handleLogout(_ context.Context, _ *struct{}) (*AuthOutput, erro
 		MaxAge:   -1,
 	}
 
-	resp := &AuthOutput{}
-	resp.Cookies = []string{cookie.String()}
+	impersonatorCookie := cookie
+	impersonatorCookie.Name = ImpersonatorCookieName
+
+	resp := &LogoutOutput{}
+	resp.Cookies = []string{cookie.String(), impersonatorCookie.String()}
+
+	if logoutURL := s.idpLogoutURL(); logoutURL != "" {
+		resp.Status = http.StatusSeeOther
+		resp.Location = logoutURL
+	}
 
 	return resp, nil
 }
 
+// idpLogoutURL returns the URL that ends the session at the external IDP, or an empty
+// string when logout is local only.
+func (s *Server) idpLogoutURL() string {
+	if !s.isExternalIDPEnabled() {
+		return ""
+	}
+
+	return s.endSessionURL
+}
+
+// newEndSessionURL builds the RP-initiated logout URL from the IDP's end-session endpoint,
+// adding post_logout_redirect_uri when a redirect back to the wiki is configured. Query
+// parameters already present on the endpoint, such as client_id, are kept.
+func newEndSessionURL(endpoint, postLogoutRedirect string) (string, error) {
+	if endpoint == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || !u.IsAbs() {
+		return "", fmt.Errorf("invalid end session endpoint %q: must be an absolute URL", endpoint)
+	}
+
+	if postLogoutRedirect != "" {
+		redirect, err := url.Parse(postLogoutRedirect)
+		if err != nil || !redirect.IsAbs() {
+			return "", fmt.Errorf(
+				"invalid post logout redirect URL %q: must be an absolute URL", postLogoutRedirect,
+			)
+		}
+
+		query := u.Query()
+		query.Set("post_logout_redirect_uri", redirect.String())
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
 // handleStartOTPEnrollment handles a request to enroll an OTP secret.
 func (s *Server) handleStartOTPEnrollment(
 	ctx context.Context,
@@ -288,13 +536,21 @@ func (s *Server) handleStartOTPEnrollment(
 		return nil, huma.Error401Unauthorized("User not found in context")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	if !utils.CheckPassword(input.Body.Password, user.Hash) {
 		return nil, huma.Error401Unauthorized("Invalid password")
 	}
 
 	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer:      s.WikiName,
+		Issuer:      s.otpIssuer,
 		AccountName: user.Email,
+		Period:      s.totpOptions.Period,
+		Digits:      s.totpOptions.Digits,
+		Algorithm:   s.totpOptions.Algorithm,
 	})
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Failed to generate OTP secret", err)
@@ -319,31 +575,82 @@ func (s *Server) handleStartOTPEnrollment(
 		formattedCodes[i] = utils.FormatBackupCode(code)
 	}
 
-	qrCodeImage, err := key.Image(256, 256)
-	if err != nil {
-		return nil, huma.Error500InternalServerError("Failed to generate QR code", err)
-	}
-
-	var buf bytes.Buffer
-	err = png.Encode(&buf, qrCodeImage)
+	qrCode, err := otpQRCode(key)
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Failed to encode QR code", err)
+		return nil, err
 	}
 
 	qrCodeBase64 := fmt.Sprintf(
 		"data:image/png;base64,%s",
-		base64.StdEncoding.EncodeToString(buf.Bytes()),
+		base64.StdEncoding.EncodeToString(qrCode),
 	)
 
 	resp := &OTPStartEnrollmentOutput{}
 	resp.Body.Code = key.Secret()
 	resp.Body.QRCode = qrCodeBase64
-	resp.Body.Issuer = s.WikiName
+	resp.Body.Issuer = s.otpIssuer
 	resp.Body.BackupCodes = formattedCodes
 
 	return resp, nil
 }
 
+// handleGetOTPQRCode handles a request for the QR code of the caller's OTP enrollment in progress.
+func (s *Server) handleGetOTPQRCode(ctx context.Context, _ *struct{}) (*OTPQRCodeOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("User not found in context")
+	}
+
+	cachedSecret := s.otpCache.Get(user.Email)
+	if cachedSecret == nil {
+		return nil, huma.Error404NotFound("OTP enrollment not found or expired")
+	}
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(cachedSecret.Value())
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to decode OTP secret", err)
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.otpIssuer,
+		AccountName: user.Email,
+		Period:      s.totpOptions.Period,
+		Secret:      secret,
+		Digits:      s.totpOptions.Digits,
+		Algorithm:   s.totpOptions.Algorithm,
+	})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to generate OTP key", err)
+	}
+
+	qrCode, err := otpQRCode(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTPQRCodeOutput{
+		ContentType:  "image/png",
+		CacheControl: "no-store",
+		Body:         qrCode,
+	}, nil
+}
+
+// otpQRCode renders the QR code of an OTP key as a PNG image.
+func otpQRCode(key *otp.Key) ([]byte, error) {
+	qrCodeImage, err := key.Image(256, 256)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to generate QR code", err)
+	}
+
+	var buf bytes.Buffer
+	err = png.Encode(&buf, qrCodeImage)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to encode QR code", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
 // handleCompleteOTPEnrollment handles a request to complete an OTP enrollment.
 func (s *Server) handleCompleteOTPEnrollment(
 	ctx context.Context,
@@ -354,63 +661,117 @@ func (s *Server) handleCompleteOTPEnrollment(
 		return nil, huma.Error401Unauthorized("User not found in context")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	cachedSecret := s.otpCache.Get(user.Email)
 	if cachedSecret == nil {
 		return nil, huma.Error400BadRequest("OTP enrollment not found or expired")
 	}
 
-	valid := totp.Validate(input.Code, cachedSecret.Value())
+	valid := s.validateTOTP(input.Code, cachedSecret.Value())
 	if !valid {
 		return nil, huma.Error400BadRequest("Invalid OTP code")
 	}
 
+	backupCacheKey := user.Email + "_backup_codes"
+	cachedBackupCodes := s.otpCache.Get(backupCacheKey)
+	if cachedBackupCodes == nil {
+		return nil, huma.Error400BadRequest("OTP enrollment not found or expired")
+	}
+
+	var backupCodes []string
+	err = json.Unmarshal([]byte(cachedBackupCodes.Value()), &backupCodes)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to decode backup codes", err)
+	}
+
+	err = s.db.EnableOTP(ctx, user.Id, cachedSecret.Value(), backupCodes)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to enable OTP", err)
+	}
+
 	user.OTPSecret = cachedSecret.Value()
-	err := s.db.UpdateUser(ctx, user, "otp_secret")
+
+	s.otpCache.Delete(backupCacheKey)
+	s.otpCache.Delete(user.Email)
+
+	// The codes cannot be read back once enrollment completes, so they are held for a
+	// single download for a short time only.
+	s.otpCache.Set(backupCodesDownloadKey(user.Email), cachedBackupCodes.Value(), backupCodesDownloadTTL)
+
+	resp := &struct{ Status int }{}
+	resp.Status = 200
+
+	return resp, nil
+}
+
+// backupCodesDownloadKey returns the otpCache key holding a user's downloadable backup codes.
+func backupCodesDownloadKey(email string) string {
+	return email + "_backup_codes_download"
+}
+
+// handleDownloadBackupCodes handles a request to download the backup codes issued at enrollment.
+// The codes are served from the cache at most once; codes already used in the meantime are left out.
+func (s *Server) handleDownloadBackupCodes(
+	ctx context.Context,
+	_ *struct{},
+) (*OTPBackupCodesDownloadOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("User not found in context")
+	}
+
+	// Backup codes are credentials, so read-only tokens cannot fetch them.
+	err := requireScope(ctx, models.ScopeWrite)
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Failed to save OTP secret", err)
+		return nil, err
 	}
 
-	backupCacheKey := user.Email + "_backup_codes"
-	cachedBackupCodes := s.otpCache.Get(backupCacheKey)
+	cacheKey := backupCodesDownloadKey(user.Email)
 
-	if cachedBackupCodes != nil {
-		var backupCodes []string
-		err = json.Unmarshal([]byte(cachedBackupCodes.Value()), &backupCodes)
-		if err != nil {
-			return nil, huma.Error500InternalServerError("Failed to decode backup codes", err)
-		}
+	cachedBackupCodes := s.otpCache.Get(cacheKey)
+	if cachedBackupCodes == nil {
+		return nil, huma.Error404NotFound(
+			"Backup codes are only available for download once, shortly after enrollment",
+		)
+	}
 
-		err = s.db.DeleteBackupCodesByUserId(ctx, user.Id)
-		if err != nil {
-			return nil, huma.Error500InternalServerError(
-				"Failed to delete existing backup codes",
-				err,
-			)
-		}
+	s.otpCache.Delete(cacheKey)
 
-		dbBackupCodes := make([]*models.BackupCode, len(backupCodes))
-		for i, code := range backupCodes {
-			dbBackupCodes[i] = &models.BackupCode{
-				UserId: user.Id,
-				Code:   code,
-				Used:   false,
-			}
-		}
+	var backupCodes []string
+	err = json.Unmarshal([]byte(cachedBackupCodes.Value()), &backupCodes)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to decode backup codes", err)
+	}
 
-		err = s.db.CreateBackupCodes(ctx, dbBackupCodes)
-		if err != nil {
-			return nil, huma.Error500InternalServerError("Failed to save backup codes", err)
-		}
+	unused, err := s.db.GetUnusedBackupCodesByUserId(ctx, user.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
 
-		s.otpCache.Delete(backupCacheKey)
+	isUnused := make(map[string]bool, len(unused))
+	for _, code := range unused {
+		isUnused[code.Code] = true
 	}
 
-	s.otpCache.Delete(user.Email)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s backup codes for %s\n", s.otpIssuer, user.Email)
+	buf.WriteString("Each code can be used once to sign in without your authenticator app.\n\n")
 
-	resp := &struct{ Status int }{}
-	resp.Status = 200
+	for _, code := range backupCodes {
+		if isUnused[code] {
+			buf.WriteString(utils.FormatBackupCode(code) + "\n")
+		}
+	}
 
-	return resp, nil
+	return &OTPBackupCodesDownloadOutput{
+		ContentType:        "text/plain; charset=utf-8",
+		ContentDisposition: `attachment; filename="` + utils.ToKebabCase(s.otpIssuer) + `-backup-codes.txt"`,
+		Body:               buf.Bytes(),
+	}, nil
 }
 
 // handleRemoveOTP handles a request to remove an OTP enrollment.
@@ -423,8 +784,12 @@ func (s *Server) handleRemoveOTP(
 		return nil, huma.Error401Unauthorized("User not found in context")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	var targetUser *models.User
-	var err error
 
 	if input.Email != "" {
 		if reqUser.Role != models.ADMIN {
@@ -441,23 +806,44 @@ func (s *Server) handleRemoveOTP(
 		targetUser = reqUser
 	}
 
+	err = s.removeOTP(ctx, reqUser, targetUser)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &struct{ Status int }{}
+	resp.Status = 200
+
+	return resp, nil
+}
+
+// removeOTP disables OTP for the target user and deletes their backup codes.
+// Removals performed by an admin on another user are recorded in the logs.
+func (s *Server) removeOTP(ctx context.Context, reqUser, targetUser *models.User) error {
 	if targetUser.OTPSecret == "" {
-		return nil, huma.Error400BadRequest("User does not have OTP enabled")
+		return huma.Error400BadRequest("User does not have OTP enabled")
 	}
 
 	targetUser.OTPSecret = ""
-	err = s.db.UpdateUser(ctx, targetUser, "otp_secret")
+	err := s.db.UpdateUser(ctx, targetUser, "otp_secret")
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Failed to remove OTP secret", err)
+		return huma.Error500InternalServerError("Failed to remove OTP secret", err)
 	}
 
 	err = s.db.DeleteBackupCodesByUserId(ctx, targetUser.Id)
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Failed to delete backup codes", err)
+		return huma.Error500InternalServerError("Failed to delete backup codes", err)
 	}
 
-	resp := &struct{ Status int }{}
-	resp.Status = 200
+	if reqUser.Id != targetUser.Id {
+		_ = s.db.CreateLogEntry(
+			ctx,
+			models.LevelWarning,
+			"AUTH",
+			"OTP removed",
+			fmt.Sprintf("Admin: %s | User: %s", reqUser.Email, targetUser.Email),
+		)
+	}
 
-	return resp, nil
+	return nil
 }
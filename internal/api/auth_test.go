@@ -1,8 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 	"wikilite/pkg/models"
@@ -10,9 +16,11 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHandleLoginToken_Success(t *testing.T) {
@@ -50,6 +58,41 @@ func TestHandleLoginToken_Success(t *testing.T) {
 	assert.Equal(t, float64(user.Role), claims["role"])
 }
 
+func TestHandleLoginToken_EmailCaseInsensitive(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	password := "password123"
+	user := &models.User{
+		Name:  "Mixed Case User",
+		Email: " User@Example.com ",
+		Role:  models.WRITE,
+	}
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user.Hash = hash
+	err = db.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", user.Email)
+
+	input := &LoginInput{}
+	input.Body.Email = "USER@example.COM"
+	input.Body.Password = password
+
+	resp, err := server.handleLoginToken(context.Background(), input)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.NotEmpty(t, resp.Body.Token)
+
+	token, err := jwt.Parse(resp.Body.Token, func(token *jwt.Token) (any, error) {
+		return server.jwtSecret, nil
+	})
+	require.NoError(t, err)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	assert.Equal(t, "user@example.com", claims["email"])
+}
+
 func TestHandleLoginToken_InvalidCredentials(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -234,6 +277,172 @@ func TestHandleCompleteOTPEnrollment_Success(t *testing.T) {
 	}
 }
 
+func TestOTPEnrollment_CustomParameters(t *testing.T) {
+	db := newTestDB(t)
+
+	server, err := NewServer(ServerConfig{
+		Database:     db,
+		JwtSecret:    "test-secret",
+		WikiName:     "Test Wiki",
+		OTPIssuer:    "Example Corp",
+		OTPDigits:    8,
+		OTPAlgorithm: "sha256",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	password := "password123"
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE, Hash: hash}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	ctx := contextWithUser(user)
+
+	startInput := &OTPStartEnrollmentInput{}
+	startInput.Body.Password = password
+
+	startResp, err := server.handleStartOTPEnrollment(ctx, startInput)
+	require.NoError(t, err)
+	assert.Equal(t, "Example Corp", startResp.Body.Issuer)
+	secret := startResp.Body.Code
+
+	opts := totp.ValidateOpts{Period: 30, Digits: otp.DigitsEight, Algorithm: otp.AlgorithmSHA256}
+	code, err := totp.GenerateCodeCustom(secret, time.Now(), opts)
+	require.NoError(t, err)
+	require.Len(t, code, 8)
+
+	defaultCode, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	_, err = server.handleCompleteOTPEnrollment(ctx, &OTPCompleteEnrollmentInput{Code: defaultCode})
+	require.Error(t, err, "codes generated with the default parameters must be rejected")
+
+	_, err = server.handleCompleteOTPEnrollment(ctx, &OTPCompleteEnrollmentInput{Code: code})
+	require.NoError(t, err)
+
+	assert.NoError(t, server.validateOTP(context.Background(), code, secret, user.Id))
+	assert.Error(t, server.validateOTP(context.Background(), defaultCode, secret, user.Id))
+}
+
+func TestHandleDownloadBackupCodes(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	password := "password123"
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE, Hash: hash}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	ctx := contextWithUser(user)
+
+	_, err = server.handleDownloadBackupCodes(ctx, nil)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status, "codes are not downloadable before enrollment completes")
+
+	startInput := &OTPStartEnrollmentInput{}
+	startInput.Body.Password = password
+	startResp, err := server.handleStartOTPEnrollment(ctx, startInput)
+	require.NoError(t, err)
+
+	code, err := totp.GenerateCode(startResp.Body.Code, time.Now())
+	require.NoError(t, err)
+	_, err = server.handleCompleteOTPEnrollment(ctx, &OTPCompleteEnrollmentInput{Code: code})
+	require.NoError(t, err)
+
+	usedCode := startResp.Body.BackupCodes[0]
+	require.NoError(t, server.validateOTP(context.Background(), usedCode, startResp.Body.Code, user.Id))
+
+	resp, err := server.handleDownloadBackupCodes(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.ContentType)
+	assert.Equal(t, `attachment; filename="test-wiki-backup-codes.txt"`, resp.ContentDisposition)
+
+	body := string(resp.Body)
+	assert.NotContains(t, body, usedCode)
+	for _, backupCode := range startResp.Body.BackupCodes[1:] {
+		assert.Contains(t, body, backupCode)
+	}
+
+	_, err = server.handleDownloadBackupCodes(ctx, nil)
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status, "codes can only be downloaded once")
+}
+
+func TestHandleDownloadBackupCodes_ReadScope(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	user, readToken := newApiTokenFixture(t, server, "read")
+
+	server.otpCache.Set(backupCodesDownloadKey(user.Email), `["AAAA-BBBB"]`, backupCodesDownloadTTL)
+
+	rr := serveWithToken(server, http.MethodGet, "/api/otp/backup-codes/download", readToken.Body.Token)
+	assert.Equal(t, http.StatusForbidden, rr.Code, "read-only tokens cannot fetch backup codes")
+	assert.NotContains(t, rr.Body.String(), "AAAA-BBBB")
+
+	assert.NotNil(t, server.otpCache.Get(backupCodesDownloadKey(user.Email)),
+		"a rejected request does not use up the download")
+}
+
+func TestHandleGetOTPQRCode(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	password := "password123"
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE, Hash: hash}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	ctx := contextWithUser(user)
+
+	_, err = server.handleGetOTPQRCode(ctx, nil)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status, "no enrollment is in progress")
+
+	startInput := &OTPStartEnrollmentInput{}
+	startInput.Body.Password = password
+	startResp, err := server.handleStartOTPEnrollment(ctx, startInput)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/otp/qr", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(ctx))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+	_, err = png.Decode(bytes.NewReader(rr.Body.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t,
+		startResp.Body.QRCode,
+		"data:image/png;base64,"+base64.StdEncoding.EncodeToString(rr.Body.Bytes()),
+		"the image encodes the same key as the enrollment response",
+	)
+
+	code, err := totp.GenerateCode(startResp.Body.Code, time.Now())
+	require.NoError(t, err)
+	_, err = server.handleCompleteOTPEnrollment(ctx, &OTPCompleteEnrollmentInput{Code: code})
+	require.NoError(t, err)
+
+	_, err = server.handleGetOTPQRCode(ctx, nil)
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status, "the image is gone once enrollment completes")
+}
+
+func TestNewTOTPOptions_Invalid(t *testing.T) {
+	_, err := newTOTPOptions(0, 7, "")
+	assert.Error(t, err)
+
+	_, err = newTOTPOptions(0, 0, "MD5")
+	assert.Error(t, err)
+}
+
 func TestHandleCompleteOTPEnrollment_InvalidCode(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -640,3 +849,159 @@ func TestHandleRemoveOTP_NonAdminTriesToRemoveOtherUser(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, 403, humaErr.Status)
 }
+
+func TestValidateToken_RevokedByPasswordChange(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	password := "password123"
+	user := &models.User{
+		Name:  "Test User",
+		Email: "test@example.com",
+		Role:  models.WRITE,
+	}
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user.Hash = hash
+	err = db.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+
+	input := &LoginInput{}
+	input.Body.Email = user.Email
+	input.Body.Password = password
+
+	before, err := server.handleLoginToken(context.Background(), input)
+	require.NoError(t, err)
+
+	_, err = server.validateToken(context.Background(), before.Body.Token)
+	require.NoError(t, err)
+
+	newPassword := "newpassword456"
+	hash, err = utils.HashPassword(newPassword)
+	require.NoError(t, err)
+	user.Hash = hash
+	err = db.UpdateUser(context.Background(), user, "hash")
+	require.NoError(t, err)
+
+	_, err = server.validateToken(context.Background(), before.Body.Token)
+	assert.Error(t, err, "token issued before the password change should be rejected")
+
+	input.Body.Password = newPassword
+	after, err := server.handleLoginToken(context.Background(), input)
+	require.NoError(t, err)
+
+	validated, err := server.validateToken(context.Background(), after.Body.Token)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, validated.Email)
+}
+
+func TestValidateToken_UnrelatedUpdateKeepsSession(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{
+		Name:  "Test User",
+		Email: "test@example.com",
+		Role:  models.WRITE,
+	}
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	user.Hash = hash
+	err = db.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+
+	token, err := server.signSessionToken(user)
+	require.NoError(t, err)
+
+	user.Name = "Renamed User"
+	err = db.UpdateUser(context.Background(), user, "name")
+	require.NoError(t, err)
+
+	_, err = server.validateToken(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+func TestHandleLoginCheck(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	password := "password123"
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	otpUser := &models.User{
+		Name:      "OTP User",
+		Email:     "otp@example.com",
+		Role:      models.WRITE,
+		Hash:      hash,
+		OTPSecret: "JBSWY3DPEHPK3PXP",
+	}
+	require.NoError(t, db.CreateUser(context.Background(), otpUser))
+
+	plainUser := &models.User{
+		Name:  "Plain User",
+		Email: "plain@example.com",
+		Role:  models.WRITE,
+		Hash:  hash,
+	}
+	require.NoError(t, db.CreateUser(context.Background(), plainUser))
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		expected bool
+	}{
+		{"otp user", "OTP@example.com", password, true},
+		{"otp user wrong password", otpUser.Email, "wrong-password", false},
+		{"user without otp", plainUser.Email, password, false},
+		{"unknown user", "missing@example.com", password, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &LoginCheckInput{}
+			input.Body.Email = tt.email
+			input.Body.Password = tt.password
+
+			resp, err := server.handleLoginCheck(context.Background(), input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.Body.OTPRequired)
+		})
+	}
+}
+
+func TestDummyPasswordHash(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+
+	realCost, err := bcrypt.Cost([]byte(hash))
+	require.NoError(t, err)
+
+	dummyCost, err := bcrypt.Cost([]byte(dummyPasswordHash()))
+	require.NoError(t, err)
+	assert.Equal(t, realCost, dummyCost, "unknown emails cost as much to reject as wrong passwords")
+
+	assert.False(t, utils.CheckPassword("password123", dummyPasswordHash()))
+}
+
+func TestHandleLoginCheck_RateLimited(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	check := func() int {
+		body := strings.NewReader(`{"email":"missing@example.com","password":"password123"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/login/check", body)
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	for range loginCheckBurst {
+		assert.Equal(t, http.StatusOK, check())
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, check())
+}
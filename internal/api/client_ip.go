@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+)
+
+// DefaultTrustedProxyHops is the default number of reverse proxies in front of the server.
+const DefaultTrustedProxyHops = 1
+
+// clientIPContextKey is the key used to store/retrieve the client IP from context.
+const clientIPContextKey contextKey = "clientIP"
+
+// clientIP resolves the address of the client that made the request.
+// Proxy headers are only honored when trustProxyHeaders is set, since any client can send them.
+// X-Forwarded-For is read from the right, skipping one entry per trusted proxy hop beyond the
+// first, because the left-most entries are supplied by the client. X-Real-IP is used when
+// X-Forwarded-For is absent. Otherwise the connection's remote address is used.
+func (s *Server) clientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	host, _, err := net.SplitHostPort(remote)
+	if err == nil {
+		remote = host
+	}
+
+	if !s.trustProxyHeaders {
+		return remote
+	}
+
+	forwarded := forwardedFor(r)
+	if len(forwarded) > 0 {
+		hop := max(len(forwarded)-s.trustedProxyHops, 0)
+		return forwarded[hop]
+	}
+
+	realIP := strings.TrimSpace(r.Header.Get("X-Real-IP"))
+	if net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remote
+}
+
+// forwardedFor returns the valid addresses from all X-Forwarded-For headers, in order.
+func forwardedFor(r *http.Request) []string {
+	var ips []string
+
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, part := range strings.Split(header, ",") {
+			ip := strings.TrimSpace(part)
+			if net.ParseIP(ip) != nil {
+				ips = append(ips, ip)
+			}
+		}
+	}
+
+	return ips
+}
+
+// withClientIP is a huma middleware that makes the client IP available to the handler
+// through getClientIPFromContext.
+func (s *Server) withClientIP(ctx huma.Context, next func(huma.Context)) {
+	r, _ := humago.Unwrap(ctx)
+
+	next(huma.WithValue(ctx, clientIPContextKey, s.clientIP(r)))
+}
+
+// getClientIPFromContext returns the client IP stored by withClientIP, or "" if there is none.
+func getClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey).(string)
+
+	return ip
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		trust    bool
+		hops     int
+		forward  []string
+		realIP   string
+		expected string
+	}{
+		{"untrusted ignores spoofed forwarded-for", false, 1, []string{"6.6.6.6"}, "", "10.0.0.1"},
+		{"untrusted ignores spoofed real ip", false, 1, nil, "6.6.6.6", "10.0.0.1"},
+		{"trusted without headers", true, 1, nil, "", "10.0.0.1"},
+		{"trusted single hop", true, 1, []string{"203.0.113.7"}, "", "203.0.113.7"},
+		{
+			"trusted takes right-most for one proxy",
+			true, 1, []string{"6.6.6.6, 203.0.113.7"}, "", "203.0.113.7",
+		},
+		{
+			"trusted skips proxy hops",
+			true, 2, []string{"6.6.6.6, 203.0.113.7, 192.168.1.2"}, "", "203.0.113.7",
+		},
+		{"trusted hops beyond chain", true, 5, []string{"203.0.113.7"}, "", "203.0.113.7"},
+		{
+			"trusted multiple headers",
+			true, 1, []string{"6.6.6.6", "203.0.113.7"}, "", "203.0.113.7",
+		},
+		{"trusted skips invalid entries", true, 1, []string{"203.0.113.7, garbage"}, "", "203.0.113.7"},
+		{"trusted falls back to real ip", true, 1, nil, "203.0.113.9", "203.0.113.9"},
+		{"trusted ignores invalid real ip", true, 1, nil, "not-an-ip", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{trustProxyHeaders: tt.trust, trustedProxyHops: tt.hops}
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = "10.0.0.1:54321"
+			for _, value := range tt.forward {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			assert.Equal(t, tt.expected, server.clientIP(req))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// compressionMinSize is the smallest response body, in bytes, that is compressed. Below it
+// the encoding overhead outweighs the savings.
+const compressionMinSize = 1024
+
+// compressibleTypes are the media types compressed in addition to text/* and the +json
+// and +xml structured syntaxes.
+var compressibleTypes = map[string]bool{
+	"application/json":       true,
+	"application/javascript": true,
+	"application/xml":        true,
+	"application/yaml":       true,
+}
+
+// compressionMiddleware compresses text responses with gzip or deflate when the client
+// accepts it and the body reaches compressionMinSize.
+func (s *Server) compressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: compressionMinSize}
+		defer func() {
+			_ = cw.Close()
+		}()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the content coding for an Accept-Encoding header: "gzip",
+// "deflate", or "" when neither is acceptable. gzip wins ties.
+func negotiateEncoding(acceptEncoding string) string {
+	encoding := ""
+	bestQuality := 0.0
+
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		quality := parseQuality(params)
+		if quality <= 0 {
+			continue
+		}
+
+		var candidate string
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip", "*":
+			candidate = "gzip"
+		case "deflate":
+			candidate = "deflate"
+		default:
+			continue
+		}
+
+		if quality > bestQuality || (quality == bestQuality && candidate == "gzip") {
+			encoding = candidate
+			bestQuality = quality
+		}
+	}
+
+	return encoding
+}
+
+// isCompressible reports whether a response with the given Content-Type is worth compressing.
+func isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "+xml") ||
+		compressibleTypes[mediaType]
+}
+
+// encoder is a compressing writer that can flush pending output.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter buffers the start of a response until it knows whether to compress:
+// responses that are not compressible, already encoded or shorter than minSize are written
+// unchanged.
+type compressWriter struct {
+	http.ResponseWriter
+
+	encoding string
+	minSize  int
+	status   int
+	buf      []byte
+	decided  bool
+	encoder  encoder
+}
+
+// WriteHeader records the status code; it is sent once the encoding is decided.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	if cw.status != 0 {
+		return
+	}
+
+	cw.status = code
+
+	if code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusPartialContent {
+		cw.decide(false)
+	}
+}
+
+// Write buffers p until minSize bytes are available, then writes it compressed if possible.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	if !cw.decided {
+		if !cw.canCompress() {
+			cw.decide(false)
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) >= cw.minSize {
+				cw.decide(true)
+			}
+
+			return len(p), nil
+		}
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends buffered output to the client. A response flushed before reaching minSize
+// is streamed, so it is compressed regardless of its size.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+
+		cw.decide(cw.canCompress())
+	}
+
+	if cw.encoder != nil {
+		_ = cw.encoder.Flush()
+	}
+
+	flusher, ok := cw.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Close writes any buffered output and ends the compressed stream.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if cw.status == 0 {
+			return nil
+		}
+
+		cw.decide(false)
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Close()
+	}
+
+	return nil
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// canCompress reports whether the response headers allow compressing the body.
+func (cw *compressWriter) canCompress() bool {
+	header := cw.Header()
+
+	return header.Get("Content-Encoding") == "" &&
+		header.Get("Content-Range") == "" &&
+		isCompressible(header.Get("Content-Type"))
+}
+
+// decide sends the headers, compressed or not, followed by the buffered output.
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	header := cw.Header()
+
+	if isCompressible(header.Get("Content-Type")) {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	if compress {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+
+		// The compressed body is a different representation, so a strong entity tag no
+		// longer applies byte for byte.
+		etag := header.Get("ETag")
+		if etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+
+		if cw.encoding == "gzip" {
+			cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			cw.encoder = zlib.NewWriter(cw.ResponseWriter)
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if len(cw.buf) == 0 {
+		return
+	}
+
+	if cw.encoder != nil {
+		_, _ = cw.encoder.Write(cw.buf)
+	} else {
+		_, _ = cw.ResponseWriter.Write(cw.buf)
+	}
+
+	cw.buf = nil
+}
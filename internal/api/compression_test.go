@@ -0,0 +1,199 @@
+package api
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeMarkdown returns article content well above compressionMinSize.
+func largeMarkdown() string {
+	return "# Large\n\n" + strings.Repeat("Wiki pages are highly compressible text. ", 200)
+}
+
+// serveCompressed sends a GET request through the compression middleware and the router.
+func serveCompressed(server *Server, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	rr := httptest.NewRecorder()
+	server.compressionMiddleware(server.router).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestCompressionMiddleware_ArticleContent(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	page := publishArticle(t, db, "Large", largeMarkdown())
+
+	for _, format := range []string{"html", "md"} {
+		t.Run(format, func(t *testing.T) {
+			target := "/api/articles/" + page.Slug + "/content?format=" + format
+
+			plain := serveCompressed(server, target, nil)
+			require.Equal(t, http.StatusOK, plain.Code)
+			assert.Empty(t, plain.Header().Get("Content-Encoding"))
+
+			rr := serveCompressed(server, target, http.Header{"Accept-Encoding": {"gzip, deflate"}})
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+			assert.Contains(t, rr.Header().Values("Vary"), "Accept-Encoding")
+			assert.Less(t, rr.Body.Len(), plain.Body.Len())
+
+			reader, err := gzip.NewReader(rr.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, plain.Body.String(), string(body))
+		})
+	}
+}
+
+func TestCompressionMiddleware_Deflate(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	page := publishArticle(t, db, "Large", largeMarkdown())
+
+	rr := serveCompressed(
+		server,
+		"/api/articles/"+page.Slug+"/content?format=md",
+		http.Header{"Accept-Encoding": {"gzip;q=0.5, deflate"}},
+	)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "deflate", rr.Header().Get("Content-Encoding"))
+
+	reader, err := zlib.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Wiki pages are highly compressible text.")
+}
+
+func TestCompressionMiddleware_Skipped(t *testing.T) {
+	handler := func(contentType string, size int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write([]byte(strings.Repeat("a", size)))
+		})
+	}
+
+	server := &Server{}
+
+	for _, tt := range []struct {
+		name        string
+		handler     http.Handler
+		method      string
+		compressed  bool
+		wantVaryHdr bool
+	}{
+		{"large text", handler("text/plain", 4096), http.MethodGet, true, true},
+		{"small text", handler("text/plain", 100), http.MethodGet, false, true},
+		{"image", handler("image/png", 4096), http.MethodGet, false, false},
+		{"head request", handler("text/plain", 4096), http.MethodHead, false, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rr := httptest.NewRecorder()
+
+			server.compressionMiddleware(tt.handler).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.compressed, rr.Header().Get("Content-Encoding") == "gzip")
+			assert.Equal(t, tt.wantVaryHdr, rr.Header().Get("Vary") == "Accept-Encoding")
+
+			if !tt.compressed && tt.method == http.MethodGet {
+				assert.Equal(t, "a", rr.Body.String()[:1])
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware_Flush(t *testing.T) {
+	server := &Server{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(" second"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+
+	server.compressionMiddleware(handler).ServeHTTP(rr, req)
+
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"), "flushed responses are streamed compressed")
+
+	reader, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "first second", string(body))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for _, tt := range []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0.2, deflate;q=0.8", "deflate"},
+		{"gzip;q=0", ""},
+		{"br", ""},
+		{"*", "gzip"},
+		{"identity", ""},
+	} {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.header))
+		})
+	}
+}
+
+func TestArticleContent_ConditionalRequest(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	page := publishArticle(t, db, "Large", largeMarkdown())
+	target := "/api/articles/" + page.Slug + "/content?format=html"
+
+	rr := serveCompressed(server, target, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.False(t, strings.HasPrefix(etag, "W/"))
+
+	rr = serveCompressed(server, target, http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	compressed := serveCompressed(server, target, http.Header{"Accept-Encoding": {"gzip"}})
+	require.Equal(t, http.StatusOK, compressed.Code)
+	weak := compressed.Header().Get("ETag")
+	assert.Equal(t, "W/"+etag, weak, "compressed responses carry a weak tag")
+
+	rr = serveCompressed(server, target, http.Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {weak}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = serveCompressed(server, target, http.Header{"If-None-Match": {`"stale"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// jwksCheckTimeout bounds how long Validate waits for the JWKS endpoint to respond.
+const jwksCheckTimeout = 10 * time.Second
+
+// Validate checks the configuration before the server is created, so problems that would
+// otherwise surface at runtime, such as an unreachable JWKS endpoint or a port that is
+// already in use, are reported upfront. Every problem found is returned, joined into a
+// single error.
+func (c ServerConfig) Validate(ctx context.Context) error {
+	var errs []error
+
+	if c.Database == nil {
+		errs = append(errs, errors.New("no database configured"))
+	}
+
+	if c.JwtSecret == "" && c.JwksURL == "" {
+		errs = append(errs, errors.New(
+			"no authentication configured: set a JWT secret for local auth or a JWKS URL for an external IDP",
+		))
+	}
+
+	if c.JwksURL != "" {
+		err := checkJWKSURL(ctx, c.JwksURL)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.PluginPath != "" {
+		err := checkPath("plugin directory", c.PluginPath, true)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.JsPkgsPath != "" {
+		err := checkPath("JavaScript packages bundle", c.JsPkgsPath, false)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	_, err := newTOTPOptions(c.OTPPeriod, c.OTPDigits, c.OTPAlgorithm)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = newEndSessionURL(c.EndSessionEndpoint, c.PostLogoutRedirectURL)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = checkPort(c.Port)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkJWKSURL reports an error unless the JWKS endpoint answers a GET request successfully.
+func checkJWKSURL(ctx context.Context, jwksURL string) error {
+	u, err := url.Parse(jwksURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid JWKS URL %q: must be an absolute http or https URL", jwksURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jwksCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid JWKS URL %q: %w", jwksURL, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("JWKS URL %s is not reachable: %w", jwksURL, err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("JWKS URL %s responded with %s", jwksURL, resp.Status)
+	}
+
+	return nil
+}
+
+// checkPath reports an error unless path exists and is a directory when dir is true, or a
+// regular file otherwise.
+func checkPath(name, path string, dir bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%s %s cannot be used: %w", name, path, err)
+	}
+
+	if dir && !info.IsDir() {
+		return fmt.Errorf("%s %s is not a directory", name, path)
+	}
+
+	if !dir && !info.Mode().IsRegular() {
+		return fmt.Errorf("%s %s is not a file", name, path)
+	}
+
+	return nil
+}
+
+// checkPort reports an error unless the server can listen on port.
+func checkPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("port %d is not available: %w", port, err)
+	}
+
+	return listener.Close()
+}
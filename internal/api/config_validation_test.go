@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freePort returns a port that nothing is listening on.
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	return port
+}
+
+func TestServerConfigValidate(t *testing.T) {
+	database := newTestDB(t)
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys": []}`))
+	}))
+	t.Cleanup(jwks.Close)
+
+	failingJWKS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failingJWKS.Close)
+
+	closedJWKS := httptest.NewServer(http.NotFoundHandler())
+	closedJWKS.Close()
+
+	pluginDir := t.TempDir()
+	pluginFile := filepath.Join(t.TempDir(), "plugin.js")
+	require.NoError(t, os.WriteFile(pluginFile, []byte(""), 0o600))
+
+	busy, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = busy.Close() })
+
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name    string
+		modify  func(c *ServerConfig)
+		wantErr string
+	}{
+		{
+			name:   "valid local auth",
+			modify: func(c *ServerConfig) {},
+		},
+		{
+			name: "valid external IDP",
+			modify: func(c *ServerConfig) {
+				c.JwtSecret = ""
+				c.JwksURL = jwks.URL
+			},
+		},
+		{
+			name:   "valid plugin directory",
+			modify: func(c *ServerConfig) { c.PluginPath = pluginDir },
+		},
+		{
+			name:    "missing database",
+			modify:  func(c *ServerConfig) { c.Database = nil },
+			wantErr: "no database configured",
+		},
+		{
+			name:    "missing authentication",
+			modify:  func(c *ServerConfig) { c.JwtSecret = "" },
+			wantErr: "no authentication configured",
+		},
+		{
+			name:    "invalid JWKS URL",
+			modify:  func(c *ServerConfig) { c.JwksURL = "jwks.json" },
+			wantErr: "invalid JWKS URL",
+		},
+		{
+			name:    "unreachable JWKS URL",
+			modify:  func(c *ServerConfig) { c.JwksURL = closedJWKS.URL },
+			wantErr: "is not reachable",
+		},
+		{
+			name:    "failing JWKS URL",
+			modify:  func(c *ServerConfig) { c.JwksURL = failingJWKS.URL },
+			wantErr: "responded with 503 Service Unavailable",
+		},
+		{
+			name:    "missing plugin directory",
+			modify:  func(c *ServerConfig) { c.PluginPath = filepath.Join(pluginDir, "missing") },
+			wantErr: "plugin directory",
+		},
+		{
+			name:    "plugin path is a file",
+			modify:  func(c *ServerConfig) { c.PluginPath = pluginFile },
+			wantErr: "is not a directory",
+		},
+		{
+			name:   "valid JavaScript packages bundle",
+			modify: func(c *ServerConfig) { c.JsPkgsPath = pluginFile },
+		},
+		{
+			name:    "missing JavaScript packages bundle",
+			modify:  func(c *ServerConfig) { c.JsPkgsPath = filepath.Join(pluginDir, "missing.js") },
+			wantErr: "JavaScript packages bundle",
+		},
+		{
+			name:    "JavaScript packages bundle is a directory",
+			modify:  func(c *ServerConfig) { c.JsPkgsPath = pluginDir },
+			wantErr: "is not a file",
+		},
+		{
+			name:    "invalid OTP settings",
+			modify:  func(c *ServerConfig) { c.OTPDigits = 7 },
+			wantErr: "invalid OTP digits 7",
+		},
+		{
+			name:    "invalid end session endpoint",
+			modify:  func(c *ServerConfig) { c.EndSessionEndpoint = "/logout" },
+			wantErr: "invalid end session endpoint",
+		},
+		{
+			name:    "invalid port",
+			modify:  func(c *ServerConfig) { c.Port = 70000 },
+			wantErr: "invalid port 70000",
+		},
+		{
+			name:    "port in use",
+			modify:  func(c *ServerConfig) { c.Port = busyPort },
+			wantErr: "is not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := ServerConfig{
+				Database:  database,
+				JwtSecret: "test-secret",
+				WikiName:  "Test Wiki",
+				Port:      freePort(t),
+			}
+			tt.modify(&config)
+
+			err := config.Validate(context.Background())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestServerConfigValidate_ReportsEveryProblem(t *testing.T) {
+	config := ServerConfig{
+		PluginPath: filepath.Join(t.TempDir(), "missing"),
+		Port:       0,
+	}
+
+	err := config.Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no database configured")
+	assert.Contains(t, err.Error(), "no authentication configured")
+	assert.Contains(t, err.Error(), "plugin directory")
+	assert.Contains(t, err.Error(), "invalid port 0")
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SetCustomCodeInput represents the input for setting the custom code of an article.
+type SetCustomCodeInput struct {
+	Body struct {
+		CSS string `doc:"CSS added to the article page in a <style> element; empty removes it"         json:"css"`
+		JS  string `doc:"JavaScript added to the article page in a <script> element; empty removes it" json:"js"`
+	}
+	Slug string `doc:"The URL slug of the article" path:"slug"`
+}
+
+// CustomCodeOutput represents the custom code of an article.
+type CustomCodeOutput struct {
+	Body struct {
+		CSS string `json:"css"`
+		JS  string `json:"js"`
+	}
+}
+
+// registerCustomCodeRoutes registers the routes for per-article custom CSS and JavaScript.
+func (s *Server) registerCustomCodeRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "set-article-custom-code",
+		Method:      http.MethodPut,
+		Path:        "/api/articles/{slug}/custom-code",
+		Summary:     "Set Article Custom Code",
+		Description: "Replace the CSS and JavaScript added to the page of a single article. " +
+			"Admin only. The code runs for every reader of the article.",
+		Tags:     []string{"Articles"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleSetCustomCode)
+}
+
+// handleSetCustomCode handles the request to replace the custom code of an article.
+func (s *Server) handleSetCustomCode(
+	ctx context.Context,
+	input *SetCustomCodeInput,
+) (*CustomCodeOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can set custom article code")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Body.CSS)+len(input.Body.JS) > s.maxContentSize {
+		return nil, errContentTooLarge(s.maxContentSize)
+	}
+
+	err = validateCustomCode(input.Body.CSS, input.Body.JS)
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	err = s.db.SetArticleCustomCode(ctx, article.Id, input.Body.CSS, input.Body.JS)
+	if err != nil {
+		return nil, dbError(err, "Article", "Failed to set custom code")
+	}
+
+	_ = s.db.CreateLogEntry(
+		ctx,
+		models.LevelWarning,
+		"API",
+		"Article custom code updated",
+		fmt.Sprintf("Admin: %s | Article: %s | CSS: %d bytes | JS: %d bytes",
+			admin.Email, article.Slug, len(input.Body.CSS), len(input.Body.JS)),
+	)
+
+	resp := &CustomCodeOutput{}
+	resp.Body.CSS = input.Body.CSS
+	resp.Body.JS = input.Body.JS
+
+	return resp, nil
+}
+
+// validateCustomCode rejects code that could end the element it is written into, since the
+// page template inserts it verbatim.
+func validateCustomCode(css, js string) error {
+	if strings.Contains(strings.ToLower(css), "</style") {
+		return huma.Error400BadRequest("Custom CSS must not contain </style>")
+	}
+
+	lowerJS := strings.ToLower(js)
+	for _, token := range []string{"</script", "<script", "<!--"} {
+		if strings.Contains(lowerJS, token) {
+			return huma.Error400BadRequest("Custom JavaScript must not contain " + token)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikilite/internal/db"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCustomCodeServer creates a test server with custom article code enabled.
+func newCustomCodeServer(t *testing.T, database *db.DB) *Server {
+	t.Helper()
+
+	server, err := NewServer(ServerConfig{
+		Database:          database,
+		JwtSecret:         "test-secret",
+		WikiName:          "Test Wiki",
+		CustomArticleCode: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	return server
+}
+
+func TestHandleSetCustomCode(t *testing.T) {
+	db := newTestDB(t)
+	server := newCustomCodeServer(t, db)
+	page := publishArticle(t, db, "Demo", "# Demo")
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	input := &SetCustomCodeInput{Slug: page.Slug}
+	input.Body.CSS = ".demo { color: red; }"
+	input.Body.JS = "console.log('demo');"
+
+	resp, err := server.handleSetCustomCode(contextWithUser(admin), input)
+	require.NoError(t, err)
+	assert.Equal(t, input.Body.CSS, resp.Body.CSS)
+
+	article, err := server.handleGetArticleJSON(context.Background(), &ArticleSlugInput{Slug: page.Slug})
+	require.NoError(t, err)
+	assert.Equal(t, input.Body.CSS, article.Body.CustomCSS)
+	assert.Equal(t, input.Body.JS, article.Body.CustomJS)
+
+	input.Slug = "missing"
+	_, err = server.handleSetCustomCode(contextWithUser(admin), input)
+	var statusErr huma.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.GetStatus())
+}
+
+func TestHandleSetCustomCode_NonAdmin(t *testing.T) {
+	db := newTestDB(t)
+	server := newCustomCodeServer(t, db)
+	page := publishArticle(t, db, "Demo", "# Demo")
+
+	for _, user := range []*models.User{
+		nil,
+		{Email: "reader@example.com", Role: models.READ},
+		{Email: "writer@example.com", Role: models.WRITE},
+	} {
+		input := &SetCustomCodeInput{Slug: page.Slug}
+		input.Body.JS = "alert(1)"
+
+		ctx := context.Background()
+		if user != nil {
+			ctx = contextWithUser(user)
+		}
+
+		_, err := server.handleSetCustomCode(ctx, input)
+		var statusErr huma.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusForbidden, statusErr.GetStatus())
+	}
+
+	article, err := db.GetArticleBySlug(context.Background(), page.Slug)
+	require.NoError(t, err)
+	assert.Empty(t, article.CustomJS)
+}
+
+func TestHandleSetCustomCode_RejectsClosingTags(t *testing.T) {
+	db := newTestDB(t)
+	server := newCustomCodeServer(t, db)
+	page := publishArticle(t, db, "Demo", "# Demo")
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+
+	for _, code := range []struct{ css, js string }{
+		{"p {} </STYLE><script>alert(1)</script>", ""},
+		{"", "x = 1;</script><script>alert(1)"},
+		{"", "<!-- <script>"},
+	} {
+		input := &SetCustomCodeInput{Slug: page.Slug}
+		input.Body.CSS = code.css
+		input.Body.JS = code.js
+
+		_, err := server.handleSetCustomCode(contextWithUser(admin), input)
+		var statusErr huma.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadRequest, statusErr.GetStatus())
+	}
+}
+
+func TestCustomCode_Disabled(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	page := publishArticle(t, db, "Demo", "# Demo")
+
+	assert.NotContains(t, server.api.OpenAPI().Paths, "/api/articles/{slug}/custom-code")
+
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/api/articles/"+page.Slug+"/custom-code",
+		bytes.NewBufferString(`{"css": "", "js": "alert(1)"}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(&models.User{Email: "admin@test.com", Role: models.ADMIN})))
+	assert.NotEqual(t, http.StatusOK, rr.Code)
+
+	// Code stored while the feature was enabled is not served once it is disabled.
+	article, err := db.GetArticleBySlug(context.Background(), page.Slug)
+	require.NoError(t, err)
+	require.NoError(t, db.SetArticleCustomCode(context.Background(), article.Id, "", "alert(1)"))
+
+	resp, err := server.handleGetArticleJSON(context.Background(), &ArticleSlugInput{Slug: page.Slug})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Body.CustomJS)
+}
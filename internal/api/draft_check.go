@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// DraftCheckBody carries unsaved editor content to check instead of the saved draft.
+type DraftCheckBody struct {
+	Content string `doc:"The full markdown content to check" json:"content" required:"true"`
+}
+
+// DraftCheckInput represents the input for a pre-publish check of a draft.
+type DraftCheckInput struct {
+	// Body is optional; without it the saved draft is checked.
+	Body *DraftCheckBody
+	ID   int `doc:"The ID of the draft" path:"id"`
+}
+
+// DraftPluginError is an error thrown by a plugin while processing a draft.
+type DraftPluginError struct {
+	PluginID string `json:"pluginId"`
+	Hook     string `json:"hook"`
+	Error    string `json:"error"`
+}
+
+// DraftCheckOutput represents the report of a pre-publish check of a draft.
+type DraftCheckOutput struct {
+	Body struct {
+		BrokenLinks    []string           `doc:"Internal link targets that do not match an existing article"               json:"brokenLinks"`
+		RenderWarnings []string           `doc:"Problems rendering the content, such as includes that cannot be resolved"  json:"renderWarnings"`
+		PluginErrors   []DraftPluginError `doc:"Errors thrown by plugins while rendering the content"                      json:"pluginErrors"`
+		SizeBytes      int                `doc:"The size of the content in bytes"                                          json:"sizeBytes"`
+		MaxSizeBytes   int                `doc:"The maximum size of article content in bytes"                              json:"maxSizeBytes"`
+		OK             bool               `doc:"True when no problems were found and the content is within the size limit" json:"ok"`
+	}
+}
+
+// handleCheckDraft handles the request to check a draft for problems before publishing.
+// Nothing is saved, published or logged.
+func (s *Server) handleCheckDraft(
+	ctx context.Context,
+	input *DraftCheckInput,
+) (*DraftCheckOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
+		return nil, huma.Error403Forbidden("You can only check your own drafts")
+	}
+
+	if input.Body != nil {
+		content = input.Body.Content
+	}
+
+	resp := &DraftCheckOutput{}
+	resp.Body.SizeBytes = len(content)
+	resp.Body.MaxSizeBytes = s.maxContentSize
+
+	resp.Body.BrokenLinks, err = s.db.FindBrokenLinks(ctx, content)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to validate links", err)
+	}
+
+	resolved, warnings, err := s.resolveIncludesWithWarnings(ctx, draft.Article.Slug, content)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	resp.Body.RenderWarnings = warnings
+
+	var buf bytes.Buffer
+
+	err = s.renderer.RenderHTML(ctx, &buf, resolved)
+	if err != nil {
+		resp.Body.RenderWarnings = append(resp.Body.RenderWarnings, "Failed to render markdown: "+err.Error())
+	} else if s.hasActivePlugins() {
+		pluginCtx := map[string]any{
+			"User": user,
+			"Slug": draft.Article.Slug,
+			"Tags": draft.Article.Tags,
+		}
+
+		resp.Body.PluginErrors, err = s.pluginPipelineErrors("onArticleRender", buf.String(), pluginCtx)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to execute plugins", err)
+		}
+	}
+
+	if resp.Body.BrokenLinks == nil {
+		resp.Body.BrokenLinks = []string{}
+	}
+
+	if resp.Body.RenderWarnings == nil {
+		resp.Body.RenderWarnings = []string{}
+	}
+
+	if resp.Body.PluginErrors == nil {
+		resp.Body.PluginErrors = []DraftPluginError{}
+	}
+
+	resp.Body.OK = len(resp.Body.BrokenLinks) == 0 &&
+		len(resp.Body.RenderWarnings) == 0 &&
+		len(resp.Body.PluginErrors) == 0 &&
+		resp.Body.SizeBytes <= resp.Body.MaxSizeBytes
+
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCheckDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	_, draft, err := db.CreateArticleWithDraft(context.Background(), "Checked Page", user.Email)
+	require.NoError(t, err)
+
+	content := "See [Home](/wiki/home).\n\n{{include:home}}"
+	require.NoError(t, db.UpdateDraft(context.Background(), draft.Id, content, user.Email, false, nil))
+
+	resp, err := server.handleCheckDraft(ctx, &DraftCheckInput{ID: draft.Id})
+	require.NoError(t, err)
+	assert.True(t, resp.Body.OK)
+	assert.Empty(t, resp.Body.BrokenLinks)
+	assert.Empty(t, resp.Body.RenderWarnings)
+	assert.Empty(t, resp.Body.PluginErrors)
+	assert.Equal(t, len(content), resp.Body.SizeBytes)
+	assert.Equal(t, DefaultMaxContentSize, resp.Body.MaxSizeBytes)
+
+	unsaved := "See [Nowhere](/wiki/nowhere).\n\n{{include:missing}}"
+	resp, err = server.handleCheckDraft(ctx, &DraftCheckInput{ID: draft.Id, Body: &DraftCheckBody{Content: unsaved}})
+	require.NoError(t, err)
+	assert.False(t, resp.Body.OK)
+	assert.ElementsMatch(t, []string{"missing", "nowhere"}, resp.Body.BrokenLinks)
+	assert.Equal(t, []string{"Included article not found: missing"}, resp.Body.RenderWarnings)
+
+	_, saved, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, content, saved, "checking unsaved content does not save it")
+}
+
+func TestHandleCheckDraft_Oversized(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.maxContentSize = 10
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	_, draft, err := db.CreateArticleWithDraft(context.Background(), "Long Page", user.Email)
+	require.NoError(t, err)
+
+	resp, err := server.handleCheckDraft(
+		contextWithUser(user),
+		&DraftCheckInput{ID: draft.Id, Body: &DraftCheckBody{Content: "Far more than ten bytes."}},
+	)
+	require.NoError(t, err, "oversized content is reported rather than rejected")
+	assert.False(t, resp.Body.OK)
+	assert.Equal(t, 24, resp.Body.SizeBytes)
+	assert.Equal(t, 10, resp.Body.MaxSizeBytes)
+}
+
+func TestHandleCheckDraft_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	_, draft, err := db.CreateArticleWithDraft(context.Background(), "Private Page", "writer@example.com")
+	require.NoError(t, err)
+
+	other := &models.User{Email: "other@example.com", Role: models.WRITE}
+	_, err = server.handleCheckDraft(contextWithUser(other), &DraftCheckInput{ID: draft.Id})
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	_, err = server.handleCheckDraft(contextWithUser(other), &DraftCheckInput{ID: 9999})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
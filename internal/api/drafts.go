@@ -1,14 +1,21 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"html"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 	"wikilite/internal/db"
 	"wikilite/pkg/models"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
 // DraftIDInput represents the input for getting a draft by ID.
@@ -24,9 +31,11 @@ type ArticleSlugForDraftInput struct {
 // UpdateDraftInput represents the input for updating a draft.
 type UpdateDraftInput struct {
 	Body struct {
-		Content string `doc:"The full markdown content of the draft" json:"content" required:"true"`
+		UpdatedAt *time.Time `doc:"The updatedAt of the draft being edited; rejected with 409 if stale" json:"updatedAt,omitempty" required:"false"`
+		Content   string     `doc:"The full markdown content of the draft"                              json:"content"             required:"true"`
 	}
-	ID int `doc:"The ID of the draft" path:"id"`
+	IfMatch string `doc:"The ETag of the draft being edited; rejected with 412 if stale" header:"If-Match"`
+	ID      int    `doc:"The ID of the draft"                                          path:"id"`
 }
 
 // PublicDraft represents a draft with the reconstructed content (not patch).
@@ -38,15 +47,29 @@ type PublicDraft struct {
 	Id             int       `json:"id"`
 	ArticleId      int       `json:"articleId"`
 	ArticleVersion int       `json:"articleVersion"`
+
+	ReconstructionDegraded bool `json:"reconstructionDegraded"`
 }
 
 // DraftOutput represents the output for a single draft.
 type DraftOutput struct {
+	ETag string `header:"ETag"`
 	Body struct {
 		Draft *PublicDraft `json:"draft"`
 	}
 }
 
+// DraftSaveOutput represents the output of saving a draft.
+type DraftSaveOutput struct {
+	ETag string `header:"ETag"`
+	Body struct {
+		UpdatedAt   *time.Time `doc:"The new updatedAt of the draft, to send with the next save"           json:"updatedAt,omitempty"`
+		ArticleSlug string     `doc:"The slug of the draft's article"                                      json:"articleSlug"`
+		Content     string     `doc:"The content of the draft as the server reconstructs it"               json:"content"`
+		DraftExists bool       `doc:"False when the content matched the article, so the draft was removed" json:"draftExists"`
+	}
+}
+
 // DraftListOutput represents the output for a list of drafts.
 type DraftListOutput struct {
 	Body struct {
@@ -54,6 +77,143 @@ type DraftListOutput struct {
 	}
 }
 
+// DraftDiffInput represents the input for diffing a draft against an article version.
+type DraftDiffInput struct {
+	Against string `doc:"What to compare against: 'current' (the default) or 'version:N'" query:"against"`
+	ID      int    `doc:"The ID of the draft"                                            path:"id"`
+}
+
+// DiffChunk is a run of text that is unchanged, added or removed by a draft.
+type DiffChunk struct {
+	Op   string `enum:"equal,insert,delete" json:"op"`
+	Text string `                           json:"text"`
+}
+
+// DraftDiffOutput represents the changes a draft makes relative to an article version.
+type DraftDiffOutput struct {
+	Body struct {
+		Chunks         []DiffChunk `json:"chunks"`
+		DraftId        int         `json:"draftId"`
+		AgainstVersion int         `json:"againstVersion"`
+
+		ReconstructionDegraded bool `json:"reconstructionDegraded"`
+	}
+}
+
+// DraftPreviewBody carries unsaved editor content to preview instead of the saved draft.
+type DraftPreviewBody struct {
+	Content string `doc:"The full markdown content to preview" json:"content" required:"true"`
+}
+
+// DraftPreviewInput represents the input for previewing a draft.
+type DraftPreviewInput struct {
+	// Body is optional; without it the saved draft is previewed.
+	Body *DraftPreviewBody
+	Diff bool `doc:"Also compare the content with the current article" query:"diff"`
+	ID   int  `doc:"The ID of the draft"                               path:"id"`
+}
+
+// DraftPreviewDiff summarizes the changes a draft makes to the current article.
+type DraftPreviewDiff struct {
+	HTML    string `doc:"The markdown with insertions in <ins> and deletions in <del>, HTML-escaped" json:"html"`
+	Added   int    `doc:"Number of characters added"                                                 json:"added"`
+	Removed int    `doc:"Number of characters removed"                                               json:"removed"`
+}
+
+// DraftPreviewOutput represents a rendered draft, with its changes when requested.
+type DraftPreviewOutput struct {
+	Body struct {
+		Diff           *DraftPreviewDiff `json:"diff,omitempty"`
+		HTML           string            `json:"html"`
+		DraftId        int               `json:"draftId"`
+		AgainstVersion int               `json:"againstVersion"`
+
+		ReconstructionDegraded bool `json:"reconstructionDegraded"`
+	}
+}
+
+// RecoverDraftInput represents the input for recovering a draft from a client-side copy.
+type RecoverDraftInput struct {
+	Body struct {
+		UpdatedAt *time.Time `doc:"The updatedAt of the draft when the client copy was last saved, if known" json:"updatedAt,omitempty" required:"false"`
+		Content   string     `doc:"The full markdown content cached by the client"                          json:"content"             required:"true"`
+	}
+	ID int `doc:"The ID of the draft" path:"id"`
+}
+
+// DraftRecoverOutput represents the output of recovering a draft from a client-side copy.
+type DraftRecoverOutput struct {
+	ETag string `header:"ETag"`
+	Body struct {
+		UpdatedAt   *time.Time  `doc:"The updatedAt of the draft, to send with the next save"                        json:"updatedAt,omitempty"`
+		Chunks      []DiffChunk `doc:"How the client copy differs from the draft it replaced"                        json:"chunks"`
+		Content     string      `doc:"The content of the draft as the server reconstructs it"                        json:"content"`
+		Recovered   bool        `doc:"False when the client copy matched the server draft, so nothing was saved"     json:"recovered"`
+		ServerNewer bool        `doc:"True when the replaced draft had been saved after the client copy's updatedAt" json:"serverNewer"`
+		DraftExists bool        `doc:"False when the content matched the article, so the draft was removed"          json:"draftExists"`
+	}
+}
+
+// DraftValidationOutput represents the non-blocking warnings found when validating a draft.
+type DraftValidationOutput struct {
+	Body struct {
+		BrokenLinks []string `doc:"Internal link targets that do not match an existing article" json:"brokenLinks"`
+	}
+}
+
+// errContentTooLarge builds the 413 response returned when content exceeds the configured limit.
+func errContentTooLarge(limit int) huma.StatusError {
+	return huma.NewError(
+		http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("Content exceeds the maximum size of %d bytes", limit),
+	)
+}
+
+// errDraftPreconditionFailed builds the 412 response returned when If-Match no longer matches the draft.
+func errDraftPreconditionFailed() huma.StatusError {
+	return huma.Error412PreconditionFailed("The draft was updated elsewhere since it was loaded; reload the editor")
+}
+
+// checkDraftLimit returns a 409 error when the user already holds the maximum number of drafts.
+// A draft for articleID that would replace the user's existing draft for it is always allowed.
+// Pass zero for a new article. Admins are exempt.
+func (s *Server) checkDraftLimit(ctx context.Context, user *models.User, articleID int) error {
+	if s.maxDraftsPerUser <= 0 || user.Role == models.ADMIN {
+		return nil
+	}
+
+	if articleID != 0 {
+		existing, err := s.db.GetDraftsByArticle(ctx, articleID, user.Email)
+		if err != nil {
+			return huma.Error500InternalServerError("Database error", err)
+		}
+
+		if len(existing) > 0 {
+			return nil
+		}
+	}
+
+	count, err := s.db.CountDraftsByUser(ctx, user.Email)
+	if err != nil {
+		return huma.Error500InternalServerError("Database error", err)
+	}
+
+	if count >= s.maxDraftsPerUser {
+		return huma.Error409Conflict(fmt.Sprintf(
+			"You already have %d open drafts. Publish or discard one before starting another.",
+			count,
+		))
+	}
+
+	return nil
+}
+
+// maxBodyBytes returns the request body limit for endpoints that accept article content.
+// JSON encoding can expand content, so the limit leaves headroom above maxContentSize.
+func (s *Server) maxBodyBytes() int64 {
+	return int64(s.maxContentSize)*2 + 4096
+}
+
 // registerDraftRoutes registers the draft routes with the API.
 func (s *Server) registerDraftRoutes() {
 	huma.Register(s.api, huma.Operation{
@@ -95,12 +255,14 @@ func (s *Server) registerDraftRoutes() {
 	}, s.handleGetDraft)
 
 	huma.Register(s.api, huma.Operation{
-		OperationID: "update-draft",
-		Method:      http.MethodPut,
-		Path:        "/api/drafts/{id}",
-		Summary:     "Update Draft",
-		Tags:        []string{"Drafts"},
-		Security:    []map[string][]string{{"bearer": {}}},
+		OperationID:  "update-draft",
+		Method:       http.MethodPut,
+		Path:         "/api/drafts/{id}",
+		Summary:      "Update Draft",
+		Description:  "Save a draft and return it as stored. Send the draft ETag in If-Match to reject stale saves with 412.",
+		Tags:         []string{"Drafts"},
+		Security:     []map[string][]string{{"bearer": {}}},
+		MaxBodyBytes: s.maxBodyBytes(),
 	}, s.handleUpdateDraft)
 
 	huma.Register(s.api, huma.Operation{
@@ -112,6 +274,74 @@ func (s *Server) registerDraftRoutes() {
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handlePublishDraft)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "validate-draft",
+		Method:      http.MethodPost,
+		Path:        "/api/drafts/{id}/validate",
+		Summary:     "Validate Draft",
+		Description: "Check a draft for internal links to missing articles. Does not block publishing.",
+		Tags:        []string{"Drafts"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleValidateDraft)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "check-draft",
+		Method:      http.MethodPost,
+		Path:        "/api/drafts/{id}/check",
+		Summary:     "Check Draft",
+		Description: "Dry-run a publish of a draft, or of unsaved editor content for it, and report " +
+			"broken internal links, render warnings, plugin errors and whether the content fits " +
+			"the size limit. Nothing is saved.",
+		Tags:     []string{"Drafts"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleCheckDraft)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "diff-draft",
+		Method:      http.MethodGet,
+		Path:        "/api/drafts/{id}/diff",
+		Summary:     "Diff Draft",
+		Description: "Compare a draft with the current article, or with a historical version " +
+			"using against=version:N.",
+		Tags:     []string{"Drafts"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleDiffDraft)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "preview-draft",
+		Method:      http.MethodPost,
+		Path:        "/api/drafts/{id}/preview",
+		Summary:     "Preview Draft",
+		Description: "Render a draft, or unsaved editor content for it, to HTML. With diff=true " +
+			"the response also counts the characters added and removed relative to the current " +
+			"article and marks the changes up as HTML.",
+		Tags:     []string{"Drafts"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handlePreviewDraft)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "recover-draft",
+		Method:      http.MethodPost,
+		Path:        "/api/drafts/{id}/recover",
+		Summary:     "Recover Draft",
+		Description: "Save content the browser still holds, for example after a failed autosave, " +
+			"over the server draft without conflict checks. The response shows how the client " +
+			"copy differs from the replaced draft and whether that draft was newer.",
+		Tags:         []string{"Drafts"},
+		Security:     []map[string][]string{{"bearer": {}}},
+		MaxBodyBytes: s.maxBodyBytes(),
+	}, s.handleRecoverDraft)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "takeover-draft",
+		Method:      http.MethodPost,
+		Path:        "/api/drafts/{id}/takeover",
+		Summary:     "Take Over Draft",
+		Description: "Reassign ownership of another user's draft to the current admin.",
+		Tags:        []string{"Drafts"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleTakeOverDraft)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "discard-draft",
 		Method:      http.MethodDelete,
@@ -132,6 +362,11 @@ func (s *Server) handleCreateDraft(
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	if user.Role < models.WRITE {
 		return nil, huma.Error403Forbidden("You do not have permission to edit articles")
 	}
@@ -145,12 +380,17 @@ func (s *Server) handleCreateDraft(
 		return nil, huma.Error404NotFound("Article not found")
 	}
 
+	err = s.checkDraftLimit(ctx, user, article.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	draft, err := s.db.CreateDraft(ctx, article.Id, article.Data, user.Email)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Failed to create draft", err)
 	}
 
-	resp := &DraftOutput{}
+	resp := &DraftOutput{ETag: entityETag(draft.Id, draft.UpdatedAt)}
 	resp.Body.Draft = &PublicDraft{
 		Id:             draft.Id,
 		ArticleId:      article.Id,
@@ -204,18 +444,14 @@ func (s *Server) handleGetDraft(ctx context.Context, input *DraftIDInput) (*Draf
 
 	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Database error", err)
-	}
-
-	if draft == nil {
-		return nil, huma.Error404NotFound("Draft not found")
+		return nil, dbError(err, "Draft", "Database error")
 	}
 
 	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
 		return nil, huma.Error403Forbidden("You can only view your own drafts")
 	}
 
-	resp := &DraftOutput{}
+	resp := &DraftOutput{ETag: entityETag(draft.Id, draft.UpdatedAt)}
 	resp.Body.Draft = &PublicDraft{
 		Id:             draft.Id,
 		ArticleId:      draft.ArticleId,
@@ -224,6 +460,8 @@ func (s *Server) handleGetDraft(ctx context.Context, input *DraftIDInput) (*Draf
 		ArticleVersion: draft.ArticleVersion,
 		Content:        content,
 		UpdatedAt:      draft.UpdatedAt,
+
+		ReconstructionDegraded: draft.ReconstructionDegraded,
 	}
 
 	return resp, nil
@@ -281,21 +519,81 @@ func (s *Server) handleGetArticleDrafts(
 func (s *Server) handleUpdateDraft(
 	ctx context.Context,
 	input *UpdateDraftInput,
-) (*struct{ Status int }, error) {
+) (*DraftSaveOutput, error) {
 	user := getUserFromContext(ctx)
 	if user == nil {
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
-	err := s.db.UpdateDraft(ctx, input.ID, input.Body.Content, user.Email)
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Body.Content) > s.maxContentSize {
+		return nil, errContentTooLarge(s.maxContentSize)
+	}
+
+	err = s.checkWriteLimit(user)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin := user.Role == models.ADMIN
+
+	draft, _, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	expectedUpdatedAt := input.Body.UpdatedAt
+	if input.IfMatch != "" {
+		if !etagMatches(input.IfMatch, entityETag(draft.Id, draft.UpdatedAt)) {
+			return nil, errDraftPreconditionFailed()
+		}
+
+		// Pin the write to the checked version in case another save lands in between.
+		expectedUpdatedAt = &draft.UpdatedAt
+	}
+
+	err = s.db.UpdateDraft(ctx, input.ID, input.Body.Content, user.Email, isAdmin, expectedUpdatedAt)
 	if err != nil {
 		if errors.Is(err, db.ErrCannotEditDraft) {
 			return nil, huma.Error403Forbidden("You can only edit your own drafts")
 		}
+		if errors.Is(err, db.ErrDraftConflict) && input.IfMatch != "" {
+			return nil, errDraftPreconditionFailed()
+		}
+		if errors.Is(err, db.ErrDraftConflict) {
+			return nil, huma.Error409Conflict("The draft was updated elsewhere since it was loaded; reload the editor")
+		}
+		if errors.Is(err, db.ErrContentTooLarge) {
+			return nil, errContentTooLarge(s.maxContentSize)
+		}
 		return nil, huma.Error500InternalServerError("Failed to update draft", err)
 	}
 
-	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+	resp := &DraftSaveOutput{}
+	resp.Body.ArticleSlug = draft.Article.Slug
+
+	saved, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFound) {
+			return nil, huma.Error500InternalServerError("Database error", err)
+		}
+
+		// An empty diff removes the draft, so the article content is what is stored.
+		resp.Body.Content = draft.Article.Data
+
+		return resp, nil
+	}
+
+	resp.ETag = entityETag(saved.Id, saved.UpdatedAt)
+	resp.Body.UpdatedAt = &saved.UpdatedAt
+	resp.Body.Content = content
+	resp.Body.DraftExists = true
+
+	return resp, nil
 }
 
 // handlePublishDraft handles the request to publish a draft.
@@ -308,13 +606,23 @@ func (s *Server) handlePublishDraft(
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	if user.Role < models.WRITE {
 		return nil, huma.Error403Forbidden("You do not have permission to publish")
 	}
 
+	err = s.checkWriteLimit(user)
+	if err != nil {
+		return nil, err
+	}
+
 	draft, _, err := s.db.GetDraftByID(ctx, input.ID)
 	if err != nil {
-		return nil, huma.Error500InternalServerError("Database error", err)
+		return nil, dbError(err, "Draft", "Database error")
 	}
 
 	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
@@ -323,12 +631,321 @@ func (s *Server) handlePublishDraft(
 
 	err = s.db.PublishDraft(ctx, input.ID)
 	if err != nil {
+		if errors.Is(err, db.ErrInvalidFrontMatter) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, huma.Error500InternalServerError("Failed to publish draft", err)
 	}
 
+	s.invalidateArticleReports()
+
+	s.emitWebhook(models.EventArticlePublished, map[string]any{
+		"id":          draft.ArticleId,
+		"slug":        draft.Article.Slug,
+		"title":       draft.Article.Title,
+		"version":     draft.Article.Version + 1,
+		"publishedBy": user.Email,
+	})
+
+	s.notifyWatchers(ctx, draft.Article, draft.Article.Version+1, user, draft.CreatedBy)
+
 	return &struct{ Status int }{Status: http.StatusNoContent}, nil
 }
 
+// handleValidateDraft handles the request to check a draft for broken internal links.
+func (s *Server) handleValidateDraft(
+	ctx context.Context,
+	input *DraftIDInput,
+) (*DraftValidationOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
+		return nil, huma.Error403Forbidden("You can only validate your own drafts")
+	}
+
+	brokenLinks, err := s.db.FindBrokenLinks(ctx, content)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to validate links", err)
+	}
+
+	resp := &DraftValidationOutput{}
+	resp.Body.BrokenLinks = brokenLinks
+
+	return resp, nil
+}
+
+// parseDiffTarget parses the against parameter of a draft diff. It returns the requested
+// version, or -1 for the current article.
+func parseDiffTarget(against string) (int, error) {
+	if against == "" || against == "current" {
+		return -1, nil
+	}
+
+	versionStr, found := strings.CutPrefix(against, "version:")
+	if !found {
+		return 0, huma.Error400BadRequest("against must be 'current' or 'version:N'")
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version < 0 {
+		return 0, huma.Error400BadRequest("Invalid version: " + versionStr)
+	}
+
+	return version, nil
+}
+
+// handleDiffDraft handles the request to diff a draft against an article version.
+func (s *Server) handleDiffDraft(
+	ctx context.Context,
+	input *DraftDiffInput,
+) (*DraftDiffOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	version, err := parseDiffTarget(input.Against)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
+		return nil, huma.Error403Forbidden("You can only diff your own drafts")
+	}
+
+	base := draft.Article.Data
+	degraded := draft.ReconstructionDegraded
+
+	if version < 0 {
+		version = draft.Article.Version
+	} else {
+		var versionDegraded bool
+
+		base, versionDegraded, err = s.db.GetArticleVersion(ctx, draft.ArticleId, version)
+		if err != nil {
+			return nil, dbError(err, "Article version", "Failed to reconstruct version")
+		}
+
+		degraded = degraded || versionDegraded
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffMain(base, content, false))
+
+	resp := &DraftDiffOutput{}
+	resp.Body.DraftId = draft.Id
+	resp.Body.AgainstVersion = version
+	resp.Body.ReconstructionDegraded = degraded
+	resp.Body.Chunks = diffChunks(diffs)
+
+	return resp, nil
+}
+
+// diffChunks converts a diff to its API representation.
+func diffChunks(diffs []diffmatchpatch.Diff) []DiffChunk {
+	chunks := make([]DiffChunk, len(diffs))
+
+	for i, diff := range diffs {
+		op := "equal"
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			op = "insert"
+		case diffmatchpatch.DiffDelete:
+			op = "delete"
+		}
+
+		chunks[i] = DiffChunk{Op: op, Text: diff.Text}
+	}
+
+	return chunks
+}
+
+// handlePreviewDraft handles the request to render a draft, optionally with its changes.
+func (s *Server) handlePreviewDraft(
+	ctx context.Context,
+	input *DraftPreviewInput,
+) (*DraftPreviewOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
+		return nil, huma.Error403Forbidden("You can only preview your own drafts")
+	}
+
+	if input.Body != nil {
+		if len(input.Body.Content) > s.maxContentSize {
+			return nil, errContentTooLarge(s.maxContentSize)
+		}
+
+		content = input.Body.Content
+	}
+
+	resolved, err := s.resolveIncludes(ctx, draft.Article.Slug, content)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = s.renderer.RenderHTML(ctx, &buf, resolved)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to render draft", err)
+	}
+
+	resp := &DraftPreviewOutput{}
+	resp.Body.HTML = buf.String()
+	resp.Body.DraftId = draft.Id
+	resp.Body.AgainstVersion = draft.Article.Version
+	resp.Body.ReconstructionDegraded = draft.ReconstructionDegraded
+
+	if input.Diff {
+		dmp := diffmatchpatch.New()
+		resp.Body.Diff = previewDiff(dmp.DiffCleanupSemantic(dmp.DiffMain(draft.Article.Data, content, false)))
+	}
+
+	return resp, nil
+}
+
+// previewDiff counts the changed characters of a diff and marks them up as HTML.
+func previewDiff(diffs []diffmatchpatch.Diff) *DraftPreviewDiff {
+	result := &DraftPreviewDiff{}
+
+	var buf strings.Builder
+
+	for _, diff := range diffs {
+		text := html.EscapeString(diff.Text)
+
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			result.Added += utf8.RuneCountInString(diff.Text)
+			buf.WriteString("<ins>" + text + "</ins>")
+		case diffmatchpatch.DiffDelete:
+			result.Removed += utf8.RuneCountInString(diff.Text)
+			buf.WriteString("<del>" + text + "</del>")
+		default:
+			buf.WriteString(text)
+		}
+	}
+
+	result.HTML = buf.String()
+
+	return result
+}
+
+// handleRecoverDraft handles the request to replace a draft with the client's cached copy.
+func (s *Server) handleRecoverDraft(
+	ctx context.Context,
+	input *RecoverDraftInput,
+) (*DraftRecoverOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	if draft.CreatedBy != user.Email && user.Role != models.ADMIN {
+		return nil, huma.Error403Forbidden("You can only recover your own drafts")
+	}
+
+	resp := &DraftRecoverOutput{}
+	resp.Body.Chunks = []DiffChunk{}
+
+	if input.Body.Content == content {
+		resp.ETag = entityETag(draft.Id, draft.UpdatedAt)
+		resp.Body.UpdatedAt = &draft.UpdatedAt
+		resp.Body.Content = content
+		resp.Body.DraftExists = true
+
+		return resp, nil
+	}
+
+	dmp := diffmatchpatch.New()
+	resp.Body.Chunks = diffChunks(dmp.DiffCleanupSemantic(dmp.DiffMain(content, input.Body.Content, false)))
+	resp.Body.ServerNewer = input.Body.UpdatedAt != nil && draft.UpdatedAt.After(*input.Body.UpdatedAt)
+
+	// Saving without an expected updatedAt makes the client copy win over newer server saves.
+	update := &UpdateDraftInput{ID: input.ID}
+	update.Body.Content = input.Body.Content
+
+	saved, err := s.handleUpdateDraft(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.ETag = saved.ETag
+	resp.Body.UpdatedAt = saved.Body.UpdatedAt
+	resp.Body.Content = saved.Body.Content
+	resp.Body.DraftExists = saved.Body.DraftExists
+	resp.Body.Recovered = true
+
+	return resp, nil
+}
+
+// handleTakeOverDraft handles the request to reassign a draft to the current admin.
+func (s *Server) handleTakeOverDraft(
+	ctx context.Context,
+	input *DraftIDInput,
+) (*DraftOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can take over drafts")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.TakeOverDraft(ctx, input.ID, admin.Email)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Failed to take over draft")
+	}
+
+	draft, content, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Draft", "Database error")
+	}
+
+	resp := &DraftOutput{ETag: entityETag(draft.Id, draft.UpdatedAt)}
+	resp.Body.Draft = &PublicDraft{
+		Id:             draft.Id,
+		ArticleId:      draft.ArticleId,
+		ArticleTitle:   draft.Article.Title,
+		ArticleSlug:    draft.Article.Slug,
+		ArticleVersion: draft.ArticleVersion,
+		Content:        content,
+		UpdatedAt:      draft.UpdatedAt,
+
+		ReconstructionDegraded: draft.ReconstructionDegraded,
+	}
+
+	return resp, nil
+}
+
 // handleDiscardDraft handles the request to discard a draft.
 func (s *Server) handleDiscardDraft(
 	ctx context.Context,
@@ -339,7 +956,12 @@ func (s *Server) handleDiscardDraft(
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
-	err := s.db.DiscardDraft(ctx, input.ID, user.Email)
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.DiscardDraft(ctx, input.ID, user.Email)
 	if err != nil {
 		if errors.Is(err, db.ErrCannotDiscardDraft) {
 			return nil, huma.Error403Forbidden("You can only discard your own drafts")
@@ -2,8 +2,15 @@ package api
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 	"wikilite/pkg/models"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -79,6 +86,102 @@ func TestHandlePublishDraft_Forbidden(t *testing.T) {
 	assert.Equal(t, 403, humaErr.Status)
 }
 
+func TestHandlePublishDraft_FrontMatter(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	user := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, user))
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", user.Email)
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(ctx, article.Id, "---\ntitle: Renamed\ntags: [ops]\n---\nBody", user.Email)
+	require.NoError(t, err)
+
+	_, err = server.handlePublishDraft(contextWithUser(user), &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+
+	resp, err := server.handleGetArticleJSON(ctx, &ArticleSlugInput{Slug: article.Slug})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", resp.Body.Title)
+	assert.Equal(t, []string{"ops"}, resp.Body.Tags)
+	assert.Equal(t, "Body", resp.Body.Data)
+
+	draft, err = db.CreateDraft(ctx, article.Id, "---\ntags: {ops: true}\n---\nBody", user.Email)
+	require.NoError(t, err)
+
+	_, err = server.handlePublishDraft(contextWithUser(user), &DraftIDInput{ID: draft.Id})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+}
+
+func TestHandlePreviewDraft_Diff(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	owner := &models.User{Name: "Owner", Email: "owner@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, owner))
+
+	page := publishArticle(t, db, "Guide", "# Guide\n\nUse the old tool.")
+	draft, err := db.CreateDraft(ctx, page.Id, "# Guide\n\nUse the new <tool>.", owner.Email)
+	require.NoError(t, err)
+
+	resp, err := server.handlePreviewDraft(contextWithUser(owner), &DraftPreviewInput{ID: draft.Id, Diff: true})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Body.HTML, "<h1")
+	assert.NotContains(t, resp.Body.HTML, "<tool>", "the preview is sanitized like the article page")
+	assert.Equal(t, 1, resp.Body.AgainstVersion)
+	require.NotNil(t, resp.Body.Diff)
+	assert.Equal(t, "# Guide\n\nUse the <del>old </del><ins>new &lt;</ins>tool<ins>&gt;</ins>.", resp.Body.Diff.HTML)
+	assert.Equal(t, len("new <")+len(">"), resp.Body.Diff.Added)
+	assert.Equal(t, len("old "), resp.Body.Diff.Removed)
+
+	unsaved := &DraftPreviewInput{ID: draft.Id, Diff: true}
+	unsaved.Body = &DraftPreviewBody{Content: "# Guide\n\nUse the old tool.\n\nMore."}
+
+	resp, err = server.handlePreviewDraft(contextWithUser(owner), unsaved)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Body.HTML, "<p>More.</p>")
+	assert.Equal(t, len("\n\nMore."), resp.Body.Diff.Added)
+	assert.Zero(t, resp.Body.Diff.Removed)
+
+	resp, err = server.handlePreviewDraft(contextWithUser(owner), &DraftPreviewInput{ID: draft.Id})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Body.Diff, "the diff is only computed on request")
+
+	other := &models.User{Name: "Other", Email: "other@example.com", Role: models.WRITE}
+	_, err = server.handlePreviewDraft(contextWithUser(other), &DraftPreviewInput{ID: draft.Id, Diff: true})
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+}
+
+func TestPreviewDraft_HTTPWithoutBody(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	owner := &models.User{Name: "Owner", Email: "owner@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, owner))
+
+	page := publishArticle(t, db, "Guide", "Old")
+	draft, err := db.CreateDraft(ctx, page.Id, "New", owner.Email)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/drafts/%d/preview?diff=true", draft.Id), nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(owner)))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), `"added":3`)
+}
+
 func TestHandleDiscardDraft_Success(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -152,3 +255,589 @@ func TestHandleDiscardDraft_Forbidden(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, 403, humaErr.Status)
 }
+
+func TestHandleTakeOverDraft_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	editor := &models.User{Name: "Editor", Email: "editor@example.com", Role: models.WRITE}
+	err := db.CreateUser(context.Background(), editor)
+	require.NoError(t, err)
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", editor.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "editor content", editor.Email)
+	require.NoError(t, err)
+
+	admin := &models.User{Name: "Admin", Email: "admin@test.com", Role: models.ADMIN}
+	ctx := contextWithUser(admin)
+
+	resp, err := server.handleTakeOverDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, draft.Id, resp.Body.Draft.Id)
+	assert.Equal(t, "editor content", resp.Body.Draft.Content)
+
+	found, _, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, admin.Email, found.CreatedBy)
+
+	input := &UpdateDraftInput{ID: draft.Id}
+	input.Body.Content = "admin content"
+	_, err = server.handleUpdateDraft(ctx, input)
+	require.NoError(t, err)
+}
+
+func TestHandleTakeOverDraft_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user1 := &models.User{Name: "User One", Email: "user1@example.com", Role: models.WRITE}
+	err := db.CreateUser(context.Background(), user1)
+	require.NoError(t, err)
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", user1.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "draft content", user1.Email)
+	require.NoError(t, err)
+
+	user2 := &models.User{Name: "User Two", Email: "user2@example.com", Role: models.WRITE}
+	err = db.CreateUser(context.Background(), user2)
+	require.NoError(t, err)
+
+	ctx := contextWithUser(user2)
+	_, err = server.handleTakeOverDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, 403, humaErr.Status)
+
+	found, _, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, user1.Email, found.CreatedBy)
+}
+
+func TestHandleUpdateDraft_ContentTooLarge(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.maxContentSize = 16
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	err := db.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", user.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "small", user.Email)
+	require.NoError(t, err)
+
+	ctx := contextWithUser(user)
+	input := &UpdateDraftInput{ID: draft.Id}
+	input.Body.Content = strings.Repeat("a", 17)
+	_, err = server.handleUpdateDraft(ctx, input)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, humaErr.Status)
+
+	_, content, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "small", content)
+
+	input.Body.Content = strings.Repeat("a", 16)
+	_, err = server.handleUpdateDraft(ctx, input)
+	require.NoError(t, err)
+}
+
+func TestHandleUpdateDraft_ReturnsSavedDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", user.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "original", user.Email)
+	require.NoError(t, err)
+
+	ctx := contextWithUser(user)
+
+	input := &UpdateDraftInput{ID: draft.Id}
+	input.Body.Content = "edited"
+
+	resp, err := server.handleUpdateDraft(ctx, input)
+	require.NoError(t, err)
+	assert.True(t, resp.Body.DraftExists)
+	assert.Equal(t, "edited", resp.Body.Content)
+	assert.Equal(t, "test-article", resp.Body.ArticleSlug)
+	require.NotNil(t, resp.Body.UpdatedAt)
+
+	next := &UpdateDraftInput{ID: draft.Id}
+	next.Body.Content = "edited again"
+	next.Body.UpdatedAt = resp.Body.UpdatedAt
+	_, err = server.handleUpdateDraft(ctx, next)
+	require.NoError(t, err, "the returned updatedAt is accepted by the next save")
+
+	missing := &UpdateDraftInput{ID: 9999}
+	missing.Body.Content = "anything"
+	_, err = server.handleUpdateDraft(ctx, missing)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestHandleUpdateDraft_EmptyDiffRemovesDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, article.Data+"\nMore", user.Email)
+	require.NoError(t, err)
+
+	input := &UpdateDraftInput{ID: draft.Id}
+	input.Body.Content = article.Data
+
+	resp, err := server.handleUpdateDraft(ctx, input)
+	require.NoError(t, err)
+	assert.False(t, resp.Body.DraftExists)
+	assert.Nil(t, resp.Body.UpdatedAt)
+	assert.Equal(t, article.Data, resp.Body.Content)
+	assert.Equal(t, "home", resp.Body.ArticleSlug)
+
+	_, _, err = db.GetDraftByID(context.Background(), draft.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "the draft is removed")
+}
+
+func TestHandleUpdateDraft_StaleUpdateConflict(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", user.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "original", user.Email)
+	require.NoError(t, err)
+
+	ctx := contextWithUser(user)
+
+	// Two editor tabs load the same draft.
+	loaded, err := server.handleGetDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+	loadedAt := loaded.Body.Draft.UpdatedAt
+
+	first := &UpdateDraftInput{ID: draft.Id}
+	first.Body.Content = "first tab"
+	first.Body.UpdatedAt = &loadedAt
+	_, err = server.handleUpdateDraft(ctx, first)
+	require.NoError(t, err)
+
+	for _, content := range []string{"second tab", "first tab again"} {
+		stale := &UpdateDraftInput{ID: draft.Id}
+		stale.Body.Content = content
+		stale.Body.UpdatedAt = &loadedAt
+		_, err = server.handleUpdateDraft(ctx, stale)
+		require.Error(t, err)
+
+		var humaErr *huma.ErrorModel
+		require.True(t, errors.As(err, &humaErr))
+		assert.Equal(t, http.StatusConflict, humaErr.Status)
+	}
+
+	_, content, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "first tab", content)
+
+	reloaded, err := server.handleGetDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+
+	current := &UpdateDraftInput{ID: draft.Id}
+	current.Body.Content = "after reload"
+	current.Body.UpdatedAt = &reloaded.Body.Draft.UpdatedAt
+	_, err = server.handleUpdateDraft(ctx, current)
+	require.NoError(t, err)
+}
+
+func TestHandleUpdateDraft_IfMatch(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", user.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "original", user.Email)
+	require.NoError(t, err)
+
+	ctx := contextWithUser(user)
+
+	loaded, err := server.handleGetDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded.ETag)
+
+	first := &UpdateDraftInput{ID: draft.Id, IfMatch: loaded.ETag}
+	first.Body.Content = "first tab"
+	saved, err := server.handleUpdateDraft(ctx, first)
+	require.NoError(t, err)
+	assert.NotEqual(t, loaded.ETag, saved.ETag, "a save changes the ETag")
+
+	stale := &UpdateDraftInput{ID: draft.Id, IfMatch: loaded.ETag}
+	stale.Body.Content = "second tab"
+	_, err = server.handleUpdateDraft(ctx, stale)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusPreconditionFailed, humaErr.Status)
+
+	_, content, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "first tab", content)
+
+	next := &UpdateDraftInput{ID: draft.Id, IfMatch: `"other", ` + saved.ETag}
+	next.Body.Content = "first tab again"
+	_, err = server.handleUpdateDraft(ctx, next)
+	require.NoError(t, err, "any listed ETag may match")
+
+	weak := &UpdateDraftInput{ID: draft.Id, IfMatch: "W/" + saved.ETag}
+	weak.Body.Content = "weak"
+	_, err = server.handleUpdateDraft(ctx, weak)
+	require.Error(t, err, "weak ETags never match If-Match")
+
+	wildcard := &UpdateDraftInput{ID: draft.Id, IfMatch: "*"}
+	wildcard.Body.Content = "wildcard"
+	_, err = server.handleUpdateDraft(ctx, wildcard)
+	require.NoError(t, err)
+}
+
+func TestDraftETag_HTTP(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+	article, _, err := db.CreateArticleWithDraft(context.Background(), "Test Article", user.Email)
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, "original", user.Email)
+	require.NoError(t, err)
+
+	path := "/api/drafts/" + strconv.Itoa(draft.Id)
+
+	serve := func(method, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rr = serve(http.MethodPut, etag, `{"content":"edited"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+
+	rr = serve(http.MethodPut, etag, `{"content":"stale"}`)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+}
+
+func TestHandleValidateDraft_BrokenLinks(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	article, draft, err := db.CreateArticleWithDraft(context.Background(), "Linking Page", user.Email)
+	require.NoError(t, err)
+
+	content := "See [Home](/wiki/home) and [Nowhere](/wiki/nowhere)."
+	err = db.UpdateDraft(context.Background(), draft.Id, content, user.Email, false, nil)
+	require.NoError(t, err)
+
+	resp, err := server.handleValidateDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nowhere"}, resp.Body.BrokenLinks)
+
+	other := &models.User{Email: "other@example.com", Role: models.WRITE}
+	_, err = server.handleValidateDraft(contextWithUser(other), &DraftIDInput{ID: draft.Id})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	_, err = server.handlePublishDraft(ctx, &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err, "broken links should not block publishing")
+
+	published, err := db.GetArticleByID(context.Background(), article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published.Version)
+}
+
+func TestHandleValidateDraft_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	_, err := server.handleValidateDraft(contextWithUser(user), &DraftIDInput{ID: 9999})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestDraftHandlers_MissingDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	for _, tt := range []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/drafts/9999"},
+		{http.MethodPost, "/api/drafts/9999/publish"},
+	} {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+
+			assert.Equal(t, http.StatusNotFound, rr.Code)
+			assert.Contains(t, rr.Body.String(), "Draft not found")
+		})
+	}
+}
+
+func TestDraftLimit(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.maxDraftsPerUser = 2
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(writer)
+
+	create := &CreateArticleInput{}
+	create.Body.Title = "First"
+	_, err := server.handleCreateArticle(ctx, create)
+	require.NoError(t, err)
+
+	_, err = server.handleCreateDraft(ctx, &ArticleSlugForDraftInput{Slug: "home"})
+	require.NoError(t, err)
+
+	_, err = server.handleCreateDraft(ctx, &ArticleSlugForDraftInput{Slug: "home"})
+	require.NoError(t, err, "restarting a draft for the same article replaces it")
+
+	create.Body.Title = "Second"
+	_, err = server.handleCreateArticle(ctx, create)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusConflict, humaErr.Status)
+	assert.Contains(t, humaErr.Detail, "discard")
+
+	count, err := db.CountDraftsByUser(context.Background(), writer.Email)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	adminCtx := contextWithUser(admin)
+
+	for _, title := range []string{"Admin One", "Admin Two", "Admin Three"} {
+		create.Body.Title = title
+		_, err = server.handleCreateArticle(adminCtx, create)
+		require.NoError(t, err, "admins are exempt from the draft limit")
+	}
+
+	_, err = server.handleCreateDraft(ctx, &ArticleSlugForDraftInput{Slug: "admin-one"})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusConflict, humaErr.Status)
+}
+
+func TestHandleDiffDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	article, draft, err := db.CreateArticleWithDraft(ctx, "Diff Page", user.Email)
+	require.NoError(t, err)
+
+	require.NoError(t, db.UpdateDraft(ctx, draft.Id, "alpha", user.Email, false, nil))
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	for _, content := range []string{"alpha beta", "alpha beta gamma"} {
+		draft, err = db.CreateDraft(ctx, article.Id, content, user.Email)
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+	}
+
+	draft, err = db.CreateDraft(ctx, article.Id, "alpha delta gamma", user.Email)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		against string
+		version int
+		base    string
+	}{
+		{"current", "", 3, "alpha beta gamma"},
+		{"v0", "version:0", 0, ""},
+		{"mid history", "version:2", 2, "alpha beta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &DraftDiffInput{ID: draft.Id, Against: tt.against}
+			resp, err := server.handleDiffDraft(contextWithUser(user), input)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.version, resp.Body.AgainstVersion)
+
+			var before, after strings.Builder
+			for _, chunk := range resp.Body.Chunks {
+				if chunk.Op != "insert" {
+					before.WriteString(chunk.Text)
+				}
+				if chunk.Op != "delete" {
+					after.WriteString(chunk.Text)
+				}
+			}
+
+			assert.Equal(t, tt.base, before.String())
+			assert.Equal(t, "alpha delta gamma", after.String())
+		})
+	}
+}
+
+func TestHandleDiffDraft_Errors(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	other := &models.User{Email: "other@example.com", Role: models.WRITE}
+
+	_, draft, err := db.CreateArticleWithDraft(context.Background(), "Diff Page", user.Email)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		caller  *models.User
+		id      int
+		against string
+		status  int
+	}{
+		{"other user", other, draft.Id, "", http.StatusForbidden},
+		{"missing draft", user, 9999, "", http.StatusNotFound},
+		{"bad target", user, draft.Id, "yesterday", http.StatusBadRequest},
+		{"bad version", user, draft.Id, "version:-1", http.StatusBadRequest},
+		{"unknown version", user, draft.Id, "version:5", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &DraftDiffInput{ID: tt.id, Against: tt.against}
+			_, err := server.handleDiffDraft(contextWithUser(tt.caller), input)
+			require.Error(t, err)
+
+			var humaErr *huma.ErrorModel
+			require.True(t, errors.As(err, &humaErr))
+			assert.Equal(t, tt.status, humaErr.Status)
+		})
+	}
+}
+
+func TestHandleRecoverDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	owner := &models.User{Name: "Owner", Email: "owner@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, owner))
+
+	page := publishArticle(t, db, "Guide", "# Guide\n\nIntro.")
+	draft, err := db.CreateDraft(ctx, page.Id, "# Guide\n\nIntro.\n\nSaved.", owner.Email)
+	require.NoError(t, err)
+
+	// The browser holds newer content whose autosave never reached the server.
+	input := &RecoverDraftInput{ID: draft.Id}
+	input.Body.Content = "# Guide\n\nIntro.\n\nSaved. Unsaved."
+	input.Body.UpdatedAt = &draft.UpdatedAt
+
+	resp, err := server.handleRecoverDraft(contextWithUser(owner), input)
+	require.NoError(t, err)
+	assert.True(t, resp.Body.Recovered)
+	assert.False(t, resp.Body.ServerNewer)
+	assert.True(t, resp.Body.DraftExists)
+	assert.Equal(t, input.Body.Content, resp.Body.Content)
+	assert.NotEmpty(t, resp.ETag)
+	assert.Equal(t, []DiffChunk{
+		{Op: "equal", Text: "# Guide\n\nIntro.\n\nSaved."},
+		{Op: "insert", Text: " Unsaved."},
+	}, resp.Body.Chunks)
+
+	_, content, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, input.Body.Content, content)
+
+	resp, err = server.handleRecoverDraft(contextWithUser(owner), input)
+	require.NoError(t, err)
+	assert.False(t, resp.Body.Recovered, "content already on the server is not saved again")
+	assert.Empty(t, resp.Body.Chunks)
+
+	other := &models.User{Name: "Other", Email: "other@example.com", Role: models.WRITE}
+	_, err = server.handleRecoverDraft(contextWithUser(other), input)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+}
+
+func TestHandleRecoverDraft_ServerNewer(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	owner := &models.User{Name: "Owner", Email: "owner@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, owner))
+
+	page := publishArticle(t, db, "Guide", "Intro.")
+	draft, err := db.CreateDraft(ctx, page.Id, "Intro. Client.", owner.Email)
+	require.NoError(t, err)
+
+	// Another tab saved over the draft after the browser last synced.
+	clientSavedAt := draft.UpdatedAt.Add(-time.Second)
+	require.NoError(t, db.UpdateDraft(ctx, draft.Id, "Intro. Other tab.", owner.Email, false, nil))
+
+	input := &RecoverDraftInput{ID: draft.Id}
+	input.Body.Content = "Intro. Client."
+	input.Body.UpdatedAt = &clientSavedAt
+
+	resp, err := server.handleRecoverDraft(contextWithUser(owner), input)
+	require.NoError(t, err)
+	assert.True(t, resp.Body.Recovered, "the client copy is saved even when it conflicts")
+	assert.True(t, resp.Body.ServerNewer)
+	assert.Equal(t, "Intro. Client.", resp.Body.Content)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"wikilite/internal/db"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// dbError maps an error from the database layer to a response: 404 for db.ErrNotFound,
+// naming the missing resource, 409 for db.ErrConflict, with the message of the error, and
+// a 500 with message for anything else.
+func dbError(err error, resource string, message string) huma.StatusError {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return huma.Error404NotFound(resource + " not found")
+	case errors.Is(err, db.ErrConflict):
+		return huma.Error409Conflict(capitalize(err.Error()))
+	default:
+		return huma.Error500InternalServerError(message, err)
+	}
+}
+
+// capitalize upper-cases the first letter of an error message for use in a response.
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"wikilite/internal/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBError(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"not found", db.ErrNotFound, http.StatusNotFound, "Draft not found"},
+		{"wrapped not found", fmt.Errorf("lookup: %w", db.ErrNotFound), http.StatusNotFound, "Draft not found"},
+		{"conflict", db.ErrArticleExists, http.StatusConflict, "An article with this title already exists"},
+		{"draft conflict", db.ErrDraftConflict, http.StatusConflict, "The draft has been updated since it was loaded"},
+		{"other", errors.New("disk I/O error"), http.StatusInternalServerError, "Database error"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError(tt.err, "Draft", "Database error")
+			assert.Equal(t, tt.status, err.GetStatus())
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
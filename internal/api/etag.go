@@ -0,0 +1,50 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// entityETag returns a strong entity tag for a resource identified by id that changes
+// whenever updatedAt does.
+func entityETag(id int, updatedAt time.Time) string {
+	return `"` + strconv.Itoa(id) + "-" + strconv.FormatInt(updatedAt.UnixNano(), 36) + `"`
+}
+
+// contentETag returns a strong entity tag derived from a response body.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-Match header value matches etag. The header may be
+// "*" or a comma separated list of entity tags; weak tags never match (RFC 9110 13.1.1).
+func etagMatches(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// etagMatchesWeak reports whether an If-None-Match header value matches etag. Unlike
+// etagMatches it uses the weak comparison, so W/"x" matches "x" (RFC 9110 13.1.2).
+func etagMatchesWeak(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"wikilite/internal/export"
+	"wikilite/pkg/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ArticleExportInput represents the input for exporting an article to another wiki's format.
+type ArticleExportInput struct {
+	Slug   string `doc:"The URL slug of the article"                             path:"slug"`
+	Format string `doc:"Target format: 'confluence' (Confluence storage format)" default:"confluence" enum:"confluence" query:"format"`
+}
+
+// ArticleExportOutput represents an exported article, returned as a file download.
+type ArticleExportOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+// registerExportRoutes registers the article export routes with the API.
+func (s *Server) registerExportRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "export-article",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/{slug}/export",
+		Summary:     "Export Article",
+		Description: "Download the article converted for import into another wiki. Confluence " +
+			"exports use the storage format; links to other articles become page links.",
+		Tags: []string{"Articles"},
+	}, s.handleExportArticle)
+}
+
+// handleExportArticle handles the request to export an article to another wiki's format.
+func (s *Server) handleExportArticle(
+	ctx context.Context,
+	input *ArticleExportInput,
+) (*ArticleExportOutput, error) {
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	content, err := s.resolveIncludes(ctx, article.Slug, article.Data)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	titles, err := s.linkedArticleTitles(ctx, content)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	return &ArticleExportOutput{
+		ContentType:        "application/xhtml+xml; charset=utf-8",
+		ContentDisposition: `attachment; filename="` + article.Slug + `.xhtml"`,
+		Body:               export.Confluence(content, titles),
+	}, nil
+}
+
+// linkedArticleTitles maps the slug of every existing article linked from content to its title.
+func (s *Server) linkedArticleTitles(ctx context.Context, content string) (map[string]string, error) {
+	var slugs []string
+	for _, target := range utils.ExtractSlugsFromContent(content) {
+		slug := utils.NormalizeLinkSlug(target)
+		if slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+
+	articles, err := s.db.GetArticlesBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]string, len(articles))
+	for _, article := range articles {
+		titles[article.Slug] = article.Title
+	}
+
+	return titles, nil
+}
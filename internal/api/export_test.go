@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleExportArticle_Confluence(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Runbook", "Steps")
+	publishArticle(t, db, "Footer", "Footer *text*")
+	page := publishArticle(t, db, "Guide", "## Setup\n\nRead the [runbook](/wiki/runbook) first.\n\n{{include:footer}}")
+
+	resp, err := server.handleExportArticle(context.Background(), &ArticleExportInput{Slug: page.Slug, Format: "confluence"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/xhtml+xml; charset=utf-8", resp.ContentType)
+	assert.Equal(t, `attachment; filename="guide.xhtml"`, resp.ContentDisposition)
+	assert.Equal(t,
+		"<h2>Setup</h2>\n"+
+			`<p>Read the <ac:link><ri:page ri:content-title="Runbook" /><ac:link-body>runbook</ac:link-body></ac:link> first.</p>`+"\n"+
+			"<p>Footer <em>text</em></p>\n",
+		string(resp.Body),
+	)
+
+	_, err = server.handleExportArticle(context.Background(), &ArticleExportInput{Slug: "missing", Format: "confluence"})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestExportArticle_UnknownFormat(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/home/export?format=dokuwiki", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
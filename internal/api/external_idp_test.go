@@ -1,9 +1,12 @@
 package api
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsExternalIDPEnabled(t *testing.T) {
@@ -77,3 +80,58 @@ func TestServerCreationWithoutExternalIDP(t *testing.T) {
 	assert.NotNil(t, server)
 	assert.False(t, server.isExternalIDPEnabled())
 }
+
+func TestHandleLogout_EndSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		jwksURL  string
+		expected string
+	}{
+		{
+			name:     "External IDP redirects to end session endpoint",
+			jwksURL:  "https://example.com/.well-known/jwks.json",
+			expected: "https://idp.example.com/logout?client_id=wiki&post_logout_redirect_uri=https%3A%2F%2Fwiki.example.com%2F",
+		},
+		{
+			name:     "Local auth keeps local logout",
+			jwksURL:  "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(ServerConfig{
+				JwtSecret:             "test-secret",
+				JwksURL:               tt.jwksURL,
+				WikiName:              "Test Wiki",
+				EndSessionEndpoint:    "https://idp.example.com/logout?client_id=wiki",
+				PostLogoutRedirectURL: "https://wiki.example.com/",
+			})
+			require.NoError(t, err)
+
+			resp, err := server.handleLogout(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Len(t, resp.Cookies, 2)
+			assert.Equal(t, tt.expected, resp.Location)
+
+			if tt.expected == "" {
+				assert.Zero(t, resp.Status)
+			} else {
+				assert.Equal(t, http.StatusSeeOther, resp.Status)
+			}
+		})
+	}
+}
+
+func TestNewEndSessionURL_Invalid(t *testing.T) {
+	_, err := newEndSessionURL("/logout", "")
+	assert.Error(t, err)
+
+	_, err = newEndSessionURL("https://idp.example.com/logout", "wiki.example.com")
+	assert.Error(t, err)
+
+	logoutURL, err := newEndSessionURL("", "https://wiki.example.com/")
+	require.NoError(t, err)
+	assert.Empty(t, logoutURL)
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"wikilite/internal/graphql"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Query cost limits of the GraphQL endpoint. Every selected field costs 1; list fields and
+// article content cost more, since they hit the database or the renderer, so that aliases
+// and fragments cannot multiply the work done by one request. The limit leaves room for
+// the introspection query sent by GraphiQL and client generators.
+const (
+	maxGraphQLCost     = 300
+	graphQLListCost    = 25
+	graphQLContentCost = 10
+)
+
+// errGraphQLDatabase is reported to GraphQL clients in place of the underlying database error.
+var errGraphQLDatabase = errors.New("database error")
+
+// graphQLDateTime serializes timestamps as RFC 3339 strings.
+var graphQLDateTime = &graphql.Scalar{
+	Name: "DateTime",
+	Serialize: func(value any) (any, error) {
+		t, ok := value.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("cannot represent %v as DateTime", value)
+		}
+
+		return t.Format(time.RFC3339Nano), nil
+	},
+	Parse: func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot represent %v as DateTime", value)
+		}
+
+		return time.Parse(time.RFC3339Nano, s)
+	},
+}
+
+// GraphQLInput represents a GraphQL request.
+type GraphQLInput struct {
+	Body graphql.Request
+}
+
+// GraphQLOutput represents a GraphQL response.
+type GraphQLOutput struct {
+	Body *graphql.Result
+}
+
+// registerGraphQLRoutes registers the read-only GraphQL endpoint with the API.
+func (s *Server) registerGraphQLRoutes() {
+	schema := s.newGraphQLSchema()
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "graphql",
+		Method:      http.MethodPost,
+		Path:        "/api/graphql",
+		Summary:     "GraphQL Query",
+		Description: "Run a read-only GraphQL query. The schema exposes article(slug), " +
+			"articles(page, limit, sort) and articleHistory(slug), and supports introspection. " +
+			"Queries over the cost limit are rejected. Field and query errors are reported in " +
+			"the errors member of the response.",
+		Tags: []string{"Articles"},
+	}, func(ctx context.Context, input *GraphQLInput) (*GraphQLOutput, error) {
+		return &GraphQLOutput{Body: graphql.Execute(ctx, schema, input.Body)}, nil
+	})
+}
+
+// newGraphQLSchema builds the GraphQL schema whose resolvers read through the database
+// with the same visibility rules as the REST endpoints.
+func (s *Server) newGraphQLSchema() *graphql.Schema {
+	nonNull := func(t graphql.Type) graphql.Type {
+		return &graphql.NonNull{OfType: t}
+	}
+
+	article := &graphql.Object{
+		Name: "Article",
+		Fields: map[string]*graphql.Field{
+			"id":        {Type: nonNull(graphql.ID)},
+			"title":     {Type: nonNull(graphql.String)},
+			"slug":      {Type: nonNull(graphql.String)},
+			"version":   {Type: nonNull(graphql.Int)},
+			"data":      {Type: nonNull(graphql.String), Resolve: s.resolveArticleData, Cost: graphQLContentCost},
+			"author":    {Type: graphql.String},
+			"createdAt": {Type: nonNull(graphQLDateTime)},
+			"updatedAt": {Type: nonNull(graphQLDateTime)},
+			"html":      {Type: nonNull(graphql.String), Resolve: s.resolveArticleHTML, Cost: graphQLContentCost},
+		},
+	}
+
+	articlePage := &graphql.Object{
+		Name: "ArticlePage",
+		Fields: map[string]*graphql.Field{
+			"articles": {Type: nonNull(&graphql.List{OfType: nonNull(article)})},
+			"total":    {Type: nonNull(graphql.Int)},
+			"page":     {Type: nonNull(graphql.Int)},
+			"limit":    {Type: nonNull(graphql.Int)},
+			"sort":     {Type: nonNull(graphql.String)},
+		},
+	}
+
+	historyEntry := &graphql.Object{
+		Name: "HistoryEntry",
+		Fields: map[string]*graphql.Field{
+			"id":        {Type: nonNull(graphql.ID)},
+			"version":   {Type: nonNull(graphql.Int)},
+			"createdAt": {Type: nonNull(graphQLDateTime)},
+			"createdBy": {Type: graphql.String, Resolve: resolveHistoryCreatedBy},
+		},
+	}
+
+	slugArgs := map[string]*graphql.Argument{
+		"slug": {Type: nonNull(graphql.String)},
+	}
+
+	return &graphql.Schema{Query: &graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.Field{
+			"article": {
+				Type:    article,
+				Args:    slugArgs,
+				Resolve: s.resolveArticle,
+			},
+			"articles": {
+				Type: nonNull(articlePage),
+				Args: map[string]*graphql.Argument{
+					"page":  {Type: graphql.Int, DefaultValue: 1},
+					"limit": {Type: graphql.Int},
+					"sort":  {Type: graphql.String, DefaultValue: "updated"},
+				},
+				Resolve: s.resolveArticles,
+				Cost:    graphQLListCost,
+			},
+			"articleHistory": {
+				Type:    &graphql.List{OfType: nonNull(historyEntry)},
+				Args:    slugArgs,
+				Resolve: s.resolveArticleHistory,
+				Cost:    graphQLListCost,
+			},
+		},
+	}, MaxCost: maxGraphQLCost}
+}
+
+// resolveArticle resolves article(slug), returning null for unknown slugs.
+func (s *Server) resolveArticle(p graphql.ResolveParams) (any, error) {
+	article, err := s.db.GetArticleBySlug(p.Context, p.Args["slug"].(string))
+	if err != nil {
+		return nil, errGraphQLDatabase
+	}
+
+	if article == nil {
+		return nil, nil
+	}
+
+	return sanitizeArticle(article, getAdminUserFromContext(p.Context) != nil), nil
+}
+
+// resolveArticles resolves articles(page, limit, sort) through the paginated list endpoint.
+func (s *Server) resolveArticles(p graphql.ResolveParams) (any, error) {
+	input := &ArticlePaginationInput{Sort: p.Args["sort"].(string)}
+
+	if page, ok := p.Args["page"].(int); ok {
+		input.Page = page
+	}
+
+	if limit, ok := p.Args["limit"].(int); ok {
+		input.Limit = limit
+	}
+
+	resp, err := s.handleGetArticles(p.Context, input)
+	if err != nil {
+		return nil, graphQLError(err)
+	}
+
+	return &resp.Body, nil
+}
+
+// resolveArticleHistory resolves articleHistory(slug), returning null for unknown slugs.
+func (s *Server) resolveArticleHistory(p graphql.ResolveParams) (any, error) {
+	resp, err := s.handleGetArticleHistory(p.Context, &ArticleSlugInput{Slug: p.Args["slug"].(string)})
+	if err != nil {
+		var humaErr *huma.ErrorModel
+		if errors.As(err, &humaErr) && humaErr.Status == http.StatusNotFound {
+			return nil, nil
+		}
+
+		return nil, graphQLError(err)
+	}
+
+	return resp.Body.History, nil
+}
+
+// resolveArticleData returns the markdown of an article.
+func (s *Server) resolveArticleData(p graphql.ResolveParams) (any, error) {
+	article, err := s.withArticleContent(p.Context, p.Source.(*PublicArticle))
+	if err != nil {
+		return nil, err
+	}
+
+	return article.Data, nil
+}
+
+// resolveArticleHTML renders the markdown of an article to HTML.
+func (s *Server) resolveArticleHTML(p graphql.ResolveParams) (any, error) {
+	article, err := s.withArticleContent(p.Context, p.Source.(*PublicArticle))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getRenderedHTML(p.Context, article)
+}
+
+// withArticleContent returns the article with its content loaded. Articles from the
+// paginated list are fetched without content, so it is loaded on demand.
+func (s *Server) withArticleContent(ctx context.Context, article *PublicArticle) (*PublicArticle, error) {
+	if article.Data != "" || article.Version == 0 {
+		return article, nil
+	}
+
+	full, err := s.db.GetArticleBySlug(ctx, article.Slug)
+	if err != nil {
+		return nil, errGraphQLDatabase
+	}
+
+	if full == nil {
+		return article, nil
+	}
+
+	loaded := *article
+	loaded.Data = full.Data
+
+	return &loaded, nil
+}
+
+// resolveHistoryCreatedBy reports the editor of a revision, or null when it is hidden.
+func resolveHistoryCreatedBy(p graphql.ResolveParams) (any, error) {
+	entry := p.Source.(*models.History)
+	if entry.CreatedBy == "" {
+		return nil, nil
+	}
+
+	return entry.CreatedBy, nil
+}
+
+// graphQLError converts an error returned by a REST handler into a GraphQL field error,
+// hiding the details of server errors.
+func graphQLError(err error) error {
+	var humaErr *huma.ErrorModel
+	if errors.As(err, &humaErr) && humaErr.Status < http.StatusInternalServerError {
+		return errors.New(humaErr.Detail)
+	}
+
+	return errGraphQLDatabase
+}
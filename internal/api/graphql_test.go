@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postGraphQL sends a GraphQL request through the router and decodes the response.
+func postGraphQL(t *testing.T, server *Server, ctx context.Context, body string) map[string]any {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+
+	return result
+}
+
+func TestGraphQL_Article(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Guide", "# Guide\n\nSome **bold** text.")
+
+	query := `{"query": "query ($slug: String!) { article(slug: $slug) { id title slug version data html author createdAt } missing: article(slug: \"nope\") { id } }", "variables": {"slug": "guide"}}`
+
+	result := postGraphQL(t, server, context.Background(), query)
+	require.NotContains(t, result, "errors")
+
+	data := result["data"].(map[string]any)
+	assert.Nil(t, data["missing"])
+
+	article := data["article"].(map[string]any)
+	assert.Equal(t, "Guide", article["title"])
+	assert.Equal(t, "guide", article["slug"])
+	assert.InDelta(t, 1, article["version"], 0)
+	assert.Equal(t, "# Guide\n\nSome **bold** text.", article["data"])
+	assert.Contains(t, article["html"], "<strong>bold</strong>")
+	assert.NotEmpty(t, article["id"])
+	assert.NotEmpty(t, article["createdAt"])
+	assert.Nil(t, article["author"], "author is only visible to admins")
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	result = postGraphQL(t, server, contextWithUser(admin), query)
+	article = result["data"].(map[string]any)["article"].(map[string]any)
+	assert.Equal(t, "writer@example.com", article["author"])
+}
+
+func TestGraphQL_Articles(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Alpha", "Alpha content")
+	publishArticle(t, db, "Beta", "Beta content")
+
+	result := postGraphQL(t, server, context.Background(),
+		`{"query": "{ articles(page: 1, limit: 2, sort: \"title\") { total page limit sort articles { slug data } } }"}`)
+	require.NotContains(t, result, "errors")
+
+	page := result["data"].(map[string]any)["articles"].(map[string]any)
+	assert.InDelta(t, 3, page["total"], 0)
+	assert.InDelta(t, 2, page["limit"], 0)
+	assert.Equal(t, "title", page["sort"])
+
+	articles := page["articles"].([]any)
+	require.Len(t, articles, 2)
+	assert.Equal(t, map[string]any{"slug": "alpha", "data": "Alpha content"}, articles[0])
+	assert.Equal(t, map[string]any{"slug": "beta", "data": "Beta content"}, articles[1])
+
+	result = postGraphQL(t, server, context.Background(), `{"query": "{ articles(sort: \"sideways\") { total } }"}`)
+	require.Contains(t, result, "errors")
+	assert.Equal(t, "Invalid sort order", result["errors"].([]any)[0].(map[string]any)["message"])
+}
+
+func TestGraphQL_ArticleHistory(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	article := publishArticle(t, db, "Changelog", "v1")
+	draft, err := db.CreateDraft(ctx, article.Id, "v2", "editor@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	query := `{"query": "{ articleHistory(slug: \"changelog\") { version createdBy } none: articleHistory(slug: \"nope\") { version } }"}`
+
+	result := postGraphQL(t, server, ctx, query)
+	require.NotContains(t, result, "errors")
+
+	data := result["data"].(map[string]any)
+	assert.Nil(t, data["none"])
+
+	history := data["articleHistory"].([]any)
+	require.NotEmpty(t, history)
+
+	for _, entry := range history {
+		assert.Nil(t, entry.(map[string]any)["createdBy"], "editors are only visible to admins")
+	}
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	result = postGraphQL(t, server, contextWithUser(admin), query)
+	history = result["data"].(map[string]any)["articleHistory"].([]any)
+
+	var editors []any
+	for _, entry := range history {
+		editors = append(editors, entry.(map[string]any)["createdBy"])
+	}
+
+	assert.Contains(t, editors, "editor@example.com")
+}
+
+func TestGraphQL_ReadOnly(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	result := postGraphQL(t, server, context.Background(), `{"query": "mutation { deleteArticle(slug: \"home\") }"}`)
+
+	assert.NotContains(t, result, "data")
+	require.Len(t, result["errors"], 1)
+	assert.Contains(t, result["errors"].([]any)[0].(map[string]any)["message"], "read-only")
+
+	home, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+	assert.NotNil(t, home)
+}
+
+func TestGraphQL_Introspection(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	result := postGraphQL(t, server, context.Background(), `{"query": "{ __schema { queryType { name } } __type(name: \"Article\") { kind fields { name } } }"}`)
+	require.NotContains(t, result, "errors")
+
+	data := result["data"].(map[string]any)
+	assert.Equal(t, map[string]any{"queryType": map[string]any{"name": "Query"}}, data["__schema"])
+
+	article := data["__type"].(map[string]any)
+	assert.Equal(t, "OBJECT", article["kind"])
+	assert.Contains(t, article["fields"], map[string]any{"name": "html"})
+}
+
+func TestGraphQL_MaxCost(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	var aliases []string
+	for i := range 10 {
+		aliases = append(aliases, fmt.Sprintf(`a%d: articles(limit: 100) { articles { html } }`, i))
+	}
+
+	body, err := json.Marshal(map[string]string{"query": "{ " + strings.Join(aliases, " ") + " }"})
+	require.NoError(t, err)
+
+	result := postGraphQL(t, server, context.Background(), string(body))
+
+	assert.NotContains(t, result, "data")
+	require.Len(t, result["errors"], 1)
+	assert.Contains(t, result["errors"].([]any)[0].(map[string]any)["message"], "query is too complex")
+
+	body, err = json.Marshal(map[string]string{"query": "{ " + strings.Join(aliases[:2], " ") + " }"})
+	require.NoError(t, err)
+
+	result = postGraphQL(t, server, context.Background(), string(body))
+	assert.NotContains(t, result, "errors")
+}
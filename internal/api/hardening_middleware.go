@@ -16,24 +16,9 @@ const baseRateLimiter = 30
 // hardeningMiddleware returns a middleware chain that applies security hardening to the HTTP routes.
 func (s *Server) hardeningMiddleware(next http.Handler) http.Handler {
 	sslProxyHeaders := make(map[string]string)
-	ipLookups := make([]limiter.IPLookup, 0)
 
 	if s.trustProxyHeaders {
 		sslProxyHeaders = map[string]string{"X-Forwarded-Proto": "https"}
-		ipLookups = []limiter.IPLookup{
-			{
-				Name:           "RemoteAddr",
-				IndexFromRight: 0,
-			},
-			{
-				Name:           "X-Forwarded-For",
-				IndexFromRight: 0,
-			},
-			{
-				Name:           "X-Real-IP",
-				IndexFromRight: 0,
-			},
-		}
 	}
 
 	secureMiddleware := secure.New(secure.Options{
@@ -50,12 +35,9 @@ func (s *Server) hardeningMiddleware(next http.Handler) http.Handler {
 
 	if s.production {
 		rateLimiter := tollbooth.NewLimiter(baseRateLimiter, nil)
-		for _, lookup := range ipLookups {
-			rateLimiter.SetIPLookup(lookup)
-		}
 
 		rateLimitMiddleware = func(next http.Handler) http.Handler {
-			return tollbooth.LimitHandler(rateLimiter, next)
+			return s.rateLimitByClientIP(rateLimiter, next)
 		}
 	} else {
 		rateLimitMiddleware = func(next http.Handler) http.Handler {
@@ -76,3 +58,18 @@ func (s *Server) hardeningMiddleware(next http.Handler) http.Handler {
 
 	return rateLimitMiddleware(secureMiddleware.Handler(conditionalCSRF))
 }
+
+// rateLimitByClientIP limits requests per client, keyed by the resolved client IP.
+func (s *Server) rateLimitByClientIP(lmt *limiter.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpError := tollbooth.LimitByKeys(lmt, []string{s.clientIP(r)})
+		if httpError != nil {
+			w.Header().Add("Content-Type", lmt.GetMessageContentType())
+			w.WriteHeader(httpError.StatusCode)
+			_, _ = w.Write([]byte(httpError.Message))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,37 @@
+//go:build ui
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/didip/tollbooth/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitByClientIP(t *testing.T) {
+	server := &Server{trustProxyHeaders: true, trustedProxyHops: 1}
+	lmt := tollbooth.NewLimiter(1, nil)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := server.rateLimitByClientIP(lmt, next)
+
+	request := func(forwardedFor string) int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "10.0.0.1:54321"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("6.6.6.6, 203.0.113.7"))
+	assert.Equal(t, http.StatusTooManyRequests, request("7.7.7.7, 203.0.113.7"),
+		"spoofed left-most entries must not bypass the limiter")
+	assert.Equal(t, http.StatusOK, request("203.0.113.8"))
+}
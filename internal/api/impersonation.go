@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// ImpersonatorCookieName holds the admin's original session while impersonating in the UI.
+	ImpersonatorCookieName = "wiki_impersonator"
+	// ImpersonationDuration is the lifetime of an impersonation token.
+	ImpersonationDuration = 30 * time.Minute
+)
+
+// impersonatorContextKey is the key used to store/retrieve the impersonating admin from context.
+const impersonatorContextKey contextKey = "impersonator"
+
+// ImpersonateInput represents the input for an impersonation request.
+type ImpersonateInput struct {
+	Body struct {
+		Email string `format:"email" json:"email" required:"true"`
+	}
+}
+
+// ImpersonateOutput represents the output of an impersonation request.
+type ImpersonateOutput struct {
+	Body struct {
+		Type         string    `json:"type"`
+		Token        string    `json:"token"`
+		ExpiresAt    int64     `json:"expiresAt"`
+		User         *SafeUser `json:"user"`
+		Impersonator *SafeUser `json:"impersonator"`
+	}
+}
+
+// registerImpersonationRoutes registers the impersonation routes with the API.
+func (s *Server) registerImpersonationRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "impersonate-user",
+		Method:      http.MethodPost,
+		Path:        "/api/admin/impersonate",
+		Summary:     "Impersonate User",
+		Description: "Issue a short-lived token that acts as another user. " +
+			"The admin identity is recorded in the token and the audit log. Admin only.",
+		Tags:     []string{"Auth"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleImpersonate)
+}
+
+// handleImpersonate handles a request to issue an impersonation token.
+func (s *Server) handleImpersonate(
+	ctx context.Context,
+	input *ImpersonateInput,
+) (*ImpersonateOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can impersonate users")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	token, target, err := s.createImpersonationToken(ctx, admin, input.Body.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ImpersonateOutput{}
+	resp.Body.Type = "Bearer"
+	resp.Body.Token = token
+	resp.Body.ExpiresAt = time.Now().Add(ImpersonationDuration).Unix()
+	resp.Body.User = toSafeUser(target)
+	resp.Body.Impersonator = toSafeUser(admin)
+
+	return resp, nil
+}
+
+// createImpersonationToken signs a token acting as the target user on behalf of the admin.
+func (s *Server) createImpersonationToken(
+	ctx context.Context,
+	admin *models.User,
+	email string,
+) (string, *models.User, error) {
+	if s.isExternalIDPEnabled() {
+		return "", nil, huma.Error400BadRequest(
+			"Impersonation is not available when using an external identity provider",
+		)
+	}
+
+	if getImpersonatorFromContext(ctx) != nil {
+		return "", nil, huma.Error403Forbidden("Cannot impersonate while already impersonating")
+	}
+
+	target, err := s.db.GetUserByEmail(ctx, utils.NormalizeEmail(email))
+	if err != nil {
+		return "", nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if target == nil {
+		return "", nil, huma.Error404NotFound("User not found")
+	}
+
+	if target.Id == admin.Id {
+		return "", nil, huma.Error400BadRequest("Cannot impersonate yourself")
+	}
+
+	if target.Role == models.ADMIN {
+		return "", nil, huma.Error403Forbidden("Admins cannot be impersonated")
+	}
+
+	if target.Disabled {
+		return "", nil, huma.Error403Forbidden("Account is disabled")
+	}
+
+	claims := jwt.MapClaims{
+		"sub":          fmt.Sprintf("%d", target.Id),
+		"email":        target.Email,
+		"name":         target.Name,
+		"role":         target.Role,
+		"tv":           target.TokenVersion,
+		"iss":          s.LocalIssuer,
+		"iat":          time.Now().Unix(),
+		"exp":          time.Now().Add(ImpersonationDuration).Unix(),
+		"act":          map[string]any{"sub": admin.Email},
+		"impersonator": admin.Email,
+	}
+
+	signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return "", nil, huma.Error500InternalServerError("Failed to sign token", err)
+	}
+
+	_ = s.db.CreateLogEntry(
+		ctx,
+		models.LevelWarning,
+		"AUTH",
+		"Impersonation started",
+		fmt.Sprintf("Admin: %s | Target: %s | Expires: %s",
+			admin.Email, target.Email, ImpersonationDuration),
+	)
+
+	return signedToken, target, nil
+}
+
+// resolveImpersonator returns the admin recorded in an impersonation token, or nil if the
+// token is a regular session. The admin must still exist, be enabled and hold the ADMIN role.
+func (s *Server) resolveImpersonator(
+	ctx context.Context,
+	claims jwt.MapClaims,
+) (*models.User, error) {
+	email, ok := claims["impersonator"].(string)
+	if !ok || email == "" {
+		return nil, nil
+	}
+
+	iss, _ := claims.GetIssuer()
+	if s.isExternalIDPEnabled() || iss != s.LocalIssuer {
+		return nil, fmt.Errorf("impersonation claim from untrusted issuer %q", iss)
+	}
+
+	admin, err := s.db.GetUserByEmail(ctx, utils.NormalizeEmail(email))
+	if err != nil {
+		return nil, err
+	}
+
+	if admin == nil || admin.Disabled || admin.Role != models.ADMIN {
+		return nil, fmt.Errorf("impersonator %s is no longer an active admin", email)
+	}
+
+	return admin, nil
+}
+
+// getImpersonatorFromContext retrieves the admin acting on behalf of the current user, if any.
+func getImpersonatorFromContext(ctx context.Context) *models.User {
+	user, ok := ctx.Value(impersonatorContextKey).(*models.User)
+	if !ok {
+		return nil
+	}
+
+	return user
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImpersonationFixture returns the seeded admin and a freshly created writer.
+func newImpersonationFixture(t *testing.T, server *Server) (*models.User, *models.User) {
+	t.Helper()
+
+	admin, err := server.db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+	require.NotNil(t, admin)
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, server.db.CreateUser(context.Background(), writer))
+
+	return admin, writer
+}
+
+func TestHandleImpersonate_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin, writer := newImpersonationFixture(t, server)
+
+	input := &ImpersonateInput{}
+	input.Body.Email = "Writer@Example.com"
+
+	resp, err := server.handleImpersonate(contextWithUser(admin), input)
+	require.NoError(t, err)
+	assert.Equal(t, writer.Email, resp.Body.User.Email)
+	assert.Equal(t, admin.Email, resp.Body.Impersonator.Email)
+
+	claims, err := server.parseJWT(resp.Body.Token)
+	require.NoError(t, err)
+	assert.Equal(t, admin.Email, claims["impersonator"])
+	assert.Equal(t, map[string]any{"sub": admin.Email}, claims["act"])
+
+	var handlerUser, handlerImpersonator *models.User
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerUser = getUserFromContext(r.Context())
+		handlerImpersonator = getImpersonatorFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.Body.Token)
+	rr := httptest.NewRecorder()
+	server.authMiddleware(testHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, handlerUser)
+	require.NotNil(t, handlerImpersonator)
+	assert.Equal(t, writer.Email, handlerUser.Email)
+	assert.Equal(t, admin.Email, handlerImpersonator.Email)
+}
+
+func TestHandleImpersonate_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin, writer := newImpersonationFixture(t, server)
+
+	otherAdmin := &models.User{Name: "Other", Email: "other@example.com", Role: models.ADMIN}
+	require.NoError(t, db.CreateUser(context.Background(), otherAdmin))
+
+	tests := []struct {
+		name   string
+		caller *models.User
+		target string
+		status int
+	}{
+		{"non-admin caller", writer, admin.Email, http.StatusForbidden},
+		{"admin target", admin, otherAdmin.Email, http.StatusForbidden},
+		{"self", admin, admin.Email, http.StatusBadRequest},
+		{"unknown target", admin, "missing@example.com", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &ImpersonateInput{}
+			input.Body.Email = tt.target
+
+			_, err := server.handleImpersonate(contextWithUser(tt.caller), input)
+			require.Error(t, err)
+
+			var humaErr *huma.ErrorModel
+			require.True(t, errors.As(err, &humaErr))
+			assert.Equal(t, tt.status, humaErr.Status)
+		})
+	}
+}
+
+func TestAuthMiddleware_ImpersonatorNoLongerAdmin(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin, writer := newImpersonationFixture(t, server)
+
+	input := &ImpersonateInput{}
+	input.Body.Email = writer.Email
+
+	resp, err := server.handleImpersonate(contextWithUser(admin), input)
+	require.NoError(t, err)
+
+	admin.Role = models.WRITE
+	require.NoError(t, db.UpdateUser(context.Background(), admin, "role"))
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.Body.Token)
+	rr := httptest.NewRecorder()
+	server.strictAuthMiddleware(testHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAuthMiddleware_ForgedImpersonatorIssuer(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin, writer := newImpersonationFixture(t, server)
+
+	claims := jwt.MapClaims{
+		"email":        writer.Email,
+		"iss":          "someone-else",
+		"impersonator": admin.Email,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(server.jwtSecret)
+	require.NoError(t, err)
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	server.strictAuthMiddleware(testHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
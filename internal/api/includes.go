@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"wikilite/pkg/utils"
+)
+
+// maxIncludeDepth is how many levels of nested {{include:slug}} tokens are expanded.
+const maxIncludeDepth = 5
+
+// resolveIncludes replaces the {{include:slug}} tokens in the content of the article with
+// the given slug by the markdown of the referenced articles. Nested includes are expanded
+// up to maxIncludeDepth levels. Missing articles, cycles and includes past the depth limit
+// are replaced by an inline warning instead.
+func (s *Server) resolveIncludes(ctx context.Context, slug string, content string) (string, error) {
+	return s.expandIncludes(ctx, content, []string{slug}, nil)
+}
+
+// resolveIncludesWithWarnings resolves includes like resolveIncludes and also returns a
+// description of each include that was replaced by an inline warning.
+func (s *Server) resolveIncludesWithWarnings(
+	ctx context.Context,
+	slug string,
+	content string,
+) (string, []string, error) {
+	var warnings []string
+
+	resolved, err := s.expandIncludes(ctx, content, []string{slug}, func(warning string) {
+		if !slices.Contains(warnings, warning) {
+			warnings = append(warnings, warning)
+		}
+	})
+
+	return resolved, warnings, err
+}
+
+// expandIncludes resolves the includes in content. stack holds the slugs of the articles
+// currently being expanded, outermost first. When warn is not nil, it is called for every
+// include replaced by an inline warning.
+func (s *Server) expandIncludes(
+	ctx context.Context,
+	content string,
+	stack []string,
+	warn func(string),
+) (string, error) {
+	var resolveErr error
+
+	warning := func(reason string, target string) string {
+		if warn != nil {
+			warn(fmt.Sprintf("%s: %s", reason, target))
+		}
+
+		return includeWarning(reason, target)
+	}
+
+	resolved := utils.ReplaceIncludes(content, func(target string) string {
+		if resolveErr != nil {
+			return ""
+		}
+
+		slug := utils.NormalizeLinkSlug(target)
+		if slug == "" {
+			return warning("Invalid include", target)
+		}
+
+		if slices.Contains(stack, slug) {
+			return warning("Include cycle", slug)
+		}
+
+		if len(stack) > maxIncludeDepth {
+			return warning("Include depth limit reached", slug)
+		}
+
+		article, err := s.db.GetArticleBySlug(ctx, slug)
+		if err != nil {
+			resolveErr = err
+			return ""
+		}
+
+		if article == nil {
+			return warning("Included article not found", slug)
+		}
+
+		expanded, err := s.expandIncludes(ctx, article.Data, append(slices.Clip(stack), slug), warn)
+		if err != nil {
+			resolveErr = err
+			return ""
+		}
+
+		return expanded
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return resolved, nil
+}
+
+// includeWarning returns the inline markdown rendered in place of an include that cannot be resolved.
+func includeWarning(reason string, target string) string {
+	return fmt.Sprintf("**⚠ %s:** `%s`", reason, target)
+}
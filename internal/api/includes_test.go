@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikilite/internal/db"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishArticle creates an article with the given title and publishes content as its first version.
+func publishArticle(t *testing.T, database *db.DB, title string, content string) *PublicArticle {
+	t.Helper()
+
+	ctx := context.Background()
+
+	article, draft, err := database.CreateArticleWithContent(ctx, title, content, "writer@example.com")
+	require.NoError(t, err)
+	require.NoError(t, database.PublishDraft(ctx, draft.Id))
+
+	published, err := database.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+
+	return sanitizeArticle(published, true)
+}
+
+func TestResolveIncludes_Nested(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Footer", "Footer text")
+	publishArticle(t, db, "Sidebar", "Sidebar text\n\n{{include:footer}}")
+	page := publishArticle(t, db, "Page", "# Page\n\n{{include:sidebar}}\n\n{{include:footer}}")
+
+	resolved, err := server.resolveIncludes(context.Background(), page.Slug, page.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "# Page\n\nSidebar text\n\nFooter text\n\nFooter text", resolved)
+}
+
+func TestResolveIncludes_Cycle(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Ping", "Ping {{include:pong}}")
+	publishArticle(t, db, "Pong", "Pong {{include:ping}}")
+	self := publishArticle(t, db, "Self", "Self {{include:self}}")
+
+	resolved, err := server.resolveIncludes(context.Background(), "ping", "Ping {{include:pong}}")
+	require.NoError(t, err)
+	assert.Equal(t, "Ping Pong "+includeWarning("Include cycle", "ping"), resolved)
+
+	resolved, err = server.resolveIncludes(context.Background(), self.Slug, self.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "Self "+includeWarning("Include cycle", "self"), resolved)
+}
+
+func TestResolveIncludes_DepthLimit(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Level 6", "six")
+	publishArticle(t, db, "Level 5", "five {{include:level-6}}")
+	publishArticle(t, db, "Level 4", "four {{include:level-5}}")
+	publishArticle(t, db, "Level 3", "three {{include:level-4}}")
+	publishArticle(t, db, "Level 2", "two {{include:level-3}}")
+	publishArticle(t, db, "Level 1", "one {{include:level-2}}")
+
+	resolved, err := server.resolveIncludes(context.Background(), "root", "root {{include:level-1}}")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"root one two three four five "+includeWarning("Include depth limit reached", "level-6"),
+		resolved,
+	)
+}
+
+func TestGetRenderedHTML_Includes(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Glossary", "**Slug**: the URL name of an article.")
+	page := publishArticle(t, db, "Guide", "# Guide\n\n{{include:glossary}}\n\n{{include:nowhere}}")
+
+	html, err := server.getRenderedHTML(context.Background(), page)
+	require.NoError(t, err)
+	assert.Contains(t, html, "<strong>Slug</strong>: the URL name of an article.")
+	assert.Contains(t, html, "<strong>⚠ Included article not found:</strong> <code>nowhere</code>")
+	assert.NotContains(t, html, "{{include:")
+
+	assert.Zero(t, server.htmlCache.Len(), "pages with includes should not be cached by version")
+
+	publishArticle(t, db, "Nowhere", "Now somewhere")
+
+	html, err = server.getRenderedHTML(context.Background(), page)
+	require.NoError(t, err)
+	assert.Contains(t, html, "Now somewhere")
+}
+
+func TestHandleGetArticleContent_MarkdownIncludes(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Snippet", "Shared snippet")
+	page := publishArticle(t, db, "Host", "Before\n\n{{include:snippet}}\n\nAfter")
+
+	resp, err := server.handleGetArticleContent(
+		context.Background(),
+		&ArticleContentInput{Slug: page.Slug, Format: "md"},
+	)
+	require.NoError(t, err)
+
+	op := &huma.Operation{
+		OperationID: "get-article-content",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/{slug}/content",
+	}
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/api/articles/host/content", nil)
+
+	resp.Body(humatest.NewContext(op, r, w))
+
+	assert.Contains(t, w.Body.String(), "Before\n\nShared snippet\n\nAfter")
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+const (
+	// jsonAPIContentType is the media type of JSON:API documents.
+	jsonAPIContentType = "application/vnd.api+json"
+	// jsonAPIArticlesPath is the path of the JSON:API articles collection.
+	jsonAPIArticlesPath = "/api/jsonapi/articles"
+	jsonAPIArticleType  = "articles"
+)
+
+// JSONAPIArticleAttributes holds the attributes of an article resource object.
+type JSONAPIArticleAttributes struct {
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	Author       *string    `json:"author,omitempty"`
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	Version      int        `json:"version"`
+	LastEditor   *string    `json:"lastEditor,omitempty"`
+	LastEditedAt *time.Time `json:"lastEditedAt,omitempty"`
+}
+
+// JSONAPIArticle is an article wrapped as a JSON:API resource object.
+type JSONAPIArticle struct {
+	Type       string                   `json:"type"`
+	Id         string                   `json:"id"`
+	Attributes JSONAPIArticleAttributes `json:"attributes"`
+}
+
+// JSONAPIMeta holds the top level meta information of a collection document.
+type JSONAPIMeta struct {
+	Total int64 `json:"total"`
+}
+
+// JSONAPILinks holds the pagination links of a collection document.
+type JSONAPILinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// JSONAPIArticleListOutput represents a page of articles as a JSON:API collection document.
+type JSONAPIArticleListOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        struct {
+		Data  []JSONAPIArticle `json:"data"`
+		Meta  JSONAPIMeta      `json:"meta"`
+		Links JSONAPILinks     `json:"links"`
+	}
+}
+
+// registerJSONAPIRoutes registers the JSON:API compatible routes with the API.
+func (s *Server) registerJSONAPIRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-articles-jsonapi",
+		Method:      http.MethodGet,
+		Path:        jsonAPIArticlesPath,
+		Summary:     "List Articles (JSON:API)",
+		Description: "Get the same paginated list of articles as GET /api/articles, " +
+			"formatted as a JSON:API collection document.",
+		Tags: []string{"Articles"},
+	}, s.handleGetArticlesJSONAPI)
+}
+
+// handleGetArticlesJSONAPI handles the request to list articles as a JSON:API document.
+func (s *Server) handleGetArticlesJSONAPI(
+	ctx context.Context,
+	input *ArticlePaginationInput,
+) (*JSONAPIArticleListOutput, error) {
+	list, err := s.handleGetArticles(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	page := list.Body
+
+	resp := &JSONAPIArticleListOutput{ContentType: jsonAPIContentType}
+	resp.Body.Data = make([]JSONAPIArticle, len(page.Articles))
+	resp.Body.Meta.Total = page.Total
+
+	for i, article := range page.Articles {
+		resp.Body.Data[i] = JSONAPIArticle{
+			Type: jsonAPIArticleType,
+			Id:   strconv.Itoa(article.Id),
+			Attributes: JSONAPIArticleAttributes{
+				CreatedAt:    article.CreatedAt,
+				UpdatedAt:    article.UpdatedAt,
+				Author:       article.Author,
+				Title:        article.Title,
+				Slug:         article.Slug,
+				Version:      article.Version,
+				LastEditor:   article.LastEditor,
+				LastEditedAt: article.LastEditedAt,
+			},
+		}
+	}
+
+	resp.Body.Links.Self = jsonAPIPageLink(page.Page, page.Limit, page.Sort, input)
+
+	if int64(page.Page*page.Limit) < page.Total {
+		resp.Body.Links.Next = jsonAPIPageLink(page.Page+1, page.Limit, page.Sort, input)
+	}
+
+	if page.Page > 1 {
+		resp.Body.Links.Prev = jsonAPIPageLink(page.Page-1, page.Limit, page.Sort, input)
+	}
+
+	return resp, nil
+}
+
+// jsonAPIPageLink returns the relative URL of a page of the JSON:API articles collection,
+// keeping the creation date filters of the original request.
+func jsonAPIPageLink(page, limit int, sort string, input *ArticlePaginationInput) string {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("sort", sort)
+
+	if input.CreatedAfter != "" {
+		query.Set("createdAfter", input.CreatedAfter)
+	}
+
+	if input.CreatedBefore != "" {
+		query.Set("createdBefore", input.CreatedBefore)
+	}
+
+	return jsonAPIArticlesPath + "?" + query.Encode()
+}
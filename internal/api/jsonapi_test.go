@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetArticlesJSONAPI(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	for i := range 4 {
+		_, _, err := db.CreateArticleWithDraft(ctx, fmt.Sprintf("Page %d", i), "writer@example.com")
+		require.NoError(t, err)
+	}
+
+	resp, err := server.handleGetArticlesJSONAPI(ctx, &ArticlePaginationInput{Page: 2, Limit: 2, Sort: "title"})
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonAPIContentType, resp.ContentType)
+	assert.Equal(t, int64(5), resp.Body.Meta.Total)
+	require.Len(t, resp.Body.Data, 2)
+
+	first := resp.Body.Data[0]
+	assert.Equal(t, "articles", first.Type)
+	assert.Equal(t, "Page 1", first.Attributes.Title)
+	assert.Equal(t, "page-1", first.Attributes.Slug)
+	assert.Nil(t, first.Attributes.Author, "Author should be nil for non-admin users")
+
+	article, err := db.GetArticleBySlug(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(article.Id), first.Id)
+
+	assert.Equal(t, "/api/jsonapi/articles?limit=2&page=2&sort=title", resp.Body.Links.Self)
+	assert.Equal(t, "/api/jsonapi/articles?limit=2&page=3&sort=title", resp.Body.Links.Next)
+	assert.Equal(t, "/api/jsonapi/articles?limit=2&page=1&sort=title", resp.Body.Links.Prev)
+
+	resp, err = server.handleGetArticlesJSONAPI(ctx, &ArticlePaginationInput{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, resp.Body.Data, 5)
+	assert.Empty(t, resp.Body.Links.Next)
+	assert.Empty(t, resp.Body.Links.Prev)
+}
+
+func TestHandleGetArticlesJSONAPI_Response(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/jsonapi/articles", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, jsonAPIContentType, rr.Header().Get("Content-Type"))
+
+	var doc struct {
+		Data []struct {
+			Type       string         `json:"type"`
+			Id         string         `json:"id"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"data"`
+		Meta  map[string]any    `json:"meta"`
+		Links map[string]string `json:"links"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+
+	require.Len(t, doc.Data, 1)
+	assert.Equal(t, "articles", doc.Data[0].Type)
+	assert.Equal(t, "home", doc.Data[0].Attributes["slug"])
+	assert.NotContains(t, doc.Data[0].Attributes, "id")
+	assert.InDelta(t, 1, doc.Meta["total"], 0)
+	assert.Contains(t, doc.Links, "self")
+}
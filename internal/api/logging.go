@@ -11,8 +11,8 @@ import (
 // LogsPaginationInput represents the input for paginating logs.
 type LogsPaginationInput struct {
 	Level models.LogLevel `doc:"Filter by log level (INFO, ERROR, etc.)" query:"level" required:"false"`
-	Page  int             `doc:"Page number"                             query:"page"                   default:"1"  minimum:"1"`
-	Limit int             `doc:"Items per page"                          query:"limit"                  default:"50" minimum:"1" maximum:"100"`
+	Page  int             `doc:"Page number"                             query:"page"                   default:"1" minimum:"1"`
+	Limit int             `doc:"Items per page (capped by server config)" query:"limit"`
 }
 
 // LogsListOutput represents the output for a list of logs.
@@ -48,15 +48,8 @@ func (s *Server) handleGetLogs(
 		return nil, huma.Error403Forbidden("Only admins can view system logs")
 	}
 
-	if input.Page < 1 {
-		input.Page = 1
-	}
-
-	if input.Limit < 1 {
-		input.Limit = 50
-	}
-
-	offset := (input.Page - 1) * input.Limit
+	var offset int
+	input.Page, input.Limit, offset = s.paginate(input.Page, input.Limit)
 
 	logs, total, err := s.db.GetLogs(ctx, input.Limit, offset, input.Level)
 	if err != nil {
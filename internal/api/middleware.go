@@ -2,14 +2,15 @@ package api
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -64,14 +65,48 @@ func (s *Server) LoggerMiddleware(next http.Handler) http.Handler {
 		if user != nil {
 			userLog = user.Email
 		}
+		if impersonator := getImpersonatorFromContext(r.Context()); impersonator != nil {
+			userLog = fmt.Sprintf("%s (impersonated by %s)", userLog, impersonator.Email)
+		}
 		message := fmt.Sprintf("%s %s - %d", r.Method, r.URL.Path, rw.status)
 		data := fmt.Sprintf("User: %s | Duration: %s | IP: %s | UserAgent: %s",
-			userLog, duration, r.RemoteAddr, r.UserAgent())
+			userLog, duration, s.clientIP(r), r.UserAgent())
 
 		_ = s.db.CreateLogEntry(context.Background(), level, "API", message, data)
 	})
 }
 
+// errRequestTooLarge returns the 413 error for a request body over limit bytes.
+func errRequestTooLarge(limit int64) huma.StatusError {
+	return huma.NewError(
+		http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("Request body exceeds the maximum size of %d bytes", limit),
+	)
+}
+
+// bodyLimitMiddleware caps every request body at maxRequestBodySize. Requests declaring a
+// larger Content-Length are rejected with 413 up front; otherwise reads past the limit fail
+// with an *http.MaxBytesError.
+func (s *Server) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > s.maxRequestBodySize {
+			writeStatusError(w, errRequestTooLarge(s.maxRequestBodySize))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBodySize)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeStatusError writes err as an RFC 9457 problem response, matching the API's error format.
+func writeStatusError(w http.ResponseWriter, err huma.StatusError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(err.GetStatus())
+	_ = json.NewEncoder(w).Encode(err)
+}
+
 // authMiddleware checks for a Bearer token, validates it, and sets the user in context.
 // It is "soft" authentication: if no token or invalid token, it proceeds with user=nil.
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
@@ -125,20 +160,48 @@ func (s *Server) authMiddlewareWithOptions(next http.Handler, strict bool) http.
 			}
 		}
 
+		if strings.HasPrefix(tokenString, utils.ApiTokenPrefix) {
+			user, token, err := s.resolveApiToken(r.Context(), tokenString)
+			if err != nil {
+				fail("Invalid or expired token")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userContextKey, user)
+			ctx = context.WithValue(ctx, apiTokenContextKey, token)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		if tokenString != "" {
 			if len(tokenString) < 10 || strings.Contains(tokenString, " ") {
 				fail("Invalid token format")
 				return
 			}
 
-			user, err := s.validateToken(r.Context(), tokenString)
+			claims, err := s.parseJWT(tokenString)
+			if err != nil {
+				fail("Invalid or expired token")
+				return
+			}
+
+			user, err := s.resolveUser(r.Context(), claims)
 			if err != nil {
 				fail("Invalid or expired token")
 				return
 			}
 
+			impersonator, err := s.resolveImpersonator(r.Context(), claims)
+			if err != nil {
+				fail("Invalid impersonation token")
+				return
+			}
+
 			if user != nil {
 				ctx := context.WithValue(r.Context(), userContextKey, user)
+				if impersonator != nil {
+					ctx = context.WithValue(ctx, impersonatorContextKey, impersonator)
+				}
 				r = r.WithContext(ctx)
 			}
 		} else if strict {
@@ -198,13 +261,18 @@ func (s *Server) validateToken(ctx context.Context, tokenString string) (*models
 		return nil, err
 	}
 
+	return s.resolveUser(ctx, claims)
+}
+
+// resolveUser resolves the User from the DB for already validated claims.
+func (s *Server) resolveUser(ctx context.Context, claims jwt.MapClaims) (*models.User, error) {
 	email := s.extractEmailFromClaims(claims)
 	if email == "" {
 		return nil, fmt.Errorf("email claim is empty (checked: %s)", s.jwtEmailClaim)
 	}
 
 	user, err := s.db.GetUserByEmail(ctx, email)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	if err != nil {
 		return nil, err
 	}
 
@@ -216,6 +284,14 @@ func (s *Server) validateToken(ctx context.Context, tokenString string) (*models
 		return nil, fmt.Errorf("user account is disabled")
 	}
 
+	iss, _ := claims.GetIssuer()
+	if !s.isExternalIDPEnabled() && iss == s.LocalIssuer {
+		tokenVersion, _ := claims["tv"].(float64)
+		if int(tokenVersion) < user.TokenVersion {
+			return nil, fmt.Errorf("token was issued before the last password change")
+		}
+	}
+
 	return user, nil
 }
 
@@ -223,25 +299,25 @@ func (s *Server) validateToken(ctx context.Context, tokenString string) (*models
 func (s *Server) extractEmailFromClaims(claims jwt.MapClaims) string {
 	if s.jwtEmailClaim != "" {
 		if v, ok := claims[s.jwtEmailClaim].(string); ok {
-			return v
+			return utils.NormalizeEmail(v)
 		}
 		return ""
 	}
 
 	if v, ok := claims["email"].(string); ok {
-		return v
+		return utils.NormalizeEmail(v)
 	}
 
 	for k, v := range claims {
 		if strings.HasSuffix(k, "email") {
 			if strVal, ok := v.(string); ok {
-				return strVal
+				return utils.NormalizeEmail(strVal)
 			}
 		}
 	}
 
 	if v, ok := claims["sub"].(string); ok {
-		return v
+		return utils.NormalizeEmail(v)
 	}
 
 	return ""
@@ -270,6 +346,8 @@ func (s *Server) createExternalUser(
 		return nil, fmt.Errorf("failed to provision new user: %w", err)
 	}
 
+	s.emitWebhook(models.EventUserCreated, toSafeUser(newUser))
+
 	return newUser, nil
 }
 
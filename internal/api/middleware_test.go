@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 	"wikilite/pkg/models"
@@ -501,3 +504,69 @@ func TestExtractNameFromClaims(t *testing.T) {
 		})
 	}
 }
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.maxRequestBodySize = 1024
+
+	var readErr error
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := server.bodyLimitMiddleware(testHandler)
+
+	t.Run("below limit", func(t *testing.T) {
+		readErr = nil
+		req := httptest.NewRequest("POST", "/api/articles", strings.NewReader(strings.Repeat("a", 1024)))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NoError(t, readErr)
+	})
+
+	t.Run("declared length above limit", func(t *testing.T) {
+		readErr = nil
+		req := httptest.NewRequest("POST", "/api/articles", strings.NewReader(strings.Repeat("a", 1025)))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), "maximum size of 1024 bytes")
+	})
+
+	t.Run("streamed body above limit", func(t *testing.T) {
+		readErr = nil
+		req := httptest.NewRequest("POST", "/api/articles", strings.NewReader(strings.Repeat("a", 2048)))
+		req.ContentLength = -1
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var maxBytesErr *http.MaxBytesError
+		require.True(t, errors.As(readErr, &maxBytesErr))
+		assert.Equal(t, int64(1024), maxBytesErr.Limit)
+	})
+}
+
+func TestNewServer_RequestBodyLimitDefaults(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	assert.Equal(t, int64(DefaultMaxRequestBodySize), server.maxRequestBodySize)
+	assert.Equal(t, int64(DefaultMaxMultipartMemory), server.maxMultipartMemory)
+
+	big, err := NewServer(ServerConfig{
+		Database:       db,
+		JwtSecret:      "test-secret",
+		WikiName:       "Test Wiki",
+		MaxContentSize: 8 << 20,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = big.Close() })
+
+	assert.Equal(t, big.maxBodyBytes(), big.maxRequestBodySize,
+		"the default limit should leave room for the largest allowed article")
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// maxBatchOTPRemovals is the maximum number of users in a single batch OTP removal.
+const maxBatchOTPRemovals = 100
+
+// UserOTPStatus reports whether a user has two-factor authentication enabled.
+type UserOTPStatus struct {
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Id         int    `json:"id"`
+	IsExternal bool   `json:"isExternal"`
+	Disabled   bool   `json:"disabled"`
+	OTPEnabled bool   `json:"otpEnabled"`
+}
+
+// OTPStatusOutput represents the output of the paginated OTP status listing.
+type OTPStatusOutput struct {
+	Body struct {
+		Users []*UserOTPStatus `json:"users"`
+		Total int64            `json:"total"`
+		Page  int              `json:"page"`
+		Limit int              `json:"limit"`
+	}
+}
+
+// BatchOTPRemoveInput represents the input for removing OTP from several users.
+type BatchOTPRemoveInput struct {
+	Body struct {
+		Emails []string `json:"emails" maxItems:"100" minItems:"1" required:"true"`
+	}
+}
+
+// OTPRemoveResult is the outcome of removing OTP for a single user in a batch.
+type OTPRemoveResult struct {
+	Email   string `json:"email"`
+	Error   string `json:"error,omitempty"`
+	Removed bool   `json:"removed"`
+}
+
+// BatchOTPRemoveOutput represents the output of a batch OTP removal.
+type BatchOTPRemoveOutput struct {
+	Body struct {
+		Results []*OTPRemoveResult `json:"results"`
+	}
+}
+
+// registerOTPAdminRoutes registers the admin OTP management routes with the API.
+func (s *Server) registerOTPAdminRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-otp-status",
+		Method:      http.MethodGet,
+		Path:        "/api/admin/otp-status",
+		Summary:     "List OTP Status",
+		Description: "List a page of users and whether they have two-factor authentication " +
+			"enabled. Admin only.",
+		Tags:     []string{"Auth"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleListOTPStatus)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "batch-remove-otp",
+		Method:      http.MethodPost,
+		Path:        "/api/admin/otp-remove",
+		Summary:     "Batch Remove OTP",
+		Description: "Remove two-factor authentication from several users, reporting the " +
+			"outcome for each. Every removal is recorded in the logs. Admin only.",
+		Tags:     []string{"Auth"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleBatchRemoveOTP)
+}
+
+// handleListOTPStatus handles listing the OTP status of a page of users.
+func (s *Server) handleListOTPStatus(
+	ctx context.Context,
+	input *UserPaginationInput,
+) (*OTPStatusOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can view OTP status")
+	}
+
+	var offset int
+	input.Page, input.Limit, offset = s.paginate(input.Page, input.Limit)
+
+	users, total, err := s.db.GetUsers(ctx, input.Limit, offset)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	resp := &OTPStatusOutput{}
+	resp.Body.Users = make([]*UserOTPStatus, len(users))
+
+	for i, u := range users {
+		resp.Body.Users[i] = &UserOTPStatus{
+			Id:         u.Id,
+			Name:       u.Name,
+			Email:      u.Email,
+			IsExternal: u.IsExternal,
+			Disabled:   u.Disabled,
+			OTPEnabled: u.OTPSecret != "",
+		}
+	}
+
+	resp.Body.Total = total
+	resp.Body.Page = input.Page
+	resp.Body.Limit = input.Limit
+
+	return resp, nil
+}
+
+// handleBatchRemoveOTP handles removing OTP from a list of users.
+// A failure for one user does not stop the others from being processed.
+func (s *Server) handleBatchRemoveOTP(
+	ctx context.Context,
+	input *BatchOTPRemoveInput,
+) (*BatchOTPRemoveOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can remove OTP for other users")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Body.Emails) > maxBatchOTPRemovals {
+		return nil, huma.Error400BadRequest("Too many users in a single request")
+	}
+
+	var emails []string
+	for _, email := range input.Body.Emails {
+		email = utils.NormalizeEmail(email)
+		if !slices.Contains(emails, email) {
+			emails = append(emails, email)
+		}
+	}
+
+	resp := &BatchOTPRemoveOutput{}
+	resp.Body.Results = make([]*OTPRemoveResult, len(emails))
+
+	for i, email := range emails {
+		result := &OTPRemoveResult{Email: email}
+		resp.Body.Results[i] = result
+
+		targetUser, err := s.db.GetUserByEmail(ctx, email)
+		if err != nil {
+			result.Error = "Database error"
+			continue
+		}
+
+		if targetUser == nil {
+			result.Error = "User not found"
+			continue
+		}
+
+		err = s.removeOTP(ctx, admin, targetUser)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+
+		result.Removed = true
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOTPAdminFixture returns the seeded admin plus one user with OTP and one without.
+func newOTPAdminFixture(t *testing.T, server *Server) (*models.User, *models.User, *models.User) {
+	t.Helper()
+
+	admin, err := server.db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+	require.NotNil(t, admin)
+
+	withOTP := &models.User{
+		Name:      "OTP User",
+		Email:     "otp@example.com",
+		Role:      models.WRITE,
+		OTPSecret: "JBSWY3DPEHPK3PXP",
+	}
+	require.NoError(t, server.db.CreateUser(context.Background(), withOTP))
+	require.NoError(t, server.db.CreateBackupCode(context.Background(), &models.BackupCode{
+		UserId: withOTP.Id,
+		Code:   "AAAA-BBBB",
+	}))
+
+	withoutOTP := &models.User{Name: "Plain User", Email: "plain@example.com", Role: models.WRITE}
+	require.NoError(t, server.db.CreateUser(context.Background(), withoutOTP))
+
+	return admin, withOTP, withoutOTP
+}
+
+func TestHandleListOTPStatus(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin, withOTP, withoutOTP := newOTPAdminFixture(t, server)
+
+	resp, err := server.handleListOTPStatus(contextWithUser(admin), &UserPaginationInput{Page: 1})
+	require.NoError(t, err)
+
+	status := make(map[string]bool)
+	for _, u := range resp.Body.Users {
+		status[u.Email] = u.OTPEnabled
+	}
+
+	assert.Len(t, status, 3)
+	assert.True(t, status[withOTP.Email])
+	assert.False(t, status[withoutOTP.Email])
+	assert.False(t, status[admin.Email])
+	assert.Equal(t, int64(3), resp.Body.Total)
+
+	resp, err = server.handleListOTPStatus(contextWithUser(admin), &UserPaginationInput{Page: 2, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Users, 1)
+	assert.Equal(t, withoutOTP.Email, resp.Body.Users[0].Email, "users are paged in email order")
+	assert.Equal(t, int64(3), resp.Body.Total)
+
+	_, err = server.handleListOTPStatus(contextWithUser(withoutOTP), &UserPaginationInput{Page: 1})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+}
+
+func TestHandleBatchRemoveOTP(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin, withOTP, withoutOTP := newOTPAdminFixture(t, server)
+
+	input := &BatchOTPRemoveInput{}
+	input.Body.Emails = []string{
+		"OTP@example.com",
+		withOTP.Email,
+		withoutOTP.Email,
+		"missing@example.com",
+	}
+
+	resp, err := server.handleBatchRemoveOTP(contextWithUser(admin), input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Results, 3, "duplicate emails should be processed once")
+
+	assert.Equal(t, withOTP.Email, resp.Body.Results[0].Email)
+	assert.True(t, resp.Body.Results[0].Removed)
+	assert.Empty(t, resp.Body.Results[0].Error)
+
+	assert.False(t, resp.Body.Results[1].Removed)
+	assert.Equal(t, "User does not have OTP enabled", resp.Body.Results[1].Error)
+
+	assert.False(t, resp.Body.Results[2].Removed)
+	assert.Equal(t, "User not found", resp.Body.Results[2].Error)
+
+	updated, err := db.GetUserByEmail(context.Background(), withOTP.Email)
+	require.NoError(t, err)
+	assert.Empty(t, updated.OTPSecret)
+
+	code, err := db.GetBackupCodeByCode(context.Background(), "AAAA-BBBB")
+	require.NoError(t, err)
+	assert.Nil(t, code, "backup codes should be deleted with the OTP secret")
+}
+
+func TestHandleBatchRemoveOTP_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	_, withOTP, withoutOTP := newOTPAdminFixture(t, server)
+
+	input := &BatchOTPRemoveInput{}
+	input.Body.Emails = []string{withOTP.Email}
+
+	_, err := server.handleBatchRemoveOTP(contextWithUser(withoutOTP), input)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	unchanged, err := db.GetUserByEmail(context.Background(), withOTP.Email)
+	require.NoError(t, err)
+	assert.NotEmpty(t, unchanged.OTPSecret)
+}
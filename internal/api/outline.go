@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ArticleOutlineInput represents the input for fetching the link outline of an article.
+type ArticleOutlineInput struct {
+	Slug  string `doc:"The URL slug of the root article" path:"slug"`
+	Depth int    `default:"3"                            doc:"How many levels of links to follow" maximum:"10" minimum:"1" query:"depth"`
+}
+
+// OutlineNode is an article in an outline, with the articles it links to as children.
+type OutlineNode struct {
+	Title    string         `json:"title"`
+	Slug     string         `json:"slug"`
+	Children []*OutlineNode `json:"children,omitempty"`
+}
+
+// ArticleOutlineOutput represents the output for fetching the link outline of an article.
+type ArticleOutlineOutput struct {
+	Body *OutlineNode
+}
+
+// registerOutlineRoutes registers the article outline routes with the API.
+func (s *Server) registerOutlineRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-article-outline",
+		Method:      http.MethodGet,
+		Path:        "/api/articles/{slug}/outline",
+		Summary:     "Get Article Outline",
+		Description: "Get the tree of articles reachable through links from an article, for " +
+			"hierarchical navigation from an index page. Each article appears once, at the " +
+			"shallowest level it is reached, so link cycles are not followed.",
+		Tags: []string{"Articles"},
+	}, s.handleGetArticleOutline)
+}
+
+// handleGetArticleOutline handles the request to get the forward-link tree of an article.
+func (s *Server) handleGetArticleOutline(
+	ctx context.Context,
+	input *ArticleOutlineInput,
+) (*ArticleOutlineOutput, error) {
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	root := &OutlineNode{Title: article.Title, Slug: article.Slug}
+	visited := map[int]bool{article.Id: true}
+
+	// Expand level by level so an article reached by several paths sits at its shallowest
+	// position, and is never expanded twice.
+	level := map[int]*OutlineNode{article.Id: root}
+	order := []int{article.Id}
+
+	for depth := 0; depth < input.Depth && len(order) > 0; depth++ {
+		nextLevel := make(map[int]*OutlineNode)
+		var nextOrder []int
+
+		for _, id := range order {
+			linked, err := s.db.GetForwardLinks(ctx, id)
+			if err != nil {
+				return nil, huma.Error500InternalServerError("Database error", err)
+			}
+
+			parent := level[id]
+
+			for _, target := range linked {
+				if visited[target.Id] {
+					continue
+				}
+
+				visited[target.Id] = true
+
+				child := &OutlineNode{Title: target.Title, Slug: target.Slug}
+				parent.Children = append(parent.Children, child)
+				nextLevel[target.Id] = child
+				nextOrder = append(nextOrder, target.Id)
+			}
+		}
+
+		level, order = nextLevel, nextOrder
+	}
+
+	return &ArticleOutlineOutput{Body: root}, nil
+}
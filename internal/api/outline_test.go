@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetArticleOutline(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	publishArticle(t, db, "Glossary", "Terms")
+	publishArticle(t, db, "Setup", "See the [glossary](/wiki/glossary)")
+	publishArticle(t, db, "Usage", "After [setup](/wiki/setup), check the [glossary](/wiki/glossary)")
+	index := publishArticle(t, db, "Index", "[Usage](/wiki/usage) and [Setup](/wiki/setup)")
+
+	// Link the glossary back to the index to close a cycle.
+	glossary, err := db.GetArticleBySlug(ctx, "glossary")
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(ctx, glossary.Id, "Terms, back to the [index](/wiki/index)", "writer@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	resp, err := server.handleGetArticleOutline(ctx, &ArticleOutlineInput{Slug: index.Slug, Depth: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, &OutlineNode{
+		Title: "Index",
+		Slug:  "index",
+		Children: []*OutlineNode{
+			{Title: "Setup", Slug: "setup", Children: []*OutlineNode{
+				{Title: "Glossary", Slug: "glossary"},
+			}},
+			{Title: "Usage", Slug: "usage"},
+		},
+	}, resp.Body)
+
+	resp, err = server.handleGetArticleOutline(ctx, &ArticleOutlineInput{Slug: index.Slug, Depth: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Children, 2)
+	assert.Empty(t, resp.Body.Children[0].Children)
+
+	resp, err = server.handleGetArticleOutline(ctx, &ArticleOutlineInput{Slug: "glossary", Depth: 10})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Children, 1)
+	assert.Equal(t, "index", resp.Body.Children[0].Slug)
+	assert.Len(t, resp.Body.Children[0].Children, 2)
+
+	_, err = server.handleGetArticleOutline(ctx, &ArticleOutlineInput{Slug: "missing", Depth: 3})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestGetArticleOutline_HTTP(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Setup", "Steps")
+	publishArticle(t, db, "Index", "[Setup](/wiki/setup)")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/index/outline", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var outline OutlineNode
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &outline))
+	require.Len(t, outline.Children, 1)
+	assert.Equal(t, "setup", outline.Children[0].Slug)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/articles/index/outline?depth=11", nil)
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
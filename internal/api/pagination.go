@@ -0,0 +1,18 @@
+package api
+
+// paginate normalizes the requested page and limit against the server's pagination settings.
+// Pages below 1 become 1, a limit below 1 falls back to the default page size, and a limit
+// above the maximum is clamped. It returns the normalized page, limit, and the row offset.
+func (s *Server) paginate(page, limit int) (int, int, int) {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = s.defaultPageSize
+	}
+
+	limit = min(limit, s.maxPageSize)
+
+	return page, limit, (page - 1) * limit
+}
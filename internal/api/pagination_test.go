@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	server := &Server{defaultPageSize: 20, maxPageSize: 100}
+
+	testCases := []struct {
+		name           string
+		page           int
+		limit          int
+		expectedPage   int
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{name: "within bounds", page: 2, limit: 10, expectedPage: 2, expectedLimit: 10, expectedOffset: 10},
+		{name: "limit above max", page: 1, limit: 500, expectedPage: 1, expectedLimit: 100, expectedOffset: 0},
+		{name: "limit below min", page: 3, limit: 0, expectedPage: 3, expectedLimit: 20, expectedOffset: 40},
+		{name: "negative limit", page: 1, limit: -5, expectedPage: 1, expectedLimit: 20, expectedOffset: 0},
+		{name: "page below min", page: 0, limit: 10, expectedPage: 1, expectedLimit: 10, expectedOffset: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, limit, offset := server.paginate(tc.page, tc.limit)
+			assert.Equal(t, tc.expectedPage, page)
+			assert.Equal(t, tc.expectedLimit, limit)
+			assert.Equal(t, tc.expectedOffset, offset)
+		})
+	}
+}
+
+func TestNewServer_PaginationConfig(t *testing.T) {
+	database := newTestDB(t)
+
+	server, err := NewServer(ServerConfig{
+		Database:        database,
+		JwtSecret:       "test-secret",
+		WikiName:        "Test Wiki",
+		DefaultPageSize: 500,
+		MaxPageSize:     50,
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	assert.Equal(t, 50, server.maxPageSize)
+	assert.Equal(t, 50, server.defaultPageSize, "default page size should not exceed the maximum")
+
+	defaults := newTestServer(t, database)
+	assert.Equal(t, DefaultPageSize, defaults.defaultPageSize)
+	assert.Equal(t, DefaultMaxPageSize, defaults.maxPageSize)
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"wikilite/internal/cache"
 	"wikilite/internal/plugin"
 	"wikilite/pkg/models"
 
@@ -26,6 +28,26 @@ type PluginActionOutput struct {
 	Body any `json:"body"`
 }
 
+// PluginStorageListInput defines the input for listing a plugin's stored keys.
+type PluginStorageListInput struct {
+	PluginID string `doc:"The unique ID of the plugin"     path:"pluginID"`
+	Prefix   string `doc:"Only list keys with this prefix"                   query:"prefix" required:"false"`
+}
+
+// PluginStorageListOutput defines the output of a plugin storage listing.
+type PluginStorageListOutput struct {
+	Body struct {
+		PluginID string   `json:"pluginId"`
+		Keys     []string `json:"keys"`
+	}
+}
+
+// PluginStorageDeleteInput defines the input for deleting a key from a plugin's storage.
+type PluginStorageDeleteInput struct {
+	PluginID string `doc:"The unique ID of the plugin" path:"pluginID"`
+	Key      string `doc:"The key to delete"           path:"key"`
+}
+
 // executePlugins executes all plugins for a given hook.
 func executePlugins(
 	ctx context.Context,
@@ -54,12 +76,23 @@ func executePlugins(
 }
 
 // registerPluginRoutes registers routes specifically for plugins to receive data.
-func (s *Server) registerPluginRoutes(pluginPath, pluginStoragePath, jsPkgsPath string) error {
+func (s *Server) registerPluginRoutes(
+	pluginPath, pluginStoragePath, jsPkgsPath string,
+	limits plugin.Limits,
+	cacheConfig cache.Config,
+) error {
 	if pluginStoragePath == "" {
 		pluginStoragePath = "plugin_storage"
 	}
 
-	pluginManger, err := plugin.NewManager(pluginStoragePath, pluginPath, jsPkgsPath)
+	pluginManger, err := plugin.NewManager(
+		pluginStoragePath,
+		pluginPath,
+		jsPkgsPath,
+		limits,
+		cacheConfig,
+		s.db.CreateLogEntry,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to initialize plugin manager: %w", err)
 	}
@@ -76,6 +109,27 @@ func (s *Server) registerPluginRoutes(pluginPath, pluginStoragePath, jsPkgsPath
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handlePluginAction)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-plugin-storage",
+		Method:      http.MethodGet,
+		Path:        "/api/plugins/{pluginID}/storage",
+		Summary:     "List Plugin Storage",
+		Description: "List the keys a plugin has stored, optionally filtered by prefix. " +
+			"Works for plugins that are no longer installed. Admin only.",
+		Tags:     []string{"Plugins"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleListPluginStorage)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "delete-plugin-storage-key",
+		Method:      http.MethodDelete,
+		Path:        "/api/plugins/{pluginID}/storage/{key}",
+		Summary:     "Delete Plugin Storage Key",
+		Description: "Delete a single key from a plugin's storage. Admin only.",
+		Tags:        []string{"Plugins"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleDeletePluginStorageKey)
+
 	return nil
 }
 
@@ -86,6 +140,11 @@ func (s *Server) handlePluginAction(
 ) (*PluginActionOutput, error) {
 	user := getUserFromContext(ctx)
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	payloadBytes, err := json.Marshal(input.Body)
 	if err != nil {
 		return nil, huma.Error400BadRequest("Invalid JSON body")
@@ -128,7 +187,97 @@ func (s *Server) handlePluginAction(
 	return resp, nil
 }
 
+// pluginPipelineErrors runs content through the plugin pipeline of a hook and returns the
+// errors thrown by plugins. Unlike executePlugins, the errors are not logged.
+func (s *Server) pluginPipelineErrors(
+	hook string,
+	content string,
+	pluginCtx map[string]any,
+) ([]DraftPluginError, error) {
+	_, pluginErrs, err := s.PluginManager.ExecutePipeline(hook, content, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]DraftPluginError, len(pluginErrs))
+	for i, pluginErr := range pluginErrs {
+		errs[i] = DraftPluginError{
+			PluginID: pluginErr.PluginID,
+			Hook:     pluginErr.Hook,
+			Error:    pluginErr.Error,
+		}
+	}
+
+	return errs, nil
+}
+
 // hasActivePlugins checks if the server has an active plugin manager with plugins.
 func (s *Server) hasActivePlugins() bool {
 	return s.PluginManager != nil && s.PluginManager.HasPlugins()
 }
+
+// handleListPluginStorage handles listing the keys in a plugin's storage.
+func (s *Server) handleListPluginStorage(
+	ctx context.Context,
+	input *PluginStorageListInput,
+) (*PluginStorageListOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can inspect plugin storage")
+	}
+
+	keys, err := s.PluginManager.Store.List(input.PluginID, input.Prefix)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to list plugin storage", err)
+	}
+
+	if keys == nil {
+		keys = []string{}
+	}
+
+	resp := &PluginStorageListOutput{}
+	resp.Body.PluginID = input.PluginID
+	resp.Body.Keys = keys
+
+	return resp, nil
+}
+
+// handleDeletePluginStorageKey handles removing a key from a plugin's storage.
+func (s *Server) handleDeletePluginStorageKey(
+	ctx context.Context,
+	input *PluginStorageDeleteInput,
+) (*struct{ Status int }, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can modify plugin storage")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.PluginManager.Store.List(input.PluginID, input.Key)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to read plugin storage", err)
+	}
+
+	if !slices.Contains(keys, input.Key) {
+		return nil, huma.Error404NotFound("Key not found")
+	}
+
+	err = s.PluginManager.Store.Delete(input.PluginID, input.Key)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete plugin storage key", err)
+	}
+
+	_ = s.db.CreateLogEntry(
+		ctx,
+		models.LevelWarning,
+		"PLUGIN",
+		"Plugin storage key deleted",
+		fmt.Sprintf("Admin: %s | Plugin: %s | Key: %s", admin.Email, input.PluginID, input.Key),
+	)
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
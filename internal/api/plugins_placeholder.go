@@ -4,6 +4,7 @@ package api
 
 import (
 	"context"
+	"wikilite/internal/cache"
 	"wikilite/internal/plugin"
 	"wikilite/pkg/models"
 )
@@ -25,7 +26,16 @@ func (s *Server) hasActivePlugins() bool {
 	return false
 }
 
+// pluginPipelineErrors is a placeholder method for when the plugin system is not built.
+func (s *Server) pluginPipelineErrors(_ string, _ string, _ map[string]any) ([]DraftPluginError, error) {
+	return nil, nil
+}
+
 // registerPluginRoutes is a placeholder method for when the plugin system is not built.
-func (s *Server) registerPluginRoutes(pluginPath, pluginStoragePath, jsPkgsPath string) error {
+func (s *Server) registerPluginRoutes(
+	pluginPath, pluginStoragePath, jsPkgsPath string,
+	limits plugin.Limits,
+	cacheConfig cache.Config,
+) error {
 	return nil
 }
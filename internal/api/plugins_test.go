@@ -16,6 +16,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"wikilite/internal/cache"
+	"wikilite/internal/plugin"
 	"wikilite/pkg/models"
 )
 
@@ -114,7 +116,7 @@ func TestRegisterPluginRoutes_NilPluginManager(t *testing.T) {
 	tempPluginDir := t.TempDir()
 	tempStoragePath := t.TempDir() + "/plugin_storage.db"
 
-	err := server.registerPluginRoutes(tempPluginDir, tempStoragePath, "")
+	err := server.registerPluginRoutes(tempPluginDir, tempStoragePath, "", plugin.Limits{}, cache.Config{})
 	require.NoError(t, err)
 
 	require.NotNil(t, server.PluginManager)
@@ -128,7 +130,7 @@ func TestRegisterPluginRoutes_NoPlugins(t *testing.T) {
 	tempPluginDir := t.TempDir()
 	tempStoragePath := t.TempDir() + "/plugin_storage.db"
 
-	err := server.registerPluginRoutes(tempPluginDir, tempStoragePath, "")
+	err := server.registerPluginRoutes(tempPluginDir, tempStoragePath, "", plugin.Limits{}, cache.Config{})
 	require.NoError(t, err)
 
 	require.NotNil(t, server.PluginManager)
@@ -196,3 +198,122 @@ func TestExecutePlugins_LoggerFunction(t *testing.T) {
 	assert.Contains(t, loggedMessages[0], "first error")
 	assert.Contains(t, loggedMessages[1], "second error")
 }
+
+func TestHandlePluginStorage(t *testing.T) {
+	testDB := newTestDB(t)
+	server := newTestServerWithPlugins(t, testDB, t.TempDir())
+
+	admin, err := testDB.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	store := server.PluginManager.Store
+	require.NoError(t, store.Set("counter", "views:home", "3"))
+	require.NoError(t, store.Set("counter", "views:about", "1"))
+	require.NoError(t, store.Set("counter", "last-run", "today"))
+
+	ctx := contextWithUser(admin)
+
+	list, err := server.handleListPluginStorage(ctx, &PluginStorageListInput{PluginID: "counter"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"views:home", "views:about", "last-run"}, list.Body.Keys)
+
+	list, err = server.handleListPluginStorage(
+		ctx,
+		&PluginStorageListInput{PluginID: "counter", Prefix: "views:"},
+	)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"views:home", "views:about"}, list.Body.Keys)
+
+	list, err = server.handleListPluginStorage(ctx, &PluginStorageListInput{PluginID: "missing"})
+	require.NoError(t, err)
+	assert.NotNil(t, list.Body.Keys)
+	assert.Empty(t, list.Body.Keys)
+
+	resp, err := server.handleDeletePluginStorageKey(
+		ctx,
+		&PluginStorageDeleteInput{PluginID: "counter", Key: "views:home"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.Status)
+
+	val, err := store.Get("counter", "views:home")
+	require.NoError(t, err)
+	assert.Empty(t, val)
+
+	assert.Eventually(t, func() bool {
+		logs, _, err := testDB.GetLogs(context.Background(), 10, 0, models.LevelWarning)
+		require.NoError(t, err)
+
+		return len(logs) == 1 && logs[0].Source == "PLUGIN"
+	}, time.Second, 10*time.Millisecond, "the deletion is logged")
+
+	_, err = server.handleDeletePluginStorageKey(
+		ctx,
+		&PluginStorageDeleteInput{PluginID: "counter", Key: "views:home"},
+	)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestHandlePluginStorage_Forbidden(t *testing.T) {
+	testDB := newTestDB(t)
+	server := newTestServerWithPlugins(t, testDB, t.TempDir())
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, testDB.CreateUser(context.Background(), writer))
+
+	require.NoError(t, server.PluginManager.Store.Set("counter", "views:home", "3"))
+
+	ctx := contextWithUser(writer)
+
+	_, err := server.handleListPluginStorage(ctx, &PluginStorageListInput{PluginID: "counter"})
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	_, err = server.handleDeletePluginStorageKey(
+		ctx,
+		&PluginStorageDeleteInput{PluginID: "counter", Key: "views:home"},
+	)
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	val, err := server.PluginManager.Store.Get("counter", "views:home")
+	require.NoError(t, err)
+	assert.Equal(t, "3", val)
+}
+
+func TestHandleCheckDraft_PluginErrors(t *testing.T) {
+	testDB := newTestDB(t)
+
+	tempPluginDir := t.TempDir()
+
+	pluginContent := `
+function onArticleRender(content, ctx) {
+	throw new Error("cannot render " + ctx.Slug);
+}
+`
+	err := os.WriteFile(filepath.Join(tempPluginDir, "01-failing.js"), []byte(pluginContent), 0644)
+	require.NoError(t, err)
+
+	server := newTestServerWithPlugins(t, testDB, tempPluginDir)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	_, draft, err := testDB.CreateArticleWithDraft(context.Background(), "Plugin Page", user.Email)
+	require.NoError(t, err)
+
+	content := "See [Nowhere](/wiki/nowhere)."
+	require.NoError(t, testDB.UpdateDraft(context.Background(), draft.Id, content, user.Email, false, nil))
+
+	resp, err := server.handleCheckDraft(contextWithUser(user), &DraftCheckInput{ID: draft.Id})
+	require.NoError(t, err)
+	assert.False(t, resp.Body.OK)
+	assert.Equal(t, []string{"nowhere"}, resp.Body.BrokenLinks)
+	assert.Empty(t, resp.Body.RenderWarnings)
+	require.Len(t, resp.Body.PluginErrors, 1)
+	assert.Equal(t, "onArticleRender", resp.Body.PluginErrors[0].Hook)
+	assert.NotEmpty(t, resp.Body.PluginErrors[0].PluginID)
+	assert.Contains(t, resp.Body.PluginErrors[0].Error, "cannot render plugin-page")
+}
@@ -4,28 +4,114 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 
 	"github.com/jellydator/ttlcache/v3"
 )
 
+// previewExcerptLength is the maximum number of characters in an article preview excerpt.
+const previewExcerptLength = 200
+
+// getRenderedHTML renders an article to sanitized HTML, resolving any {{include:slug}} tokens.
+// Articles with includes bypass the version keyed cache, since the included articles
+// may change without the including article getting a new version.
 func (s *Server) getRenderedHTML(ctx context.Context, article *PublicArticle) (string, error) {
 	key := fmt.Sprintf("%d-%d", article.Id, article.Version)
+	content := article.Data
+	cacheable := !utils.HasIncludes(content)
 
-	item := s.htmlCache.Get(key)
-	if item != nil {
-		return item.Value(), nil
+	if cacheable {
+		item := s.htmlCache.Get(key)
+		if item != nil {
+			return item.Value(), nil
+		}
+	} else {
+		var err error
+
+		content, err = s.resolveIncludes(ctx, article.Slug, content)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	var buf bytes.Buffer
 
-	err := s.renderer.RenderHTML(ctx, &buf, article.Data)
+	err := s.renderer.RenderHTML(ctx, &buf, content)
 	if err != nil {
 		return "", err
 	}
 
 	htmlContent := buf.String()
 
-	s.htmlCache.Set(key, htmlContent, ttlcache.DefaultTTL)
+	if cacheable {
+		s.htmlCache.Set(key, htmlContent, ttlcache.DefaultTTL)
+	}
 
 	return htmlContent, nil
 }
+
+// renderArticleHTML renders an article to HTML and runs it through the onArticleRender
+// plugin pipeline for the current user, producing what the article page shows.
+func (s *Server) renderArticleHTML(ctx context.Context, article *PublicArticle) (string, error) {
+	return s.renderArticleHTMLFor(ctx, article, getUserFromContext(ctx))
+}
+
+// renderArticleHTMLFor renders an article like renderArticleHTML, but runs the plugin
+// pipeline as user, which may be nil for an anonymous reader.
+func (s *Server) renderArticleHTMLFor(
+	ctx context.Context,
+	article *PublicArticle,
+	user *models.User,
+) (string, error) {
+	wikiContent, err := s.getRenderedHTML(ctx, article)
+	if err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	if !s.hasActivePlugins() {
+		return wikiContent, nil
+	}
+
+	pluginCtx := map[string]any{
+		"User": user,
+		"Slug": article.Slug,
+		"Tags": article.Tags,
+	}
+
+	finalBody, err := executePlugins(
+		ctx,
+		s.PluginManager,
+		"onArticleRender",
+		wikiContent,
+		pluginCtx,
+		s.db.CreateLogEntry,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute plugins: %w", err)
+	}
+
+	return finalBody, nil
+}
+
+// getArticlePreview returns the cached preview metadata for an article, building it if needed.
+func (s *Server) getArticlePreview(article *models.Article) *ArticlePreview {
+	key := fmt.Sprintf("%s-%d", article.Slug, article.Version)
+
+	item := s.previewCache.Get(key)
+	if item != nil {
+		return item.Value()
+	}
+
+	preview := &ArticlePreview{
+		Title:       article.Title,
+		Slug:        article.Slug,
+		Excerpt:     s.renderer.Excerpt(article.Data, previewExcerptLength),
+		ReadingTime: s.renderer.ReadingTime(article.Data),
+		Version:     article.Version,
+	}
+
+	s.previewCache.Set(key, preview, ttlcache.DefaultTTL)
+
+	return preview
+}
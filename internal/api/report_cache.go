@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+const (
+	// articleReportTtl is how long the orphan and stub listings are reused before recomputing.
+	articleReportTtl = 60 * time.Second
+	orphansReportKey = "orphans"
+	stubsReportKey   = "stubs"
+)
+
+// ArticleReportInput represents the input for the cached article listings.
+type ArticleReportInput struct {
+	Fresh bool `doc:"Recompute the listing instead of using the cached result" query:"fresh"`
+}
+
+// newArticleReportCache creates the cache holding the orphan and stub listings.
+func newArticleReportCache() *ttlcache.Cache[string, []*models.Article] {
+	return ttlcache.New[string, []*models.Article](
+		ttlcache.WithTTL[string, []*models.Article](articleReportTtl),
+		ttlcache.WithCapacity[string, []*models.Article](2),
+	)
+}
+
+// cachedArticleReport returns the cached listing for key, computing and caching it when it
+// is missing, expired or fresh is set.
+func (s *Server) cachedArticleReport(
+	ctx context.Context,
+	key string,
+	fresh bool,
+	compute func(context.Context) ([]*models.Article, error),
+) ([]*models.Article, error) {
+	if !fresh {
+		if item := s.articleReportCache.Get(key); item != nil {
+			return item.Value(), nil
+		}
+	}
+
+	articles, err := compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.articleReportCache.Set(key, articles, ttlcache.DefaultTTL)
+
+	return articles, nil
+}
+
+// invalidateArticleReports drops the cached listings after an article is published or deleted.
+func (s *Server) invalidateArticleReports() {
+	s.articleReportCache.DeleteAll()
+}
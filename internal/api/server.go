@@ -5,23 +5,43 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"sync"
 	"time"
+	"wikilite/internal/cache"
 	"wikilite/internal/db"
 	"wikilite/internal/markdown"
+	"wikilite/internal/notify"
 	"wikilite/internal/plugin"
+	"wikilite/internal/webhook"
+	"wikilite/pkg/models"
 	"wikilite/pkg/utils"
 
 	"github.com/MicahParks/keyfunc/v3"
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/didip/tollbooth/v8/limiter"
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/pquerna/otp/totp"
 )
 
 const (
 	DefaultWikiName = "WikiLite"
 	DefaultPort     = 8080
-	cacheTtl        = 30 * time.Minute
-	cacheSize       = 1000
+	// DefaultMaxContentSize is the default maximum size of article content in bytes (1 MiB).
+	DefaultMaxContentSize = 1 << 20
+	// DefaultMaxRequestBodySize is the default maximum size of any request body in bytes (8 MiB).
+	DefaultMaxRequestBodySize = 8 << 20
+	// DefaultMaxMultipartMemory is the default number of bytes of a multipart form kept in
+	// memory; the remainder is spooled to temporary files.
+	DefaultMaxMultipartMemory = 32 << 20
+	// DefaultPageSize is the default number of items returned by paginated endpoints.
+	DefaultPageSize = 20
+	// DefaultMaxPageSize is the default upper bound on items per page.
+	DefaultMaxPageSize = 100
+	// DefaultOTPPeriod is the default TOTP time step in seconds.
+	DefaultOTPPeriod = 30
+	// DefaultStubWordCount is the default number of words below which an article is a stub.
+	DefaultStubWordCount = 100
 )
 
 type ServerConfig struct {
@@ -34,31 +54,115 @@ type ServerConfig struct {
 	PluginPath        string
 	PluginStoragePath string
 	JsPkgsPath        string
+	// PluginMemoryLimit is the maximum heap size of each plugin VM in bytes.
+	PluginMemoryLimit int
 	Production        bool
 	TrustProxyHeaders bool
+	TrustedProxyHops  int
 	InsecureCookies   bool
 	Port              int
+	MaxContentSize    int
+	// MaxRequestBodySize caps every request body in bytes. When unset it defaults to
+	// DefaultMaxRequestBodySize, raised if needed to fit MaxContentSize.
+	MaxRequestBodySize int
+	// MaxMultipartMemory is the number of bytes of a multipart form kept in memory.
+	MaxMultipartMemory int
+	DefaultPageSize    int
+	MaxPageSize        int
+	MaxDraftsPerUser   int
+	EmojiShortcodes    bool
+	DefinitionLists    bool
+	Abbreviations      bool
+	HardWraps          bool
+	LazyImages         bool
+	PasswordPolicy     utils.PasswordPolicy
+	// OTPIssuer is the issuer shown in authenticator apps. Defaults to WikiName.
+	OTPIssuer string
+	// OTPPeriod is the TOTP time step in seconds. Defaults to DefaultOTPPeriod.
+	OTPPeriod uint
+	// OTPDigits is the length of TOTP codes, 6 or 8. Defaults to 6.
+	OTPDigits int
+	// OTPAlgorithm is the TOTP hash algorithm: SHA1, SHA256 or SHA512. Defaults to SHA1.
+	OTPAlgorithm string
+	// EndSessionEndpoint is the external IDP's end-session URL. When set together with
+	// JwksURL, logout redirects the browser there so the IDP session ends as well.
+	EndSessionEndpoint string
+	// PostLogoutRedirectURL is where the IDP sends the browser back after logout.
+	PostLogoutRedirectURL string
+	// SSOLoginURL is where the login page sends users to sign in with the external IDP.
+	SSOLoginURL string
+	// StubWordCount is the number of words below which a published article is reported as
+	// a stub. Defaults to DefaultStubWordCount.
+	StubWordCount int
+	// Suggestions lets anyone, including visitors who are not signed in, propose edits
+	// that writers review before they are published.
+	Suggestions bool
+	// ArticleAST exposes the markdown syntax tree of articles as JSON.
+	ArticleAST bool
+	// CustomArticleCode lets admins add CSS and JavaScript to individual article pages.
+	CustomArticleCode bool
+	// WriteRateLimit is the number of article writes (creating articles, saving and
+	// publishing drafts) a non-admin user may make per WriteRateWindow. Zero is unlimited.
+	WriteRateLimit int
+	// AdminWriteRateLimit is the write limit for admins. Zero exempts admins.
+	AdminWriteRateLimit int
+	// WriteRateWindow is the window of the write limits. Defaults to DefaultWriteRateWindow.
+	WriteRateWindow time.Duration
+	// Cache sets the lifetime and capacity of the rendered HTML, preview and plugin output
+	// caches. Unset fields default to those of cache.Default.
+	Cache cache.Config
+	// OTPCache sets the lifetime and capacity of the cache holding OTP enrollments in
+	// progress, which must be confirmed within the TTL. Defaults to cache.DefaultOTP.
+	OTPCache cache.Config
+	// Notifier delivers watch list notifications. Defaults to writing them to the system logs.
+	Notifier notify.Notifier
 }
 
 // Server represents the main application server.
 type Server struct {
-	api               huma.API
-	jwks              keyfunc.Keyfunc
-	db                *db.DB
-	router            *http.ServeMux
-	renderer          *markdown.Renderer
-	articleTemplate   *template.Template
-	compiledTemplates map[string]*template.Template
-	httpServer        *http.Server
-	port              int
+	api                huma.API
+	jwks               keyfunc.Keyfunc
+	db                 *db.DB
+	router             *http.ServeMux
+	renderer           *markdown.Renderer
+	articleTemplate    *template.Template
+	compiledTemplates  map[string]*template.Template
+	httpServer         *http.Server
+	port               int
+	maxContentSize     int
+	maxRequestBodySize int64
+	maxMultipartMemory int64
+	defaultPageSize    int
+	maxPageSize        int
+	maxDraftsPerUser   int
+	stubWordCount      int
+	customArticleCode  bool
+	writeLimiter       *limiter.Limiter
+	adminWriteLimiter  *limiter.Limiter
+	passwordPolicy     utils.PasswordPolicy
+	otpIssuer          string
+	totpOptions        totp.ValidateOpts
 
 	PluginManager *plugin.Manager
 
-	htmlCache      *ttlcache.Cache[string, string]
-	otpCache       *ttlcache.Cache[string, string]
-	jwksURL        string
-	externalIssuer string
-	jwtEmailClaim  string
+	webhooks      *webhook.Dispatcher
+	notifications *notify.Queue
+
+	// apiTokenTouches tracks the background writes recording the last use of API tokens,
+	// and touchingApiTokens the IDs of the tokens being written.
+	apiTokenTouches   sync.WaitGroup
+	touchingApiTokens sync.Map
+
+	htmlCache          *ttlcache.Cache[string, string]
+	previewCache       *ttlcache.Cache[string, *ArticlePreview]
+	otpCache           *ttlcache.Cache[string, string]
+	adminStatsCache    *ttlcache.Cache[string, *AdminStats]
+	articleReportCache *ttlcache.Cache[string, []*models.Article]
+	jwksURL            string
+	externalIssuer     string
+	jwtEmailClaim      string
+	endSessionURL      string
+	ssoLoginURL        string
 
 	WikiName    string
 	LocalIssuer string
@@ -67,6 +171,7 @@ type Server struct {
 
 	production        bool
 	trustProxyHeaders bool
+	trustedProxyHops  int
 	insecureCookies   bool
 }
 
@@ -94,7 +199,60 @@ func NewServer(
 
 	api := humago.New(router, humaConfig)
 
-	mdRenderer := markdown.NewRenderer()
+	mdRenderer := markdown.NewRenderer(
+		markdown.WithEmoji(config.EmojiShortcodes),
+		markdown.WithDefinitionLists(config.DefinitionLists),
+		markdown.WithAbbreviations(config.Abbreviations),
+		markdown.WithHardWraps(config.HardWraps),
+		markdown.WithLazyImages(config.LazyImages),
+	)
+
+	maxContentSize := config.MaxContentSize
+	if maxContentSize <= 0 {
+		maxContentSize = DefaultMaxContentSize
+	}
+
+	maxPageSize := config.MaxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = DefaultMaxPageSize
+	}
+
+	defaultPageSize := config.DefaultPageSize
+	if defaultPageSize <= 0 {
+		defaultPageSize = DefaultPageSize
+	}
+
+	defaultPageSize = min(defaultPageSize, maxPageSize)
+
+	maxMultipartMemory := config.MaxMultipartMemory
+	if maxMultipartMemory <= 0 {
+		maxMultipartMemory = DefaultMaxMultipartMemory
+	}
+
+	trustedProxyHops := config.TrustedProxyHops
+	if trustedProxyHops <= 0 {
+		trustedProxyHops = DefaultTrustedProxyHops
+	}
+
+	stubWordCount := config.StubWordCount
+	if stubWordCount <= 0 {
+		stubWordCount = DefaultStubWordCount
+	}
+
+	otpIssuer := config.OTPIssuer
+	if otpIssuer == "" {
+		otpIssuer = config.WikiName
+	}
+
+	totpOptions, err := newTOTPOptions(config.OTPPeriod, config.OTPDigits, config.OTPAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	endSessionURL, err := newEndSessionURL(config.EndSessionEndpoint, config.PostLogoutRedirectURL)
+	if err != nil {
+		return nil, err
+	}
 
 	tmpl, err := template.New("article").Parse(articleTemplateStr)
 	if err != nil {
@@ -102,21 +260,41 @@ func NewServer(
 	}
 
 	server := &Server{
-		db:                config.Database,
-		router:            router,
-		api:               api,
-		renderer:          mdRenderer,
-		articleTemplate:   tmpl,
-		jwtSecret:         []byte(config.JwtSecret),
-		WikiName:          config.WikiName,
-		LocalIssuer:       localIssuer,
-		jwksURL:           config.JwksURL,
-		externalIssuer:    config.JwtIssuer,
-		jwtEmailClaim:     config.JwtEmailClaim,
-		production:        config.Production,
-		trustProxyHeaders: config.TrustProxyHeaders,
-		insecureCookies:   config.InsecureCookies,
-		port:              config.Port,
+		db:                 config.Database,
+		router:             router,
+		api:                api,
+		renderer:           mdRenderer,
+		articleTemplate:    tmpl,
+		jwtSecret:          []byte(config.JwtSecret),
+		WikiName:           config.WikiName,
+		LocalIssuer:        localIssuer,
+		jwksURL:            config.JwksURL,
+		externalIssuer:     config.JwtIssuer,
+		jwtEmailClaim:      config.JwtEmailClaim,
+		endSessionURL:      endSessionURL,
+		ssoLoginURL:        config.SSOLoginURL,
+		production:         config.Production,
+		trustProxyHeaders:  config.TrustProxyHeaders,
+		trustedProxyHops:   trustedProxyHops,
+		insecureCookies:    config.InsecureCookies,
+		port:               config.Port,
+		maxContentSize:     maxContentSize,
+		maxMultipartMemory: int64(maxMultipartMemory),
+		defaultPageSize:    defaultPageSize,
+		maxPageSize:        maxPageSize,
+		maxDraftsPerUser:   config.MaxDraftsPerUser,
+		stubWordCount:      stubWordCount,
+		customArticleCode:  config.CustomArticleCode,
+		writeLimiter:       newWriteLimiter(config.WriteRateLimit, config.WriteRateWindow),
+		adminWriteLimiter:  newWriteLimiter(config.AdminWriteRateLimit, config.WriteRateWindow),
+		passwordPolicy:     config.PasswordPolicy,
+		otpIssuer:          otpIssuer,
+		totpOptions:        totpOptions,
+	}
+
+	server.maxRequestBodySize = int64(config.MaxRequestBodySize)
+	if server.maxRequestBodySize <= 0 {
+		server.maxRequestBodySize = max(DefaultMaxRequestBodySize, server.maxBodyBytes())
 	}
 
 	if config.JwksURL != "" {
@@ -133,6 +311,32 @@ func NewServer(
 	server.registerDraftRoutes()
 	server.registerLogRoutes()
 	server.registerAuthRoutes()
+	server.registerImpersonationRoutes()
+	server.registerOTPAdminRoutes()
+	server.registerWebhookRoutes()
+	server.registerTemplateRoutes()
+	server.registerActivityRoutes()
+	server.registerAdminStatsRoutes()
+	server.registerWatchRoutes()
+	server.registerApiTokenRoutes()
+	server.registerJSONAPIRoutes()
+	server.registerGraphQLRoutes()
+	server.registerExportRoutes()
+	server.registerOutlineRoutes()
+	server.registerVersionRoutes()
+	server.registerTagRoutes()
+
+	if config.Suggestions {
+		server.registerSuggestionRoutes()
+	}
+
+	if config.ArticleAST {
+		server.registerASTRoutes()
+	}
+
+	if config.CustomArticleCode {
+		server.registerCustomCodeRoutes()
+	}
 
 	err = server.registerFrontendRoutes(router)
 	if err != nil {
@@ -144,26 +348,42 @@ func NewServer(
 			config.PluginPath,
 			config.PluginStoragePath,
 			config.JsPkgsPath,
+			plugin.Limits{MemoryLimit: config.PluginMemoryLimit},
+			config.Cache,
 		)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	htmlCache := ttlcache.New[string, string](
-		ttlcache.WithTTL[string, string](cacheTtl),
-		ttlcache.WithCapacity[string, string](cacheSize),
-	)
+	htmlCache := cache.New[string, string](config.Cache.Or(cache.Default))
 	go htmlCache.Start()
 
-	otpCache := ttlcache.New[string, string](
-		ttlcache.WithTTL[string, string](10*time.Minute),
-		ttlcache.WithCapacity[string, string](1000),
-	)
+	previewCache := cache.New[string, *ArticlePreview](config.Cache.Or(cache.Default))
+	go previewCache.Start()
+
+	otpCache := cache.New[string, string](config.OTPCache.Or(cache.DefaultOTP))
 	go otpCache.Start()
 
+	adminStatsCache := newAdminStatsCache()
+	go adminStatsCache.Start()
+
+	articleReportCache := newArticleReportCache()
+	go articleReportCache.Start()
+
 	server.htmlCache = htmlCache
+	server.previewCache = previewCache
 	server.otpCache = otpCache
+	server.adminStatsCache = adminStatsCache
+	server.articleReportCache = articleReportCache
+	server.webhooks = webhook.NewDispatcher(config.Database, config.Database.CreateLogEntry)
+
+	notifier := config.Notifier
+	if notifier == nil {
+		notifier = notify.NewLogNotifier(config.Database.CreateLogEntry)
+	}
+
+	server.notifications = notify.NewQueue(notifier, config.Database.CreateLogEntry)
 
 	return server, nil
 }
@@ -171,6 +391,8 @@ func NewServer(
 // Start starts the HTTP server.
 func (s *Server) Start() error {
 	handler := s.hardeningMiddleware(s.router)
+	handler = s.compressionMiddleware(handler)
+	handler = s.bodyLimitMiddleware(handler)
 	handler = s.LoggerMiddleware(handler)
 	handler = s.authMiddleware(handler)
 	handler = s.contextMiddleware(handler)
@@ -194,14 +416,36 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 // Close cleans up internal resources like plugins and caches.
 func (s *Server) Close() error {
+	s.apiTokenTouches.Wait()
+
 	if s.htmlCache != nil {
 		s.htmlCache.Stop()
 	}
 
+	if s.previewCache != nil {
+		s.previewCache.Stop()
+	}
+
 	if s.otpCache != nil {
 		s.otpCache.Stop()
 	}
 
+	if s.adminStatsCache != nil {
+		s.adminStatsCache.Stop()
+	}
+
+	if s.articleReportCache != nil {
+		s.articleReportCache.Stop()
+	}
+
+	if s.webhooks != nil {
+		s.webhooks.Close()
+	}
+
+	if s.notifications != nil {
+		s.notifications.Close()
+	}
+
 	if s.PluginManager != nil {
 		err := s.PluginManager.Close()
 		if err != nil {
@@ -1,74 +1,144 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"gopkg.in/yaml.v3"
 )
 
+// ReconstructionDegradedHeader is set on article responses rebuilt from a corrupted history.
+const ReconstructionDegradedHeader = "X-Reconstruction-Degraded"
+
+// negotiateContentFormat picks the article content format ("html" or "md") for an Accept header.
+// The supported media range with the highest quality wins, and HTML wins ties. An empty header
+// accepts anything. ok is false if no supported media type is acceptable.
+func negotiateContentFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return "html", true
+	}
+
+	format := ""
+	bestQuality := 0.0
+
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		quality := parseQuality(params)
+
+		var candidate string
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/markdown", "text/x-markdown":
+			candidate = "md"
+		case "text/html", "text/*", "*/*":
+			candidate = "html"
+		default:
+			continue
+		}
+
+		if quality > bestQuality || (quality == bestQuality && candidate == "html") {
+			format = candidate
+			bestQuality = quality
+		}
+	}
+
+	return format, format != "" && bestQuality > 0
+}
+
+// parseQuality returns the q parameter of an Accept media range's parameters, defaulting to 1.
+func parseQuality(params string) float64 {
+	quality := 1.0
+	for param := range strings.SplitSeq(params, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(name, "q") {
+			q, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				quality = q
+			}
+		}
+	}
+
+	return quality
+}
+
 // streamHTML streams the HTML representation of an article using Server dependencies.
 func (s *Server) streamHTML(article *PublicArticle) *huma.StreamResponse {
 	return &huma.StreamResponse{
 		Body: func(ctx huma.Context) {
 			ctx.SetHeader("Content-Type", "text/html; charset=utf-8")
-			w := ctx.BodyWriter()
+			ctx.SetHeader("Vary", "Accept")
+			setReconstructionHeader(ctx, article)
 
-			wikiContent, err := s.getRenderedHTML(ctx.Context(), article)
-			if err != nil {
-				_, _ = fmt.Fprintf(w, "\n%v", err)
+			writeContent(ctx, s.articleDocument(ctx.Context(), article))
+		},
+	}
+}
 
-				return
-			}
+// articleDocument renders the HTML document of an article for the current user. Errors are
+// written into the document, after whatever was rendered before them.
+func (s *Server) articleDocument(ctx context.Context, article *PublicArticle) []byte {
+	var w bytes.Buffer
 
-			var author string
-			if article.Author != nil {
-				author = *article.Author
-			}
+	wikiContent, err := s.getRenderedHTML(ctx, article)
+	if err != nil {
+		_, _ = fmt.Fprintf(&w, "\n%v", err)
 
-			if s.hasActivePlugins() {
-				pluginCtx := map[string]any{
-					"User": getUserFromContext(ctx.Context()),
-					"Slug": article.Slug,
-				}
-
-				finalBody, err := executePlugins(
-					ctx.Context(),
-					s.PluginManager,
-					"onArticleRender",
-					wikiContent,
-					pluginCtx,
-					s.db.CreateLogEntry,
-				)
-				if err != nil {
-					_, _ = fmt.Fprintf(w, "\n<!-- Error executing plugins: %v -->", err)
-					return
-				}
-
-				wikiContent = finalBody
-			}
+		return w.Bytes()
+	}
 
-			data := struct {
-				Title   string
-				Author  string
-				Content template.HTML
-				Id      int
-				Version int
-			}{
-				Id:      article.Id,
-				Version: article.Version,
-				Title:   article.Title,
-				Author:  author,
-				Content: template.HTML(wikiContent),
-			}
+	var author string
+	if article.Author != nil {
+		author = *article.Author
+	}
 
-			err = s.articleTemplate.Execute(w, data)
-			if err != nil {
-				_, _ = fmt.Fprintf(w, "\n%v", err)
-			}
-		},
+	if s.hasActivePlugins() {
+		pluginCtx := map[string]any{
+			"User": getUserFromContext(ctx),
+			"Slug": article.Slug,
+			"Tags": article.Tags,
+		}
+
+		finalBody, err := executePlugins(
+			ctx,
+			s.PluginManager,
+			"onArticleRender",
+			wikiContent,
+			pluginCtx,
+			s.db.CreateLogEntry,
+		)
+		if err != nil {
+			_, _ = fmt.Fprintf(&w, "\n<!-- Error executing plugins: %v -->", err)
+			return w.Bytes()
+		}
+
+		wikiContent = finalBody
+	}
+
+	data := struct {
+		Title   string
+		Author  string
+		Content template.HTML
+		Id      int
+		Version int
+	}{
+		Id:      article.Id,
+		Version: article.Version,
+		Title:   article.Title,
+		Author:  author,
+		Content: template.HTML(wikiContent),
+	}
+
+	err = s.articleTemplate.Execute(&w, data)
+	if err != nil {
+		_, _ = fmt.Fprintf(&w, "\n%v", err)
 	}
+
+	return w.Bytes()
 }
 
 // streamMarkdown constructs a file with Frontmatter + Content.
@@ -85,6 +155,10 @@ func (s *Server) streamMarkdown(article *PublicArticle) *huma.StreamResponse {
 		metadata["author"] = *article.Author
 	}
 
+	if article.ReconstructionDegraded {
+		metadata["reconstructionDegraded"] = true
+	}
+
 	fm, _ := yaml.Marshal(metadata)
 
 	fullDoc := fmt.Sprintf("---\n%s---\n\n%s", string(fm), article.Data)
@@ -92,8 +166,33 @@ func (s *Server) streamMarkdown(article *PublicArticle) *huma.StreamResponse {
 	return &huma.StreamResponse{
 		Body: func(ctx huma.Context) {
 			ctx.SetHeader("Content-Type", "text/markdown; charset=utf-8")
-			w := ctx.BodyWriter()
-			_, _ = w.Write([]byte(fullDoc))
+			ctx.SetHeader("Vary", "Accept")
+			setReconstructionHeader(ctx, article)
+
+			writeContent(ctx, []byte(fullDoc))
 		},
 	}
 }
+
+// writeContent writes an article content body tagged with an entity tag derived from it,
+// or responds 304 Not Modified when the request's If-None-Match already matches the tag.
+// The tag is computed from the rendered body, so it also changes with included articles,
+// plugin output and the reader's permissions.
+func writeContent(ctx huma.Context, body []byte) {
+	etag := contentETag(body)
+	ctx.SetHeader("ETag", etag)
+
+	if etagMatchesWeak(ctx.Header("If-None-Match"), etag) {
+		ctx.SetStatus(http.StatusNotModified)
+		return
+	}
+
+	_, _ = ctx.BodyWriter().Write(body)
+}
+
+// setReconstructionHeader flags responses whose content is only an approximate reconstruction.
+func setReconstructionHeader(ctx huma.Context, article *PublicArticle) {
+	if article.ReconstructionDegraded {
+		ctx.SetHeader(ReconstructionDegradedHeader, "true")
+	}
+}
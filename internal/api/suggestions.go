@@ -0,0 +1,296 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+	"wikilite/internal/db"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/didip/tollbooth/v8"
+)
+
+// anonymousSuggester is recorded as the author of suggestions made without signing in.
+const anonymousSuggester = "anonymous"
+
+const (
+	// suggestionRate is the sustained number of suggestions allowed per second per client.
+	suggestionRate = 1.0 / 60
+	// suggestionBurst is the number of suggestions a client may make in quick succession.
+	suggestionBurst = 5
+)
+
+// CreateSuggestionInput represents the input for suggesting an edit of an article.
+type CreateSuggestionInput struct {
+	Body struct {
+		Content string `doc:"The full proposed markdown content of the article" json:"content" required:"true"`
+	}
+	Slug string `doc:"The URL slug of the article" path:"slug"`
+}
+
+// PublicSuggestion represents a suggested edit awaiting review.
+type PublicSuggestion struct {
+	CreatedAt      time.Time `json:"createdAt"`
+	ArticleTitle   string    `json:"articleTitle"`
+	ArticleSlug    string    `json:"articleSlug"`
+	SuggestedBy    string    `json:"suggestedBy"`
+	SuggestedFrom  string    `json:"suggestedFrom,omitempty"`
+	Content        string    `json:"content"`
+	Id             int       `json:"id"`
+	ArticleVersion int       `json:"articleVersion"`
+
+	ReconstructionDegraded bool `json:"reconstructionDegraded"`
+}
+
+// SuggestionOutput represents the output for a single suggestion.
+type SuggestionOutput struct {
+	Body struct {
+		Suggestion *PublicSuggestion `json:"suggestion"`
+	}
+}
+
+// SuggestionListOutput represents the output for the suggestions awaiting review.
+type SuggestionListOutput struct {
+	Body struct {
+		Suggestions []*PublicSuggestion `json:"suggestions"`
+	}
+}
+
+// registerSuggestionRoutes registers the suggested edit routes with the API.
+func (s *Server) registerSuggestionRoutes() {
+	suggestionLimiter := tollbooth.NewLimiter(suggestionRate, nil)
+	suggestionLimiter.SetBurst(suggestionBurst)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "create-suggestion",
+		Method:      http.MethodPost,
+		Path:        "/api/articles/{slug}/suggestions",
+		Summary:     "Suggest Edit",
+		Description: "Propose new content for a published article. Anyone, including visitors " +
+			"who are not signed in, can suggest an edit; writers review it before it is published. " +
+			"Rate limited per client.",
+		Tags:          []string{"Suggestions"},
+		DefaultStatus: http.StatusCreated,
+		Middlewares:   huma.Middlewares{s.rateLimitOperation(suggestionLimiter), s.withClientIP},
+	}, s.handleCreateSuggestion)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-suggestions",
+		Method:      http.MethodGet,
+		Path:        "/api/suggestions",
+		Summary:     "List Suggestions",
+		Description: "Get the suggested edits awaiting review, oldest first. Writers and admins only.",
+		Tags:        []string{"Suggestions"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleGetSuggestions)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "approve-suggestion",
+		Method:      http.MethodPost,
+		Path:        "/api/suggestions/{id}/approve",
+		Summary:     "Approve Suggestion",
+		Description: "Publish a suggested edit as a new version credited to its suggester.",
+		Tags:        []string{"Suggestions"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleApproveSuggestion)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "reject-suggestion",
+		Method:      http.MethodDelete,
+		Path:        "/api/suggestions/{id}",
+		Summary:     "Reject Suggestion",
+		Tags:        []string{"Suggestions"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleRejectSuggestion)
+}
+
+// requireModerator returns the current user if they may review suggestions.
+func requireModerator(ctx context.Context) (*models.User, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	if user.Role < models.WRITE {
+		return nil, huma.Error403Forbidden("Only writers and admins can review suggestions")
+	}
+
+	return user, nil
+}
+
+// handleCreateSuggestion handles the request to suggest an edit of an article.
+func (s *Server) handleCreateSuggestion(
+	ctx context.Context,
+	input *CreateSuggestionInput,
+) (*SuggestionOutput, error) {
+	if len(input.Body.Content) > s.maxContentSize {
+		return nil, errContentTooLarge(s.maxContentSize)
+	}
+
+	article, err := s.db.GetArticleBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil || article.Version == 0 {
+		return nil, huma.Error404NotFound("Article not found")
+	}
+
+	if input.Body.Content == article.Data {
+		return nil, huma.Error400BadRequest("The suggestion does not change the article")
+	}
+
+	suggestedBy := anonymousSuggester
+	if user := getUserFromContext(ctx); user != nil {
+		suggestedBy = user.Email
+	}
+
+	draft, err := s.db.CreateSuggestion(
+		ctx,
+		article.Id,
+		input.Body.Content,
+		suggestedBy,
+		getClientIPFromContext(ctx),
+	)
+	if err != nil {
+		if errors.Is(err, db.ErrContentTooLarge) {
+			return nil, errContentTooLarge(s.maxContentSize)
+		}
+		return nil, huma.Error500InternalServerError("Failed to save suggestion", err)
+	}
+
+	resp := &SuggestionOutput{}
+	resp.Body.Suggestion = &PublicSuggestion{
+		CreatedAt:      draft.CreatedAt,
+		ArticleTitle:   article.Title,
+		ArticleSlug:    article.Slug,
+		SuggestedBy:    draft.SuggestedBy,
+		Content:        input.Body.Content,
+		Id:             draft.Id,
+		ArticleVersion: draft.ArticleVersion,
+	}
+
+	return resp, nil
+}
+
+// handleGetSuggestions handles the request to list the suggestions awaiting review.
+func (s *Server) handleGetSuggestions(ctx context.Context, _ *struct{}) (*SuggestionListOutput, error) {
+	_, err := requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.db.GetSuggestions(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	isAdmin := getAdminUserFromContext(ctx) != nil
+
+	resp := &SuggestionListOutput{}
+	resp.Body.Suggestions = make([]*PublicSuggestion, 0, len(suggestions))
+
+	for _, suggestion := range suggestions {
+		draft, content, err := s.db.GetDraftByID(ctx, suggestion.Id)
+		if err != nil {
+			return nil, dbError(err, "Suggestion", "Failed to reconstruct suggestion")
+		}
+
+		public := &PublicSuggestion{
+			CreatedAt:              draft.CreatedAt,
+			ArticleTitle:           draft.Article.Title,
+			ArticleSlug:            draft.Article.Slug,
+			SuggestedBy:            draft.SuggestedBy,
+			Content:                content,
+			Id:                     draft.Id,
+			ArticleVersion:         draft.ArticleVersion,
+			ReconstructionDegraded: draft.ReconstructionDegraded,
+		}
+
+		// Client addresses are personal data, so only admins see them.
+		if isAdmin {
+			public.SuggestedFrom = draft.SuggestedFrom
+		}
+
+		resp.Body.Suggestions = append(resp.Body.Suggestions, public)
+	}
+
+	return resp, nil
+}
+
+// handleApproveSuggestion handles the request to publish a suggested edit.
+func (s *Server) handleApproveSuggestion(
+	ctx context.Context,
+	input *DraftIDInput,
+) (*struct{ Status int }, error) {
+	user, err := requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkWriteLimit(user)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, _, err := s.db.GetDraftByID(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Suggestion", "Database error")
+	}
+
+	if !draft.IsSuggestion {
+		return nil, huma.Error404NotFound("Suggestion not found")
+	}
+
+	err = s.db.PublishDraft(ctx, draft.Id)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidFrontMatter) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError("Failed to publish suggestion", err)
+	}
+
+	s.invalidateArticleReports()
+
+	s.emitWebhook(models.EventArticlePublished, map[string]any{
+		"id":          draft.ArticleId,
+		"slug":        draft.Article.Slug,
+		"title":       draft.Article.Title,
+		"version":     draft.Article.Version + 1,
+		"publishedBy": user.Email,
+	})
+
+	s.notifyWatchers(ctx, draft.Article, draft.Article.Version+1, user, draft.SuggestedBy)
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
+
+// handleRejectSuggestion handles the request to discard a suggested edit.
+func (s *Server) handleRejectSuggestion(
+	ctx context.Context,
+	input *DraftIDInput,
+) (*struct{ Status int }, error) {
+	_, err := requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.RejectSuggestion(ctx, input.ID)
+	if err != nil {
+		return nil, dbError(err, "Suggestion", "Failed to reject suggestion")
+	}
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSuggestionTestServer creates a test server with suggestions enabled.
+func newSuggestionTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server, err := NewServer(ServerConfig{
+		Database:    newTestDB(t),
+		JwtSecret:   "test-secret",
+		WikiName:    "Test Wiki",
+		Suggestions: true,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	return server
+}
+
+func TestSuggestions_SubmitAndApprove(t *testing.T) {
+	server := newSuggestionTestServer(t)
+	ctx := context.Background()
+
+	page := publishArticle(t, server.db, "Guide", "Original")
+
+	create := &CreateSuggestionInput{Slug: page.Slug}
+	create.Body.Content = "Improved"
+
+	created, err := server.handleCreateSuggestion(ctx, create)
+	require.NoError(t, err)
+	assert.Equal(t, anonymousSuggester, created.Body.Suggestion.SuggestedBy)
+
+	reader := &models.User{Email: "reader@example.com", Role: models.READ}
+	create.Body.Content = "Reader version"
+	_, err = server.handleCreateSuggestion(contextWithUser(reader), create)
+	require.NoError(t, err)
+
+	_, err = server.handleGetSuggestions(contextWithUser(reader), nil)
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusForbidden, humaErr.Status)
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, server.db.CreateUser(ctx, writer))
+
+	list, err := server.handleGetSuggestions(contextWithUser(writer), nil)
+	require.NoError(t, err)
+	require.Len(t, list.Body.Suggestions, 2, "suggestions do not replace each other")
+	assert.Equal(t, "Improved", list.Body.Suggestions[0].Content)
+	assert.Equal(t, "reader@example.com", list.Body.Suggestions[1].SuggestedBy)
+
+	_, err = server.handleApproveSuggestion(contextWithUser(writer), &DraftIDInput{ID: created.Body.Suggestion.Id})
+	require.NoError(t, err)
+
+	article, err := server.db.GetArticleBySlug(ctx, page.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, "Improved", article.Data)
+	assert.Equal(t, 2, article.Version)
+
+	history, err := server.db.GetArticleHistory(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, anonymousSuggester, history[0].CreatedBy, "the version is credited to the suggester")
+
+	_, err = server.handleRejectSuggestion(contextWithUser(writer), &DraftIDInput{ID: list.Body.Suggestions[1].Id})
+	require.NoError(t, err)
+
+	list, err = server.handleGetSuggestions(contextWithUser(writer), nil)
+	require.NoError(t, err)
+	assert.Empty(t, list.Body.Suggestions)
+
+	_, err = server.handleApproveSuggestion(contextWithUser(writer), &DraftIDInput{ID: created.Body.Suggestion.Id})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestApproveSuggestion_RejectsRegularDrafts(t *testing.T) {
+	server := newSuggestionTestServer(t)
+	ctx := context.Background()
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, server.db.CreateUser(ctx, writer))
+
+	page := publishArticle(t, server.db, "Guide", "Original")
+	draft, err := server.db.CreateDraft(ctx, page.Id, "Edited", writer.Email)
+	require.NoError(t, err)
+
+	_, err = server.handleApproveSuggestion(contextWithUser(writer), &DraftIDInput{ID: draft.Id})
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+
+	_, err = server.handleRejectSuggestion(contextWithUser(writer), &DraftIDInput{ID: draft.Id})
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+}
+
+func TestSuggestions_HTTP(t *testing.T) {
+	server := newSuggestionTestServer(t)
+	publishArticle(t, server.db, "Guide", "Original")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/articles/guide/suggestions", strings.NewReader(`{"content":"Better"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var body struct {
+		Suggestion PublicSuggestion `json:"suggestion"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "Better", body.Suggestion.Content)
+
+	disabled := newTestServer(t, newTestDB(t))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/articles/home/suggestions", strings.NewReader(`{"content":"Better"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	disabled.router.ServeHTTP(rr, req)
+	assert.NotEqual(t, http.StatusCreated, rr.Code, "suggestions are off by default")
+}
+
+func TestSuggestions_RateLimitedPerClient(t *testing.T) {
+	server := newSuggestionTestServer(t)
+	publishArticle(t, server.db, "Guide", "Original")
+
+	suggest := func(remoteAddr string, i int) int {
+		body := strings.NewReader(`{"content":"Idea ` + strconv.Itoa(i) + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/articles/guide/suggestions", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	for i := range suggestionBurst {
+		require.Equal(t, http.StatusCreated, suggest("203.0.113.7:1234", i))
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, suggest("203.0.113.7:1234", suggestionBurst))
+	assert.Equal(t, http.StatusCreated, suggest("198.51.100.2:1234", 0), "limits are kept per client")
+
+	admin, err := server.db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	list, err := server.handleGetSuggestions(contextWithUser(admin), nil)
+	require.NoError(t, err)
+	require.Len(t, list.Body.Suggestions, suggestionBurst+1)
+	assert.Equal(t, "203.0.113.7", list.Body.Suggestions[0].SuggestedFrom, "the client address is recorded")
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	list, err = server.handleGetSuggestions(contextWithUser(writer), nil)
+	require.NoError(t, err)
+	assert.Empty(t, list.Body.Suggestions[0].SuggestedFrom, "only admins see client addresses")
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MergeTagInput represents the input for merging one tag into another.
+type MergeTagInput struct {
+	Body struct {
+		Source string `doc:"Tag to replace"                      json:"source" minLength:"1" required:"true"`
+		Target string `doc:"Tag that articles get instead of it" json:"target" minLength:"1" required:"true"`
+	}
+}
+
+// RenameTagInput represents the input for renaming a tag.
+type RenameTagInput struct {
+	Body struct {
+		From string `doc:"Current name of the tag"                       json:"from" minLength:"1" required:"true"`
+		To   string `doc:"New name of the tag, which must not be in use" json:"to"   minLength:"1" required:"true"`
+	}
+}
+
+// TagChangeOutput represents the result of a wiki-wide tag change.
+type TagChangeOutput struct {
+	Body struct {
+		Articles int `doc:"Number of articles whose tags changed" json:"articles"`
+	}
+}
+
+// registerTagRoutes registers the tag maintenance routes with the API.
+func (s *Server) registerTagRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "merge-tag",
+		Method:      http.MethodPost,
+		Path:        "/api/tags/merge",
+		Summary:     "Merge Tags",
+		Description: "Replace the source tag with the target tag on every article. Admin only.",
+		Tags:        []string{"Articles"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleMergeTag)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "rename-tag",
+		Method:      http.MethodPost,
+		Path:        "/api/tags/rename",
+		Summary:     "Rename Tag",
+		Description: "Rename a tag on every article. Fails with 409 when the new name is already " +
+			"in use; merge the tags instead. Admin only.",
+		Tags:     []string{"Articles"},
+		Security: []map[string][]string{{"bearer": {}}},
+	}, s.handleRenameTag)
+}
+
+// handleMergeTag handles the request to merge one tag into another.
+func (s *Server) handleMergeTag(ctx context.Context, input *MergeTagInput) (*TagChangeOutput, error) {
+	source, target, err := s.checkTagChange(ctx, input.Body.Source, input.Body.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.db.MergeTag(ctx, source, target)
+	if err != nil {
+		return nil, dbError(err, "Tag", "Failed to merge tags")
+	}
+
+	resp := &TagChangeOutput{}
+	resp.Body.Articles = count
+
+	return resp, nil
+}
+
+// handleRenameTag handles the request to rename a tag.
+func (s *Server) handleRenameTag(ctx context.Context, input *RenameTagInput) (*TagChangeOutput, error) {
+	from, to, err := s.checkTagChange(ctx, input.Body.From, input.Body.To)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.db.RenameTag(ctx, from, to)
+	if err != nil {
+		return nil, dbError(err, "Tag", "Failed to rename tag")
+	}
+
+	resp := &TagChangeOutput{}
+	resp.Body.Articles = count
+
+	return resp, nil
+}
+
+// checkTagChange checks that the current user may change tags wiki-wide and returns the
+// current and new tag, trimmed like the tags set in front-matter.
+func (s *Server) checkTagChange(ctx context.Context, from, to string) (string, string, error) {
+	if getAdminUserFromContext(ctx) == nil {
+		return "", "", huma.Error403Forbidden("Only admins can change tags")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return "", "", err
+	}
+
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if from == "" || to == "" {
+		return "", "", huma.Error400BadRequest("Tags must not be blank")
+	}
+
+	if from == to {
+		return "", "", huma.Error400BadRequest("The tags are the same")
+	}
+
+	return from, to, nil
+}
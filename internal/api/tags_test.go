@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMergeTag(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	intro := publishArticle(t, db, "Intro", "---\ntags: [js, guide]\n---\n# Intro")
+	both := publishArticle(t, db, "Both", "---\ntags: [javascript, js]\n---\n# Both")
+	other := publishArticle(t, db, "Other", "---\ntags: [go]\n---\n# Other")
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/api/tags/merge",
+		bytes.NewBufferString(`{"source": "js", "target": "javascript"}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(&models.User{Email: "admin@test.com", Role: models.ADMIN})))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp struct {
+		Articles int `json:"articles"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Articles)
+
+	for slug, want := range map[string][]string{
+		intro.Slug: {"javascript", "guide"},
+		both.Slug:  {"javascript"},
+		other.Slug: {"go"},
+	} {
+		article, err := server.handleGetArticleJSON(ctx, &ArticleSlugInput{Slug: slug})
+		require.NoError(t, err)
+		assert.Equal(t, want, article.Body.Tags, slug)
+	}
+}
+
+func TestHandleRenameTag(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	admin := contextWithUser(&models.User{Email: "admin@test.com", Role: models.ADMIN})
+
+	page := publishArticle(t, db, "Intro", "---\ntags: [setup]\n---\n# Intro")
+	publishArticle(t, db, "Other", "---\ntags: [ops]\n---\n# Other")
+
+	input := &RenameTagInput{}
+	input.Body.From = " setup "
+	input.Body.To = "installation"
+
+	resp, err := server.handleRenameTag(admin, input)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Body.Articles)
+
+	article, err := db.GetArticleBySlug(context.Background(), page.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"installation"}, article.Tags)
+
+	input.Body.From = "installation"
+	input.Body.To = "ops"
+
+	_, err = server.handleRenameTag(admin, input)
+	var statusErr huma.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusConflict, statusErr.GetStatus())
+}
+
+func TestHandleMergeTag_Invalid(t *testing.T) {
+	server := newTestServer(t, newTestDB(t))
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		source string
+		target string
+		status int
+	}{
+		{"writer", &models.User{Email: "writer@example.com", Role: models.WRITE}, "js", "javascript", http.StatusForbidden},
+		{"same tag", &models.User{Email: "admin@test.com", Role: models.ADMIN}, "js", " js", http.StatusBadRequest},
+		{"blank tag", &models.User{Email: "admin@test.com", Role: models.ADMIN}, "js", "  ", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &MergeTagInput{}
+			input.Body.Source = tt.source
+			input.Body.Target = tt.target
+
+			_, err := server.handleMergeTag(contextWithUser(tt.user), input)
+			var statusErr huma.StatusError
+			require.ErrorAs(t, err, &statusErr)
+			assert.Equal(t, tt.status, statusErr.GetStatus())
+		})
+	}
+}
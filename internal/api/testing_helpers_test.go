@@ -66,6 +66,10 @@ func newTestServer(t *testing.T, database *db.DB) *Server {
 	require.NoError(t, err, "Failed to create new test server")
 	require.NotNil(t, server, "Server object should not be nil")
 
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
 	return server
 }
 
@@ -8,7 +8,9 @@ import (
 	"html/template"
 	"net/http"
 	"strings"
+	"time"
 	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 )
 
 //go:embed templates/*
@@ -26,6 +28,10 @@ func (s *Server) initTemplates() error {
 		"safeHTML": func(s string) template.HTML {
 			return template.HTML(s)
 		},
+		"timeAgo": func(t time.Time) string {
+			return utils.TimeAgo(t, time.Now())
+		},
+		"formatBytes": utils.FormatBytes,
 		"formatRole": func(role models.UserRole) string {
 			switch role {
 			case models.READ:
@@ -69,11 +75,65 @@ func (s *Server) initTemplates() error {
 
 // templateData is the standardized structure passed to all views.
 type templateData struct {
-	User     *models.User
-	Data     any
-	WikiName string
-	Error    string
-	Success  string
+	User         *models.User
+	Impersonator *models.User
+	Data         any
+	WikiName     string
+	Version      string
+	Error        string
+	Success      string
+	DraftCount   int
+	Breadcrumbs  []breadcrumb
+	// PreviewRole is the role an admin is previewing the article page as, if any.
+	PreviewRole string
+	// ExternalIDP is true when users sign in with the external IDP instead of a password,
+	// at SSOLoginURL when one is configured.
+	ExternalIDP bool
+	SSOLoginURL string
+	// CustomCSS and CustomJS are the admin-provided code of the article page. When set, the
+	// page is served with a Content-Security-Policy that only runs scripts with CSPNonce,
+	// and navigation away from it is not boosted.
+	CustomCSS template.CSS
+	CustomJS  template.JS
+	CSPNonce  string
+}
+
+// breadcrumb is one step of the navigation trail shown above an article.
+// URL is empty for crumbs that do not link anywhere.
+type breadcrumb struct {
+	Title string
+	URL   string
+}
+
+// articleBreadcrumbs builds the trail for an article from its slug path, so
+// "docs/guides/intro" becomes Home / Docs / Guides / <title>. Articles outside
+// a namespace get Home / <title>.
+func articleBreadcrumbs(slug, title string) []breadcrumb {
+	crumbs := []breadcrumb{{Title: "Home", URL: "/"}}
+
+	segments := strings.Split(strings.Trim(slug, "/"), "/")
+	for _, segment := range segments[:len(segments)-1] {
+		if segment == "" {
+			continue
+		}
+
+		// Namespaces do not have listing pages yet, so namespace crumbs are plain text.
+		crumbs = append(crumbs, breadcrumb{Title: namespaceTitle(segment)})
+	}
+
+	return append(crumbs, breadcrumb{Title: title})
+}
+
+// namespaceTitle turns a kebab-case slug segment into a display title.
+func namespaceTitle(segment string) string {
+	words := strings.Split(segment, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, " ")
 }
 
 // RegisterRoutes attaches all frontend-specific paths to the provided ServeMux.
@@ -85,6 +145,8 @@ func (s *Server) registerFrontendRoutes(mux *http.ServeMux) error {
 	}
 
 	if s.isExternalIDPEnabled() {
+		mux.HandleFunc("GET /login", s.uiRenderLogin)
+		mux.HandleFunc("POST /logout", s.uiHandleLogout)
 		mux.HandleFunc("GET /{path...}", s.uiRenderExternalIDPDisabled)
 		return nil
 	}
@@ -93,18 +155,24 @@ func (s *Server) registerFrontendRoutes(mux *http.ServeMux) error {
 	mux.HandleFunc("GET /", s.uiRenderHome)
 	mux.HandleFunc("GET /wiki/{slug}", s.uiRenderArticle)
 	mux.HandleFunc("GET /wiki/{slug}/history", s.uiRenderHistory)
+	mux.HandleFunc("GET /wiki/{slug}/source", s.uiRenderSource)
 	mux.HandleFunc("GET /wiki/{slug}/history/{version}", s.uiRenderPastVersion)
+	mux.HandleFunc("GET /p/{id}", s.uiRedirectPermalink)
+	mux.HandleFunc("GET /recent", s.uiRenderRecentChanges)
 
 	// Auth
 	mux.HandleFunc("GET /login", s.uiRenderLogin)
 	mux.HandleFunc("POST /login", s.uiHandleLoginSubmit)
 	mux.HandleFunc("POST /logout", s.uiHandleLogout)
+	mux.HandleFunc("POST /impersonate/exit", s.uiHandleExitImpersonation)
 
 	// App
 	mux.HandleFunc("GET /dashboard", s.uiRenderDashboard)
 	mux.HandleFunc("GET /new", s.uiRenderNewArticle)
 	mux.HandleFunc("POST /new", s.uiActionCreateIntent)
 	mux.HandleFunc("POST /wiki/{slug}/edit", s.uiActionEditIntent)
+	mux.HandleFunc("POST /wiki/{slug}/watch", s.uiActionWatch)
+	mux.HandleFunc("POST /wiki/{slug}/unwatch", s.uiActionUnwatch)
 	mux.HandleFunc("GET /editor/{draftID}", s.uiRenderEditor)
 
 	// Editor Actions
@@ -115,6 +183,7 @@ func (s *Server) registerFrontendRoutes(mux *http.ServeMux) error {
 	// User
 	mux.HandleFunc("GET /user", s.uiRenderUser)
 	mux.HandleFunc("POST /user", s.uiActionUpdateUserPassword)
+	mux.HandleFunc("POST /user/editor", s.uiActionUpdateEditorMode)
 
 	// OTP
 	mux.HandleFunc("GET /user/otp", s.uiRenderOTPSettings)
@@ -126,9 +195,12 @@ func (s *Server) registerFrontendRoutes(mux *http.ServeMux) error {
 	// Admin Actions
 	mux.HandleFunc("POST /wiki/{slug}/delete", s.uiActionDeleteArticle)
 	mux.HandleFunc("GET /admin/logs", s.uiRenderLogs)
+	mux.HandleFunc("GET /admin/stats", s.uiRenderAdminStats)
+	mux.HandleFunc("POST /admin/impersonate", s.uiHandleImpersonate)
 
 	// Special
 	mux.HandleFunc("GET /special/orphans", s.uiRenderOrphans)
+	mux.HandleFunc("GET /special/stubs", s.uiRenderStubs)
 
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUIRenderExternalIDPDisabled(t *testing.T) {
@@ -110,3 +111,87 @@ func TestRegisterFrontendRoutesWithExternalIDP(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), "Login") // Should show login page, not disabled page
 	})
 }
+
+func TestUIHandleLogout_ExternalIDP(t *testing.T) {
+	db := newTestDB(t)
+
+	server, err := NewServer(ServerConfig{
+		Database:              db,
+		JwtSecret:             "test-secret",
+		JwksURL:               "https://example.com/.well-known/jwks.json",
+		WikiName:              "Test Wiki",
+		EndSessionEndpoint:    "https://idp.example.com/logout",
+		PostLogoutRedirectURL: "https://wiki.example.com/",
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	require.NoError(t, server.registerFrontendRoutes(mux))
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusSeeOther, rr.Code)
+	assert.Equal(
+		t,
+		"https://idp.example.com/logout?post_logout_redirect_uri=https%3A%2F%2Fwiki.example.com%2F",
+		rr.Header().Get("Location"),
+	)
+	assert.Contains(t, rr.Header().Get("Set-Cookie"), CookieName+"=")
+
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.Header.Set("HX-Request", "true")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(
+		t,
+		"https://idp.example.com/logout?post_logout_redirect_uri=https%3A%2F%2Fwiki.example.com%2F",
+		rr.Header().Get("HX-Redirect"),
+	)
+}
+
+func TestUIRenderLogin_ExternalIDP(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		jwksURL     string
+		ssoLoginURL string
+		wantForm    bool
+		want        string
+	}{
+		{"local login", "", "", true, `name="password"`},
+		{"SSO with login URL", "https://example.com/.well-known/jwks.json", "https://idp.example.com/authorize", false, `href="https://idp.example.com/authorize"`},
+		{"SSO without login URL", "https://example.com/.well-known/jwks.json", "", false, "identity provider"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(ServerConfig{
+				Database:    newTestDB(t),
+				JwtSecret:   "test-secret",
+				JwksURL:     tt.jwksURL,
+				WikiName:    "Test Wiki",
+				SSOLoginURL: tt.ssoLoginURL,
+			})
+			require.NoError(t, err)
+
+			mux := http.NewServeMux()
+			require.NoError(t, server.registerFrontendRoutes(mux))
+
+			req := httptest.NewRequest(http.MethodGet, "/login", nil)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			body := rr.Body.String()
+			assert.Contains(t, body, tt.want)
+
+			if tt.wantForm {
+				assert.Contains(t, body, `id="loginForm"`)
+			} else {
+				assert.NotContains(t, body, `id="loginForm"`, "pure SSO deployments have no password form")
+				assert.NotContains(t, body, `name="password"`)
+			}
+		})
+	}
+}
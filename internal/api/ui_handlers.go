@@ -5,12 +5,18 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
+	"wikilite/internal/db"
+	"wikilite/internal/version"
 	"wikilite/pkg/models"
 	"wikilite/pkg/utils"
 
@@ -22,6 +28,33 @@ func isHTMXRequest(r *http.Request) bool {
 	return r.Header.Get("HX-Request") == "true"
 }
 
+// wantsJSON reports whether an error response should be JSON rather than an HTML page: for
+// scripted requests (XHR, or HTMX outside of boosted navigation), or when the Accept header
+// ranks a JSON media type above HTML.
+func wantsJSON(r *http.Request) bool {
+	if r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
+		return true
+	}
+
+	if isHTMXRequest(r) && !isHTMXBoost(r) {
+		return true
+	}
+
+	jsonQuality, htmlQuality := 0.0, 0.0
+	for part := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json", "application/problem+json":
+			jsonQuality = max(jsonQuality, parseQuality(params))
+		case "text/html":
+			htmlQuality = max(htmlQuality, parseQuality(params))
+		}
+	}
+
+	return jsonQuality > htmlQuality
+}
+
 // isHTMXBoost checks if this is a boosted navigation request
 func isHTMXBoost(r *http.Request) bool {
 	return r.Header.Get("HX-Boosted") == "true"
@@ -35,8 +68,8 @@ func (s *Server) uiRenderExternalIDPDisabled(w http.ResponseWriter, r *http.Requ
 // uiRenderHome renders the home page with a paginated list of articles.
 func (s *Server) uiRenderHome(w http.ResponseWriter, r *http.Request) {
 	input := &ArticlePaginationInput{
-		Page:  1,
-		Limit: 20,
+		Page: 1,
+		Sort: r.URL.Query().Get("sort"),
 	}
 
 	pageStr := r.URL.Query().Get("page")
@@ -60,42 +93,169 @@ func (s *Server) uiRenderArticle(w http.ResponseWriter, r *http.Request) {
 	input := &ArticleSlugInput{Slug: slug}
 
 	resp, err := s.handleGetArticleJSON(r.Context(), input)
+
+	var statusErr huma.StatusError
+	if errors.As(err, &statusErr) && statusErr.GetStatus() == http.StatusNotFound && !wantsJSON(r) {
+		s.uiRenderArticleNotFound(w, r, slug)
+		return
+	}
+
 	if err != nil {
 		s.uiError(w, r, err)
 		return
 	}
 
-	wikiContent, err := s.getRenderedHTML(r.Context(), resp.Body.PublicArticle)
-	if err != nil {
-		s.uiError(w, r, fmt.Errorf("failed to render markdown: %w", err))
+	hasCustomCode := resp.Body.CustomCSS != "" || resp.Body.CustomJS != ""
+
+	// A boosted navigation would swap the page in without its Content-Security-Policy and
+	// leave its script running on the pages visited next, so the article is loaded in full.
+	if hasCustomCode && isHTMXBoost(r) {
+		w.Header().Set("HX-Redirect", r.URL.RequestURI())
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	if s.hasActivePlugins() {
-		pluginCtx := map[string]any{
-			"User": getUserFromContext(r.Context()),
-			"Slug": slug,
+	// Views are counted from rendered pages only, so API reads and HEAD requests stay reads.
+	if r.Method != http.MethodHead && !wantsJSON(r) {
+		s.db.RecordView(resp.Body.Id)
+	}
+
+	pluginUser := getUserFromContext(r.Context())
+
+	previewRole := r.URL.Query().Get("as")
+	if previewRole != "" && getAdminUserFromContext(r.Context()) != nil {
+		role, ok := previewRoles[previewRole]
+		if !ok {
+			s.uiError(w, r, huma.Error400BadRequest("Unknown role: "+previewRole))
+			return
 		}
 
-		finalBody, err := executePlugins(
-			r.Context(),
-			s.PluginManager,
-			"onArticleRender",
-			wikiContent,
-			pluginCtx,
-			s.db.CreateLogEntry,
-		)
+		pluginUser = previewUser(pluginUser, role)
+	} else {
+		previewRole = ""
+	}
+
+	wikiContent, err := s.renderArticleHTMLFor(r.Context(), resp.Body.PublicArticle, pluginUser)
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	resp.Body.PublicArticle.Data = wikiContent
+
+	payload := s.newTemplateData(r, resp.Body)
+	payload.Breadcrumbs = articleBreadcrumbs(resp.Body.Slug, resp.Body.Title)
+	payload.PreviewRole = previewRole
+
+	if hasCustomCode {
+		nonce, err := newCSPNonce()
 		if err != nil {
-			s.uiError(w, r, fmt.Errorf("failed to execute plugins: %w", err))
+			s.uiError(w, r, err)
 			return
 		}
 
-		wikiContent = finalBody
+		// The code was checked to not close its element when it was set, so it is written
+		// into the page as is.
+		payload.CustomCSS = template.CSS(resp.Body.CustomCSS)
+		payload.CustomJS = template.JS(resp.Body.CustomJS)
+		payload.CSPNonce = nonce
+
+		w.Header().Set("Content-Security-Policy", fmt.Sprintf(
+			"script-src 'self' 'nonce-%s' https://unpkg.com; object-src 'none'; base-uri 'self'",
+			nonce,
+		))
 	}
 
-	resp.Body.PublicArticle.Data = wikiContent
+	s.render(w, r, "article.gohtml", payload)
+}
+
+// notFoundSuggestions is the number of similarly named articles suggested on the 404 page.
+const notFoundSuggestions = 5
+
+// uiRenderArticleNotFound renders the 404 page for a missing article, suggesting articles
+// with similar names and offering to create the page under the title derived from its slug.
+func (s *Server) uiRenderArticleNotFound(w http.ResponseWriter, r *http.Request, slug string) {
+	suggestions, err := s.db.SearchArticles(r.Context(), slug, notFoundSuggestions)
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(slug, "/"), "/")
+	title := namespaceTitle(segments[len(segments)-1])
+
+	data := struct {
+		Slug        string
+		Title       string
+		CreateURL   string
+		Suggestions []*models.Article
+	}{
+		Slug:        slug,
+		Title:       title,
+		CreateURL:   "/new?" + url.Values{"title": {title}}.Encode(),
+		Suggestions: suggestions,
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	s.renderWithUser(w, r, "article_not_found.gohtml", data)
+}
+
+// newCSPNonce returns a random nonce for the script-src directive of a Content-Security-Policy.
+func newCSPNonce() (string, error) {
+	nonce := make([]byte, 16)
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate CSP nonce: %w", err)
+	}
 
-	s.renderWithUser(w, r, "article.gohtml", resp.Body.PublicArticle)
+	// The URL-safe alphabet has no characters html/template escapes in attributes, so the
+	// nonce attribute matches the header byte for byte.
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// previewRoles maps the values of the article page's "as" parameter, which lets admins see
+// the plugin output for another role, to that role. Zero stands for an anonymous reader.
+var previewRoles = map[string]models.UserRole{
+	"anonymous": 0,
+	"read":      models.READ,
+	"write":     models.WRITE,
+	"admin":     models.ADMIN,
+}
+
+// previewUser returns a copy of user with the given role, or nil for an anonymous reader.
+// The session itself is unchanged; the copy only stands in for user in the plugin context.
+func previewUser(user *models.User, role models.UserRole) *models.User {
+	if role == 0 {
+		return nil
+	}
+
+	preview := *user
+	preview.Role = role
+
+	return &preview
+}
+
+// uiRedirectPermalink redirects a numeric article permalink to the article's current slug.
+func (s *Server) uiRedirectPermalink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		s.uiError(w, r, huma.Error404NotFound("Article not found"))
+		return
+	}
+
+	article, err := s.db.GetArticleByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			s.uiError(w, r, huma.Error404NotFound("Article not found"))
+			return
+		}
+
+		s.uiError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/wiki/"+url.PathEscape(article.Slug), http.StatusMovedPermanently)
 }
 
 // uiRenderHistory renders the history page for an article.
@@ -120,6 +280,17 @@ func (s *Server) uiRenderHistory(w http.ResponseWriter, r *http.Request) {
 	s.renderWithUser(w, r, "history.gohtml", data)
 }
 
+// uiRenderSource renders the markdown source of an article with a linkable anchor per line.
+func (s *Server) uiRenderSource(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.handleGetArticleSource(r.Context(), &ArticleSlugInput{Slug: r.PathValue("slug")})
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	s.renderWithUser(w, r, "source.gohtml", resp.Body)
+}
+
 // uiRenderPastVersion renders a specific past version of an article.
 func (s *Server) uiRenderPastVersion(w http.ResponseWriter, r *http.Request) {
 	slug := r.PathValue("slug")
@@ -132,7 +303,7 @@ func (s *Server) uiRenderPastVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	content, err := s.db.GetArticleVersion(r.Context(), article.Id, version)
+	content, degraded, err := s.db.GetArticleVersion(r.Context(), article.Id, version)
 	if err != nil {
 		s.uiError(w, r, err)
 		return
@@ -145,19 +316,22 @@ func (s *Server) uiRenderPastVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	viewData := &PublicArticle{
+	viewData := &ArticleOutput{}
+	viewData.Body.PublicArticle = &PublicArticle{
 		Id:      article.Id,
 		Title:   article.Title,
 		Slug:    article.Slug,
 		Version: version,
 		Data:    buf.String(),
+
+		ReconstructionDegraded: degraded,
 	}
-	s.renderWithUser(w, r, "article.gohtml", viewData)
+	s.renderWithUser(w, r, "article.gohtml", viewData.Body)
 }
 
 // uiRenderLogin renders the login page.
 func (s *Server) uiRenderLogin(w http.ResponseWriter, r *http.Request) {
-	data := map[string]string{}
+	data := map[string]string{"Next": localRedirectPath(r.URL.Query().Get("next"))}
 
 	if r.URL.Query().Get("error") == "1" {
 		data["Error"] = "Invalid credentials"
@@ -170,7 +344,7 @@ func (s *Server) uiRenderLogin(w http.ResponseWriter, r *http.Request) {
 func (s *Server) uiHandleLoginSubmit(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
-		s.uiError(w, r, huma.Error400BadRequest("Bad Request"))
+		s.uiError(w, r, formError(err))
 		return
 	}
 
@@ -179,16 +353,16 @@ func (s *Server) uiHandleLoginSubmit(w http.ResponseWriter, r *http.Request) {
 	input.Body.Password = r.FormValue("password")
 	input.Body.OTP = r.FormValue("otp")
 
+	next := localRedirectPath(r.FormValue("next"))
+
 	resp, err := s.handleLogin(r.Context(), input)
 	if err != nil {
-		if isHTMXRequest(r) || r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
-			w.Header().Set("Content-Type", "text/plain")
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte(err.Error()))
+		if wantsJSON(r) {
+			s.uiError(w, r, err)
 			return
 		}
 
-		s.renderWithUser(w, r, "login.gohtml", map[string]string{"Error": "Invalid credentials"})
+		s.renderWithUser(w, r, "login.gohtml", map[string]string{"Error": "Invalid credentials", "Next": next})
 		return
 	}
 
@@ -196,20 +370,49 @@ func (s *Server) uiHandleLoginSubmit(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Set-Cookie", cookieStr)
 	}
 
+	target := next
+	if target == "" {
+		target = "/dashboard"
+	}
+
 	if isHTMXRequest(r) {
-		w.Header().Set("HX-Redirect", "/dashboard")
+		w.Header().Set("HX-Redirect", target)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	if r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("Login successful"))
+	if wantsJSON(r) {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	http.Redirect(w, r, "/dashboard", http.StatusFound)
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// localRedirectPath returns next when it is a path on this site, and an empty string for
+// anything that could send the browser elsewhere, such as absolute or protocol-relative URLs.
+func localRedirectPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") ||
+		strings.ContainsAny(next, "\\\r\n\t") {
+		return ""
+	}
+
+	u, err := url.Parse(next)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return ""
+	}
+
+	return next
+}
+
+// loginRedirectURL returns the login page URL for r, asking to come back to the current
+// page after a successful login when it can be revisited with a GET request.
+func loginRedirectURL(r *http.Request) string {
+	if r.Method != http.MethodGet {
+		return "/login"
+	}
+
+	return "/login?next=" + url.QueryEscape(r.URL.RequestURI())
 }
 
 // uiHandleLogout handles user logout.
@@ -219,29 +422,141 @@ func (s *Server) uiHandleLogout(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Set-Cookie", cookieStr)
 	}
 
+	target, status := "/", http.StatusFound
+	if resp.Location != "" {
+		target, status = resp.Location, http.StatusSeeOther
+	}
+
 	if isHTMXRequest(r) {
-		w.Header().Set("HX-Redirect", "/")
+		w.Header().Set("HX-Redirect", target)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	http.Redirect(w, r, "/", http.StatusFound)
+	http.Redirect(w, r, target, status)
+}
+
+// uiHandleImpersonate starts an impersonation session, keeping the admin session aside.
+func (s *Server) uiHandleImpersonate(w http.ResponseWriter, r *http.Request) {
+	admin := getAdminUserFromContext(r.Context())
+	if admin == nil {
+		s.uiError(w, r, huma.Error403Forbidden("Only admins can impersonate users"))
+		return
+	}
+
+	adminCookie, err := r.Cookie(CookieName)
+	if err != nil || adminCookie.Value == "" {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	token, _, err := s.createImpersonationToken(r.Context(), admin, r.FormValue("email"))
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, s.sessionCookie(ImpersonatorCookieName, adminCookie.Value, SessionDuration))
+	http.SetCookie(w, s.sessionCookie(CookieName, token, ImpersonationDuration))
+
+	http.Redirect(w, r, "/dashboard", http.StatusFound)
+}
+
+// uiHandleExitImpersonation ends an impersonation session and restores the admin session.
+func (s *Server) uiHandleExitImpersonation(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	impersonator := getImpersonatorFromContext(r.Context())
+
+	adminCookie, err := r.Cookie(ImpersonatorCookieName)
+	if impersonator == nil || err != nil || adminCookie.Value == "" {
+		s.uiHandleLogout(w, r)
+		return
+	}
+
+	admin, err := s.validateToken(r.Context(), adminCookie.Value)
+	if err != nil || admin.Id != impersonator.Id {
+		s.uiHandleLogout(w, r)
+		return
+	}
+
+	_ = s.db.CreateLogEntry(
+		r.Context(),
+		models.LevelWarning,
+		"AUTH",
+		"Impersonation ended",
+		fmt.Sprintf("Admin: %s | Target: %s", admin.Email, user.Email),
+	)
+
+	http.SetCookie(w, s.sessionCookie(CookieName, adminCookie.Value, SessionDuration))
+	http.SetCookie(w, s.sessionCookie(ImpersonatorCookieName, "", -1))
+
+	http.Redirect(w, r, "/dashboard", http.StatusFound)
+}
+
+// sessionCookie builds a session cookie with the same attributes used at login.
+// A negative lifetime expires the cookie immediately.
+func (s *Server) sessionCookie(name, value string, lifetime time.Duration) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  time.Now().Add(lifetime),
+		HttpOnly: true,
+		Secure:   !s.insecureCookies,
+		SameSite: http.SameSiteStrictMode,
+	}
+
+	if lifetime < 0 {
+		cookie.Expires = time.Unix(0, 0)
+		cookie.MaxAge = -1
+	}
+
+	return cookie
 }
 
 // uiRenderNewArticle displays the form to name a new article.
 func (s *Server) uiRenderNewArticle(w http.ResponseWriter, r *http.Request) {
-	s.renderWithUser(w, r, "new_article.gohtml", nil)
+	selected, _ := strconv.Atoi(r.URL.Query().Get("template"))
+	s.renderNewArticle(w, r, r.URL.Query().Get("title"), selected, "")
+}
+
+// renderNewArticle renders the new article form with the template picker.
+func (s *Server) renderNewArticle(
+	w http.ResponseWriter,
+	r *http.Request,
+	title string,
+	selected int,
+	errMsg string,
+) {
+	templates, err := s.db.GetTemplates(r.Context())
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	data := struct {
+		Title     string
+		Error     string
+		Templates []*models.Template
+		Selected  int
+	}{
+		Title:     title,
+		Error:     errMsg,
+		Templates: templates,
+		Selected:  selected,
+	}
+
+	s.renderWithUser(w, r, "new_article.gohtml", data)
 }
 
 // uiActionCreateIntent handles the intent to create a new article.
 func (s *Server) uiActionCreateIntent(w http.ResponseWriter, r *http.Request) {
 	input := &CreateArticleInput{}
 	input.Body.Title = strings.TrimSpace(r.FormValue("title"))
+	input.Body.TemplateID, _ = strconv.Atoi(r.FormValue("template"))
 
 	if input.Body.Title == "" {
-		s.renderWithUser(w, r, "new_article.gohtml", map[string]string{
-			"Error": "Title is required",
-		})
+		s.renderNewArticle(w, r, "", input.Body.TemplateID, "Title is required")
 		return
 	}
 
@@ -277,6 +592,32 @@ func (s *Server) uiActionEditIntent(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirectUrl, http.StatusFound)
 }
 
+// uiActionWatch handles watching an article from its page.
+func (s *Server) uiActionWatch(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	_, err := s.handleWatchArticle(r.Context(), &ArticleSlugInput{Slug: slug})
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/wiki/"+url.PathEscape(slug), http.StatusFound)
+}
+
+// uiActionUnwatch handles unwatching an article from its page.
+func (s *Server) uiActionUnwatch(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	_, err := s.handleUnwatchArticle(r.Context(), &ArticleSlugInput{Slug: slug})
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/wiki/"+url.PathEscape(slug), http.StatusFound)
+}
+
 // uiRenderEditor renders the article editor page.
 func (s *Server) uiRenderEditor(w http.ResponseWriter, r *http.Request) {
 	draftID, _ := strconv.Atoi(r.PathValue("draftID"))
@@ -288,7 +629,21 @@ func (s *Server) uiRenderEditor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.renderWithUser(w, r, "editor.gohtml", resp.Body.Draft)
+	data := struct {
+		*PublicDraft
+		BrokenLinks []string
+		EditorMode  models.EditorMode
+	}{
+		PublicDraft: resp.Body.Draft,
+		EditorMode:  editorMode(getUserFromContext(r.Context())),
+	}
+
+	validation, err := s.handleValidateDraft(r.Context(), input)
+	if err == nil {
+		data.BrokenLinks = validation.Body.BrokenLinks
+	}
+
+	s.renderWithUser(w, r, "editor.gohtml", data)
 }
 
 // uiActionSaveDraft handles saving a draft of an article.
@@ -297,41 +652,65 @@ func (s *Server) uiActionSaveDraft(w http.ResponseWriter, r *http.Request) {
 
 	err := r.ParseForm()
 	if err != nil {
-		s.uiError(w, r, huma.Error400BadRequest("Bad form data"))
+		s.uiError(w, r, formError(err))
 		return
 	}
 
 	input := &UpdateDraftInput{ID: draftID}
 	input.Body.Content = r.FormValue("content")
+	input.Body.UpdatedAt = formDraftUpdatedAt(r)
 
-	_, err = s.handleUpdateDraft(r.Context(), input)
+	resp, err := s.handleUpdateDraft(r.Context(), input)
 	if err != nil {
 		s.uiError(w, r, err)
 		return
 	}
 
+	// Saving content identical to the article removes the draft, so there is no editor to return to.
+	if !resp.Body.DraftExists {
+		http.Redirect(w, r, "/wiki/"+url.PathEscape(resp.Body.ArticleSlug), http.StatusFound)
+		return
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/editor/%d", draftID), http.StatusFound)
 }
 
+// formDraftUpdatedAt returns the draft timestamp the editor was loaded with, or nil
+// when the form does not carry one.
+func formDraftUpdatedAt(r *http.Request) *time.Time {
+	updatedAt, err := time.Parse(time.RFC3339Nano, r.FormValue("updatedAt"))
+	if err != nil {
+		return nil
+	}
+
+	return &updatedAt
+}
+
 // uiActionPublishDraft handles publishing a draft of an article.
 func (s *Server) uiActionPublishDraft(w http.ResponseWriter, r *http.Request) {
 	draftID, _ := strconv.Atoi(r.PathValue("draftID"))
 
-	err := r.ParseMultipartForm(32 << 20)
+	err := r.ParseMultipartForm(s.maxMultipartMemory)
 	if err != nil {
-		s.uiError(w, r, fmt.Errorf("bad form data: %w", err))
+		s.uiError(w, r, formError(err))
 		return
 	}
 	content := r.FormValue("content")
 
 	updateInput := &UpdateDraftInput{ID: draftID}
 	updateInput.Body.Content = content
-	_, err = s.handleUpdateDraft(r.Context(), updateInput)
+	updateInput.Body.UpdatedAt = formDraftUpdatedAt(r)
+	saved, err := s.handleUpdateDraft(r.Context(), updateInput)
 	if err != nil {
 		s.uiError(w, r, err)
 		return
 	}
 
+	if !saved.Body.DraftExists {
+		http.Redirect(w, r, "/wiki/"+url.PathEscape(saved.Body.ArticleSlug), http.StatusFound)
+		return
+	}
+
 	draftResp, err := s.handleGetDraft(r.Context(), &DraftIDInput{ID: draftID})
 	if err != nil {
 		s.uiError(w, r, err)
@@ -365,15 +744,17 @@ func (s *Server) uiActionDiscardDraft(w http.ResponseWriter, r *http.Request) {
 func (s *Server) uiRenderDashboard(w http.ResponseWriter, r *http.Request) {
 	draftsResp, err := s.handleGetMyDrafts(r.Context(), nil)
 	if err != nil {
-		http.Redirect(w, r, "/login", http.StatusFound)
+		http.Redirect(w, r, loginRedirectURL(r), http.StatusFound)
 		return
 	}
 
 	articlesResp, _ := s.handleGetArticlesByUser(r.Context(), &ArticleListInput{})
+	watchesResp, _ := s.handleGetMyWatches(r.Context(), nil)
 
 	data := struct {
 		Drafts   []*PublicDraft
 		Articles []*PublicArticle
+		Watches  []*PublicArticle
 	}{
 		Drafts:   draftsResp.Body.Drafts,
 		Articles: nil,
@@ -381,13 +762,16 @@ func (s *Server) uiRenderDashboard(w http.ResponseWriter, r *http.Request) {
 	if articlesResp != nil {
 		data.Articles = articlesResp.Body.Articles
 	}
+	if watchesResp != nil {
+		data.Watches = watchesResp.Body.Articles
+	}
 
 	s.renderWithUser(w, r, "dashboard.gohtml", data)
 }
 
 // uiRenderOrphans renders the page for orphaned articles.
 func (s *Server) uiRenderOrphans(w http.ResponseWriter, r *http.Request) {
-	resp, err := s.handleGetOrphans(r.Context(), nil)
+	resp, err := s.handleGetOrphans(r.Context(), &ArticleReportInput{})
 	if err != nil {
 		s.uiError(w, r, err)
 		return
@@ -401,6 +785,22 @@ func (s *Server) uiRenderOrphans(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// uiRenderStubs renders the page for stub articles.
+func (s *Server) uiRenderStubs(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.handleGetStubs(r.Context(), &ArticleReportInput{})
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	s.renderWithUser(
+		w,
+		r,
+		"stubs.gohtml",
+		struct{ Articles []*PublicArticle }{Articles: resp.Body.Articles},
+	)
+}
+
 // uiActionDeleteArticle handles deleting an article.
 func (s *Server) uiActionDeleteArticle(w http.ResponseWriter, r *http.Request) {
 	slug := r.PathValue("slug")
@@ -415,11 +815,31 @@ func (s *Server) uiActionDeleteArticle(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/", http.StatusFound)
 }
 
+// uiRenderRecentChanges renders the recent changes feed.
+func (s *Server) uiRenderRecentChanges(w http.ResponseWriter, r *http.Request) {
+	input := &ActivityInput{
+		Page:   1,
+		Author: r.URL.Query().Get("author"),
+	}
+
+	p, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err == nil && p > 0 {
+		input.Page = p
+	}
+
+	resp, err := s.handleGetRecentChanges(r.Context(), input)
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	s.renderWithUser(w, r, "recent_changes.gohtml", resp.Body)
+}
+
 // uiRenderLogs renders the logs page.
 func (s *Server) uiRenderLogs(w http.ResponseWriter, r *http.Request) {
 	input := &LogsPaginationInput{
-		Page:  1,
-		Limit: 50,
+		Page: 1,
 	}
 
 	pageStr := r.URL.Query().Get("page")
@@ -437,7 +857,30 @@ func (s *Server) uiRenderLogs(w http.ResponseWriter, r *http.Request) {
 	s.renderWithUser(w, r, "logs.gohtml", resp.Body)
 }
 
-// uiError logs the error to the database and renders a user-friendly error page.
+// uiRenderAdminStats renders the admin statistics dashboard.
+func (s *Server) uiRenderAdminStats(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.handleGetAdminStats(r.Context(), nil)
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	s.renderWithUser(w, r, "admin_stats.gohtml", resp.Body)
+}
+
+// formError converts a form parsing error into a status error, reporting a body over the
+// request size limit as 413 rather than as malformed form data.
+func formError(err error) huma.StatusError {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errRequestTooLarge(maxBytesErr.Limit)
+	}
+
+	return huma.Error400BadRequest("Bad form data")
+}
+
+// uiError logs the error to the database and responds with a user-friendly error: an HTML
+// error page, or a Huma-style JSON error for requests that want JSON (see wantsJSON).
 func (s *Server) uiError(w http.ResponseWriter, r *http.Request, err error) {
 	userEmail := "Anonymous"
 	if user := getUserFromContext(r.Context()); user != nil {
@@ -470,6 +913,16 @@ func (s *Server) uiError(w http.ResponseWriter, r *http.Request, err error) {
 		)
 	}()
 
+	if wantsJSON(r) {
+		writeStatusError(w, huma.NewError(statusCode, message))
+		return
+	}
+
+	if statusCode == http.StatusUnauthorized && r.Method == http.MethodGet {
+		http.Redirect(w, r, loginRedirectURL(r), http.StatusFound)
+		return
+	}
+
 	w.WriteHeader(statusCode)
 
 	data := struct {
@@ -544,20 +997,67 @@ func (s *Server) getAvailableTemplates() []string {
 
 // renderWithUser wraps data with User context.
 func (s *Server) renderWithUser(w http.ResponseWriter, r *http.Request, tmplName string, data any) {
+	s.render(w, r, tmplName, s.newTemplateData(r, data))
+}
+
+// newTemplateData builds the template payload for the current user around the page data.
+func (s *Server) newTemplateData(r *http.Request, data any) templateData {
 	user := getUserFromContext(r.Context())
 
 	payload := templateData{
-		User:     user,
-		Data:     data,
-		WikiName: s.WikiName,
+		User:         user,
+		Impersonator: getImpersonatorFromContext(r.Context()),
+		Data:         data,
+		WikiName:     s.WikiName,
+		Version:      version.Version,
+		ExternalIDP:  s.isExternalIDPEnabled(),
+		SSOLoginURL:  s.ssoLoginURL,
+	}
+
+	if user != nil {
+		count, err := s.db.CountDraftsByUser(r.Context(), user.Email)
+		if err == nil {
+			payload.DraftCount = count
+		}
 	}
-	s.render(w, r, tmplName, payload)
+
+	return payload
 }
 
 func (s *Server) uiRenderUser(w http.ResponseWriter, r *http.Request) {
 	s.renderWithUser(w, r, "user.gohtml", nil)
 }
 
+// uiActionUpdateEditorMode saves the editor layout chosen on the profile page.
+func (s *Server) uiActionUpdateEditorMode(w http.ResponseWriter, r *http.Request) {
+	if getUserFromContext(r.Context()) == nil {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		s.uiError(w, r, formError(err))
+		return
+	}
+
+	input := &PreferencesInput{}
+	input.Body.EditorMode = models.EditorMode(r.FormValue("editor_mode"))
+
+	if input.Body.EditorMode != models.EditorMarkdown && input.Body.EditorMode != models.EditorSplitPreview {
+		s.uiError(w, r, huma.Error400BadRequest("Unknown editor layout"))
+		return
+	}
+
+	_, err = s.handleUpdatePreferences(r.Context(), input)
+	if err != nil {
+		s.uiError(w, r, err)
+		return
+	}
+
+	s.renderWithUser(w, r, "user.gohtml", map[string]string{"Success": "Editor preference saved"})
+}
+
 func (s *Server) uiActionUpdateUserPassword(w http.ResponseWriter, r *http.Request) {
 	user := getUserFromContext(r.Context())
 	if user == nil {
@@ -567,7 +1067,7 @@ func (s *Server) uiActionUpdateUserPassword(w http.ResponseWriter, r *http.Reque
 
 	err := r.ParseForm()
 	if err != nil {
-		s.uiError(w, r, err)
+		s.uiError(w, r, formError(err))
 		return
 	}
 
@@ -585,6 +1085,12 @@ func (s *Server) uiActionUpdateUserPassword(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	err = utils.ValidatePassword(newPassword, s.passwordPolicy)
+	if err != nil {
+		s.renderWithUser(w, r, "user.gohtml", map[string]string{"Error": err.Error()})
+		return
+	}
+
 	dbUser, err := s.db.GetUserByID(r.Context(), user.Id)
 	if err != nil {
 		s.uiError(w, r, err)
@@ -619,6 +1125,16 @@ func (s *Server) uiActionUpdateUserPassword(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if getImpersonatorFromContext(r.Context()) == nil {
+		token, err := s.signSessionToken(dbUser)
+		if err != nil {
+			s.uiError(w, r, err)
+			return
+		}
+
+		http.SetCookie(w, s.sessionCookie(CookieName, token, SessionDuration))
+	}
+
 	s.renderWithUser(
 		w,
 		r,
@@ -648,7 +1164,7 @@ func (s *Server) uiHandleOTPStartEnrollment(w http.ResponseWriter, r *http.Reque
 
 	err := r.ParseForm()
 	if err != nil {
-		s.uiError(w, r, huma.Error400BadRequest("Bad form data"))
+		s.uiError(w, r, formError(err))
 		return
 	}
 
@@ -705,16 +1221,14 @@ func (s *Server) uiHandleOTPVerify(w http.ResponseWriter, r *http.Request) {
 
 	err := r.ParseForm()
 	if err != nil {
-		s.uiError(w, r, huma.Error400BadRequest("Bad form data"))
+		s.uiError(w, r, formError(err))
 		return
 	}
 
 	code := r.FormValue("code")
 	if code == "" {
-		if r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
-			w.Header().Set("Content-Type", "text/plain")
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("Verification code is required"))
+		if wantsJSON(r) {
+			s.uiError(w, r, huma.Error400BadRequest("Verification code is required"))
 			return
 		}
 		s.renderWithUser(
@@ -731,10 +1245,8 @@ func (s *Server) uiHandleOTPVerify(w http.ResponseWriter, r *http.Request) {
 
 	_, err = s.handleCompleteOTPEnrollment(r.Context(), input)
 	if err != nil {
-		if r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
-			w.Header().Set("Content-Type", "text/plain")
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("Invalid verification code"))
+		if wantsJSON(r) {
+			s.uiError(w, r, huma.Error400BadRequest("Invalid verification code"))
 			return
 		}
 		s.renderWithUser(
@@ -746,10 +1258,8 @@ func (s *Server) uiHandleOTPVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("Two-factor authentication enabled successfully"))
+	if wantsJSON(r) {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
@@ -757,7 +1267,10 @@ func (s *Server) uiHandleOTPVerify(w http.ResponseWriter, r *http.Request) {
 		w,
 		r,
 		"otp_settings.gohtml",
-		map[string]string{"Success": "Two-factor authentication enabled successfully"},
+		map[string]string{
+			"Success":             "Two-factor authentication enabled successfully",
+			"BackupCodesDownload": "/api/otp/backup-codes/download",
+		},
 	)
 }
 
@@ -3,13 +3,18 @@
 package api
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
 	"time"
+	"wikilite/internal/version"
 	"wikilite/pkg/models"
 	"wikilite/pkg/utils"
 
@@ -29,6 +34,7 @@ func TestUIRenderHome(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Test Wiki")
+	assert.Contains(t, rr.Body.String(), "WikiLite "+version.Version)
 }
 
 func TestUIRenderArticle(t *testing.T) {
@@ -44,6 +50,229 @@ func TestUIRenderArticle(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "Welcome to your Home")
 }
 
+func TestUIRenderArticle_CountsViews(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/wiki/home", nil),
+		httptest.NewRequest(http.MethodHead, "/wiki/home", nil),
+		httptest.NewRequest(http.MethodGet, "/api/articles/home", nil),
+	} {
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, req.Method+" "+req.URL.Path)
+	}
+
+	require.NoError(t, db.FlushViews(ctx))
+
+	home, err := db.GetArticleBySlug(ctx, "home")
+	require.NoError(t, err)
+
+	stored, err := db.GetArticleByID(ctx, home.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.ViewCount, "only the rendered page counts as a view")
+}
+
+func TestUIRenderArticle_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	publishArticle(t, db, "Quantum Physics", "# Quantum")
+	publishArticle(t, db, "Cooking", "# Cooking")
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	req := httptest.NewRequest(http.MethodGet, "/wiki/quantum-mechanics", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(writer)))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, "Quantum Mechanics")
+	assert.Contains(t, body, `href="/wiki/quantum-physics"`, "similarly named articles are suggested")
+	assert.NotContains(t, body, `href="/wiki/cooking"`)
+	assert.Contains(t, body, `href="/new?title=Quantum&#43;Mechanics"`, "the create link carries the derived title")
+
+	req = httptest.NewRequest(http.MethodGet, "/wiki/quantum-mechanics", nil)
+	req.Header.Set("Accept", "application/json")
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "json", "JSON clients still get a JSON error")
+}
+
+func TestUIRenderArticle_Compressed(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	rr := serveCompressed(server, "/wiki/home", http.Header{"Accept-Encoding": {"gzip"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Welcome to your Home")
+}
+
+func TestUIRenderArticle_ResumeDraft(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(context.Background(), article.Id, article.Data, user.Email)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/wiki/home", nil)
+	req = req.WithContext(contextWithUser(user))
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), fmt.Sprintf("/editor/%d", draft.Id))
+	assert.Contains(t, rr.Body.String(), "Resume Draft")
+}
+
+func TestUIRenderArticle_Breadcrumbs(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest("GET", "/wiki/home", nil)
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `<nav class="breadcrumbs" aria-label="Breadcrumb">`)
+	assert.Contains(t, rr.Body.String(), `<a href="/">Home</a>`)
+}
+
+func TestUIRenderStubs(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Tiny Page", "admin@test.com")
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(ctx, article.Id, "Just a sentence.", "admin@test.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	req := httptest.NewRequest("GET", "/wiki/tiny-page", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `class="badge stub-badge"`)
+
+	user, err := db.GetUserByEmail(ctx, "admin@test.com")
+	require.NoError(t, err)
+
+	req = httptest.NewRequest("GET", "/special/stubs", nil)
+	req = req.WithContext(contextWithUser(user))
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `href="/wiki/tiny-page"`)
+}
+
+func TestUIRenderSource(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest("GET", "/wiki/home/source", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `<span class="source-line" id="L1"><a class="line-number" href="#L1">1</a>`)
+
+	req = httptest.NewRequest("GET", "/wiki/missing/source", nil)
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestArticleBreadcrumbs(t *testing.T) {
+	assert.Equal(t, []breadcrumb{
+		{Title: "Home", URL: "/"},
+		{Title: "Welcome"},
+	}, articleBreadcrumbs("welcome", "Welcome"))
+
+	assert.Equal(t, []breadcrumb{
+		{Title: "Home", URL: "/"},
+		{Title: "Docs"},
+		{Title: "Getting Started"},
+		{Title: "Introduction"},
+	}, articleBreadcrumbs("docs/getting-started/intro", "Introduction"))
+}
+
+func TestUIRenderEditor_BrokenLinkWarning(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	_, draft, err := db.CreateArticleWithDraft(context.Background(), "Editor Links", user.Email)
+	require.NoError(t, err)
+
+	content := "[Missing](/wiki/does-not-exist)"
+	err = db.UpdateDraft(context.Background(), draft.Id, content, user.Email, false, nil)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/editor/%d", draft.Id), nil)
+	req = req.WithContext(contextWithUser(user))
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Links to missing articles")
+	assert.Contains(t, rr.Body.String(), "<code>does-not-exist</code>")
+}
+
+func TestUIRedirectPermalink(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+	require.NotNil(t, article)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/p/%d", article.Id), nil)
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/wiki/home", rr.Header().Get("Location"))
+}
+
+func TestUIRedirectPermalink_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest("GET", "/p/9999", nil)
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
 func TestUIRenderHistory(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -66,6 +295,7 @@ func TestUIRenderHistory(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Contains(t, rr.Body.String(), "History: history-test")
 	assert.Contains(t, rr.Body.String(), ">v1<")
+	assert.Contains(t, rr.Body.String(), ">+11<")
 }
 
 func TestUIRenderPastVersion(t *testing.T) {
@@ -133,6 +363,79 @@ func TestUIHandleLoginSubmit_Success(t *testing.T) {
 	assert.NotEmpty(t, rr.Header().Get("Set-Cookie"))
 }
 
+func TestUIHandleLoginSubmit_Next(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	password := "password123"
+	user := &models.User{Name: "Login User", Email: "login@user.com", Role: models.WRITE}
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user.Hash = hash
+	err = db.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		next     string
+		expected string
+	}{
+		{"local path", "/editor/42?tab=preview", "/editor/42?tab=preview"},
+		{"external URL", "https://evil.example.com/", "/dashboard"},
+		{"protocol relative URL", "//evil.example.com/", "/dashboard"},
+		{"backslash URL", "/\\evil.example.com/", "/dashboard"},
+		{"relative path", "editor/42", "/dashboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{}
+			form.Add("email", user.Email)
+			form.Add("password", password)
+			form.Add("next", tt.next)
+			req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+			req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+
+			server.router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusFound, rr.Code)
+			assert.Equal(t, tt.expected, rr.Header().Get("Location"))
+		})
+	}
+}
+
+func TestUIRenderLogin_Next(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest("GET", "/login?next="+url.QueryEscape("/editor/42"), nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `name="next" value="/editor/42"`)
+
+	req = httptest.NewRequest("GET", "/login?next="+url.QueryEscape("https://evil.example.com/"), nil)
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), `name="next"`)
+}
+
+func TestUIRenderEditor_LoggedOutRedirectsToLogin(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest("GET", "/editor/1", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/login?next="+url.QueryEscape("/editor/1"), rr.Header().Get("Location"))
+}
+
 func TestUIHandleLoginSubmit_Failure(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -261,6 +564,73 @@ func TestUIHandleOTPStartEnrollment_Success(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "Verify Setup")
 }
 
+func TestUIActionSaveDraft_WriteLimit(t *testing.T) {
+	db := newTestDB(t)
+	server := newRateLimitedServer(t, db, 1, 0, time.Minute)
+
+	article := publishArticle(t, db, "Limited Page", "# Limited")
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	draft, err := db.CreateDraft(context.Background(), article.Id, article.Data, writer.Email)
+	require.NoError(t, err)
+
+	save := func(content string) int {
+		form := url.Values{}
+		form.Add("content", content)
+		req := httptest.NewRequest(
+			http.MethodPost,
+			fmt.Sprintf("/editor/%d/save", draft.Id),
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req.WithContext(contextWithUser(writer)))
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusFound, save("# First save"))
+	assert.Equal(t, http.StatusTooManyRequests, save("# Second save"), "the editor draws on the write limit")
+}
+
+func TestUIActionUpdateUserPassword_Policy(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	user.Hash = hash
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	change := func(newPassword string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Add("current_password", "password123")
+		form.Add("new_password", newPassword)
+		form.Add("confirm_password", newPassword)
+		req := httptest.NewRequest(http.MethodPost, "/user", strings.NewReader(form.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+
+		return rr
+	}
+
+	rr := change("short")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "at least 8 characters")
+
+	stored, err := db.GetUserByID(context.Background(), user.Id)
+	require.NoError(t, err)
+	assert.True(t, utils.CheckPassword("password123", stored.Hash), "a weak password is not saved")
+
+	change("a-much-longer-password")
+
+	stored, err = db.GetUserByID(context.Background(), user.Id)
+	require.NoError(t, err)
+	assert.True(t, utils.CheckPassword("a-much-longer-password", stored.Hash))
+}
+
 func TestUIHandleOTPStartEnrollment_InvalidPassword(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -574,3 +944,474 @@ func TestUIHandleOTPDisable_Unauthenticated(t *testing.T) {
 	assert.Equal(t, http.StatusFound, rr.Code)
 	assert.Equal(t, "/login", rr.Header().Get("Location"))
 }
+
+func TestUIImpersonation_BannerAndExit(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	handler := server.authMiddleware(server.router)
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), writer))
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	admin.Hash = hash
+	require.NoError(t, db.UpdateUser(context.Background(), admin, "hash"))
+
+	loginInput := &LoginInput{}
+	loginInput.Body.Email = admin.Email
+	loginInput.Body.Password = "password123"
+	loginResp, err := server.handleLoginToken(context.Background(), loginInput)
+	require.NoError(t, err)
+	adminSession := loginResp.Body.Token
+
+	form := url.Values{"email": {writer.Email}}
+	req := httptest.NewRequest("POST", "/admin/impersonate", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: CookieName, Value: adminSession})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusFound, rr.Code)
+	cookies := map[string]string{}
+	for _, c := range rr.Result().Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	assert.Equal(t, adminSession, cookies[ImpersonatorCookieName])
+	require.NotEmpty(t, cookies[CookieName])
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: CookieName, Value: cookies[CookieName]})
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Exit Impersonation")
+	assert.Contains(t, rr.Body.String(), "impersonated by admin@test.com")
+
+	req = httptest.NewRequest("POST", "/impersonate/exit", nil)
+	req.AddCookie(&http.Cookie{Name: CookieName, Value: cookies[CookieName]})
+	req.AddCookie(&http.Cookie{Name: ImpersonatorCookieName, Value: adminSession})
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusFound, rr.Code)
+	restored := map[string]string{}
+	for _, c := range rr.Result().Cookies() {
+		restored[c.Name] = c.Value
+	}
+	assert.Equal(t, adminSession, restored[CookieName])
+	assert.Empty(t, restored[ImpersonatorCookieName])
+}
+
+func TestUIRenderRecentChanges(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(context.Background(), article.Id, "# Home v1", "writer@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(context.Background(), draft.Id))
+
+	req := httptest.NewRequest("GET", "/recent", nil)
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Recent Changes")
+	assert.Contains(t, rr.Body.String(), `href="/wiki/home/history/1"`)
+	assert.NotContains(t, rr.Body.String(), "writer@example.com")
+}
+
+func TestUIRenderArticle_LastEdited(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(context.Background(), article.Id, "# Home v1", "writer@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(context.Background(), draft.Id))
+
+	req := httptest.NewRequest("GET", "/wiki/home", nil)
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Last edited just now")
+	assert.NotContains(t, rr.Body.String(), "writer@example.com")
+}
+
+func TestUINewArticle_FromTemplate(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	tmpl := &models.Template{Name: "Meeting Notes", Content: "# {{title}}\n\n## Agenda\n"}
+	require.NoError(t, db.CreateTemplate(context.Background(), tmpl))
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/new?template=%d", tmpl.Id), nil)
+	req = req.WithContext(contextWithUser(user))
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), fmt.Sprintf(`<option value="%d" selected>Meeting Notes</option>`, tmpl.Id))
+
+	form := url.Values{}
+	form.Set("title", "Weekly Sync")
+	form.Set("template", fmt.Sprint(tmpl.Id))
+
+	req = httptest.NewRequest("POST", "/new", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(contextWithUser(user))
+	rr = httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusFound, rr.Code)
+
+	var draftID int
+	_, err := fmt.Sscanf(rr.Header().Get("Location"), "/editor/%d", &draftID)
+	require.NoError(t, err)
+
+	_, content, err := db.GetDraftByID(context.Background(), draftID)
+	require.NoError(t, err)
+	assert.Equal(t, "# Weekly Sync\n\n## Agenda\n", content)
+}
+
+func TestUIRenderAdminStats(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/admin/stats", nil)
+	req = req.WithContext(contextWithUser(admin))
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Database Size")
+	assert.Contains(t, rr.Body.String(), "(1 admin, 0 editor, 0 user)")
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	req = httptest.NewRequest("GET", "/admin/stats", nil)
+	req = req.WithContext(contextWithUser(writer))
+	rr = httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUIActionSaveDraft_BodyTooLarge(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.maxRequestBodySize = 1024
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	form := url.Values{}
+	form.Set("content", strings.Repeat("a", 2048))
+
+	req := httptest.NewRequest("POST", "/editor/1/save", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.ContentLength = -1
+	req = req.WithContext(contextWithUser(admin))
+	rr := httptest.NewRecorder()
+
+	server.bodyLimitMiddleware(server.router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, rr.Body.String(), "maximum size of 1024 bytes")
+}
+
+func TestUIActionSaveDraft_StaleEditor(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	_, draft, err := db.CreateArticleWithDraft(context.Background(), "Stale Editor", user.Email)
+	require.NoError(t, err)
+
+	editorURL := fmt.Sprintf("/editor/%d", draft.Id)
+
+	req := httptest.NewRequest("GET", editorURL, nil)
+	req = req.WithContext(contextWithUser(user))
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	loaded, _, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	loadedAt := loaded.UpdatedAt.Format(time.RFC3339Nano)
+	assert.Contains(t, rr.Body.String(), `name="updatedAt" value="`+loadedAt+`"`)
+
+	save := func(content string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("content", content)
+		form.Set("updatedAt", loadedAt)
+
+		req := httptest.NewRequest("POST", editorURL+"/save", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(contextWithUser(user))
+		rr := httptest.NewRecorder()
+
+		server.router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	assert.Equal(t, http.StatusFound, save("# Tab one, saved").Code)
+	assert.Equal(t, http.StatusConflict, save("# Tab two, stale").Code)
+
+	_, content, err := db.GetDraftByID(context.Background(), draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tab one, saved", content)
+}
+
+func TestUIActionSaveDraft_UnchangedContent(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	article, err := db.GetArticleBySlug(context.Background(), "home")
+	require.NoError(t, err)
+	draft, err := db.CreateDraft(context.Background(), article.Id, article.Data+"\nMore", user.Email)
+	require.NoError(t, err)
+
+	form := url.Values{}
+	form.Set("content", article.Data)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/editor/%d/save", draft.Id), strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(contextWithUser(user))
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/wiki/home", rr.Header().Get("Location"), "the removed draft's editor is not reopened")
+}
+
+func TestUIError_ContentNegotiation(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		wantJSON bool
+	}{
+		{"browser", map[string]string{"Accept": "text/html,application/xhtml+xml,*/*;q=0.8"}, false},
+		{"no accept", nil, false},
+		{"json accept", map[string]string{"Accept": "application/json"}, true},
+		{"problem json accept", map[string]string{"Accept": "application/problem+json"}, true},
+		{"html preferred", map[string]string{"Accept": "text/html, application/json;q=0.5"}, false},
+		{"json preferred", map[string]string{"Accept": "text/html;q=0.5, application/json"}, true},
+		{"xhr", map[string]string{"X-Requested-With": "XMLHttpRequest"}, true},
+		{"htmx", map[string]string{"HX-Request": "true"}, true},
+		{"htmx boost", map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/logs", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			req = req.WithContext(contextWithUser(writer))
+			rr := httptest.NewRecorder()
+
+			server.router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusForbidden, rr.Code)
+
+			if !tt.wantJSON {
+				assert.Contains(t, rr.Header().Get("Content-Type"), "text/html")
+				assert.Contains(t, rr.Body.String(), "Only admins")
+				return
+			}
+
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+
+			var problem struct {
+				Status int    `json:"status"`
+				Detail string `json:"detail"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			assert.Equal(t, http.StatusForbidden, problem.Status)
+			assert.Contains(t, problem.Detail, "Only admins")
+		})
+	}
+}
+
+func TestUIError_InternalErrorJSONHidesDetails(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+
+	server.uiError(rr, req, fmt.Errorf("connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "connection refused")
+	assert.Contains(t, rr.Body.String(), "Something went wrong")
+}
+
+func TestUIHandleLoginSubmit_XHRFailure(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	form := url.Values{}
+	form.Add("email", "admin@test.com")
+	form.Add("password", "wrong-password")
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("X-Requested-With", "XMLHttpRequest")
+	rr := httptest.NewRecorder()
+
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "Invalid email or password")
+}
+
+func TestUIWatchToggle(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	render := func() string {
+		req := httptest.NewRequest("GET", "/wiki/home", nil)
+		req = req.WithContext(contextWithUser(admin))
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.Contains(t, render(), `action="/wiki/home/watch"`)
+
+	req := httptest.NewRequest("POST", "/wiki/home/watch", nil)
+	req = req.WithContext(contextWithUser(admin))
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/wiki/home", rr.Header().Get("Location"))
+	assert.Contains(t, render(), `action="/wiki/home/unwatch"`)
+
+	req = httptest.NewRequest("GET", "/dashboard", nil)
+	req = req.WithContext(contextWithUser(admin))
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), "Watched Articles")
+	assert.Contains(t, rr.Body.String(), `action="/wiki/home/unwatch"`)
+}
+
+func TestUIRenderArticle_CustomCode(t *testing.T) {
+	db := newTestDB(t)
+	server := newCustomCodeServer(t, db)
+	ctx := context.Background()
+
+	demo := publishArticle(t, db, "Demo", "# Demo")
+	publishArticle(t, db, "Other", "# Other")
+
+	article, err := db.GetArticleBySlug(ctx, demo.Slug)
+	require.NoError(t, err)
+	require.NoError(t, db.SetArticleCustomCode(ctx, article.Id, ".demo { color: red; }", "window.demo = 1;"))
+
+	req := httptest.NewRequest("GET", "/wiki/"+demo.Slug, nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	policy := rr.Header().Get("Content-Security-Policy")
+	require.Contains(t, policy, "'nonce-")
+	nonce, _, _ := strings.Cut(strings.SplitN(policy, "'nonce-", 2)[1], "'")
+
+	body := rr.Body.String()
+	assert.Contains(t, body, `<style nonce="`+nonce+`">.demo { color: red; }</style>`)
+	assert.Contains(t, body, `<script nonce="`+nonce+`">window.demo = 1;</script>`)
+	assert.Contains(t, body, `<script nonce="`+nonce+`">`+"\n    htmx.config", "the page's own script is allowed too")
+	assert.Contains(t, body, `<body hx-boost="false">`, "links away from the page reload it")
+
+	req = httptest.NewRequest("GET", "/wiki/"+demo.Slug, nil)
+	req.Header.Set("HX-Request", "true")
+	req.Header.Set("HX-Boosted", "true")
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	assert.Equal(t, "/wiki/"+demo.Slug, rr.Header().Get("HX-Redirect"), "boosted navigation loads the page in full")
+	assert.Empty(t, rr.Body.String())
+
+	req = httptest.NewRequest("GET", "/wiki/other", nil)
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+	assert.NotContains(t, rr.Body.String(), "window.demo")
+	assert.NotContains(t, rr.Body.String(), ".demo {")
+	assert.Contains(t, rr.Body.String(), `<body hx-boost="true">`)
+}
+
+func TestUIRenderEditor_SplitPreview(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	user, err := db.GetUserByEmail(ctx, "admin@test.com")
+	require.NoError(t, err)
+
+	_, draft, err := db.CreateArticleWithDraft(ctx, "Split Editor", user.Email)
+	require.NoError(t, err)
+
+	render := func() string {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/editor/%d", draft.Id), nil)
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.NotContains(t, render(), `id="live-preview"`)
+
+	form := url.Values{"editor_mode": {"split-preview"}}
+	req := httptest.NewRequest("POST", "/user/editor", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `<option value="split-preview" selected>`)
+
+	body := render()
+	assert.Contains(t, body, `id="live-preview"`)
+	assert.Contains(t, body, fmt.Sprintf("/api/drafts/%d/preview", draft.Id))
+}
@@ -0,0 +1,107 @@
+//go:build ui && plugins
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetArticle_RenderHTMLMatchesUI(t *testing.T) {
+	db := newTestDB(t)
+
+	pluginDir := t.TempDir()
+	pluginContent := `
+function onArticleRender(html, ctx) {
+	return html + "<p>rendered-for-role-" + (ctx.User ? ctx.User.role : 0) + "</p>";
+}
+`
+	err := os.WriteFile(filepath.Join(pluginDir, "01-role.js"), []byte(pluginContent), 0644)
+	require.NoError(t, err)
+
+	server := newTestServerWithPlugins(t, db, pluginDir)
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		marker string
+	}{
+		{"anonymous", context.Background(), "rendered-for-role-0"},
+		{"writer", contextWithUser(&models.User{Email: "writer@example.com", Role: models.WRITE}), "rendered-for-role-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.handleGetArticle(tt.ctx, &ArticleViewInput{Slug: "home", Render: "html"})
+			require.NoError(t, err)
+			assert.Contains(t, resp.Body.HTML, tt.marker)
+
+			req := httptest.NewRequest(http.MethodGet, "/wiki/home", nil).WithContext(tt.ctx)
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), resp.Body.HTML, "the API returns what the UI shows")
+		})
+	}
+}
+
+func TestUIRenderArticle_PreviewAsRole(t *testing.T) {
+	db := newTestDB(t)
+
+	pluginDir := t.TempDir()
+	pluginContent := `
+function onArticleRender(html, ctx) {
+	return html + "<p>rendered-for-role-" + (ctx.User ? ctx.User.role : 0) + "</p>";
+}
+`
+	err := os.WriteFile(filepath.Join(pluginDir, "01-role.js"), []byte(pluginContent), 0644)
+	require.NoError(t, err)
+
+	server := newTestServerWithPlugins(t, db, pluginDir)
+
+	admin := &models.User{Email: "admin@example.com", Role: models.ADMIN}
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		query  string
+		marker string
+	}{
+		{"admin", admin, "", "rendered-for-role-3"},
+		{"admin as read", admin, "?as=read", "rendered-for-role-1"},
+		{"admin as anonymous", admin, "?as=anonymous", "rendered-for-role-0"},
+		{"non-admins cannot preview", writer, "?as=read", "rendered-for-role-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/wiki/home"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, req.WithContext(contextWithUser(tt.user)))
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.marker)
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/wiki/home?as=read", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(admin)))
+	assert.Contains(t, rr.Body.String(), "Previewing plugin output for the <strong>read</strong> role")
+	assert.Equal(t, models.ADMIN, admin.Role, "the admin's own user is unchanged")
+
+	req = httptest.NewRequest(http.MethodGet, "/wiki/home?as=owner", nil)
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(admin)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"wikilite/pkg/models"
 	"wikilite/pkg/utils"
 
@@ -19,6 +21,12 @@ type UserIDInput struct {
 	ID int `doc:"The numeric ID of the user" path:"id"`
 }
 
+// UserPaginationInput represents the input for paginating users.
+type UserPaginationInput struct {
+	Page  int `default:"1"                                    doc:"Page number" minimum:"1" query:"page"`
+	Limit int `doc:"Items per page (capped by server config)" query:"limit"`
+}
+
 // CreateUserInput represents the input for creating a new user.
 type CreateUserInput struct {
 	Body struct {
@@ -59,10 +67,46 @@ type UserOutput struct {
 	}
 }
 
-// UserListOutput represents the output for a list of users.
+// UserListOutput represents the output for a paginated list of users.
 type UserListOutput struct {
 	Body struct {
 		Users []*SafeUser `json:"users"`
+		Total int64       `json:"total"`
+		Page  int         `json:"page"`
+		Limit int         `json:"limit"`
+	}
+}
+
+// UserSummaryOutput represents the output for the current user's summary counts.
+type UserSummaryOutput struct {
+	Body struct {
+		DraftCount   int `json:"draftCount"`
+		ArticleCount int `json:"articleCount"`
+	}
+}
+
+// MeOutput represents the output for the current user and what they are allowed to do.
+type MeOutput struct {
+	Body struct {
+		User       *SafeUser         `json:"user"`
+		CanWrite   bool              `doc:"Whether the user can create and edit articles" json:"canWrite"`
+		IsAdmin    bool              `doc:"Whether the user is an admin"                  json:"isAdmin"`
+		OTPEnabled bool              `doc:"Whether two-factor authentication is enabled"  json:"otpEnabled"`
+		EditorMode models.EditorMode `doc:"Layout of the article editor the user prefers" json:"editorMode"`
+	}
+}
+
+// PreferencesInput represents the input for updating the current user's preferences.
+type PreferencesInput struct {
+	Body struct {
+		EditorMode models.EditorMode `doc:"Layout of the article editor: the markdown editor alone, or with a live preview" enum:"markdown,split-preview" json:"editorMode" required:"true"`
+	}
+}
+
+// PreferencesOutput represents the current user's preferences.
+type PreferencesOutput struct {
+	Body struct {
+		EditorMode models.EditorMode `json:"editorMode"`
 	}
 }
 
@@ -78,6 +122,16 @@ func (s *Server) registerUserRoutes() {
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handleCreateUser)
 
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-users",
+		Method:      http.MethodGet,
+		Path:        "/api/users",
+		Summary:     "List Users",
+		Description: "Retrieve a paginated list of users ordered by email (Admin only).",
+		Tags:        []string{"Users"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleListUsers)
+
 	huma.Register(s.api, huma.Operation{
 		OperationID: "get-user",
 		Method:      http.MethodGet,
@@ -114,6 +168,46 @@ func (s *Server) registerUserRoutes() {
 		Tags:        []string{"Users"},
 		Security:    []map[string][]string{{"bearer": {}}},
 	}, s.handleDeleteUser)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "logout-all-user-sessions",
+		Method:      http.MethodPost,
+		Path:        "/api/users/{email}/logout-all",
+		Summary:     "Logout All User Sessions",
+		Description: "Invalidate every active token issued to a local user (Admin only).",
+		Tags:        []string{"Users"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleLogoutAllUserSessions)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-my-summary",
+		Method:      http.MethodGet,
+		Path:        "/api/me/summary",
+		Summary:     "Get My Summary",
+		Description: "Get draft and article counts for the current user.",
+		Tags:        []string{"Users"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleGetMySummary)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-me",
+		Method:      http.MethodGet,
+		Path:        "/api/me",
+		Summary:     "Get Current User",
+		Description: "Get the signed-in user and their capabilities, e.g. to bootstrap a single page app.",
+		Tags:        []string{"Users"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleGetMe)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "update-my-preferences",
+		Method:      http.MethodPut,
+		Path:        "/api/me/preferences",
+		Summary:     "Update My Preferences",
+		Description: "Update the preferences of the signed-in user, such as the editor layout.",
+		Tags:        []string{"Users"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleUpdatePreferences)
 }
 
 // toSafeUser converts a user model to a safe user model.
@@ -138,15 +232,21 @@ func (s *Server) handleCreateUser(
 		return nil, huma.Error403Forbidden("Only admins can create users")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := s.validateCreateUser(input)
+	if len(errs) > 0 {
+		return nil, huma.Error400BadRequest("Invalid user details", errs...)
+	}
+
 	var hash string
 
 	if input.Body.IsExternal {
 		hash = ""
 	} else {
-		if input.Body.Password == nil || *input.Body.Password == "" {
-			return nil, huma.Error400BadRequest("Password is required for local users")
-		}
-
 		hashed, err := utils.HashPassword(*input.Body.Password)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("Failed to process password", err)
@@ -156,24 +256,71 @@ func (s *Server) handleCreateUser(
 	}
 
 	newUser := &models.User{
-		Name:       input.Body.Name,
+		Name:       strings.TrimSpace(input.Body.Name),
 		Email:      input.Body.Email,
 		Hash:       hash,
 		IsExternal: input.Body.IsExternal,
 		Role:       models.UserRole(input.Body.Role),
 	}
 
-	err := s.db.CreateUser(ctx, newUser)
+	err = s.db.CreateUser(ctx, newUser)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Failed to create user", err)
 	}
 
+	s.emitWebhook(models.EventUserCreated, toSafeUser(newUser))
+
 	resp := &UserOutput{}
 	resp.Body.User = toSafeUser(newUser)
 
 	return resp, nil
 }
 
+// validateCreateUser checks the fields of a create user request and returns one error per
+// invalid field, located by its JSON path so clients can map it to a form field.
+func (s *Server) validateCreateUser(input *CreateUserInput) []error {
+	var errs []error
+
+	if strings.TrimSpace(input.Body.Name) == "" {
+		errs = append(errs, &huma.ErrorDetail{
+			Message:  "Name is required",
+			Location: "body.name",
+		})
+	}
+
+	if strings.TrimSpace(input.Body.Email) == "" {
+		errs = append(errs, &huma.ErrorDetail{
+			Message:  "Email is required",
+			Location: "body.email",
+		})
+	}
+
+	role := models.UserRole(input.Body.Role)
+	if role < models.READ || role > models.ADMIN {
+		errs = append(errs, &huma.ErrorDetail{
+			Message:  "Role must be 1 (Read), 2 (Write), or 3 (Admin)",
+			Location: "body.role",
+			Value:    input.Body.Role,
+		})
+	}
+
+	if !input.Body.IsExternal {
+		if input.Body.Password == nil || *input.Body.Password == "" {
+			errs = append(errs, &huma.ErrorDetail{
+				Message:  "Password is required for local users",
+				Location: "body.password",
+			})
+		} else if err := utils.ValidatePassword(*input.Body.Password, s.passwordPolicy); err != nil {
+			errs = append(errs, &huma.ErrorDetail{
+				Message:  err.Error(),
+				Location: "body.password",
+			})
+		}
+	}
+
+	return errs
+}
+
 // handleGetUser handles getting a user by email.
 func (s *Server) handleGetUser(ctx context.Context, input *UserEmailInput) (*UserOutput, error) {
 	reqUser := getUserFromContext(ctx)
@@ -181,7 +328,7 @@ func (s *Server) handleGetUser(ctx context.Context, input *UserEmailInput) (*Use
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
-	if reqUser.Email != input.Email && reqUser.Role != models.ADMIN {
+	if reqUser.Email != utils.NormalizeEmail(input.Email) && reqUser.Role != models.ADMIN {
 		return nil, huma.Error403Forbidden("You can only view your own profile")
 	}
 
@@ -200,6 +347,37 @@ func (s *Server) handleGetUser(ctx context.Context, input *UserEmailInput) (*Use
 	return resp, nil
 }
 
+// handleListUsers handles listing a page of users.
+func (s *Server) handleListUsers(
+	ctx context.Context,
+	input *UserPaginationInput,
+) (*UserListOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can list users")
+	}
+
+	var offset int
+	input.Page, input.Limit, offset = s.paginate(input.Page, input.Limit)
+
+	users, total, err := s.db.GetUsers(ctx, input.Limit, offset)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	resp := &UserListOutput{}
+	resp.Body.Users = make([]*SafeUser, len(users))
+	for i, u := range users {
+		resp.Body.Users[i] = toSafeUser(u)
+	}
+
+	resp.Body.Total = total
+	resp.Body.Page = input.Page
+	resp.Body.Limit = input.Limit
+
+	return resp, nil
+}
+
 // handleGetUserByID handles getting a user by ID.
 func (s *Server) handleGetUserByID(ctx context.Context, input *UserIDInput) (*UserOutput, error) {
 	user := getAdminUserFromContext(ctx)
@@ -232,6 +410,11 @@ func (s *Server) handleUpdateUser(
 		return nil, huma.Error401Unauthorized("Authentication required")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	targetUser, err := s.db.GetUserByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Database error", err)
@@ -257,12 +440,24 @@ func (s *Server) handleUpdateUser(
 	}
 
 	if input.Body.Email != nil {
-		targetUser.Email = *input.Body.Email
+		targetUser.Email = utils.NormalizeEmail(*input.Body.Email)
 
 		cols = append(cols, "email")
 	}
 
 	if input.Body.Password != nil {
+		// The policy applies to passwords users choose for themselves; an admin resetting
+		// another user's password is not held to it.
+		if isSelf {
+			err := utils.ValidatePassword(*input.Body.Password, s.passwordPolicy)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid user details", &huma.ErrorDetail{
+					Message:  err.Error(),
+					Location: "body.password",
+				})
+			}
+		}
+
 		hashed, err := utils.HashPassword(*input.Body.Password)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("Failed to process password", err)
@@ -310,6 +505,11 @@ func (s *Server) handleDeleteUser(
 		return nil, huma.Error403Forbidden("Only admins can delete users")
 	}
 
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	targetUser, err := s.db.GetUserByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Database error", err)
@@ -328,5 +528,131 @@ func (s *Server) handleDeleteUser(
 		return nil, huma.Error500InternalServerError("Failed to delete user", err)
 	}
 
+	s.emitWebhook(models.EventUserDeleted, toSafeUser(targetUser))
+
 	return &struct{ Status int }{Status: http.StatusNoContent}, nil
 }
+
+// handleLogoutAllUserSessions handles revoking all active tokens of a user.
+func (s *Server) handleLogoutAllUserSessions(
+	ctx context.Context,
+	input *UserEmailInput,
+) (*struct{ Status int }, error) {
+	reqUser := getAdminUserFromContext(ctx)
+	if reqUser == nil {
+		return nil, huma.Error403Forbidden("Only admins can log out other users")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	targetUser, err := s.db.GetUserByEmail(ctx, utils.NormalizeEmail(input.Email))
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if targetUser == nil {
+		return nil, huma.Error404NotFound("User not found")
+	}
+
+	if targetUser.IsExternal {
+		return nil, huma.Error400BadRequest(
+			"Sessions of external users are managed by their identity provider",
+		)
+	}
+
+	err = s.db.RevokeUserTokens(ctx, targetUser)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to revoke sessions", err)
+	}
+
+	_ = s.db.CreateLogEntry(
+		ctx,
+		models.LevelWarning,
+		"AUTH",
+		"Sessions revoked",
+		fmt.Sprintf("Admin: %s | User: %s", reqUser.Email, targetUser.Email),
+	)
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
+
+// handleGetMe handles the request to get the current user.
+func (s *Server) handleGetMe(ctx context.Context, _ *struct{}) (*MeOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	resp := &MeOutput{}
+	resp.Body.User = toSafeUser(user)
+	resp.Body.CanWrite = user.Role >= models.WRITE
+	resp.Body.IsAdmin = user.Role == models.ADMIN
+	resp.Body.OTPEnabled = user.OTPSecret != ""
+	resp.Body.EditorMode = editorMode(user)
+
+	return resp, nil
+}
+
+// handleUpdatePreferences handles updating the preferences of the current user.
+func (s *Server) handleUpdatePreferences(
+	ctx context.Context,
+	input *PreferencesInput,
+) (*PreferencesOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	user.EditorMode = input.Body.EditorMode
+
+	err = s.db.UpdateUser(ctx, user, "editor_mode")
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to update preferences", err)
+	}
+
+	resp := &PreferencesOutput{}
+	resp.Body.EditorMode = user.EditorMode
+
+	return resp, nil
+}
+
+// editorMode returns the editor layout preferred by user, defaulting to the markdown editor.
+func editorMode(user *models.User) models.EditorMode {
+	if user == nil || user.EditorMode == "" {
+		return models.EditorMarkdown
+	}
+
+	return user.EditorMode
+}
+
+// handleGetMySummary handles getting draft and article counts for the current user.
+func (s *Server) handleGetMySummary(ctx context.Context, _ *struct{}) (*UserSummaryOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	draftCount, err := s.db.CountDraftsByUser(ctx, user.Email)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	articleCount, err := s.db.CountArticlesByUser(ctx, user.Email)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	resp := &UserSummaryOutput{}
+	resp.Body.DraftCount = draftCount
+	resp.Body.ArticleCount = articleCount
+
+	return resp, nil
+}
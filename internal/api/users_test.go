@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/stretchr/testify/assert"
@@ -112,6 +115,132 @@ func TestHandleCreateUser_MissingPassword(t *testing.T) {
 	assert.Equal(t, 400, humaErr.Status)
 }
 
+func TestHandleCreateUser_ValidationErrors(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+	ctx := contextWithUser(admin)
+
+	validPassword := "password123"
+	shortPassword := "short"
+
+	testCases := []struct {
+		name     string
+		modify   func(input *CreateUserInput)
+		location string
+	}{
+		{
+			name:     "empty name",
+			modify:   func(input *CreateUserInput) { input.Body.Name = "   " },
+			location: "body.name",
+		},
+		{
+			name:     "empty email",
+			modify:   func(input *CreateUserInput) { input.Body.Email = "" },
+			location: "body.email",
+		},
+		{
+			name:     "role too low",
+			modify:   func(input *CreateUserInput) { input.Body.Role = 0 },
+			location: "body.role",
+		},
+		{
+			name:     "role too high",
+			modify:   func(input *CreateUserInput) { input.Body.Role = 4 },
+			location: "body.role",
+		},
+		{
+			name:     "missing password",
+			modify:   func(input *CreateUserInput) { input.Body.Password = nil },
+			location: "body.password",
+		},
+		{
+			name:     "short password",
+			modify:   func(input *CreateUserInput) { input.Body.Password = &shortPassword },
+			location: "body.password",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := &CreateUserInput{}
+			input.Body.Name = "New User"
+			input.Body.Email = "new@user.com"
+			input.Body.Password = &validPassword
+			input.Body.Role = int(models.WRITE)
+			tc.modify(input)
+
+			_, err := server.handleCreateUser(ctx, input)
+			require.Error(t, err)
+
+			var humaErr *huma.ErrorModel
+			ok := errors.As(err, &humaErr)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+			require.Len(t, humaErr.Errors, 1)
+			assert.Equal(t, tc.location, humaErr.Errors[0].Location)
+		})
+	}
+}
+
+func TestHandleCreateUser_MultipleValidationErrors(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+	ctx := contextWithUser(admin)
+
+	input := &CreateUserInput{}
+	input.Body.Email = "new@user.com"
+	input.Body.Role = 9
+
+	_, err = server.handleCreateUser(ctx, input)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+
+	locations := make([]string, 0, len(humaErr.Errors))
+	for _, detail := range humaErr.Errors {
+		locations = append(locations, detail.Location)
+	}
+	assert.ElementsMatch(t, []string{"body.name", "body.role", "body.password"}, locations)
+}
+
+func TestHandleCreateUser_PasswordPolicy(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.passwordPolicy = utils.PasswordPolicy{MinLength: 12, RequireDigit: true}
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+	ctx := contextWithUser(admin)
+
+	password := "longenoughbutnodigits"
+	input := &CreateUserInput{}
+	input.Body.Name = "New User"
+	input.Body.Email = "new@user.com"
+	input.Body.Password = &password
+	input.Body.Role = int(models.READ)
+
+	_, err = server.handleCreateUser(ctx, input)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	require.Len(t, humaErr.Errors, 1)
+	assert.Contains(t, humaErr.Errors[0].Message, "digit")
+
+	password = "longenough4sure"
+	_, err = server.handleCreateUser(ctx, input)
+	require.NoError(t, err)
+}
+
 func TestHandleGetUser_Success_Self(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -230,6 +359,57 @@ func TestHandleGetUser_NotFound_AsAdmin(t *testing.T) {
 	assert.Equal(t, 404, humaErr.Status)
 }
 
+func TestHandleListUsers(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	for _, email := range []string{"carol@example.com", "bob@example.com"} {
+		require.NoError(t, db.CreateUser(context.Background(), &models.User{
+			Name: "User", Email: email, Role: models.READ,
+		}))
+	}
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+	ctx := contextWithUser(admin)
+
+	resp, err := server.handleListUsers(ctx, &UserPaginationInput{Page: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Users, 3)
+	assert.Equal(t, "admin@test.com", resp.Body.Users[0].Email)
+	assert.Equal(t, "bob@example.com", resp.Body.Users[1].Email)
+	assert.Equal(t, int64(3), resp.Body.Total)
+	assert.Equal(t, server.defaultPageSize, resp.Body.Limit)
+
+	resp, err = server.handleListUsers(ctx, &UserPaginationInput{Page: 2, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Users, 1)
+	assert.Equal(t, "carol@example.com", resp.Body.Users[0].Email)
+	assert.Equal(t, 2, resp.Body.Page)
+	assert.Equal(t, int64(3), resp.Body.Total)
+
+	server.maxPageSize = 2
+
+	resp, err = server.handleListUsers(ctx, &UserPaginationInput{Page: 1, Limit: 50})
+	require.NoError(t, err)
+	assert.Len(t, resp.Body.Users, 2)
+	assert.Equal(t, 2, resp.Body.Limit, "the limit is clamped to the maximum page size")
+}
+
+func TestHandleListUsers_Unauthorized(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users?page=1&limit=10", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
 func TestHandleGetUserByID_Success(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -354,6 +534,39 @@ func TestHandleUpdateUser_Success_Admin(t *testing.T) {
 	assert.Equal(t, models.ADMIN, updatedUser.Role)
 }
 
+func TestHandleUpdateUser_PasswordPolicy(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	server.passwordPolicy = utils.PasswordPolicy{MinLength: 12}
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	weak := "short"
+	input := &UpdateUserInput{Email: user.Email}
+	input.Body.Password = &weak
+
+	_, err = server.handleUpdateUser(contextWithUser(user), input)
+	require.Error(t, err, "users changing their own password must meet the policy")
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusBadRequest, humaErr.Status)
+
+	_, err = server.handleUpdateUser(contextWithUser(admin), input)
+	require.NoError(t, err, "admin resets are not held to the policy")
+
+	newName := "Renamed Admin"
+	input = &UpdateUserInput{Email: admin.Email}
+	input.Body.Name = &newName
+
+	_, err = server.handleUpdateUser(contextWithUser(admin), input)
+	require.NoError(t, err, "updates without a new password skip the policy")
+}
+
 func TestHandleUpdateUser_Forbidden(t *testing.T) {
 	db := newTestDB(t)
 	server := newTestServer(t, db)
@@ -527,3 +740,245 @@ func TestHandleDeleteUser_Self(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, 400, humaErr.Status)
 }
+
+func TestHandleGetMe(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		canWrite bool
+		isAdmin  bool
+		otp      bool
+	}{
+		{"reader", &models.User{Email: "reader@example.com", Role: models.READ}, false, false, false},
+		{"writer with 2FA", &models.User{Email: "writer@example.com", Role: models.WRITE, OTPSecret: "secret"}, true, false, true},
+		{"admin", &models.User{Email: "admin@example.com", Role: models.ADMIN}, true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.handleGetMe(contextWithUser(tt.user), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.user.Email, resp.Body.User.Email)
+			assert.Equal(t, tt.user.Role, resp.Body.User.Role)
+			assert.Equal(t, tt.canWrite, resp.Body.CanWrite)
+			assert.Equal(t, tt.isAdmin, resp.Body.IsAdmin)
+			assert.Equal(t, tt.otp, resp.Body.OTPEnabled)
+		})
+	}
+}
+
+func TestHandleUpdatePreferences(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	user, err := db.GetUserByEmail(ctx, "admin@test.com")
+	require.NoError(t, err)
+
+	me, err := server.handleGetMe(contextWithUser(user), nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.EditorMarkdown, me.Body.EditorMode, "the markdown editor is the default")
+
+	input := &PreferencesInput{}
+	input.Body.EditorMode = models.EditorSplitPreview
+
+	resp, err := server.handleUpdatePreferences(contextWithUser(user), input)
+	require.NoError(t, err)
+	assert.Equal(t, models.EditorSplitPreview, resp.Body.EditorMode)
+
+	stored, err := db.GetUserByEmail(ctx, "admin@test.com")
+	require.NoError(t, err)
+	assert.Equal(t, models.EditorSplitPreview, stored.EditorMode)
+
+	me, err = server.handleGetMe(contextWithUser(stored), nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.EditorSplitPreview, me.Body.EditorMode)
+}
+
+func TestHandleUpdatePreferences_HTTP(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	for body, status := range map[string]int{
+		`{"editorMode": "split-preview"}`: http.StatusOK,
+		`{"editorMode": "wysiwyg"}`:       http.StatusUnprocessableEntity,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/api/me/preferences", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+		assert.Equal(t, status, rr.Code, body)
+	}
+}
+
+func TestHandleGetMe_Anonymous(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	rr := httptest.NewRecorder()
+	server.authMiddleware(server.router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestHandleGetMySummary_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	_, _, err := db.CreateArticleWithDraft(context.Background(), "Summary Article", user.Email)
+	require.NoError(t, err)
+
+	resp, err := server.handleGetMySummary(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Body.DraftCount)
+	assert.Equal(t, 1, resp.Body.ArticleCount)
+}
+
+func TestHandleGetMySummary_Unauthorized(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	_, err := server.handleGetMySummary(context.Background(), nil)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, humaErr.Status)
+}
+
+func TestHandleLogoutAllUserSessions_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	err = db.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+
+	token, err := server.signSessionToken(user)
+	require.NoError(t, err)
+
+	_, err = server.validateToken(context.Background(), token)
+	require.NoError(t, err)
+
+	input := &UserEmailInput{Email: user.Email}
+	resp, err := server.handleLogoutAllUserSessions(contextWithUser(admin), input)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.Status)
+
+	_, err = server.validateToken(context.Background(), token)
+	assert.Error(t, err)
+
+	updated, err := db.GetUserByEmail(context.Background(), user.Email)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated.TokenVersion)
+
+	fresh, err := server.signSessionToken(updated)
+	require.NoError(t, err)
+
+	_, err = server.validateToken(context.Background(), fresh)
+	assert.NoError(t, err)
+}
+
+func TestHandleLogoutAllUserSessions_Errors(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	writer := &models.User{Name: "Writer", Email: "writer@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(context.Background(), writer))
+
+	external := &models.User{
+		Name:       "External",
+		Email:      "external@example.com",
+		Role:       models.READ,
+		IsExternal: true,
+	}
+	require.NoError(t, db.CreateUser(context.Background(), external))
+
+	tests := []struct {
+		name   string
+		caller *models.User
+		target string
+		status int
+	}{
+		{"non-admin caller", writer, admin.Email, http.StatusForbidden},
+		{"unknown target", admin, "missing@example.com", http.StatusNotFound},
+		{"external target", admin, external.Email, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &UserEmailInput{Email: tt.target}
+			_, err := server.handleLogoutAllUserSessions(contextWithUser(tt.caller), input)
+			require.Error(t, err)
+
+			var humaErr *huma.ErrorModel
+			require.True(t, errors.As(err, &humaErr))
+			assert.Equal(t, tt.status, humaErr.Status)
+		})
+	}
+}
+
+func TestDisableUser_BlocksActiveSession(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	handler := server.authMiddleware(server.router)
+
+	admin, err := db.GetUserByEmail(context.Background(), "admin@test.com")
+	require.NoError(t, err)
+
+	password := "password123"
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	user.Hash = hash
+	require.NoError(t, db.CreateUser(context.Background(), user))
+
+	login := &LoginInput{}
+	login.Body.Email = user.Email
+	login.Body.Password = password
+	session, err := server.handleLoginToken(context.Background(), login)
+	require.NoError(t, err)
+
+	summary := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/me/summary", nil)
+		req.Header.Set("Authorization", "Bearer "+session.Body.Token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, summary())
+
+	disabled := true
+	update := &UpdateUserInput{Email: user.Email}
+	update.Body.Disabled = &disabled
+	_, err = server.handleUpdateUser(contextWithUser(admin), update)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, summary())
+
+	enabled := false
+	update.Body.Disabled = &enabled
+	_, err = server.handleUpdateUser(contextWithUser(admin), update)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, summary(), "re-enabling must not revive old sessions")
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"wikilite/internal/version"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// VersionOutput represents the build information of the server.
+type VersionOutput struct {
+	Body struct {
+		Version    string `doc:"Application version"                      json:"version"`
+		Commit     string `doc:"Git commit the server was built from"     json:"commit"`
+		BuildTime  string `doc:"When the server was built"                json:"buildTime"`
+		APIVersion string `doc:"Version of the API described by /openapi" json:"apiVersion"`
+		OpenAPI    string `doc:"OpenAPI specification version"            json:"openapi"`
+	}
+}
+
+// registerVersionRoutes registers the build information route with the API.
+func (s *Server) registerVersionRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-version",
+		Method:      http.MethodGet,
+		Path:        "/api/version",
+		Summary:     "Get Version",
+		Description: "Get the application version, git commit and build time of the server, " +
+			"and the version of its API.",
+		Tags: []string{"System"},
+	}, s.handleGetVersion)
+}
+
+// handleGetVersion handles the request for the server's build information.
+func (s *Server) handleGetVersion(_ context.Context, _ *struct{}) (*VersionOutput, error) {
+	resp := &VersionOutput{}
+	resp.Body.Version = version.Version
+	resp.Body.Commit = version.Commit
+	resp.Body.BuildTime = version.BuildTime
+	resp.Body.APIVersion = s.api.OpenAPI().Info.Version
+	resp.Body.OpenAPI = s.api.OpenAPI().OpenAPI
+
+	return resp, nil
+}
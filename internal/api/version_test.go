@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikilite/internal/version"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVersion_HTTP(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	original := version.Version
+	version.Version = "1.2.3"
+	t.Cleanup(func() { version.Version = original })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version", nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+
+	assert.Equal(t, "1.2.3", body["version"])
+	assert.Equal(t, version.Commit, body["commit"])
+	assert.Equal(t, version.BuildTime, body["buildTime"])
+	assert.Equal(t, "1.0.0", body["apiVersion"])
+	assert.NotEmpty(t, body["openapi"])
+}
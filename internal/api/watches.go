@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"wikilite/internal/notify"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// registerWatchRoutes registers the article watch list routes with the API.
+func (s *Server) registerWatchRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "watch-article",
+		Method:      http.MethodPost,
+		Path:        "/api/articles/{slug}/watch",
+		Summary:     "Watch Article",
+		Description: "Get notified when the article is published by someone else.",
+		Tags:        []string{"Articles"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleWatchArticle)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "unwatch-article",
+		Method:      http.MethodDelete,
+		Path:        "/api/articles/{slug}/watch",
+		Summary:     "Unwatch Article",
+		Tags:        []string{"Articles"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleUnwatchArticle)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-my-watches",
+		Method:      http.MethodGet,
+		Path:        "/api/me/watches",
+		Summary:     "List Watched Articles",
+		Description: "Get the articles the current user is watching.",
+		Tags:        []string{"Articles"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleGetMyWatches)
+}
+
+// watchTarget resolves the current user and the article for a watch request.
+func (s *Server) watchTarget(
+	ctx context.Context,
+	slug string,
+) (*models.User, *models.Article, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	article, err := s.db.GetArticleBySlug(ctx, slug)
+	if err != nil {
+		return nil, nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if article == nil {
+		return nil, nil, huma.Error404NotFound("Article not found")
+	}
+
+	return user, article, nil
+}
+
+// handleWatchArticle handles the request to watch an article.
+func (s *Server) handleWatchArticle(
+	ctx context.Context,
+	input *ArticleSlugInput,
+) (*struct{ Status int }, error) {
+	user, article, err := s.watchTarget(ctx, input.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WatchArticle(ctx, user.Id, article.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to watch article", err)
+	}
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
+
+// handleUnwatchArticle handles the request to stop watching an article.
+func (s *Server) handleUnwatchArticle(
+	ctx context.Context,
+	input *ArticleSlugInput,
+) (*struct{ Status int }, error) {
+	user, article, err := s.watchTarget(ctx, input.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.UnwatchArticle(ctx, user.Id, article.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to unwatch article", err)
+	}
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
+
+// handleGetMyWatches handles the request to list the articles the current user watches.
+func (s *Server) handleGetMyWatches(ctx context.Context, _ *struct{}) (*ArticleListOutput, error) {
+	user := getUserFromContext(ctx)
+	if user == nil {
+		return nil, huma.Error401Unauthorized("Authentication required")
+	}
+
+	articles, err := s.db.GetWatchedArticles(ctx, user.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	isAdmin := user.Role == models.ADMIN
+
+	resp := &ArticleListOutput{}
+	resp.Body.Articles = make([]*PublicArticle, len(articles))
+
+	for i, a := range articles {
+		resp.Body.Articles[i] = sanitizeArticle(a, isAdmin)
+	}
+
+	return resp, nil
+}
+
+// notifyWatchers queues a notification for everyone watching a newly published article,
+// except the publisher and the author of the published draft.
+func (s *Server) notifyWatchers(
+	ctx context.Context,
+	article *models.Article,
+	version int,
+	publisher *models.User,
+	author string,
+) {
+	if s.notifications == nil {
+		return
+	}
+
+	watchers, err := s.db.GetArticleWatchers(ctx, article.Id)
+	if err != nil {
+		_ = s.db.CreateLogEntry(
+			ctx,
+			models.LevelError,
+			"NOTIFY",
+			"Failed to load article watchers",
+			fmt.Sprintf("Article ID: %d | Error: %v", article.Id, err),
+		)
+		return
+	}
+
+	for _, watcher := range watchers {
+		if watcher.Email == publisher.Email || watcher.Email == author {
+			continue
+		}
+
+		s.notifications.Enqueue(notify.Notification{
+			Recipient: watcher.Email,
+			Subject:   fmt.Sprintf("%s was updated", article.Title),
+			Message:   fmt.Sprintf("%s published version %d.", publisher.Email, version),
+			Link:      "/wiki/" + article.Slug,
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"wikilite/internal/notify"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier keeps every notification it is asked to deliver.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Notification
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, n notify.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sent = append(r.sent, n)
+
+	return nil
+}
+
+func TestHandleWatchArticle(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+	ctx := context.Background()
+
+	reader := &models.User{Name: "Reader", Email: "reader@example.com", Role: models.READ}
+	require.NoError(t, db.CreateUser(ctx, reader))
+
+	input := &ArticleSlugInput{Slug: "home"}
+
+	_, err := server.handleWatchArticle(contextWithUser(reader), input)
+	require.NoError(t, err)
+
+	article, err := server.handleGetArticleJSON(contextWithUser(reader), input)
+	require.NoError(t, err)
+	assert.True(t, article.Body.Watching)
+
+	watches, err := server.handleGetMyWatches(contextWithUser(reader), nil)
+	require.NoError(t, err)
+	require.Len(t, watches.Body.Articles, 1)
+	assert.Equal(t, "home", watches.Body.Articles[0].Slug)
+
+	_, err = server.handleUnwatchArticle(contextWithUser(reader), input)
+	require.NoError(t, err)
+
+	watches, err = server.handleGetMyWatches(contextWithUser(reader), nil)
+	require.NoError(t, err)
+	assert.Empty(t, watches.Body.Articles)
+
+	_, err = server.handleWatchArticle(contextWithUser(reader), &ArticleSlugInput{Slug: "missing"})
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusNotFound, humaErr.Status)
+
+	_, err = server.handleWatchArticle(context.Background(), input)
+	require.Error(t, err)
+	require.True(t, errors.As(err, &humaErr))
+	assert.Equal(t, http.StatusUnauthorized, humaErr.Status)
+}
+
+func TestPublishDraft_NotifiesWatchers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	notifier := &recordingNotifier{}
+	server, err := NewServer(ServerConfig{
+		Database:  db,
+		JwtSecret: "test-secret",
+		WikiName:  "Test Wiki",
+		Notifier:  notifier,
+	})
+	require.NoError(t, err)
+
+	watcher := &models.User{Name: "Watcher", Email: "watcher@example.com", Role: models.READ}
+	require.NoError(t, db.CreateUser(ctx, watcher))
+
+	publisher := &models.User{Name: "Publisher", Email: "publisher@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, publisher))
+
+	article, draft, err := db.CreateArticleWithDraft(ctx, "Watched Page", publisher.Email)
+	require.NoError(t, err)
+
+	require.NoError(t, db.WatchArticle(ctx, watcher.Id, article.Id))
+	require.NoError(t, db.WatchArticle(ctx, publisher.Id, article.Id))
+
+	_, err = server.handlePublishDraft(contextWithUser(publisher), &DraftIDInput{ID: draft.Id})
+	require.NoError(t, err)
+
+	require.NoError(t, server.Close(), "closing the server delivers queued notifications")
+
+	require.Len(t, notifier.sent, 1, "the publisher is not notified of their own change")
+	assert.Equal(t, watcher.Email, notifier.sent[0].Recipient)
+	assert.Equal(t, "Watched Page was updated", notifier.sent[0].Subject)
+	assert.Equal(t, "/wiki/watched-page", notifier.sent[0].Link)
+}
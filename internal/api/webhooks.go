@@ -0,0 +1,365 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// WebhookIDInput represents the input for addressing a webhook by ID.
+type WebhookIDInput struct {
+	ID int `doc:"The ID of the webhook" path:"id"`
+}
+
+// CreateWebhookInput represents the input for creating a webhook.
+type CreateWebhookInput struct {
+	Body struct {
+		URL    string   `doc:"Endpoint that receives event POSTs"                  json:"url"    required:"true"`
+		Secret string   `doc:"HMAC secret used to sign payloads"                   json:"secret" required:"false"`
+		Events []string `doc:"Events to deliver. Empty subscribes to all events." json:"events" required:"false"`
+		Active *bool    `doc:"Whether deliveries are enabled. Defaults to true."  json:"active" required:"false"`
+	}
+}
+
+// UpdateWebhookInput represents the input for updating a webhook.
+type UpdateWebhookInput struct {
+	Body struct {
+		URL    *string   `json:"url,omitempty"`
+		Secret *string   `json:"secret,omitempty"`
+		Events *[]string `json:"events,omitempty"`
+		Active *bool     `json:"active,omitempty"`
+	}
+	ID int `doc:"The ID of the webhook" path:"id"`
+}
+
+// PublicWebhook is a webhook with its secret withheld.
+type PublicWebhook struct {
+	UpdatedAt time.Time             `json:"updatedAt"`
+	URL       string                `json:"url"`
+	Events    []models.WebhookEvent `json:"events"`
+	Id        int                   `json:"id"`
+	Active    bool                  `json:"active"`
+	HasSecret bool                  `json:"hasSecret"`
+}
+
+// WebhookOutput represents the output for a single webhook.
+type WebhookOutput struct {
+	Body struct {
+		Webhook *PublicWebhook `json:"webhook"`
+	}
+}
+
+// WebhookListOutput represents the output for a list of webhooks.
+type WebhookListOutput struct {
+	Body struct {
+		Webhooks []*PublicWebhook `json:"webhooks"`
+	}
+}
+
+// registerWebhookRoutes registers the webhook routes with the API.
+func (s *Server) registerWebhookRoutes() {
+	huma.Register(s.api, huma.Operation{
+		OperationID: "list-webhooks",
+		Method:      http.MethodGet,
+		Path:        "/api/webhooks",
+		Summary:     "List Webhooks",
+		Description: "List configured outbound webhooks. Admin only.",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleListWebhooks)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "create-webhook",
+		Method:      http.MethodPost,
+		Path:        "/api/webhooks",
+		Summary:     "Create Webhook",
+		Description: "Register an endpoint to receive signed event notifications. Admin only.",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleCreateWebhook)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "get-webhook",
+		Method:      http.MethodGet,
+		Path:        "/api/webhooks/{id}",
+		Summary:     "Get Webhook",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleGetWebhook)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "update-webhook",
+		Method:      http.MethodPatch,
+		Path:        "/api/webhooks/{id}",
+		Summary:     "Update Webhook",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleUpdateWebhook)
+
+	huma.Register(s.api, huma.Operation{
+		OperationID: "delete-webhook",
+		Method:      http.MethodDelete,
+		Path:        "/api/webhooks/{id}",
+		Summary:     "Delete Webhook",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, s.handleDeleteWebhook)
+}
+
+// toPublicWebhook converts a webhook model to a safe API response.
+func toPublicWebhook(w *models.Webhook) *PublicWebhook {
+	events := w.Events
+	if events == nil {
+		events = []models.WebhookEvent{}
+	}
+
+	return &PublicWebhook{
+		Id:        w.Id,
+		URL:       w.URL,
+		Events:    events,
+		Active:    w.Active,
+		HasSecret: w.Secret != "",
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// validateWebhookURL ensures the target is an absolute http(s) URL.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return huma.Error400BadRequest("Webhook URL must be an absolute http(s) URL")
+	}
+
+	return nil
+}
+
+// parseWebhookEvents validates and converts event names.
+func parseWebhookEvents(raw []string) ([]models.WebhookEvent, error) {
+	events := make([]models.WebhookEvent, 0, len(raw))
+
+	for _, e := range raw {
+		event := models.WebhookEvent(e)
+		if !slices.Contains(models.WebhookEvents, event) {
+			return nil, huma.Error400BadRequest("Unknown webhook event: " + e)
+		}
+
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// emitWebhook queues an event for delivery to subscribed webhooks.
+func (s *Server) emitWebhook(event models.WebhookEvent, data any) {
+	if s.webhooks != nil {
+		s.webhooks.Dispatch(event, data)
+	}
+}
+
+// handleListWebhooks handles the request to list webhooks.
+func (s *Server) handleListWebhooks(ctx context.Context, _ *struct{}) (*WebhookListOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage webhooks")
+	}
+
+	webhooks, err := s.db.GetWebhooks(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	publicWebhooks := make([]*PublicWebhook, len(webhooks))
+	for i, w := range webhooks {
+		publicWebhooks[i] = toPublicWebhook(w)
+	}
+
+	resp := &WebhookListOutput{}
+	resp.Body.Webhooks = publicWebhooks
+
+	return resp, nil
+}
+
+// handleCreateWebhook handles the creation of a new webhook.
+func (s *Server) handleCreateWebhook(
+	ctx context.Context,
+	input *CreateWebhookInput,
+) (*WebhookOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage webhooks")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateWebhookURL(input.Body.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := parseWebhookEvents(input.Body.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	active := true
+	if input.Body.Active != nil {
+		active = *input.Body.Active
+	}
+
+	webhook := &models.Webhook{
+		URL:    input.Body.URL,
+		Secret: input.Body.Secret,
+		Events: events,
+		Active: active,
+	}
+
+	err = s.db.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to create webhook", err)
+	}
+
+	resp := &WebhookOutput{}
+	resp.Body.Webhook = toPublicWebhook(webhook)
+
+	return resp, nil
+}
+
+// handleGetWebhook handles the request to get a single webhook.
+func (s *Server) handleGetWebhook(
+	ctx context.Context,
+	input *WebhookIDInput,
+) (*WebhookOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage webhooks")
+	}
+
+	webhook, err := s.db.GetWebhookByID(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if webhook == nil {
+		return nil, huma.Error404NotFound("Webhook not found")
+	}
+
+	resp := &WebhookOutput{}
+	resp.Body.Webhook = toPublicWebhook(webhook)
+
+	return resp, nil
+}
+
+// handleUpdateWebhook handles updating a webhook.
+func (s *Server) handleUpdateWebhook(
+	ctx context.Context,
+	input *UpdateWebhookInput,
+) (*WebhookOutput, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage webhooks")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.db.GetWebhookByID(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if webhook == nil {
+		return nil, huma.Error404NotFound("Webhook not found")
+	}
+
+	var cols []string
+
+	if input.Body.URL != nil {
+		err = validateWebhookURL(*input.Body.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		webhook.URL = *input.Body.URL
+
+		cols = append(cols, "url")
+	}
+
+	if input.Body.Secret != nil {
+		webhook.Secret = *input.Body.Secret
+
+		cols = append(cols, "secret")
+	}
+
+	if input.Body.Events != nil {
+		events, err := parseWebhookEvents(*input.Body.Events)
+		if err != nil {
+			return nil, err
+		}
+
+		webhook.Events = events
+
+		cols = append(cols, "events")
+	}
+
+	if input.Body.Active != nil {
+		webhook.Active = *input.Body.Active
+
+		cols = append(cols, "active")
+	}
+
+	if len(cols) > 0 {
+		err = s.db.UpdateWebhook(ctx, webhook, cols...)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to update webhook", err)
+		}
+	}
+
+	resp := &WebhookOutput{}
+	resp.Body.Webhook = toPublicWebhook(webhook)
+
+	return resp, nil
+}
+
+// handleDeleteWebhook handles deleting a webhook.
+func (s *Server) handleDeleteWebhook(
+	ctx context.Context,
+	input *WebhookIDInput,
+) (*struct{ Status int }, error) {
+	admin := getAdminUserFromContext(ctx)
+	if admin == nil {
+		return nil, huma.Error403Forbidden("Only admins can manage webhooks")
+	}
+
+	err := requireScope(ctx, models.ScopeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.db.GetWebhookByID(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Database error", err)
+	}
+
+	if webhook == nil {
+		return nil, huma.Error404NotFound("Webhook not found")
+	}
+
+	err = s.db.DeleteWebhook(ctx, webhook.Id)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to delete webhook", err)
+	}
+
+	return &struct{ Status int }{Status: http.StatusNoContent}, nil
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateWebhook_Success(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	ctx := contextWithUser(admin)
+
+	input := &CreateWebhookInput{}
+	input.Body.URL = "https://example.com/hook"
+	input.Body.Secret = "secret"
+	input.Body.Events = []string{"article.published", "article.published"}
+
+	resp, err := server.handleCreateWebhook(ctx, input)
+	require.NoError(t, err)
+	assert.True(t, resp.Body.Webhook.Active)
+	assert.True(t, resp.Body.Webhook.HasSecret)
+	assert.Equal(t, []models.WebhookEvent{models.EventArticlePublished}, resp.Body.Webhook.Events)
+
+	list, err := server.handleListWebhooks(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Body.Webhooks, 1)
+	assert.Equal(t, "https://example.com/hook", list.Body.Webhooks[0].URL)
+
+	_, err = server.handleDeleteWebhook(ctx, &WebhookIDInput{ID: resp.Body.Webhook.Id})
+	require.NoError(t, err)
+
+	webhooks, err := db.GetWebhooks(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, webhooks)
+}
+
+func TestHandleCreateWebhook_Validation(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	ctx := contextWithUser(admin)
+
+	testCases := []struct {
+		name   string
+		url    string
+		events []string
+	}{
+		{name: "relative url", url: "/hook"},
+		{name: "unsupported scheme", url: "ftp://example.com/hook"},
+		{name: "unknown event", url: "https://example.com/hook", events: []string{"nope"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := &CreateWebhookInput{}
+			input.Body.URL = tc.url
+			input.Body.Events = tc.events
+
+			_, err := server.handleCreateWebhook(ctx, input)
+			require.Error(t, err)
+
+			var humaErr *huma.ErrorModel
+			ok := errors.As(err, &humaErr)
+			require.True(t, ok)
+			assert.Equal(t, 400, humaErr.Status)
+		})
+	}
+}
+
+func TestHandleWebhooks_Forbidden(t *testing.T) {
+	db := newTestDB(t)
+	server := newTestServer(t, db)
+
+	user := &models.User{Email: "user@example.com", Role: models.WRITE}
+	ctx := contextWithUser(user)
+
+	_, err := server.handleListWebhooks(ctx, nil)
+	require.Error(t, err)
+
+	var humaErr *huma.ErrorModel
+	ok := errors.As(err, &humaErr)
+	require.True(t, ok)
+	assert.Equal(t, 403, humaErr.Status)
+
+	input := &CreateWebhookInput{}
+	input.Body.URL = "https://example.com/hook"
+	_, err = server.handleCreateWebhook(ctx, input)
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/didip/tollbooth/v8"
+	"github.com/didip/tollbooth/v8/limiter"
+)
+
+// DefaultWriteRateWindow is the default window of the per-user write rate limits.
+const DefaultWriteRateWindow = time.Minute
+
+// newWriteLimiter returns a limiter allowing limit writes per window for each key, or nil
+// when limit is zero or less, meaning writes are not limited.
+func newWriteLimiter(limit int, window time.Duration) *limiter.Limiter {
+	if limit <= 0 {
+		return nil
+	}
+
+	if window <= 0 {
+		window = DefaultWriteRateWindow
+	}
+
+	lmt := tollbooth.NewLimiter(float64(limit)/window.Seconds(), &limiter.ExpirableOptions{
+		DefaultExpirationTTL: window,
+	})
+	lmt.SetBurst(limit)
+
+	return lmt
+}
+
+// checkWriteLimit counts an article write against the user's rate limit, keyed by email, so
+// a misbehaving script cannot flood the diff engine. It returns a 429 error once the limit is
+// used up. Admins have their own limit. Every handler that writes article content calls it,
+// so the limit also applies when the UI actions and other handlers go through them.
+func (s *Server) checkWriteLimit(user *models.User) error {
+	lmt := s.writeLimiter
+	if user.Role == models.ADMIN {
+		lmt = s.adminWriteLimiter
+	}
+
+	if lmt == nil {
+		return nil
+	}
+
+	httpError := tollbooth.LimitByKeys(lmt, []string{user.Email})
+	if httpError != nil {
+		return huma.NewError(httpError.StatusCode, "Too many writes, try again later")
+	}
+
+	return nil
+}
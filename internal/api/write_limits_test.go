@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"wikilite/internal/db"
+	"wikilite/pkg/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitedServer returns a test server allowing limit writes per window.
+func newRateLimitedServer(t *testing.T, database *db.DB, limit, adminLimit int, window time.Duration) *Server {
+	t.Helper()
+
+	server, err := NewServer(ServerConfig{
+		Database:            database,
+		JwtSecret:           "test-secret",
+		WikiName:            "Test Wiki",
+		WriteRateLimit:      limit,
+		AdminWriteRateLimit: adminLimit,
+		WriteRateWindow:     window,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	return server
+}
+
+// publishNewDraft opens a draft of the article for user and publishes it through the API,
+// returning the response status.
+func publishNewDraft(t *testing.T, server *Server, database *db.DB, article *PublicArticle, user *models.User) int {
+	t.Helper()
+
+	ctx := context.Background()
+
+	draft, err := database.CreateDraft(ctx, article.Id, article.Data, user.Email)
+	require.NoError(t, err)
+	require.NoError(t, database.UpdateDraft(ctx, draft.Id, time.Now().String(), user.Email, false, nil))
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/drafts/%d/publish", draft.Id), nil)
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(contextWithUser(user)))
+
+	return rr.Code
+}
+
+func TestRateLimitWrites_Publish(t *testing.T) {
+	database := newTestDB(t)
+	window := 300 * time.Millisecond
+	server := newRateLimitedServer(t, database, 2, 0, window)
+
+	article := publishArticle(t, database, "Busy Page", "# Busy")
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	other := &models.User{Email: "other@example.com", Role: models.WRITE}
+
+	assert.Equal(t, http.StatusNoContent, publishNewDraft(t, server, database, article, writer))
+	assert.Equal(t, http.StatusNoContent, publishNewDraft(t, server, database, article, writer))
+	assert.Equal(t, http.StatusTooManyRequests, publishNewDraft(t, server, database, article, writer),
+		"the writer's publish budget is exhausted")
+
+	assert.Equal(t, http.StatusNoContent, publishNewDraft(t, server, database, article, other),
+		"limits are kept per user")
+
+	time.Sleep(window)
+
+	assert.Equal(t, http.StatusNoContent, publishNewDraft(t, server, database, article, writer),
+		"the budget recovers after the window")
+}
+
+func TestRateLimitWrites_SharedBudget(t *testing.T) {
+	database := newTestDB(t)
+	server := newRateLimitedServer(t, database, 1, 0, time.Minute)
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	ctx := contextWithUser(writer)
+
+	createArticle := func(title string) int {
+		body := strings.NewReader(fmt.Sprintf(`{"title": %q}`, title))
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", body)
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req.WithContext(ctx))
+
+		return rr.Code
+	}
+
+	assert.NotEqual(t, http.StatusTooManyRequests, createArticle("First"))
+	assert.Equal(t, http.StatusTooManyRequests, createArticle("Second"))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/drafts/1", strings.NewReader(`{"content": "x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req.WithContext(ctx))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "saving drafts draws on the same budget")
+}
+
+func TestRateLimitWrites_Admins(t *testing.T) {
+	database := newTestDB(t)
+	article := publishArticle(t, database, "Admin Page", "# Admin")
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+
+	exempt := newRateLimitedServer(t, database, 1, 0, time.Minute)
+	for range 3 {
+		assert.Equal(t, http.StatusNoContent, publishNewDraft(t, exempt, database, article, admin),
+			"admins are exempt without an admin limit")
+	}
+
+	limited := newRateLimitedServer(t, database, 1, 2, time.Minute)
+	assert.Equal(t, http.StatusNoContent, publishNewDraft(t, limited, database, article, admin))
+	assert.Equal(t, http.StatusNoContent, publishNewDraft(t, limited, database, article, admin))
+	assert.Equal(t, http.StatusTooManyRequests, publishNewDraft(t, limited, database, article, admin))
+}
+
+func TestRateLimitWrites_Unlimited(t *testing.T) {
+	database := newTestDB(t)
+	server := newTestServer(t, database)
+
+	article := publishArticle(t, database, "Open Page", "# Open")
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+
+	for range 5 {
+		assert.Equal(t, http.StatusNoContent, publishNewDraft(t, server, database, article, writer))
+	}
+}
+
+func TestRateLimitWrites_IndirectWrites(t *testing.T) {
+	database := newTestDB(t)
+	server := newRateLimitedServer(t, database, 1, 1, time.Minute)
+	ctx := context.Background()
+
+	article := publishArticle(t, database, "Shared Page", "# Shared")
+
+	writer := &models.User{Email: "writer@example.com", Role: models.WRITE}
+	writerCtx := contextWithUser(writer)
+
+	draft, err := database.CreateDraft(ctx, article.Id, article.Data, writer.Email)
+	require.NoError(t, err)
+
+	recovery := &RecoverDraftInput{ID: draft.Id}
+	recovery.Body.Content = "# Recovered"
+	_, err = server.handleRecoverDraft(writerCtx, recovery)
+	require.NoError(t, err)
+
+	recovery.Body.Content = "# Recovered again"
+	_, err = server.handleRecoverDraft(writerCtx, recovery)
+	assertTooManyWrites(t, err, "recovering a draft saves it")
+
+	clone := &CloneArticleInput{Slug: article.Slug}
+	clone.Body.Title = "Shared Copy"
+	_, err = server.handleCloneArticle(writerCtx, clone)
+	assertTooManyWrites(t, err, "cloning publishes an article")
+
+	admin := &models.User{Email: "admin@test.com", Role: models.ADMIN}
+	adminCtx := contextWithUser(admin)
+
+	for _, content := range []string{"# First idea", "# Second idea"} {
+		_, err = database.CreateSuggestion(ctx, article.Id, content, "", "")
+		require.NoError(t, err)
+	}
+
+	suggestions, err := database.GetSuggestions(ctx)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+
+	_, err = server.handleApproveSuggestion(adminCtx, &DraftIDInput{ID: suggestions[0].Id})
+	require.NoError(t, err)
+
+	_, err = server.handleApproveSuggestion(adminCtx, &DraftIDInput{ID: suggestions[1].Id})
+	assertTooManyWrites(t, err, "approving a suggestion publishes it")
+}
+
+// assertTooManyWrites asserts that err is the 429 returned once the write limit is used up.
+func assertTooManyWrites(t *testing.T, err error, msg string) {
+	t.Helper()
+
+	var statusErr huma.StatusError
+	require.ErrorAs(t, err, &statusErr, msg)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.GetStatus(), msg)
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// Default is the lifetime and capacity of the article, HTML and plugin caches.
+var Default = Config{TTL: 30 * time.Minute, Size: 1000}
+
+// DefaultOTP is the lifetime and capacity of the cache holding OTP enrollments in progress.
+// It is kept short since the cached secrets are only needed until enrollment is confirmed.
+var DefaultOTP = Config{TTL: 10 * time.Minute, Size: 1000}
+
+// Config sets how long entries are kept in a cache and how many entries it holds.
+type Config struct {
+	TTL  time.Duration
+	Size uint64
+}
+
+// Or returns the config with unset fields taken from defaults.
+func (c Config) Or(defaults Config) Config {
+	if c.TTL <= 0 {
+		c.TTL = defaults.TTL
+	}
+
+	if c.Size == 0 {
+		c.Size = defaults.Size
+	}
+
+	return c
+}
+
+// New creates a cache with the given lifetime and capacity. Callers start it with
+// go cache.Start() when expired entries should be removed in the background.
+func New[K comparable, V any](c Config) *ttlcache.Cache[K, V] {
+	return ttlcache.New[K, V](
+		ttlcache.WithTTL[K, V](c.TTL),
+		ttlcache.WithCapacity[K, V](c.Size),
+	)
+}
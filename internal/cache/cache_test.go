@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Or(t *testing.T) {
+	assert.Equal(t, Default, Config{}.Or(Default))
+	assert.Equal(t, Config{TTL: time.Minute, Size: 1000}, Config{TTL: time.Minute}.Or(Default))
+	assert.Equal(t, Config{TTL: 10 * time.Minute, Size: 5}, Config{Size: 5}.Or(DefaultOTP))
+}
+
+func TestNew_Expiry(t *testing.T) {
+	c := New[string, string](Config{TTL: 50 * time.Millisecond, Size: 10})
+
+	c.Set("key", "value", ttlcache.DefaultTTL)
+	item := c.Get("key")
+	require.NotNil(t, item)
+	assert.Equal(t, "value", item.Value())
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Nil(t, c.Get("key"), "entries expire after the configured TTL")
+}
+
+func TestNew_Capacity(t *testing.T) {
+	c := New[int, int](Config{TTL: time.Hour, Size: 2})
+
+	for i := range 3 {
+		c.Set(i, i, ttlcache.DefaultTTL)
+	}
+
+	assert.Equal(t, 2, c.Len())
+	assert.Nil(t, c.Get(0), "the oldest entry is evicted beyond the configured size")
+}
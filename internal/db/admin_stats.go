@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/uptrace/bun"
+)
+
+// CountArticles returns the total number of articles.
+func (d *DB) CountArticles(ctx context.Context) (int, error) {
+	return d.NewSelect().
+		Model((*models.Article)(nil)).
+		Count(ctx)
+}
+
+// CountDrafts returns the total number of open drafts.
+func (d *DB) CountDrafts(ctx context.Context) (int, error) {
+	return d.NewSelect().
+		Model((*models.Draft)(nil)).
+		Count(ctx)
+}
+
+// CountUsersByRole returns the number of users holding each role.
+// Roles without any users are omitted from the result.
+func (d *DB) CountUsersByRole(ctx context.Context) (map[models.UserRole]int, error) {
+	var rows []struct {
+		Role  models.UserRole `bun:"role"`
+		Count int             `bun:"count"`
+	}
+
+	err := d.NewSelect().
+		Model((*models.User)(nil)).
+		Column("role").
+		ColumnExpr("COUNT(*) AS count").
+		Group("role").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.UserRole]int, len(rows))
+	for _, row := range rows {
+		counts[row.Role] = row.Count
+	}
+
+	return counts, nil
+}
+
+// CountLogsSince returns the number of log entries at any of the given levels
+// created at or after since.
+func (d *DB) CountLogsSince(
+	ctx context.Context,
+	since time.Time,
+	levels ...models.LogLevel,
+) (int, error) {
+	return d.logDB.NewSelect().
+		Model((*models.SystemLog)(nil)).
+		Where("level IN (?)", bun.In(levels)).
+		Where("created_at >= ?", since).
+		Count(ctx)
+}
+
+// DatabaseSize returns the size of the main database in bytes.
+func (d *DB) DatabaseSize(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+
+	err := d.NewRaw("PRAGMA page_count").Scan(ctx, &pageCount)
+	if err != nil {
+		return 0, err
+	}
+
+	err = d.NewRaw("PRAGMA page_size").Scan(ctx, &pageSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return pageCount * pageSize, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestCountUsersByRole(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	for i, role := range []models.UserRole{models.ADMIN, models.WRITE, models.WRITE} {
+		user := &models.User{
+			Name:  "User",
+			Email: string(rune('a'+i)) + "@example.com",
+			Role:  role,
+		}
+		require.NoError(t, db.CreateUser(ctx, user))
+	}
+
+	counts, err := db.CountUsersByRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.UserRole]int{models.ADMIN: 1, models.WRITE: 2}, counts)
+}
+
+func TestCountLogsSince(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	entries := []*models.SystemLog{
+		{Level: models.LevelError, Source: "TEST", CreatedAt: now.Add(-time.Hour)},
+		{Level: models.LevelSQLError, Source: "TEST", CreatedAt: now.Add(-time.Hour)},
+		{Level: models.LevelError, Source: "TEST", CreatedAt: now.Add(-48 * time.Hour)},
+		{Level: models.LevelWarning, Source: "TEST", CreatedAt: now.Add(-time.Hour)},
+	}
+	for _, entry := range entries {
+		_, err := db.logDB.NewInsert().Model(entry).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	since := now.Add(-24 * time.Hour)
+
+	count, err := db.CountLogsSince(ctx, since, models.LevelError)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = db.CountLogsSince(ctx, since, models.LevelError, models.LevelSQLError)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestDatabaseSize(t *testing.T) {
+	db := newTestDB(t)
+
+	size, err := db.DatabaseSize(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, size)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wikilite/pkg/models"
+)
+
+// CreateApiToken inserts a new personal access token.
+func (d *DB) CreateApiToken(ctx context.Context, token *models.ApiToken) error {
+	_, err := d.NewInsert().
+		Model(token).
+		Exec(ctx)
+
+	return err
+}
+
+// GetApiTokenByHash fetches a personal access token by the hash of its secret.
+func (d *DB) GetApiTokenByHash(ctx context.Context, hash string) (*models.ApiToken, error) {
+	token := new(models.ApiToken)
+	err := d.NewSelect().
+		Model(token).
+		Where("hashed_token = ?", hash).
+		Scan(ctx)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GetApiTokensByUser returns the personal access tokens of a user, newest first.
+func (d *DB) GetApiTokensByUser(ctx context.Context, userID int) ([]*models.ApiToken, error) {
+	var tokens []*models.ApiToken
+	err := d.NewSelect().
+		Model(&tokens).
+		Where("user_id = ?", userID).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// DeleteApiToken revokes a personal access token of a user. It reports whether a token
+// was deleted, so tokens of other users are never revoked.
+func (d *DB) DeleteApiToken(ctx context.Context, id, userID int) (bool, error) {
+	res, err := d.NewDelete().
+		Model(&models.ApiToken{Id: id}).
+		WherePK().
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// TouchApiToken records when a personal access token was last used.
+func (d *DB) TouchApiToken(ctx context.Context, id int, usedAt time.Time) error {
+	_, err := d.NewUpdate().
+		Model((*models.ApiToken)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
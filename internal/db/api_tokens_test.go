@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestApiTokens(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	owner := &models.User{Name: "Owner", Email: "owner@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, owner))
+
+	other := &models.User{Name: "Other", Email: "other@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, other))
+
+	first := &models.ApiToken{
+		UserId:      owner.Id,
+		Name:        "CI",
+		HashedToken: "hash-1",
+		Scopes:      []models.ApiTokenScope{models.ScopeRead},
+	}
+	require.NoError(t, db.CreateApiToken(ctx, first))
+
+	second := &models.ApiToken{
+		UserId:      owner.Id,
+		Name:        "Deploy",
+		HashedToken: "hash-2",
+		Scopes:      []models.ApiTokenScope{models.ScopeWrite},
+	}
+	require.NoError(t, db.CreateApiToken(ctx, second))
+
+	found, err := db.GetApiTokenByHash(ctx, "hash-2")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "Deploy", found.Name)
+	assert.Equal(t, []models.ApiTokenScope{models.ScopeWrite}, found.Scopes)
+	assert.Nil(t, found.LastUsedAt)
+
+	missing, err := db.GetApiTokenByHash(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	tokens, err := db.GetApiTokensByUser(ctx, owner.Id)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "Deploy", tokens[0].Name, "newest tokens come first")
+
+	usedAt := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, db.TouchApiToken(ctx, first.Id, usedAt))
+
+	found, err = db.GetApiTokenByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, found.LastUsedAt)
+	assert.True(t, usedAt.Equal(*found.LastUsedAt))
+
+	deleted, err := db.DeleteApiToken(ctx, first.Id, other.Id)
+	require.NoError(t, err)
+	assert.False(t, deleted, "tokens of other users are not deleted")
+
+	deleted, err = db.DeleteApiToken(ctx, first.Id, owner.Id)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	tokens, err = db.GetApiTokensByUser(ctx, owner.Id)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+
+	require.NoError(t, db.DeleteUser(ctx, owner.Id))
+
+	found, err = db.GetApiTokenByHash(ctx, "hash-2")
+	require.NoError(t, err)
+	assert.Nil(t, found, "deleting a user removes their tokens")
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"time"
+	"wikilite/pkg/models"
+)
+
+// SetArticleTemplate sets the boilerplate used as the genesis draft content of new articles.
+// The {{title}} and {{date}} placeholders are substituted as for library templates.
+// An empty template leaves new articles blank.
+func (d *DB) SetArticleTemplate(tmpl string) {
+	d.articleTemplate = tmpl
+}
+
+// renderArticleTemplate returns the genesis content for a new article with the given title.
+func (d *DB) renderArticleTemplate(title string) string {
+	tmpl := &models.Template{Content: d.articleTemplate}
+
+	return tmpl.Render(title, time.Now())
+}
@@ -6,14 +6,36 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 
 	"github.com/jellydator/ttlcache/v3"
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/uptrace/bun"
 )
 
+// Sort orders accepted by GetArticles.
+const (
+	SortCreated = "created"
+	SortUpdated = "updated"
+	SortTitle   = "title"
+	SortPopular = "popular"
+)
+
+// ErrInvalidSort is returned when an unknown sort order is requested.
+var ErrInvalidSort = errors.New("invalid sort order")
+
+// articleOrders maps each allowed sort order to its ORDER BY clause. Only these fixed
+// clauses reach the query, so a sort value is never interpolated into SQL.
+var articleOrders = map[string]string{
+	SortCreated: "created_at DESC, id DESC",
+	SortUpdated: "updated_at DESC, id DESC",
+	SortTitle:   "title COLLATE NOCASE ASC, id ASC",
+	SortPopular: "view_count DESC, updated_at DESC, id DESC",
+}
+
 // CreateArticleWithDraft initializes a new Article at Version 0 (Empty)
 // and immediately creates the first Draft for it.
 func (d *DB) CreateArticleWithDraft(
@@ -21,6 +43,20 @@ func (d *DB) CreateArticleWithDraft(
 	title string,
 	userID string,
 ) (*models.Article, *models.Draft, error) {
+	return d.CreateArticleWithContent(ctx, title, d.renderArticleTemplate(title), userID)
+}
+
+// CreateArticleWithContent creates a new article whose first Draft starts with the given content.
+func (d *DB) CreateArticleWithContent(
+	ctx context.Context,
+	title string,
+	content string,
+	userID string,
+) (*models.Article, *models.Draft, error) {
+	if len(content) > maxDiffContentSize {
+		return nil, nil, ErrContentTooLarge
+	}
+
 	tx, err := d.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, nil, err
@@ -33,12 +69,19 @@ func (d *DB) CreateArticleWithDraft(
 		}
 	}(tx)
 
+	slug, err := d.articleSlug(ctx, tx, title)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	article := &models.Article{
 		Title:     title,
+		Slug:      slug,
 		Version:   0,
 		Data:      "",
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
 	_, err = tx.NewInsert().Model(article).Exec(ctx)
@@ -47,7 +90,7 @@ func (d *DB) CreateArticleWithDraft(
 	}
 
 	// Pass 'tx' as the executor
-	draft, err := d.createGenesisDraft(ctx, tx, article.Id, userID)
+	draft, err := d.createGenesisDraft(ctx, tx, article.Id, content, userID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -86,7 +129,28 @@ func (d *DB) GetArticleBySlug(ctx context.Context, slug string) (*models.Article
 	return article, nil
 }
 
-// GetArticleByID fetches the latest version of an article by ID.
+// GetArticlesBySlugs fetches the latest version of every article matching one of the given slugs.
+// Slugs that do not match an article are ignored.
+func (d *DB) GetArticlesBySlugs(ctx context.Context, slugs []string) ([]*models.Article, error) {
+	var articles []*models.Article
+	if len(slugs) == 0 {
+		return articles, nil
+	}
+
+	err := d.NewSelect().
+		Model(&articles).
+		Where("slug IN (?)", bun.In(slugs)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleByID fetches the latest version of an article by ID, or returns ErrNotFound
+// when there is none with the ID.
 func (d *DB) GetArticleByID(ctx context.Context, id int) (*models.Article, error) {
 	article := new(models.Article)
 
@@ -95,7 +159,7 @@ func (d *DB) GetArticleByID(ctx context.Context, id int) (*models.Article, error
 		Where("id = ?", id).
 		Scan(ctx)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	if article.Slug != "" {
@@ -122,13 +186,85 @@ func (d *DB) GetArticlesByUser(ctx context.Context, userID string) ([]*models.Ar
 	return articles, nil
 }
 
-// GetArticles returns a paginated list of articles.
-func (d *DB) GetArticles(ctx context.Context, limit, offset int) ([]*models.Article, int64, error) {
+// GetPublishedArticles returns every article that has a published version, with its
+// content, ordered by title.
+func (d *DB) GetPublishedArticles(ctx context.Context) ([]*models.Article, error) {
 	var articles []*models.Article
-	count, err := d.NewSelect().
+	err := d.NewSelect().
 		Model(&articles).
-		Column("id", "title", "slug", "version", "created_by", "created_at").
-		Order("created_at DESC").
+		Where("version > 0").
+		Order("title ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// CountArticlesByUser returns the number of articles created by a specific user.
+func (d *DB) CountArticlesByUser(ctx context.Context, userID string) (int, error) {
+	return d.NewSelect().
+		Model((*models.Article)(nil)).
+		Where("created_by = ?", userID).
+		Count(ctx)
+}
+
+// ArticleFilter narrows the articles returned by GetArticlesFiltered. Zero values are not applied.
+type ArticleFilter struct {
+	// CreatedAfter keeps articles created at or after this time.
+	CreatedAfter time.Time
+	// CreatedBefore keeps articles created at or before this time.
+	CreatedBefore time.Time
+}
+
+// GetArticles returns a paginated list of articles in the given sort order.
+// An empty sort uses SortUpdated; unknown values return ErrInvalidSort.
+func (d *DB) GetArticles(
+	ctx context.Context,
+	sort string,
+	limit, offset int,
+) ([]*models.Article, int64, error) {
+	return d.GetArticlesFiltered(ctx, sort, ArticleFilter{}, limit, offset)
+}
+
+// GetArticlesFiltered returns a paginated list of the articles matching the filter in the
+// given sort order, along with the total number of matching articles.
+// An empty sort uses SortUpdated; unknown values return ErrInvalidSort.
+func (d *DB) GetArticlesFiltered(
+	ctx context.Context,
+	sort string,
+	filter ArticleFilter,
+	limit, offset int,
+) ([]*models.Article, int64, error) {
+	if sort == "" {
+		sort = SortUpdated
+	}
+
+	order, ok := articleOrders[sort]
+	if !ok {
+		return nil, 0, ErrInvalidSort
+	}
+
+	var articles []*models.Article
+	query := d.NewSelect().
+		Model(&articles).
+		Column(
+			"id", "title", "slug", "version", "view_count", "created_by", "created_at", "updated_at",
+		)
+
+	switch {
+	case !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero():
+		query.Where("created_at BETWEEN ? AND ?", filter.CreatedAfter, filter.CreatedBefore)
+	case !filter.CreatedAfter.IsZero():
+		query.Where("created_at >= ?", filter.CreatedAfter)
+	case !filter.CreatedBefore.IsZero():
+		query.Where("created_at <= ?", filter.CreatedBefore)
+	}
+
+	count, err := query.
+		OrderExpr(order).
 		Limit(limit).
 		Offset(offset).
 		ScanAndCount(ctx)
@@ -140,53 +276,125 @@ func (d *DB) GetArticles(ctx context.Context, limit, offset int) ([]*models.Arti
 	return articles, int64(count), nil
 }
 
-// GetArticleVersion reconstructs a specific version of an article.
+// SetArticleCustomCode replaces the custom CSS and JavaScript of an article. Empty strings
+// remove them. It returns ErrNotFound when there is no article with the ID.
+func (d *DB) SetArticleCustomCode(ctx context.Context, articleID int, css, js string) error {
+	var slug string
+
+	err := d.NewUpdate().
+		Model((*models.Article)(nil)).
+		Set("custom_css = ?", css).
+		Set("custom_js = ?", js).
+		Where("id = ?", articleID).
+		Returning("slug").
+		Scan(ctx, &slug)
+	if err != nil {
+		return notFound(err)
+	}
+
+	d.articleCache.Delete(slug)
+
+	return nil
+}
+
+// GetArticleVersion reconstructs a specific version of an article, replaying patches from the
+// nearest preceding full-content snapshot.
+// If the history chain is corrupted, a best-effort reconstruction is returned and
+// degraded is set to true instead of failing.
 func (d *DB) GetArticleVersion(
 	ctx context.Context,
 	articleID int,
 	targetVersion int,
-) (string, error) {
-	var maxVersion sql.NullInt64
+) (string, bool, error) {
+	var minVersion, maxVersion sql.NullInt64
 	err := d.NewSelect().
 		Model((*models.History)(nil)).
-		ColumnExpr("MAX(version)").
+		ColumnExpr("MIN(version), MAX(version)").
 		Where("article_id = ?", articleID).
 		Where("version > 0").
-		Scan(ctx, &maxVersion)
+		Scan(ctx, &minVersion, &maxVersion)
 
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	if !maxVersion.Valid || targetVersion > int(maxVersion.Int64) {
-		return "", sql.ErrNoRows
+		return "", false, ErrNotFound
+	}
+
+	// Versions before a collapsed baseline are no longer stored (see SetMaxHistory).
+	if minVersion.Int64 > 1 && targetVersion < int(minVersion.Int64) {
+		return "", false, ErrNotFound
+	}
+
+	var snapshotVersion sql.NullInt64
+	err = d.NewSelect().
+		Model((*models.History)(nil)).
+		ColumnExpr("MAX(version)").
+		Where("article_id = ?", articleID).
+		Where("snapshot = ?", true).
+		Where("version <= ?", targetVersion).
+		Scan(ctx, &snapshotVersion)
+
+	if err != nil {
+		return "", false, err
 	}
 
 	var history []models.History
 	err = d.NewSelect().
 		Model(&history).
 		Where("article_id = ?", articleID).
+		Where("version >= ?", snapshotVersion.Int64).
 		Where("version <= ?", targetVersion).
 		Order("version ASC").
 		Scan(ctx)
 
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	dmp := diffmatchpatch.New()
 	currentText := ""
 
+	var corrupted []string
+
 	for _, h := range history {
-		patches, err := dmp.PatchFromText(h.Data)
+		data, err := decodeData(h.Data, h.Compressed)
 		if err != nil {
-			return "", fmt.Errorf("failed to parse patch for v%d: %w", h.Version, err)
+			return "", false, fmt.Errorf("failed to decode history for v%d: %w", h.Version, err)
 		}
 
-		currentText, _ = dmp.PatchApply(patches, currentText)
+		if h.Snapshot {
+			currentText = data
+			continue
+		}
+
+		patches, err := dmp.PatchFromText(data)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse patch for v%d: %w", h.Version, err)
+		}
+
+		var degraded bool
+		var failed int
+
+		currentText, degraded, failed = applyPatches(patches, currentText)
+		if degraded {
+			corrupted = append(corrupted, fmt.Sprintf("v%d (%d failed)", h.Version, failed))
+		}
 	}
 
-	return currentText, nil
+	if len(corrupted) > 0 {
+		_ = d.CreateLogEntry(
+			ctx,
+			models.LevelError,
+			"DATABASE",
+			"History Reconstruction Degraded",
+			fmt.Sprintf("Article ID: %d | Target Version: %d | Corrupted Patches: %s",
+				articleID, targetVersion, strings.Join(corrupted, ", ")),
+		)
+	}
+
+	return currentText, len(corrupted) > 0, nil
 }
 
 // GetArticleHistory returns the versions for an article.
@@ -194,7 +402,8 @@ func (d *DB) GetArticleHistory(ctx context.Context, articleID int) ([]*models.Hi
 	var history []*models.History
 	err := d.NewSelect().
 		Model(&history).
-		Column("id", "article_id", "version", "created_at").
+		Column("id", "article_id", "version", "created_by", "created_at").
+		Column("data", "snapshot", "compressed").
 		Where("article_id = ?", articleID).
 		Where("version > 0").
 		Order("version DESC").
@@ -204,6 +413,124 @@ func (d *DB) GetArticleHistory(ctx context.Context, articleID int) ([]*models.Hi
 		return nil, err
 	}
 
+	for _, h := range history {
+		err = d.fillChangeStats(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Data = ""
+	}
+
+	return history, nil
+}
+
+// GetHistoryForArticles fetches the most recent published versions, newest first, of each of
+// the given articles in a single query, keyed by article ID. At most limit entries are
+// returned per article.
+func (d *DB) GetHistoryForArticles(
+	ctx context.Context,
+	articleIDs []int,
+	limit int,
+) (map[int][]*models.History, error) {
+	result := make(map[int][]*models.History, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+
+	ranked := d.NewSelect().
+		Model((*models.History)(nil)).
+		Column("id").
+		ColumnExpr("ROW_NUMBER() OVER (PARTITION BY article_id ORDER BY version DESC) AS rn").
+		Where("article_id IN (?)", bun.In(articleIDs)).
+		Where("version > 0")
+
+	latest := d.NewSelect().
+		TableExpr("(?) AS ranked", ranked).
+		Column("id").
+		Where("rn <= ?", limit)
+
+	var history []*models.History
+	err := d.NewSelect().
+		Model(&history).
+		Column("id", "article_id", "version", "created_by", "created_at").
+		Column("data", "snapshot", "compressed").
+		Where("id IN (?)", latest).
+		Order("article_id", "version DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range history {
+		err = d.fillChangeStats(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Data = ""
+		result[h.ArticleId] = append(result[h.ArticleId], h)
+	}
+
+	return result, nil
+}
+
+// GetLastEdit returns the latest published version of an article without its patch data,
+// or nil if the article has never been published.
+func (d *DB) GetLastEdit(ctx context.Context, articleID int) (*models.History, error) {
+	history := new(models.History)
+	err := d.NewSelect().
+		Model(history).
+		Column("id", "article_id", "version", "created_by", "created_at").
+		Where("article_id = ?", articleID).
+		Where("version > 0").
+		Order("version DESC").
+		Limit(1).
+		Scan(ctx)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetRecentChanges returns published versions across all articles, newest first.
+// If author is not empty, only versions published by that email are returned.
+// Each entry includes the article title and slug but not the patch data.
+func (d *DB) GetRecentChanges(
+	ctx context.Context,
+	author string,
+	limit, offset int,
+) ([]*models.History, error) {
+	var history []*models.History
+	query := d.NewSelect().
+		Model(&history).
+		Column("h.id", "h.article_id", "h.version", "h.created_by", "h.created_at").
+		Relation("Article", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("title", "slug")
+		}).
+		Where("h.version > 0")
+
+	if author != "" {
+		query = query.Where("lower(h.created_by) = ?", utils.NormalizeEmail(author))
+	}
+
+	err := query.
+		Order("h.created_at DESC", "h.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return history, nil
 }
 
@@ -256,6 +583,14 @@ func (d *DB) DeleteArticle(ctx context.Context, articleID int) error {
 		return err
 	}
 
+	_, err = tx.NewDelete().
+		Model((*models.Watch)(nil)).
+		Where("article_id = ?", articleID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.NewDelete().
 		Model((*models.Article)(nil)).
 		Where("id = ?", articleID).
@@ -2,7 +2,14 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/uptrace/bun/driver/sqliteshim"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,6 +38,34 @@ func TestCreateArticleWithDraft(t *testing.T) {
 	assert.Equal(t, user.Email, draft.CreatedBy)
 }
 
+func TestCreateArticleWithDraft_Template(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compress=%v", compress), func(t *testing.T) {
+			db := newTestDB(t)
+			db.SetCompression(compress)
+			ctx := context.Background()
+
+			db.SetArticleTemplate("# {{title}}\n\n## Overview\n\nAbout {{title}}.\n")
+
+			_, draft, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+			require.NoError(t, err)
+
+			_, content, err := db.GetDraftByID(ctx, draft.Id)
+			require.NoError(t, err)
+			assert.Equal(t, "# Test Article\n\n## Overview\n\nAbout Test Article.\n", content)
+
+			db.SetArticleTemplate("")
+
+			_, draft, err = db.CreateArticleWithDraft(ctx, "Blank Article", "test@example.com")
+			require.NoError(t, err)
+
+			_, content, err = db.GetDraftByID(ctx, draft.Id)
+			require.NoError(t, err)
+			assert.Empty(t, content)
+		})
+	}
+}
+
 func TestGetArticleBySlug(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -101,3 +136,592 @@ func TestGetArticlesByUser(t *testing.T) {
 	assert.Len(t, articles, 1)
 	assert.Equal(t, "third-article", articles[0].Slug)
 }
+
+func TestCountArticlesByUser(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, _, err := db.CreateArticleWithDraft(ctx, "First Article", "user1@example.com")
+	require.NoError(t, err)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Second Article", "user1@example.com")
+	require.NoError(t, err)
+
+	count, err := db.CountArticlesByUser(ctx, "user1@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = db.CountArticlesByUser(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestGetArticlesBySlugs(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, _, err := db.CreateArticleWithDraft(ctx, "First Article", "user1@example.com")
+	require.NoError(t, err)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Second Article", "user1@example.com")
+	require.NoError(t, err)
+
+	articles, err := db.GetArticlesBySlugs(ctx, []string{"first-article", "second-article", "missing"})
+	require.NoError(t, err)
+	assert.Len(t, articles, 2)
+
+	articles, err = db.GetArticlesBySlugs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+}
+
+func TestGetArticleVersion_CorruptedHistory(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	v1 := "# Title\n\nIntro.\n\n" +
+		"This whole paragraph is going to be removed by the second revision of the page.\n\n" +
+		"Outro.\n"
+	v2 := "# Title\n\nIntro.\n\nOutro.\n"
+
+	for _, content := range []string{v1, v2} {
+		draft, err := db.CreateDraft(ctx, article.Id, content, "test@example.com")
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+	}
+
+	content, degraded, err := db.GetArticleVersion(ctx, article.Id, 2)
+	require.NoError(t, err)
+	assert.False(t, degraded)
+	assert.Equal(t, v2, content)
+
+	// Rewrite v1 so the v2 patch no longer matches the text it expects to delete.
+	corrupted := "# Title\n\nIntro.\n\n" +
+		"This entire section will be dropped by the next edit of this wiki page here.\n\n" +
+		"Outro.\n"
+	dmp := diffmatchpatch.New()
+
+	_, err = db.NewUpdate().
+		Model((*models.History)(nil)).
+		Set("data = ?", dmp.PatchToText(dmp.PatchMake("", corrupted))).
+		Where("article_id = ?", article.Id).
+		Where("version = 1").
+		Exec(ctx)
+	require.NoError(t, err)
+
+	content, degraded, err = db.GetArticleVersion(ctx, article.Id, 2)
+	require.NoError(t, err, "corrupted history should not hard-error")
+	assert.True(t, degraded)
+	assert.Equal(t, v2, content)
+}
+
+// publishVersions publishes count sequential edits and returns each version's content.
+func publishVersions(t testing.TB, db *DB, articleID, count int) []string {
+	t.Helper()
+	ctx := context.Background()
+
+	contents := make([]string, 0, count)
+	text := "# Title\n"
+
+	for i := 1; i <= count; i++ {
+		text += fmt.Sprintf("\nParagraph %d of the article.\n", i)
+
+		draft, err := db.CreateDraft(ctx, articleID, text, "test@example.com")
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+		contents = append(contents, text)
+	}
+
+	return contents
+}
+
+func TestGetArticleVersion_Snapshots(t *testing.T) {
+	db := newTestDB(t)
+	db.snapshotInterval = 2
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	contents := publishVersions(t, db, article.Id, 5)
+
+	var history []models.History
+	err = db.NewSelect().Model(&history).Where("article_id = ?", article.Id).Scan(ctx)
+	require.NoError(t, err)
+
+	for _, h := range history {
+		assert.Equal(t, h.Version > 0 && h.Version%2 == 0, h.Snapshot, "v%d", h.Version)
+		if h.Snapshot {
+			assert.Equal(t, contents[h.Version-1], h.Data)
+		}
+	}
+
+	for i, expected := range contents {
+		content, degraded, err := db.GetArticleVersion(ctx, article.Id, i+1)
+		require.NoError(t, err)
+		assert.False(t, degraded)
+		assert.Equal(t, expected, content, "v%d", i+1)
+	}
+}
+
+func TestAddMissingColumns_LegacyHistory(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, "ALTER TABLE history DROP COLUMN snapshot")
+	require.NoError(t, err)
+
+	require.NoError(t, db.addMissingColumns(ctx))
+	require.NoError(t, db.addMissingColumns(ctx), "adding columns should be idempotent")
+
+	var count int
+	err = db.NewRaw("SELECT COUNT(*) FROM pragma_table_info('history') WHERE name = 'snapshot'").
+		Scan(ctx, &count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func BenchmarkGetArticleVersion(b *testing.B) {
+	const versions = 500
+
+	for _, bench := range []struct {
+		name     string
+		interval int
+	}{
+		{"patches-only", 0},
+		{"snapshots", DefaultSnapshotInterval},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			db := newTestDB(b)
+			db.snapshotInterval = bench.interval
+			ctx := context.Background()
+
+			article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+			require.NoError(b, err)
+
+			publishVersions(b, db, article.Id, versions)
+
+			for b.Loop() {
+				_, _, err := db.GetArticleVersion(ctx, article.Id, versions-1)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestGetRecentChanges(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	first, _, err := db.CreateArticleWithDraft(ctx, "First Article", "first@example.com")
+	require.NoError(t, err)
+	second, _, err := db.CreateArticleWithDraft(ctx, "Second Article", "second@example.com")
+	require.NoError(t, err)
+
+	for _, edit := range []struct {
+		articleID int
+		author    string
+		content   string
+	}{
+		{first.Id, "first@example.com", "# First v1"},
+		{second.Id, "second@example.com", "# Second v1"},
+		{first.Id, "second@example.com", "# First v2"},
+	} {
+		draft, err := db.CreateDraft(ctx, edit.articleID, edit.content, edit.author)
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+	}
+
+	changes, err := db.GetRecentChanges(ctx, "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, "first-article", changes[0].Article.Slug)
+	assert.Equal(t, 2, changes[0].Version)
+	assert.Equal(t, "second@example.com", changes[0].CreatedBy)
+	assert.Empty(t, changes[0].Data)
+	assert.Equal(t, "Second Article", changes[1].Article.Title)
+
+	page, err := db.GetRecentChanges(ctx, "", 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, 1, page[0].Version)
+	assert.Equal(t, "first-article", page[0].Article.Slug)
+}
+
+func TestGetRecentChanges_ByAuthor(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "first@example.com")
+	require.NoError(t, err)
+
+	for _, author := range []string{"first@example.com", "second@example.com", "first@example.com"} {
+		draft, err := db.CreateDraft(ctx, article.Id, "# Edit by "+author, author)
+		require.NoError(t, err)
+		require.NoError(t, db.PublishDraft(ctx, draft.Id))
+	}
+
+	changes, err := db.GetRecentChanges(ctx, "First@Example.com", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, 3, changes[0].Version)
+	assert.Equal(t, 1, changes[1].Version)
+
+	changes, err = db.GetRecentChanges(ctx, "nobody@example.com", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestAddMissingColumns_BackfillsHistoryAuthor(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+	publishVersions(t, db, article.Id, 1)
+
+	_, err = db.ExecContext(ctx, "ALTER TABLE history DROP COLUMN created_by")
+	require.NoError(t, err)
+
+	require.NoError(t, db.addMissingColumns(ctx))
+
+	changes, err := db.GetRecentChanges(ctx, "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, UnknownAuthor, changes[0].CreatedBy)
+}
+
+// legacySchema is the schema of a database created before any columns were added to it.
+var legacySchema = []string{
+	`CREATE TABLE "articles" ("created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "title" VARCHAR NOT NULL, "slug" VARCHAR NOT NULL, "data" text, "created_by" VARCHAR, "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "version" INTEGER DEFAULT 0, UNIQUE ("slug"))`,
+	`CREATE TABLE "history" ("created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "data" text, "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "article_id" INTEGER NOT NULL, "version" INTEGER NOT NULL)`,
+	`CREATE TABLE "links" ("created_at" TIMESTAMP DEFAULT current_timestamp, "parent_article_id" INTEGER NOT NULL, "linked_article_id" INTEGER NOT NULL, PRIMARY KEY ("parent_article_id", "linked_article_id"))`,
+	`CREATE TABLE "drafts" ("created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "data" text, "created_by" VARCHAR, "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "article_id" INTEGER NOT NULL, "article_version" INTEGER)`,
+	`CREATE TABLE "users" ("created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "name" VARCHAR NOT NULL, "email" VARCHAR NOT NULL, "hash" VARCHAR, "otp_secret" VARCHAR, "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "role" INTEGER NOT NULL, "is_external" BOOLEAN NOT NULL DEFAULT false, "disabled" BOOLEAN DEFAULT false, UNIQUE ("email"))`,
+	`CREATE TABLE "backup_codes" ("created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp, "code" VARCHAR NOT NULL, "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "user_id" INTEGER NOT NULL, "used" BOOLEAN DEFAULT false, UNIQUE ("code"))`,
+	`INSERT INTO articles (title, slug, data, created_by, version) VALUES ('Legacy', 'legacy', '# Legacy', 'old@example.com', 1)`,
+	`INSERT INTO users (name, email, hash, role) VALUES ('Old', 'old@example.com', 'hash', 2)`,
+}
+
+func TestNew_LegacySchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, path)
+	require.NoError(t, err)
+
+	for _, stmt := range legacySchema {
+		_, err = sqldb.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	dmp := diffmatchpatch.New()
+	patch := dmp.PatchToText(dmp.PatchMake("", "# Legacy"))
+	_, err = sqldb.Exec("INSERT INTO history (data, article_id, version) VALUES (?, 1, 1)", patch)
+	require.NoError(t, err)
+
+	require.NoError(t, sqldb.Close())
+
+	db, err := New("file:"+path, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	ctx := context.Background()
+
+	content, _, err := db.GetArticleVersion(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "# Legacy", content)
+
+	draft, err := db.CreateDraft(ctx, 1, "# Legacy\n\nUpdated.", "old@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	articles, _, err := db.GetArticles(ctx, SortUpdated, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, 2, articles[0].Version)
+	assert.False(t, articles[0].UpdatedAt.IsZero(), "updated_at is backfilled")
+
+	history, err := db.GetArticleHistory(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	user, err := db.GetUserByEmail(ctx, "old@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Old", user.Name)
+}
+
+func TestGetArticles_Sort(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	fixtures := []struct {
+		title   string
+		created time.Time
+		updated time.Time
+		views   int
+	}{
+		{"Bravo", base, base.Add(3 * time.Hour), 5},
+		{"alpha", base.Add(time.Hour), base.Add(time.Hour), 10},
+		{"Charlie", base.Add(2 * time.Hour), base.Add(2 * time.Hour), 1},
+	}
+
+	for _, f := range fixtures {
+		article, _, err := db.CreateArticleWithDraft(ctx, f.title, "test@example.com")
+		require.NoError(t, err)
+
+		_, err = db.NewUpdate().
+			Model((*models.Article)(nil)).
+			Set("created_at = ?", f.created).
+			Set("updated_at = ?", f.updated).
+			Set("view_count = ?", f.views).
+			Where("id = ?", article.Id).
+			Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		sort     string
+		expected []string
+	}{
+		{SortCreated, []string{"Charlie", "alpha", "Bravo"}},
+		{SortUpdated, []string{"Bravo", "Charlie", "alpha"}},
+		{SortTitle, []string{"alpha", "Bravo", "Charlie"}},
+		{SortPopular, []string{"alpha", "Bravo", "Charlie"}},
+		{"", []string{"Bravo", "Charlie", "alpha"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("sort_"+tt.sort, func(t *testing.T) {
+			articles, total, err := db.GetArticles(ctx, tt.sort, 10, 0)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(fixtures), total)
+
+			titles := make([]string, len(articles))
+			for i, a := range articles {
+				titles[i] = a.Title
+			}
+
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+
+	_, _, err := db.GetArticles(ctx, "created_at; DROP TABLE articles", 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestGetArticlesFiltered_CreatedRange(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, title := range []string{"First", "Second", "Third"} {
+		article, _, err := db.CreateArticleWithDraft(ctx, title, "test@example.com")
+		require.NoError(t, err)
+
+		_, err = db.NewUpdate().
+			Model((*models.Article)(nil)).
+			Set("created_at = ?", base.Add(time.Duration(i)*time.Hour)).
+			Where("id = ?", article.Id).
+			Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   ArticleFilter
+		expected []string
+	}{
+		{"no filter", ArticleFilter{}, []string{"First", "Second", "Third"}},
+		{
+			"inclusive bounds",
+			ArticleFilter{CreatedAfter: base, CreatedBefore: base.Add(time.Hour)},
+			[]string{"First", "Second"},
+		},
+		{"after only", ArticleFilter{CreatedAfter: base.Add(time.Hour)}, []string{"Second", "Third"}},
+		{"before only", ArticleFilter{CreatedBefore: base.Add(time.Hour)}, []string{"First", "Second"}},
+		{
+			"other time zone",
+			ArticleFilter{CreatedAfter: base.Add(2 * time.Hour).In(time.FixedZone("EST", -5*60*60))},
+			[]string{"Third"},
+		},
+		{
+			"empty range",
+			ArticleFilter{CreatedAfter: base.Add(time.Minute), CreatedBefore: base.Add(59 * time.Minute)},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, total, err := db.GetArticlesFiltered(ctx, SortTitle, tt.filter, 10, 0)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(tt.expected), total)
+
+			titles := make([]string, len(articles))
+			for i, a := range articles {
+				titles[i] = a.Title
+			}
+
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+}
+
+func TestRecordView(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	db.RecordView(article.Id)
+	db.RecordView(article.Id)
+
+	stored, err := db.GetArticleByID(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.ViewCount, "views are buffered until flushed")
+
+	require.NoError(t, db.FlushViews(ctx))
+
+	stored, err = db.GetArticleByID(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.ViewCount)
+
+	db.RecordView(article.Id)
+	require.NoError(t, db.FlushViews(ctx))
+	require.NoError(t, db.FlushViews(ctx))
+
+	stored, err = db.GetArticleByID(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.ViewCount, "flushed views are not written twice")
+}
+
+func TestSetArticleCustomCode(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	cached, err := db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Empty(t, cached.CustomCSS)
+
+	require.NoError(t, db.SetArticleCustomCode(ctx, article.Id, "h1 { color: red; }", "console.log(1);"))
+
+	stored, err := db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, "h1 { color: red; }", stored.CustomCSS)
+	assert.Equal(t, "console.log(1);", stored.CustomJS)
+
+	err = db.SetArticleCustomCode(ctx, article.Id+100, "", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAddMissingColumns_BackfillsArticleUpdatedAt(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, "ALTER TABLE articles DROP COLUMN updated_at")
+	require.NoError(t, err)
+
+	require.NoError(t, db.addMissingColumns(ctx))
+
+	stored, err := db.GetArticleByID(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, stored.CreatedAt, stored.UpdatedAt)
+}
+
+func TestGetArticleHistory_ChangeStats(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		interval int
+	}{
+		{"patches", 0},
+		{"snapshots", 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			db.snapshotInterval = tt.interval
+			db.SetCompression(true)
+			ctx := context.Background()
+
+			article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+			require.NoError(t, err)
+
+			for _, text := range []string{"Hello world, café+1", "Hello brave world"} {
+				draft, err := db.CreateDraft(ctx, article.Id, text, "test@example.com")
+				require.NoError(t, err)
+				require.NoError(t, db.PublishDraft(ctx, draft.Id))
+			}
+
+			history, err := db.GetArticleHistory(ctx, article.Id)
+			require.NoError(t, err)
+			require.Len(t, history, 2)
+
+			assert.Equal(t, 2, history[0].Version)
+			// Semantic cleanup turns the edit into replacing "world, café+1" with "brave world".
+			assert.Equal(t, 11, history[0].Added)
+			assert.Equal(t, 13, history[0].Removed)
+			assert.Empty(t, history[0].Data, "patch data is not returned")
+
+			assert.Equal(t, 1, history[1].Version)
+			assert.Equal(t, 19, history[1].Added)
+			assert.Equal(t, 0, history[1].Removed)
+
+			cached, err := db.GetArticleHistory(ctx, article.Id)
+			require.NoError(t, err)
+			assert.Equal(t, history[0].Added, cached[0].Added)
+			assert.Equal(t, 2, db.statsCache.Len())
+		})
+	}
+}
+
+func TestGetHistoryForArticles(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	var ids []int
+	for _, title := range []string{"First", "Second"} {
+		article, _, err := db.CreateArticleWithDraft(ctx, title, "test@example.com")
+		require.NoError(t, err)
+
+		for i := range 3 {
+			draft, err := db.CreateDraft(ctx, article.Id, fmt.Sprintf("%s v%d", title, i+1), "test@example.com")
+			require.NoError(t, err)
+			require.NoError(t, db.PublishDraft(ctx, draft.Id))
+		}
+
+		ids = append(ids, article.Id)
+	}
+
+	history, err := db.GetHistoryForArticles(ctx, ids, 2)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	for _, id := range ids {
+		require.Len(t, history[id], 2, "entries are capped per article")
+		assert.Equal(t, 3, history[id][0].Version)
+		assert.Equal(t, 2, history[id][1].Version)
+		assert.Equal(t, id, history[id][0].ArticleId)
+		assert.Empty(t, history[id][0].Data, "patch data is not returned")
+		assert.Positive(t, history[id][0].Added)
+	}
+
+	empty, err := db.GetHistoryForArticles(ctx, nil, 2)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
@@ -50,6 +50,56 @@ func (d *DB) CreateBackupCodes(ctx context.Context, backupCodes []*models.Backup
 	return tx.Commit()
 }
 
+// EnableOTP saves a user's OTP secret and replaces their backup codes in a single transaction,
+// so a failure never leaves OTP enabled without its matching backup codes.
+func (d *DB) EnableOTP(ctx context.Context, userID int, secret string, codes []string) error {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx bun.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Println(err)
+		}
+	}(tx)
+
+	_, err = tx.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("otp_secret = ?", secret).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.BackupCode)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		backupCode := &models.BackupCode{
+			UserId:    userID,
+			Code:      code,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		_, err = tx.NewInsert().Model(backupCode).Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // GetBackupCodeByCode fetches a backup code by its code string.
 func (d *DB) GetBackupCodeByCode(ctx context.Context, code string) (*models.BackupCode, error) {
 	backupCode := new(models.BackupCode)
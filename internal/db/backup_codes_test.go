@@ -217,3 +217,48 @@ func TestCountUnusedBackupCodesByUserId(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 2, count)
 }
+
+func TestEnableOTP(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, user))
+	require.NoError(t, db.CreateBackupCode(ctx, &models.BackupCode{UserId: user.Id, Code: "OLD-CODE"}))
+
+	err := db.EnableOTP(ctx, user.Id, "SECRET", []string{"CODE-1", "CODE-2"})
+	require.NoError(t, err)
+
+	found, err := db.GetUserByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET", found.OTPSecret)
+
+	codes, err := db.GetBackupCodesByUserId(ctx, user.Id)
+	require.NoError(t, err)
+	require.Len(t, codes, 2)
+	assert.Equal(t, "CODE-1", codes[0].Code)
+	assert.Equal(t, "CODE-2", codes[1].Code)
+}
+
+func TestEnableOTP_RollsBackOnFailure(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, user))
+	require.NoError(t, db.CreateBackupCode(ctx, &models.BackupCode{UserId: user.Id, Code: "OLD-CODE"}))
+
+	// The duplicate code violates the unique constraint after the secret is saved
+	// and the old codes are deleted, so the whole transaction must roll back.
+	err := db.EnableOTP(ctx, user.Id, "SECRET", []string{"CODE-1", "CODE-1"})
+	require.Error(t, err)
+
+	found, err := db.GetUserByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	assert.Empty(t, found.OTPSecret, "OTP secret must not be saved")
+
+	codes, err := db.GetBackupCodesByUserId(ctx, user.Id)
+	require.NoError(t, err)
+	require.Len(t, codes, 1, "existing backup codes must be kept")
+	assert.Equal(t, "OLD-CODE", codes[0].Code)
+}
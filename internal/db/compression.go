@@ -0,0 +1,82 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// SetCompression enables or disables gzip compression of newly written draft and history data.
+// Existing rows keep their current encoding and remain readable either way.
+func (d *DB) SetCompression(enabled bool) {
+	d.compress = enabled
+}
+
+// encodeData prepares patch or snapshot text for storage, compressing it when enabled.
+// It reports whether the returned value is compressed.
+func (d *DB) encodeData(data string) (string, bool, error) {
+	if !d.compress || data == "" {
+		return data, false, nil
+	}
+
+	compressed, err := compressData(data)
+	if err != nil {
+		return "", false, err
+	}
+
+	return compressed, true, nil
+}
+
+// decodeData returns the plain text of stored draft or history data.
+func decodeData(data string, compressed bool) (string, error) {
+	if !compressed {
+		return data, nil
+	}
+
+	return decompressData(data)
+}
+
+// compressData gzips data and base64-encodes the result so it fits a text column.
+func compressData(data string) (string, error) {
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+
+	_, err := zw.Write([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decompressData reverses compressData.
+func decompressData(data string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode compressed data: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress data: %w", err)
+	}
+
+	defer func() {
+		_ = zr.Close()
+	}()
+
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress data: %w", err)
+	}
+
+	return string(plain), nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestCompressData_RoundTrip(t *testing.T) {
+	original := strings.Repeat("@@ -1,4 +1,9 @@\n-old\n+new line of text\n", 50)
+
+	compressed, err := compressData(original)
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(original))
+
+	plain, err := decompressData(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, original, plain)
+}
+
+func TestCompression_MixedRowsReadable(t *testing.T) {
+	db := newTestDB(t)
+	db.snapshotInterval = 3
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	contents := publishVersions(t, db, article.Id, 2)
+
+	db.SetCompression(true)
+	contents = append(contents, publishVersions(t, db, article.Id, 2)...)
+
+	var history []models.History
+	err = db.NewSelect().
+		Model(&history).
+		Where("article_id = ?", article.Id).
+		Where("version > 0").
+		Order("version ASC").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.False(t, history[1].Compressed)
+	assert.True(t, history[2].Compressed)
+	assert.True(t, history[2].Snapshot)
+
+	for i, expected := range contents {
+		content, degraded, err := db.GetArticleVersion(ctx, article.Id, i+1)
+		require.NoError(t, err)
+		assert.False(t, degraded)
+		assert.Equal(t, expected, content, "v%d", i+1)
+	}
+
+	draft, err := db.CreateDraft(ctx, article.Id, "# Compressed draft", "test@example.com")
+	require.NoError(t, err)
+	assert.True(t, draft.Compressed)
+
+	require.NoError(t, db.UpdateDraft(ctx, draft.Id, "# Compressed draft v2", "test@example.com", false, nil))
+
+	db.SetCompression(false)
+
+	found, content, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	assert.True(t, found.Compressed)
+	assert.Equal(t, "# Compressed draft v2", content)
+
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	updated, err := db.GetArticleBySlug(ctx, "test-article")
+	require.NoError(t, err)
+	assert.Equal(t, "# Compressed draft v2", updated.Data)
+}
+
+func BenchmarkPatchStorage(b *testing.B) {
+	base := strings.Repeat("A paragraph of wiki content that is edited over time.\n", 200)
+	edited := strings.ReplaceAll(base, "edited", "revised")
+
+	dmp := diffmatchpatch.New()
+	patch := dmp.PatchToText(dmp.PatchMake(base, edited))
+
+	b.Run("plain", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = decodeData(patch, false)
+		}
+		b.ReportMetric(float64(len(patch)), "bytes/row")
+	})
+
+	b.Run("gzip", func(b *testing.B) {
+		var stored string
+
+		for b.Loop() {
+			var err error
+
+			stored, err = compressData(patch)
+			require.NoError(b, err)
+
+			_, err = decodeData(stored, true)
+			require.NoError(b, err)
+		}
+		b.ReportMetric(float64(len(stored)), "bytes/row")
+	})
+}
@@ -3,9 +3,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
+	"wikilite/internal/cache"
 	"wikilite/pkg/models"
 
 	"github.com/jellydator/ttlcache/v3"
@@ -15,12 +18,30 @@ import (
 )
 
 const (
-	cacheTtl       = 30 * time.Minute
-	cacheSize      = 1000
-	logChannelSize = 1000
-	logWorkers     = 5
-	DefaultWikiDb  = "wiki.db"
-	DefaultLogDb   = "logs.db"
+	DefaultWikiDb = "wiki.db"
+	DefaultLogDb  = "logs.db"
+	// DefaultLogQueueSize is the default number of log entries buffered for the log workers.
+	DefaultLogQueueSize = 1000
+	// DefaultLogWorkers is the default number of workers writing log entries to the log database.
+	DefaultLogWorkers = 5
+	// DefaultSnapshotInterval is how many versions apart full-content history snapshots are stored.
+	DefaultSnapshotInterval = 50
+	// DefaultMaxSlugLength is the default maximum length of the slugs of new articles.
+	DefaultMaxSlugLength = 80
+	// UnknownAuthor is recorded for history entries published before authors were tracked.
+	UnknownAuthor = "unknown"
+)
+
+// SQLLogMode selects which main database queries are written to the system logs.
+type SQLLogMode string
+
+const (
+	// SQLLogAll logs every query.
+	SQLLogAll SQLLogMode = "all"
+	// SQLLogErrors logs only failed queries.
+	SQLLogErrors SQLLogMode = "errors"
+	// SQLLogOff disables query logging.
+	SQLLogOff SQLLogMode = "off"
 )
 
 // DB wraps the Bun DB instance and holds the application cache.
@@ -30,14 +51,72 @@ type DB struct {
 	logDB *bun.DB
 
 	articleCache *ttlcache.Cache[string, *models.Article]
+	statsCache   *ttlcache.Cache[int, changeStats]
+
+	logs  *logQueue
+	logWg sync.WaitGroup
+
+	views *viewCounter
+
+	// snapshotInterval stores every Nth published version as full content. Zero disables it.
+	snapshotInterval int
+	// maxHistory is how many versions of each article are kept. Zero keeps all of them.
+	maxHistory int
+	// compress gzips newly written draft and history data.
+	compress bool
+	// maxSlugLength is the maximum length of the slugs of new articles. Zero does not limit it.
+	maxSlugLength int
+	// articleTemplate is the genesis draft content of new articles. Empty leaves them blank.
+	articleTemplate string
+}
+
+// logQueue buffers log entries for the log workers.
+// Entries pushed after the queue is closed are dropped instead of panicking.
+type logQueue struct {
+	entries chan *models.SystemLog
+	mu      sync.RWMutex
+	closed  bool
+	// dropped counts entries discarded because the queue was full.
+	dropped atomic.Int64
+}
+
+// newLogQueue creates a log queue with the given buffer size.
+func newLogQueue(size int) *logQueue {
+	return &logQueue{entries: make(chan *models.SystemLog, size)}
+}
 
-	logChan chan *models.SystemLog
-	logWg   sync.WaitGroup
+// push enqueues a log entry without blocking. The entry is dropped if the queue is full or closed.
+func (q *logQueue) push(entry *models.SystemLog) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return
+	}
+
+	select {
+	case q.entries <- entry:
+	default:
+		q.dropped.Add(1)
+	}
 }
 
-// dbLogger intercepts main DB queries and sends them to the log channel.
+// close stops accepting new entries and lets workers drain the remaining ones.
+func (q *logQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.entries)
+	}
+}
+
+// dbLogger intercepts main DB queries and sends them to the log queue.
 type dbLogger struct {
-	logChan chan *models.SystemLog
+	logs *logQueue
+	// errorsOnly skips queries that succeeded.
+	errorsOnly bool
 }
 
 // BeforeQuery is a no-op that satisfies the bun.QueryHook interface.
@@ -45,15 +124,22 @@ func (h *dbLogger) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.C
 	return ctx
 }
 
-// AfterQuery logs the query to the log channel.
+// AfterQuery logs the query to the log queue.
 func (h *dbLogger) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	// A select that finds no rows is a normal outcome, not a failed query.
+	failed := event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows)
+
+	if h.errorsOnly && !failed {
+		return
+	}
+
 	query := event.Query
 	if len(query) > 1000 {
 		query = query[:1000] + "...(truncated)"
 	}
 
 	level := models.LevelSQL
-	if event.Err != nil {
+	if failed {
 		level = models.LevelSQLError
 	}
 
@@ -66,14 +152,67 @@ func (h *dbLogger) AfterQuery(_ context.Context, event *bun.QueryEvent) {
 		CreatedAt: time.Now(),
 	}
 
-	select {
-	case h.logChan <- logEntry:
-	default:
+	h.logs.push(logEntry)
+}
+
+// Option configures a DB created by New.
+type Option func(*options)
+
+// options holds the settings applied by New.
+type options struct {
+	logQueueSize int
+	logWorkers   int
+	sqlLogMode   SQLLogMode
+	articleCache cache.Config
+}
+
+// WithLogQueueSize sets how many log entries are buffered before new ones are dropped.
+func WithLogQueueSize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.logQueueSize = size
+		}
+	}
+}
+
+// WithLogWorkers sets how many workers write log entries to the log database.
+func WithLogWorkers(count int) Option {
+	return func(o *options) {
+		if count > 0 {
+			o.logWorkers = count
+		}
+	}
+}
+
+// WithSQLLogging selects which queries on the main database are logged. Defaults to SQLLogAll.
+func WithSQLLogging(mode SQLLogMode) Option {
+	return func(o *options) {
+		if mode != "" {
+			o.sqlLogMode = mode
+		}
 	}
 }
 
-// New initializes connections, cache, and the log worker pool.
-func New(mainDSN string, logDSN string) (*DB, error) {
+// WithArticleCache sets the lifetime and capacity of the article cache. Unset fields
+// default to those of cache.Default.
+func WithArticleCache(config cache.Config) Option {
+	return func(o *options) {
+		o.articleCache = config
+	}
+}
+
+// New initializes connections, cache, the log worker pool and the view flusher.
+func New(mainDSN string, logDSN string, opts ...Option) (*DB, error) {
+	cfg := options{
+		logQueueSize: DefaultLogQueueSize,
+		logWorkers:   DefaultLogWorkers,
+		sqlLogMode:   SQLLogAll,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	sqldb, err := sql.Open(sqliteshim.ShimName, mainDSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open main db: %w", err)
@@ -86,35 +225,53 @@ func New(mainDSN string, logDSN string) (*DB, error) {
 		return nil, fmt.Errorf("failed to open log db: %w", err)
 	}
 
+	// SQLite allows one writer at a time; sharing a single connection between the log
+	// workers queues their inserts instead of failing them with SQLITE_BUSY.
+	logSqlDb.SetMaxOpenConns(1)
+
 	logDB := bun.NewDB(logSqlDb, sqlitedialect.New())
 
-	logChan := make(chan *models.SystemLog, logChannelSize)
+	logs := newLogQueue(cfg.logQueueSize)
 
-	mainDB.WithQueryHook(&dbLogger{logChan: logChan})
+	if cfg.sqlLogMode != SQLLogOff {
+		mainDB = mainDB.WithQueryHook(&dbLogger{logs: logs, errorsOnly: cfg.sqlLogMode == SQLLogErrors})
+	}
 
-	cache := ttlcache.New[string, *models.Article](
-		ttlcache.WithTTL[string, *models.Article](cacheTtl),
-		ttlcache.WithCapacity[string, *models.Article](cacheSize),
-	)
-	go cache.Start()
+	articleCache := cache.New[string, *models.Article](cfg.articleCache.Or(cache.Default))
+	go articleCache.Start()
 
 	d := &DB{
-		DB:           mainDB,
-		logDB:        logDB,
-		articleCache: cache,
-		logChan:      logChan,
+		DB:               mainDB,
+		logDB:            logDB,
+		articleCache:     articleCache,
+		statsCache:       newStatsCache(),
+		logs:             logs,
+		views:            newViewCounter(),
+		snapshotInterval: DefaultSnapshotInterval,
+		maxSlugLength:    DefaultMaxSlugLength,
 	}
 
-	d.startLogWorkers(logWorkers)
+	d.startLogWorkers(cfg.logWorkers)
+	d.startViewFlusher()
 
 	err = d.createTables(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
+	err = d.addMissingColumns(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
 	return d, nil
 }
 
+// DroppedLogs returns the number of log entries discarded because the log queue was full.
+func (d *DB) DroppedLogs() int64 {
+	return d.logs.dropped.Load()
+}
+
 // Ping checks the connectivity of both the main and log databases.
 func (d *DB) Ping(_ context.Context) error {
 	err := d.DB.Ping()
@@ -133,8 +290,9 @@ func (d *DB) Ping(_ context.Context) error {
 // Close cleans up resources.
 func (d *DB) Close() error {
 	d.articleCache.Stop()
+	d.stopViewFlusher()
 
-	close(d.logChan)
+	d.logs.close()
 	d.logWg.Wait()
 	_ = d.logDB.Close()
 
@@ -150,6 +308,10 @@ func (d *DB) createTables(ctx context.Context) error {
 		(*models.Draft)(nil),
 		(*models.User)(nil),
 		(*models.BackupCode)(nil),
+		(*models.Webhook)(nil),
+		(*models.Template)(nil),
+		(*models.Watch)(nil),
+		(*models.ApiToken)(nil),
 	}
 
 	for _, model := range mainModels {
@@ -173,13 +335,115 @@ func (d *DB) createTables(ctx context.Context) error {
 	return nil
 }
 
+// addMissingColumns adds columns introduced after a table was first created,
+// since createTables only creates tables that do not exist yet.
+// SQLite cannot add columns with a non-constant default, so such columns are added without
+// one and filled in by their backfill statement.
+func (d *DB) addMissingColumns(ctx context.Context) error {
+	columns := []struct {
+		model      any
+		table      string
+		name       string
+		definition string
+		backfill   string
+	}{
+		{
+			model: (*models.History)(nil), table: "history", name: "snapshot",
+			definition: "snapshot BOOLEAN NOT NULL DEFAULT FALSE",
+		},
+		{
+			model: (*models.History)(nil), table: "history", name: "compressed",
+			definition: "compressed BOOLEAN NOT NULL DEFAULT FALSE",
+		},
+		{
+			model: (*models.Draft)(nil), table: "drafts", name: "compressed",
+			definition: "compressed BOOLEAN NOT NULL DEFAULT FALSE",
+		},
+		{
+			model: (*models.History)(nil), table: "history", name: "created_by",
+			definition: "created_by VARCHAR NOT NULL DEFAULT '" + UnknownAuthor + "'",
+		},
+		{
+			model: (*models.User)(nil), table: "users", name: "token_version",
+			definition: "token_version INTEGER NOT NULL DEFAULT 0",
+		},
+		{
+			model: (*models.User)(nil), table: "users", name: "editor_mode",
+			definition: "editor_mode VARCHAR NOT NULL DEFAULT '" + string(models.EditorMarkdown) + "'",
+		},
+		{
+			model: (*models.Article)(nil), table: "articles", name: "updated_at",
+			definition: "updated_at TIMESTAMP",
+			backfill:   "UPDATE articles SET updated_at = created_at WHERE updated_at IS NULL",
+		},
+		{
+			model: (*models.Article)(nil), table: "articles", name: "view_count",
+			definition: "view_count INTEGER NOT NULL DEFAULT 0",
+		},
+		{
+			model: (*models.Article)(nil), table: "articles", name: "tags",
+			definition: "tags VARCHAR",
+		},
+		{
+			model: (*models.Article)(nil), table: "articles", name: "custom_css",
+			definition: "custom_css TEXT",
+		},
+		{
+			model: (*models.Article)(nil), table: "articles", name: "custom_js",
+			definition: "custom_js TEXT",
+		},
+		{
+			model: (*models.Draft)(nil), table: "drafts", name: "is_suggestion",
+			definition: "is_suggestion BOOLEAN NOT NULL DEFAULT FALSE",
+		},
+		{
+			model: (*models.Draft)(nil), table: "drafts", name: "suggested_by",
+			definition: "suggested_by VARCHAR",
+		},
+		{
+			model: (*models.Draft)(nil), table: "drafts", name: "suggested_from",
+			definition: "suggested_from VARCHAR",
+		},
+	}
+
+	for _, column := range columns {
+		var count int
+		err := d.NewRaw(
+			"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?",
+			column.table,
+			column.name,
+		).Scan(ctx, &count)
+		if err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", column.table, err)
+		}
+
+		if count > 0 {
+			continue
+		}
+
+		_, err = d.NewAddColumn().Model(column.model).ColumnExpr(column.definition).Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", column.table, column.name, err)
+		}
+
+		if column.backfill != "" {
+			_, err = d.ExecContext(ctx, column.backfill)
+			if err != nil {
+				return fmt.Errorf("failed to backfill %s.%s: %w", column.table, column.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // startLogWorkers spins up 'count' background goroutines to process logs.
 func (d *DB) startLogWorkers(count int) {
 	for range count {
 
 		d.logWg.Go(func() {
 
-			for entry := range d.logChan {
+			for entry := range d.logs.entries {
 				_, _ = d.logDB.NewInsert().Model(entry).Exec(context.Background())
 			}
 		})
@@ -19,23 +19,60 @@ var (
 	ErrCannotDiscardDraft = errors.New("unauthorized: you cannot discard this draft")
 )
 
+// ErrContentTooLarge is returned when draft content exceeds the size the diff engine will accept.
+var ErrContentTooLarge = errors.New("content exceeds maximum size")
+
+// ErrDraftConflict is returned when a draft was saved by someone else (or another tab)
+// after the version the caller edited.
+var ErrDraftConflict error = &kindError{msg: "the draft has been updated since it was loaded", kind: ErrConflict}
+
+const (
+	// maxDiffContentSize is a hard ceiling on content passed to the diff engine,
+	// independent of any limit enforced by the API layer.
+	maxDiffContentSize = 10 << 20
+	// diffTimeout bounds the time spent computing a single diff.
+	diffTimeout = 2 * time.Second
+)
+
+// newDiffer returns a diff engine configured with the draft diff timeout.
+func newDiffer() *diffmatchpatch.DiffMatchPatch {
+	dmp := diffmatchpatch.New()
+	dmp.DiffTimeout = diffTimeout
+
+	return dmp
+}
+
 // createGenesisDraft is internal but attached to DB to allow for future logging/metrics.
 func (d *DB) createGenesisDraft(
 	ctx context.Context,
 	db bun.IDB,
 	articleID int,
+	content string,
 	userID string,
 ) (*models.Draft, error) {
+	var patchText string
+
+	if content != "" {
+		dmp := newDiffer()
+		patchText = dmp.PatchToText(dmp.PatchMake("", content))
+	}
+
+	data, compressed, err := d.encodeData(patchText)
+	if err != nil {
+		return nil, err
+	}
+
 	draft := &models.Draft{
 		ArticleId:      articleID,
 		ArticleVersion: 0,
-		Data:           "",
+		Data:           data,
+		Compressed:     compressed,
 		CreatedBy:      userID,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
-	_, err := db.NewInsert().Model(draft).Exec(ctx)
+	_, err = db.NewInsert().Model(draft).Exec(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -50,6 +87,10 @@ func (d *DB) CreateDraft(
 	newContent string,
 	userID string,
 ) (*models.Draft, error) {
+	if len(newContent) > maxDiffContentSize {
+		return nil, ErrContentTooLarge
+	}
+
 	tx, err := d.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -77,16 +118,20 @@ func (d *DB) CreateDraft(
 		return nil, err
 	}
 
-	dmp := diffmatchpatch.New()
+	dmp := newDiffer()
 	diffs := dmp.DiffMain(article.Data, newContent, false)
 	dmp.DiffCleanupSemantic(diffs)
 	patches := dmp.PatchMake(article.Data, diffs)
-	patchText := dmp.PatchToText(patches)
+	patchText, compressed, err := d.encodeData(dmp.PatchToText(patches))
+	if err != nil {
+		return nil, err
+	}
 
 	draft := &models.Draft{
 		ArticleId:      article.Id,
 		ArticleVersion: article.Version,
 		Data:           patchText,
+		Compressed:     compressed,
 		CreatedBy:      userID,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -105,7 +150,9 @@ func (d *DB) CreateDraft(
 	return draft, nil
 }
 
-// GetDraftByID fetches a draft.
+// GetDraftByID fetches a draft, or returns ErrNotFound when there is none with the ID.
+// If the draft patch no longer applies cleanly to its article, a best-effort reconstruction
+// is returned and draft.ReconstructionDegraded is set instead of failing.
 func (d *DB) GetDraftByID(ctx context.Context, draftID int) (*models.Draft, string, error) {
 	draft := new(models.Draft)
 	err := d.NewSelect().
@@ -115,22 +162,33 @@ func (d *DB) GetDraftByID(ctx context.Context, draftID int) (*models.Draft, stri
 		Scan(ctx)
 
 	if err != nil {
-		return nil, "", err
+		return nil, "", notFound(err)
+	}
+
+	patchText, err := decodeData(draft.Data, draft.Compressed)
+	if err != nil {
+		return draft, "", err
 	}
 
 	dmp := diffmatchpatch.New()
 
-	patches, err := dmp.PatchFromText(draft.Data)
+	patches, err := dmp.PatchFromText(patchText)
 	if err != nil {
 		return draft, "", fmt.Errorf("failed to parse draft patch: %w", err)
 	}
 
-	reconstructedText, results := dmp.PatchApply(patches, draft.Article.Data)
-
-	for _, success := range results {
-		if !success {
-			return draft, "", fmt.Errorf("version mismatch caused patch conflict")
-		}
+	reconstructedText, degraded, failed := applyPatches(patches, draft.Article.Data)
+	if degraded {
+		draft.ReconstructionDegraded = true
+
+		_ = d.CreateLogEntry(
+			ctx,
+			models.LevelError,
+			"DATABASE",
+			"Draft Reconstruction Degraded",
+			fmt.Sprintf("Draft ID: %d | Article ID: %d | Failed Patches: %d",
+				draft.Id, draft.ArticleId, failed),
+		)
 	}
 
 	return draft, reconstructedText, nil
@@ -153,6 +211,14 @@ func (d *DB) GetDraftsByUser(ctx context.Context, userID string) ([]*models.Draf
 	return drafts, nil
 }
 
+// CountDraftsByUser returns the number of drafts started by a specific user.
+func (d *DB) CountDraftsByUser(ctx context.Context, userID string) (int, error) {
+	return d.NewSelect().
+		Model((*models.Draft)(nil)).
+		Where("created_by = ?", userID).
+		Count(ctx)
+}
+
 // GetDraftsByArticle returns all active drafts for a specific article.
 func (d *DB) GetDraftsByArticle(
 	ctx context.Context,
@@ -178,49 +244,144 @@ func (d *DB) GetDraftsByArticle(
 }
 
 // UpdateDraft updates the draft with new content.
-func (d *DB) UpdateDraft(ctx context.Context, draftID int, newContent string, userID string) error {
+// Admins may update drafts owned by other users.
+// When expectedUpdatedAt is set, the update is rejected with ErrDraftConflict if
+// the draft has been saved since, e.g. from another editor tab.
+func (d *DB) UpdateDraft(
+	ctx context.Context,
+	draftID int,
+	newContent string,
+	userID string,
+	isAdmin bool,
+	expectedUpdatedAt *time.Time,
+) error {
+	if len(newContent) > maxDiffContentSize {
+		return ErrContentTooLarge
+	}
+
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx bun.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Println(err)
+		}
+	}(tx)
+
 	draft := new(models.Draft)
 
-	err := d.NewSelect().Model(draft).Where("id = ?", draftID).Scan(ctx)
+	err = tx.NewSelect().Model(draft).Where("id = ?", draftID).Scan(ctx)
 	if err != nil {
 		return err
 	}
 
-	if draft.CreatedBy != userID {
+	if draft.CreatedBy != userID && !isAdmin {
 		return ErrCannotEditDraft
 	}
 
+	if expectedUpdatedAt != nil && !draft.UpdatedAt.Equal(*expectedUpdatedAt) {
+		return ErrDraftConflict
+	}
+
 	article := new(models.Article)
 
-	err = d.NewSelect().Model(article).Where("id = ?", draft.ArticleId).Scan(ctx)
+	err = tx.NewSelect().Model(article).Where("id = ?", draft.ArticleId).Scan(ctx)
 	if err != nil {
 		return err
 	}
 
-	dmp := diffmatchpatch.New()
+	dmp := newDiffer()
 	diffs := dmp.DiffMain(article.Data, newContent, false)
 	dmp.DiffCleanupSemantic(diffs)
 	patches := dmp.PatchMake(article.Data, diffs)
 
 	if len(patches) == 0 {
-		_, err := d.NewDelete().Model(draft).WherePK().Exec(ctx)
+		_, err = tx.NewDelete().Model(draft).WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return tx.Commit()
 	}
 
-	patchString := dmp.PatchToText(patches)
+	patchString, compressed, err := d.encodeData(dmp.PatchToText(patches))
+	if err != nil {
+		return err
+	}
 
 	draft.Data = patchString
+	draft.Compressed = compressed
 	draft.UpdatedAt = time.Now()
 	draft.ArticleVersion = article.Version
 
-	_, err = d.NewUpdate().
+	_, err = tx.NewUpdate().
 		Model(draft).
-		Column("data", "updated_at", "article_version").
+		Column("data", "compressed", "updated_at", "article_version").
 		WherePK().
 		Exec(ctx)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit()
+}
+
+// TakeOverDraft reassigns ownership of a draft to the given user.
+// Any other draft the new owner already holds for the same article is removed,
+// preserving the one-draft-per-user-per-article invariant.
+func (d *DB) TakeOverDraft(ctx context.Context, draftID int, userID string) (*models.Draft, error) {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(tx bun.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Println(err)
+		}
+	}(tx)
+
+	draft := new(models.Draft)
+
+	err = tx.NewSelect().Model(draft).Where("id = ?", draftID).Scan(ctx)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	if draft.CreatedBy == userID {
+		return draft, nil
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.Draft)(nil)).
+		Where("article_id = ? AND created_by = ? AND id != ?", draft.ArticleId, userID, draft.Id).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.CreatedBy = userID
+	draft.UpdatedAt = time.Now()
+
+	_, err = tx.NewUpdate().
+		Model(draft).
+		Column("created_by", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return draft, nil
 }
 
 // PublishDraft applies the draft patch to the article.
@@ -251,9 +412,14 @@ func (d *DB) PublishDraft(ctx context.Context, draftID int) error {
 		return err
 	}
 
+	patchText, err := decodeData(draft.Data, draft.Compressed)
+	if err != nil {
+		return err
+	}
+
 	dmp := diffmatchpatch.New()
 
-	patches, err := dmp.PatchFromText(draft.Data)
+	patches, err := dmp.PatchFromText(patchText)
 	if err != nil {
 		return fmt.Errorf("invalid patch data: %w", err)
 	}
@@ -266,13 +432,43 @@ func (d *DB) PublishDraft(ctx context.Context, draftID int) error {
 		}
 	}
 
+	meta, body, err := splitFrontMatter(newText)
+	if err != nil {
+		return err
+	}
+
+	columns := []string{"data", "version", "updated_at"}
+
+	if meta != nil {
+		// History records the body only, so the patch is rebuilt without the front-matter.
+		newText = body
+		patchText = dmp.PatchToText(dmp.PatchMake(article.Data, newText))
+		columns = append(columns, meta.applyTo(article)...)
+	}
+
+	author := draft.CreatedBy
+	if draft.IsSuggestion {
+		author = draft.SuggestedBy
+	}
+
 	history := &models.History{
 		ArticleId: article.Id,
 		Version:   article.Version + 1,
-		Data:      draft.Data,
+		Data:      patchText,
+		CreatedBy: author,
 		CreatedAt: draft.UpdatedAt,
 	}
 
+	if d.snapshotInterval > 0 && history.Version%d.snapshotInterval == 0 {
+		history.Data = newText
+		history.Snapshot = true
+	}
+
+	history.Data, history.Compressed, err = d.encodeData(history.Data)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.NewInsert().Model(history).Exec(ctx)
 	if err != nil {
 		return err
@@ -280,8 +476,13 @@ func (d *DB) PublishDraft(ctx context.Context, draftID int) error {
 
 	article.Data = newText
 	article.Version++
+	article.UpdatedAt = time.Now()
 
-	_, err = tx.NewUpdate().Model(article).Column("data", "version").WherePK().Exec(ctx)
+	_, err = tx.NewUpdate().
+		Model(article).
+		Column(columns...).
+		WherePK().
+		Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -298,7 +499,14 @@ func (d *DB) PublishDraft(ctx context.Context, draftID int) error {
 		return err
 	}
 
-	return tx.Commit()
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	d.collapseHistory(ctx, article.Id)
+
+	return nil
 }
 
 // DiscardDraft deletes a draft.
@@ -322,3 +530,26 @@ func (d *DB) DiscardDraft(ctx context.Context, draftID int, userID string) error
 
 	return err
 }
+
+// PruneStaleDrafts removes drafts that have not been updated within the given age.
+// Drafts for articles that have never been published are kept, as they are the
+// only content those articles have.
+func (d *DB) PruneStaleDrafts(ctx context.Context, age time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-age)
+
+	publishedArticles := d.NewSelect().
+		Model((*models.Article)(nil)).
+		Column("id").
+		Where("version > 0")
+
+	res, err := d.NewDelete().
+		Model((*models.Draft)(nil)).
+		Where("updated_at < ?", cutoff).
+		Where("article_id IN (?)", publishedArticles).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
 
 	"wikilite/pkg/models"
 )
@@ -53,7 +55,7 @@ func TestUpdateDraft_Success(t *testing.T) {
 	draft, err := db.CreateDraft(ctx, article.Id, "# First Update", user.Email)
 	require.NoError(t, err)
 
-	err = db.UpdateDraft(ctx, draft.Id, "# Second Update", user.Email)
+	err = db.UpdateDraft(ctx, draft.Id, "# Second Update", user.Email, false, nil)
 	require.NoError(t, err)
 
 	_, content, err := db.GetDraftByID(ctx, draft.Id)
@@ -61,6 +63,28 @@ func TestUpdateDraft_Success(t *testing.T) {
 	assert.Equal(t, "# Second Update", content)
 }
 
+func TestUpdateDraft_Conflict(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, draft, err := db.CreateArticleWithDraft(ctx, "Conflict Article", "test@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, article)
+
+	loaded, _, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	loadedAt := loaded.UpdatedAt
+
+	require.NoError(t, db.UpdateDraft(ctx, draft.Id, "# First", "test@example.com", false, &loadedAt))
+
+	err = db.UpdateDraft(ctx, draft.Id, "# Second", "test@example.com", false, &loadedAt)
+	assert.ErrorIs(t, err, ErrDraftConflict)
+
+	_, content, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "# First", content)
+}
+
 func TestUpdateDraft_Unauthorized(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -87,7 +111,7 @@ func TestUpdateDraft_Unauthorized(t *testing.T) {
 	draft, err := db.CreateDraft(ctx, article.Id, "# First Update", user1.Email)
 	require.NoError(t, err)
 
-	err = db.UpdateDraft(ctx, draft.Id, "# Malicious Update", user2.Email)
+	err = db.UpdateDraft(ctx, draft.Id, "# Malicious Update", user2.Email, false, nil)
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrCannotEditDraft))
 }
@@ -214,6 +238,38 @@ func TestGetDraftByID(t *testing.T) {
 	assert.Equal(t, original.CreatedBy, found.CreatedBy)
 }
 
+func TestGetDraftByID_DegradedReconstruction(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	original := "# Title\n\nSome text that the draft will change.\n"
+	draft, err := db.CreateDraft(ctx, article.Id, original, "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	draft, err = db.CreateDraft(
+		ctx,
+		article.Id,
+		"# Title\n\nSome text that the draft has changed.\n",
+		"test@example.com",
+	)
+	require.NoError(t, err)
+
+	_, err = db.NewUpdate().
+		Model((*models.Article)(nil)).
+		Set("data = ?", "Completely different content.").
+		Where("id = ?", article.Id).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	found, _, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err, "patch conflicts should not hard-error")
+	assert.True(t, found.ReconstructionDegraded)
+}
+
 func TestGetDraftByID_NotFound(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -223,3 +279,123 @@ func TestGetDraftByID_NotFound(t *testing.T) {
 	assert.Nil(t, found)
 	assert.True(t, errors.Is(err, sql.ErrNoRows))
 }
+
+func TestUpdateDraft_AdminCanEditOthers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "editor@example.com")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(ctx, article.Id, "# Editor Content", "editor@example.com")
+	require.NoError(t, err)
+
+	err = db.UpdateDraft(ctx, draft.Id, "# Admin Content", "admin@example.com", true, nil)
+	require.NoError(t, err)
+
+	found, content, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "# Admin Content", content)
+	assert.Equal(t, "editor@example.com", found.CreatedBy)
+}
+
+func TestTakeOverDraft(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "editor@example.com")
+	require.NoError(t, err)
+
+	draft, err := db.CreateDraft(ctx, article.Id, "# Editor Content", "editor@example.com")
+	require.NoError(t, err)
+
+	adminDraft, err := db.CreateDraft(ctx, article.Id, "# Admin Content", "admin@example.com")
+	require.NoError(t, err)
+
+	taken, err := db.TakeOverDraft(ctx, draft.Id, "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "admin@example.com", taken.CreatedBy)
+
+	found, content, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "admin@example.com", found.CreatedBy)
+	assert.Equal(t, "# Editor Content", content)
+
+	_, _, err = db.GetDraftByID(ctx, adminDraft.Id)
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+
+	err = db.UpdateDraft(ctx, draft.Id, "# Continued", "admin@example.com", false, nil)
+	require.NoError(t, err)
+}
+
+func TestTakeOverDraft_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.TakeOverDraft(ctx, 999, "admin@example.com")
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+}
+
+func TestPruneStaleDrafts(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	published, _, err := db.CreateArticleWithDraft(ctx, "Published Article", "test@example.com")
+	require.NoError(t, err)
+
+	first, err := db.CreateDraft(ctx, published.Id, "# Published", "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, first.Id))
+
+	staleDraft, err := db.CreateDraft(ctx, published.Id, "# Stale", "stale@example.com")
+	require.NoError(t, err)
+
+	freshDraft, err := db.CreateDraft(ctx, published.Id, "# Fresh", "fresh@example.com")
+	require.NoError(t, err)
+
+	_, genesisDraft, err := db.CreateArticleWithDraft(ctx, "Unpublished Article", "test@example.com")
+	require.NoError(t, err)
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	_, err = db.NewUpdate().
+		Model((*models.Draft)(nil)).
+		Set("updated_at = ?", old).
+		Where("id IN (?)", bun.In([]int{staleDraft.Id, genesisDraft.Id})).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	count, err := db.PruneStaleDrafts(ctx, 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, _, err = db.GetDraftByID(ctx, staleDraft.Id)
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+
+	_, _, err = db.GetDraftByID(ctx, freshDraft.Id)
+	assert.NoError(t, err)
+
+	_, _, err = db.GetDraftByID(ctx, genesisDraft.Id)
+	assert.NoError(t, err)
+}
+
+func TestCountDraftsByUser(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	first, _, err := db.CreateArticleWithDraft(ctx, "First Article", "user1@example.com")
+	require.NoError(t, err)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Second Article", "user1@example.com")
+	require.NoError(t, err)
+
+	_, err = db.CreateDraft(ctx, first.Id, "# Other", "user2@example.com")
+	require.NoError(t, err)
+
+	count, err := db.CountDraftsByUser(ctx, "user1@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = db.CountDraftsByUser(ctx, "user2@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrNotFound is returned when the record an operation needs does not exist. It matches
+// sql.ErrNoRows as well, so callers checking for either keep working.
+//
+// Lookups by slug or email, where a missing record is an expected outcome, return a nil
+// record and no error instead.
+var ErrNotFound error = &kindError{msg: "record not found", kind: sql.ErrNoRows}
+
+// ErrConflict is returned when a change conflicts with existing data. More specific errors,
+// such as ErrArticleExists and ErrDraftConflict, match it too.
+var ErrConflict = errors.New("conflict with existing data")
+
+// kindError is a sentinel error that also matches the broader kind of error it belongs to.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+// Error returns the message of the error.
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the kind of the error, for errors.Is.
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// notFound converts sql.ErrNoRows from a query to ErrNotFound, and returns other errors as is.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorKinds(t *testing.T) {
+	assert.ErrorIs(t, ErrNotFound, sql.ErrNoRows)
+	assert.ErrorIs(t, ErrArticleExists, ErrConflict)
+	assert.ErrorIs(t, ErrDraftConflict, ErrConflict)
+	assert.NotErrorIs(t, ErrArticleExists, ErrNotFound)
+}
+
+func TestNotFoundErrors(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, draft, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	_, _, err = db.GetDraftByID(ctx, draft.Id+1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = db.GetArticleByID(ctx, article.Id+1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, _, err = db.GetArticleVersion(ctx, article.Id, 5)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = db.TakeOverDraft(ctx, draft.Id+1, "admin@example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = db.RejectSuggestion(ctx, draft.Id+1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"wikilite/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidFrontMatter is returned when a draft's front-matter sets a known key to a value of
+// the wrong type, such as a list as the title.
+var ErrInvalidFrontMatter = errors.New("invalid front-matter")
+
+// frontMatter is the article metadata that can be set from a YAML block at the top of a draft.
+// Fields left out of the block keep their current value; unknown keys are ignored.
+type frontMatter struct {
+	Title *string   `yaml:"title"`
+	Tags  *[]string `yaml:"tags"`
+}
+
+// splitFrontMatter separates a leading front-matter block, delimited by "---" lines, from
+// the markdown body. A "---" line is also a markdown horizontal rule, so the block is only
+// taken as front-matter when it is a YAML mapping with a title or tags key. Any other content
+// is returned unchanged with no metadata.
+func splitFrontMatter(content string) (*frontMatter, string, error) {
+	lines := strings.SplitAfter(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if strings.TrimSuffix(lines[0], "\n") != "---" {
+		return nil, content, nil
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSuffix(lines[i], "\n") != "---" {
+			continue
+		}
+
+		block := []byte(strings.Join(lines[1:i], ""))
+
+		var keys map[string]yaml.Node
+
+		err := yaml.Unmarshal(block, &keys)
+		if err != nil {
+			return nil, content, nil
+		}
+
+		_, hasTitle := keys["title"]
+		_, hasTags := keys["tags"]
+		if !hasTitle && !hasTags {
+			return nil, content, nil
+		}
+
+		meta := &frontMatter{}
+
+		err = yaml.Unmarshal(block, meta)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidFrontMatter, err)
+		}
+
+		return meta, strings.TrimLeft(strings.Join(lines[i+1:], ""), "\n"), nil
+	}
+
+	return nil, content, nil
+}
+
+// applyTo sets the metadata given in the front-matter on an article and returns the columns
+// it changed. A blank title is ignored.
+func (m *frontMatter) applyTo(article *models.Article) []string {
+	var columns []string
+
+	if m.Title != nil && strings.TrimSpace(*m.Title) != "" {
+		article.Title = strings.TrimSpace(*m.Title)
+		columns = append(columns, "title")
+	}
+
+	if m.Tags != nil {
+		article.Tags = normalizeTags(*m.Tags)
+		columns = append(columns, "tags")
+	}
+
+	return columns
+}
+
+// normalizeTags trims tags and drops empty and repeated ones, keeping their order.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
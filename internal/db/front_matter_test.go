@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	meta, body, err := splitFrontMatter("---\ntitle: Install Guide\ntags: [setup, linux]\n---\n\n# Install\n")
+	require.NoError(t, err)
+	require.NotNil(t, meta)
+	assert.Equal(t, "Install Guide", *meta.Title)
+	assert.Equal(t, []string{"setup", "linux"}, *meta.Tags)
+	assert.Equal(t, "# Install\n", body)
+
+	meta, body, err = splitFrontMatter("---\r\ntags:\r\n  - ops\r\n---\r\nBody")
+	require.NoError(t, err)
+	require.NotNil(t, meta)
+	assert.Nil(t, meta.Title)
+	assert.Equal(t, []string{"ops"}, *meta.Tags)
+	assert.Equal(t, "Body", body)
+
+	for _, content := range []string{
+		"# No front-matter\n",
+		"---\nnever closed\n",
+		"Text\n---\nMore\n---\n",
+		"---\n\nIntro after a horizontal rule.\n\n---\n\nMore text.\n",
+		"---\nNote: this is prose, not metadata.\n---\nBody\n",
+		"---\ntitle: [unclosed\n---\nBody",
+	} {
+		meta, body, err = splitFrontMatter(content)
+		require.NoError(t, err)
+		assert.Nil(t, meta, content)
+		assert.Equal(t, content, body)
+	}
+
+	_, _, err = splitFrontMatter("---\ntitle: [Install, Guide]\n---\nBody")
+	assert.ErrorIs(t, err, ErrInvalidFrontMatter)
+}
+
+func TestPublishDraft_FrontMatter(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Draft Title", "test@example.com")
+	require.NoError(t, err)
+
+	content := "---\ntitle: Install Guide\ntags: [setup, ' linux ', setup, '']\nvisibility: public\n---\n# Install\n"
+	draft, err := db.CreateDraft(ctx, article.Id, content, "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	stored, err := db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, "Install Guide", stored.Title)
+	assert.Equal(t, "draft-title", stored.Slug, "the slug is kept so links keep working")
+	assert.Equal(t, []string{"setup", "linux"}, stored.Tags)
+	assert.Equal(t, "# Install\n", stored.Data)
+
+	version, _, err := db.GetArticleVersion(ctx, article.Id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "# Install\n", version, "history records the body without the front-matter")
+
+	draft, err = db.CreateDraft(ctx, article.Id, "---\ntags: []\n---\n# Install\n\nMore.\n", "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	stored, err = db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, "Install Guide", stored.Title, "omitted keys are left unchanged")
+	assert.Empty(t, stored.Tags)
+
+	draft, err = db.CreateDraft(ctx, article.Id, "---\ntitle: [oops]\n---\nBody", "test@example.com")
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.PublishDraft(ctx, draft.Id), ErrInvalidFrontMatter)
+}
+
+func TestPublishDraft_LeadingHorizontalRule(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Ruled Page", "test@example.com")
+	require.NoError(t, err)
+
+	content := "---\n\nAn introduction between rules.\n\n---\n\n# Details\n"
+	draft, err := db.CreateDraft(ctx, article.Id, content, "test@example.com")
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, draft.Id))
+
+	stored, err := db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, "Ruled Page", stored.Title)
+	assert.Equal(t, content, stored.Data, "a leading horizontal rule is kept as markdown")
+}
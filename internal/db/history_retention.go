@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"wikilite/pkg/models"
+
+	"github.com/uptrace/bun"
+)
+
+// SetMaxHistory caps how many versions of each article are kept. When an article has
+// more, the oldest kept version is rewritten as a full-content snapshot and the versions
+// before it are deleted. Zero or less keeps every version.
+func (d *DB) SetMaxHistory(versions int) {
+	d.maxHistory = versions
+}
+
+// collapseHistory enforces the history cap for an article. Failures are logged rather
+// than returned, since the history is still complete and readable without collapsing.
+func (d *DB) collapseHistory(ctx context.Context, articleID int) {
+	if d.maxHistory <= 0 {
+		return
+	}
+
+	err := d.collapseHistoryBefore(ctx, articleID)
+	if err != nil {
+		_ = d.CreateLogEntry(
+			ctx,
+			models.LevelError,
+			"DATABASE",
+			"History Collapse Failed",
+			fmt.Sprintf("Article ID: %d | Error: %v", articleID, err),
+		)
+	}
+}
+
+// collapseHistoryBefore turns the oldest of the newest maxHistory versions into a baseline
+// snapshot and deletes every older version, including the version 0 of seeded articles.
+func (d *DB) collapseHistoryBefore(ctx context.Context, articleID int) error {
+	var versions []int
+
+	err := d.NewSelect().
+		Model((*models.History)(nil)).
+		Column("version").
+		Where("article_id = ?", articleID).
+		Where("version > 0").
+		Order("version DESC").
+		Limit(d.maxHistory+1).
+		Scan(ctx, &versions)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) <= d.maxHistory {
+		return nil
+	}
+
+	baseline := versions[d.maxHistory-1]
+
+	content, degraded, err := d.GetArticleVersion(ctx, articleID, baseline)
+	if err != nil {
+		return err
+	}
+
+	if degraded {
+		// Collapsing would make an approximate reconstruction permanent.
+		return fmt.Errorf("v%d cannot be reconstructed exactly", baseline)
+	}
+
+	data, compressed, err := d.encodeData(content)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx bun.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Println(err)
+		}
+	}(tx)
+
+	_, err = tx.NewUpdate().
+		Model((*models.History)(nil)).
+		Set("data = ?", data).
+		Set("compressed = ?", compressed).
+		Set("snapshot = ?", true).
+		Where("article_id = ?", articleID).
+		Where("version = ?", baseline).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.History)(nil)).
+		Where("article_id = ?", articleID).
+		Where("version < ?", baseline).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
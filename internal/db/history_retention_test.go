@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishDraft_MaxHistory(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		interval int
+		compress bool
+	}{
+		{"patches only", 0, false},
+		{"with snapshots", 4, false},
+		{"compressed", 0, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			db.snapshotInterval = tt.interval
+			db.SetCompression(tt.compress)
+			db.SetMaxHistory(3)
+			ctx := context.Background()
+
+			article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+			require.NoError(t, err)
+
+			contents := publishVersions(t, db, article.Id, 7)
+
+			history, err := db.GetArticleHistory(ctx, article.Id)
+			require.NoError(t, err)
+			require.Len(t, history, 3)
+
+			var baseline models.History
+			err = db.NewSelect().Model(&baseline).
+				Where("article_id = ?", article.Id).
+				Where("version = ?", 5).
+				Scan(ctx)
+			require.NoError(t, err)
+			assert.True(t, baseline.Snapshot, "the oldest kept version becomes the baseline snapshot")
+
+			for version := 5; version <= 7; version++ {
+				content, degraded, err := db.GetArticleVersion(ctx, article.Id, version)
+				require.NoError(t, err)
+				assert.False(t, degraded)
+				assert.Equal(t, contents[version-1], content, "v%d", version)
+			}
+
+			_, _, err = db.GetArticleVersion(ctx, article.Id, 4)
+			assert.ErrorIs(t, err, sql.ErrNoRows, "collapsed versions are gone")
+
+			stored, err := db.GetArticleBySlug(ctx, article.Slug)
+			require.NoError(t, err)
+			assert.Equal(t, contents[6], stored.Data)
+			assert.Equal(t, 7, stored.Version)
+		})
+	}
+}
+
+func TestPublishDraft_UnlimitedHistory(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Test Article", "test@example.com")
+	require.NoError(t, err)
+
+	publishVersions(t, db, article.Id, 5)
+
+	history, err := db.GetArticleHistory(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Len(t, history, 5)
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+	"wikilite/pkg/models"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// statsCacheSize is the maximum number of history entries whose change sizes are kept in memory.
+const statsCacheSize = 5000
+
+// changeStats holds the number of characters added and removed by a single version.
+type changeStats struct {
+	added   int
+	removed int
+}
+
+// newStatsCache creates the cache of per-version change sizes. History entries are
+// immutable, so entries never expire and are only evicted once the cache is full.
+func newStatsCache() *ttlcache.Cache[int, changeStats] {
+	return ttlcache.New[int, changeStats](
+		ttlcache.WithCapacity[int, changeStats](statsCacheSize),
+	)
+}
+
+// fillChangeStats sets Added and Removed on a history entry, which must include its data.
+func (d *DB) fillChangeStats(ctx context.Context, h *models.History) error {
+	item := d.statsCache.Get(h.Id)
+	if item != nil {
+		h.Added, h.Removed = item.Value().added, item.Value().removed
+
+		return nil
+	}
+
+	data, err := decodeData(h.Data, h.Compressed)
+	if err != nil {
+		return err
+	}
+
+	if h.Snapshot {
+		// Snapshots hold the full content, so rebuild the patch from the previous version,
+		// the same way it is made when a draft is created.
+		// A collapsed baseline has no previous version and counts as entirely added.
+		previous, _, err := d.GetArticleVersion(ctx, h.ArticleId, h.Version-1)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		dmp := newDiffer()
+		diffs := dmp.DiffMain(previous, data, false)
+		dmp.DiffCleanupSemantic(diffs)
+		data = dmp.PatchToText(dmp.PatchMake(previous, diffs))
+	}
+
+	stats, err := patchStats(data)
+	if err != nil {
+		return err
+	}
+
+	d.statsCache.Set(h.Id, stats, ttlcache.NoTTL)
+
+	h.Added, h.Removed = stats.added, stats.removed
+
+	return nil
+}
+
+// patchStats sums the inserted and deleted characters of a patch in diffmatchpatch's text
+// format. The diffs of a parsed patch are unexported, so the text is read directly: every
+// diff line starts with '+', '-' or ' ' followed by the URL-encoded text.
+func patchStats(patchText string) (changeStats, error) {
+	var stats changeStats
+
+	for line := range strings.SplitSeq(patchText, "\n") {
+		if line == "" || (line[0] != '+' && line[0] != '-') {
+			continue
+		}
+
+		text, err := url.QueryUnescape(strings.ReplaceAll(line[1:], "+", "%2B"))
+		if err != nil {
+			return changeStats{}, err
+		}
+
+		if line[0] == '+' {
+			stats.added += utf8.RuneCountInString(text)
+		} else {
+			stats.removed += utf8.RuneCountInString(text)
+		}
+	}
+
+	return stats, nil
+}
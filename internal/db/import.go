@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+
+	"github.com/uptrace/bun"
+)
+
+// ImportRevision is one version of an article imported from another wiki.
+type ImportRevision struct {
+	CreatedAt time.Time
+	CreatedBy string
+	Content   string
+}
+
+// ImportArticle creates a published article whose history is the given revisions, oldest
+// first. Each revision becomes a version with its original author and time; revisions that
+// do not change the content are skipped. Existing articles are never overwritten, and links
+// to the new article from existing articles are recorded.
+func (d *DB) ImportArticle(
+	ctx context.Context,
+	title string,
+	revisions []ImportRevision,
+) (*models.Article, error) {
+	if len(revisions) == 0 {
+		return nil, errors.New("no revisions to import")
+	}
+
+	if utils.ToKebabCase(title) == "" {
+		return nil, fmt.Errorf("title %q does not produce a valid slug", title)
+	}
+
+	for _, revision := range revisions {
+		if len(revision.Content) > maxDiffContentSize {
+			return nil, ErrContentTooLarge
+		}
+	}
+
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(tx bun.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Println(err)
+		}
+	}(tx)
+
+	slug, err := d.articleSlug(ctx, tx, title)
+	if err != nil {
+		return nil, err
+	}
+
+	first := revisions[0]
+	article := &models.Article{
+		Title:     title,
+		Slug:      slug,
+		Version:   0,
+		CreatedBy: first.CreatedBy,
+		CreatedAt: first.CreatedAt,
+		UpdatedAt: first.CreatedAt,
+	}
+
+	_, err = tx.NewInsert().Model(article).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dmp := newDiffer()
+
+	for _, revision := range revisions {
+		if revision.Content == article.Data {
+			continue
+		}
+
+		history := &models.History{
+			ArticleId: article.Id,
+			Version:   article.Version + 1,
+			Data:      dmp.PatchToText(dmp.PatchMake(article.Data, revision.Content)),
+			CreatedBy: revision.CreatedBy,
+			CreatedAt: revision.CreatedAt,
+		}
+
+		if d.snapshotInterval > 0 && history.Version%d.snapshotInterval == 0 {
+			history.Data = revision.Content
+			history.Snapshot = true
+		}
+
+		history.Data, history.Compressed, err = d.encodeData(history.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.NewInsert().Model(history).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		article.Data = revision.Content
+		article.Version = history.Version
+		article.UpdatedAt = revision.CreatedAt
+	}
+
+	_, err = tx.NewUpdate().
+		Model(article).
+		Column("data", "version", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.updateArticleLinks(ctx, tx, article.Id, article.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update article links: %w", err)
+	}
+
+	// Pages imported earlier may already link to this one; their links could not be
+	// recorded while it did not exist. Slugs are kebab case, so they need no LIKE escaping.
+	var referrers []*models.Article
+
+	err = tx.NewSelect().
+		Model(&referrers).
+		Column("id", "data").
+		Where("id != ? AND data LIKE ?", article.Id, "%"+slug+"%").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, referrer := range referrers {
+		err = d.updateArticleLinks(ctx, tx, referrer.Id, referrer.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update article links: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	d.articleCache.Delete(article.Slug)
+	d.collapseHistory(ctx, article.Id)
+
+	return article, nil
+}
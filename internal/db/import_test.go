@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportArticle(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	first := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+	revisions := []ImportRevision{
+		{CreatedAt: first, CreatedBy: "Ann", Content: "# Guide\n"},
+		{CreatedAt: first.Add(time.Hour), CreatedBy: "Bob", Content: "# Guide\n"},
+		{CreatedAt: first.Add(2 * time.Hour), CreatedBy: "Bob", Content: "# Guide\n\nSee [FAQ](/wiki/faq).\n"},
+	}
+
+	article, err := db.ImportArticle(ctx, "Install Guide", revisions)
+	require.NoError(t, err)
+	assert.Equal(t, "install-guide", article.Slug)
+	assert.Equal(t, 2, article.Version, "revisions without changes are skipped")
+
+	stored, err := db.GetArticleBySlug(ctx, "install-guide")
+	require.NoError(t, err)
+	assert.Equal(t, revisions[2].Content, stored.Data)
+	assert.Equal(t, "Ann", stored.CreatedBy)
+	assert.True(t, first.Equal(stored.CreatedAt))
+
+	history, err := db.GetArticleHistory(ctx, article.Id)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	for _, entry := range history {
+		content, degraded, err := db.GetArticleVersion(ctx, article.Id, entry.Version)
+		require.NoError(t, err)
+		assert.False(t, degraded)
+
+		switch entry.Version {
+		case 1:
+			assert.Equal(t, revisions[0].Content, content)
+			assert.Equal(t, "Ann", entry.CreatedBy)
+			assert.True(t, first.Equal(entry.CreatedAt))
+		case 2:
+			assert.Equal(t, revisions[2].Content, content)
+			assert.Equal(t, "Bob", entry.CreatedBy)
+		}
+	}
+
+	_, err = db.ImportArticle(ctx, "Install Guide", revisions)
+	assert.ErrorIs(t, err, ErrArticleExists)
+}
+
+func TestImportArticle_LinksToLaterPages(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	guide, err := db.ImportArticle(ctx, "Guide", []ImportRevision{{CreatedAt: now, Content: "See [FAQ](/wiki/faq)."}})
+	require.NoError(t, err)
+
+	faq, err := db.ImportArticle(ctx, "FAQ", []ImportRevision{{CreatedAt: now, Content: "Questions"}})
+	require.NoError(t, err)
+
+	var links []models.Link
+	err = db.NewSelect().Model(&links).Where("parent_article_id = ?", guide.Id).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, faq.Id, links[0].LinkedArticleId)
+}
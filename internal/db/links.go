@@ -15,7 +15,7 @@ func (d *DB) updateArticleLinks(
 	parentArticleID int,
 	content string,
 ) error {
-	foundSlugs := utils.ExtractSlugsFromContent(content)
+	foundSlugs := contentLinkSlugs(content)
 
 	if len(foundSlugs) == 0 {
 		_, err := tx.NewDelete().
@@ -68,6 +68,72 @@ func (d *DB) updateArticleLinks(
 	return nil
 }
 
+// contentLinkSlugs returns the article slugs content refers to, through either
+// markdown links or {{include:slug}} tokens.
+func contentLinkSlugs(content string) []string {
+	targets := utils.ExtractSlugsFromContent(content)
+	targets = append(targets, utils.ExtractIncludesFromContent(content)...)
+
+	return normalizeLinkSlugs(targets)
+}
+
+// normalizeLinkSlugs maps raw link targets to article slugs, dropping any that
+// do not resolve to an internal slug and removing duplicates.
+func normalizeLinkSlugs(rawSlugs []string) []string {
+	seen := make(map[string]struct{}, len(rawSlugs))
+	slugs := make([]string, 0, len(rawSlugs))
+
+	for _, raw := range rawSlugs {
+		slug := utils.NormalizeLinkSlug(raw)
+		if slug == "" {
+			continue
+		}
+
+		if _, ok := seen[slug]; ok {
+			continue
+		}
+
+		seen[slug] = struct{}{}
+		slugs = append(slugs, slug)
+	}
+
+	return slugs
+}
+
+// FindBrokenLinks returns the internal link and include targets in content that do not match
+// an existing article.
+func (d *DB) FindBrokenLinks(ctx context.Context, content string) ([]string, error) {
+	slugs := contentLinkSlugs(content)
+	broken := make([]string, 0, len(slugs))
+
+	if len(slugs) == 0 {
+		return broken, nil
+	}
+
+	var existing []string
+	err := d.NewSelect().
+		Model((*models.Article)(nil)).
+		Column("slug").
+		Where("slug IN (?)", bun.In(slugs)).
+		Scan(ctx, &existing)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]struct{}, len(existing))
+	for _, slug := range existing {
+		found[slug] = struct{}{}
+	}
+
+	for _, slug := range slugs {
+		if _, ok := found[slug]; !ok {
+			broken = append(broken, slug)
+		}
+	}
+
+	return broken, nil
+}
+
 // GetOrphanedArticles returns articles that are NOT linked to by any other article.
 func (d *DB) GetOrphanedArticles(ctx context.Context) ([]*models.Article, error) {
 	var orphans []*models.Article
@@ -90,3 +156,71 @@ func (d *DB) GetOrphanedArticles(ctx context.Context) ([]*models.Article, error)
 
 	return orphans, nil
 }
+
+// GetForwardLinks returns the articles linked to from an article, ordered by title. Only the
+// id, title and slug of each article are loaded.
+func (d *DB) GetForwardLinks(ctx context.Context, articleID int) ([]*models.Article, error) {
+	var linked []*models.Article
+
+	subquery := d.NewSelect().
+		Model((*models.Link)(nil)).
+		Column("linked_article_id").
+		Where("parent_article_id = ?", articleID)
+
+	err := d.NewSelect().
+		Model(&linked).
+		Column("id", "title", "slug").
+		Where("id IN (?)", subquery).
+		Order("title ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return linked, nil
+}
+
+// LinkCounts holds the number of links to and from an article.
+type LinkCounts struct {
+	Inbound  int
+	Outbound int
+}
+
+// GetLinkCounts returns the inbound and outbound link counts of each of the given articles
+// in a single grouped query, keyed by article ID. Articles without any links are omitted.
+func (d *DB) GetLinkCounts(ctx context.Context, articleIDs []int) (map[int]LinkCounts, error) {
+	counts := make(map[int]LinkCounts, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ArticleID int `bun:"article_id"`
+		Inbound   int `bun:"inbound"`
+		Outbound  int `bun:"outbound"`
+	}
+
+	// bun parenthesises the members of a union, which SQLite rejects, so the query is raw.
+	err := d.NewRaw(`
+		SELECT article_id, SUM(inbound) AS inbound, SUM(outbound) AS outbound
+		FROM (
+			SELECT linked_article_id AS article_id, 1 AS inbound, 0 AS outbound
+			FROM links WHERE linked_article_id IN (?)
+			UNION ALL
+			SELECT parent_article_id AS article_id, 0 AS inbound, 1 AS outbound
+			FROM links WHERE parent_article_id IN (?)
+		)
+		GROUP BY article_id`,
+		bun.In(articleIDs),
+		bun.In(articleIDs),
+	).Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ArticleID] = LinkCounts{Inbound: row.Inbound, Outbound: row.Outbound}
+	}
+
+	return counts, nil
+}
@@ -32,6 +32,60 @@ func TestUpdateArticleLinks_Basic(t *testing.T) {
 	assert.Equal(t, article2.Id, links[0].LinkedArticleId)
 }
 
+func TestUpdateArticleLinks_Includes(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article1, _, err := db.CreateArticleWithDraft(ctx, "Article One", "test@example.com")
+	require.NoError(t, err)
+
+	snippet, _, err := db.CreateArticleWithDraft(ctx, "Shared Snippet", "test@example.com")
+	require.NoError(t, err)
+
+	content := "# Test Article\n\n{{include:shared-snippet}}\n\n{{include:missing}}"
+
+	err = db.updateArticleLinks(ctx, db.DB, article1.Id, content)
+	require.NoError(t, err)
+
+	var links []models.Link
+	err = db.NewSelect().Model(&links).Where("parent_article_id = ?", article1.Id).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, snippet.Id, links[0].LinkedArticleId)
+
+	broken, err := db.FindBrokenLinks(ctx, content)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing"}, broken)
+}
+
+func TestUpdateArticleLinks_Normalization(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article1, _, err := db.CreateArticleWithDraft(ctx, "Article One", "test@example.com")
+	require.NoError(t, err)
+
+	page, _, err := db.CreateArticleWithDraft(ctx, "Page", "test@example.com")
+	require.NoError(t, err)
+
+	spaced, _, err := db.CreateArticleWithDraft(ctx, "With Spaces", "test@example.com")
+	require.NoError(t, err)
+
+	content := "[Section](/wiki/page#section) [Again](/wiki/page?x=1) " +
+		"[Spaced](/wiki/with spaces) [Relative](../page) [Anchor](#top)"
+
+	err = db.updateArticleLinks(ctx, db.DB, article1.Id, content)
+	require.NoError(t, err)
+
+	var links []models.Link
+	err = db.NewSelect().Model(&links).Where("parent_article_id = ?", article1.Id).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+
+	linkedIds := []int{links[0].LinkedArticleId, links[1].LinkedArticleId}
+	assert.ElementsMatch(t, []int{page.Id, spaced.Id}, linkedIds)
+}
+
 func TestGetOrphanedArticles_Basic(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -61,3 +115,89 @@ func TestGetOrphanedArticles_Basic(t *testing.T) {
 	assert.True(t, orphanedIds[article3.Id], "Article 3 has no links, should be orphan")
 	assert.True(t, orphanedIds[article1.Id], "Article 1 has no incoming links, should be orphan")
 }
+
+func TestFindBrokenLinks(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, _, err := db.CreateArticleWithDraft(ctx, "Existing Page", "user@example.com")
+	require.NoError(t, err)
+
+	content := "[Existing](/wiki/existing-page) [Missing](/wiki/missing-page) " +
+		"[Again](/wiki/missing-page#section) [External](https://example.com)"
+
+	broken, err := db.FindBrokenLinks(ctx, content)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing-page"}, broken)
+
+	broken, err = db.FindBrokenLinks(ctx, "No links here")
+	require.NoError(t, err)
+	assert.Empty(t, broken)
+}
+
+func TestGetForwardLinks(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	index, _, err := db.CreateArticleWithDraft(ctx, "Index", "test@example.com")
+	require.NoError(t, err)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Beta", "test@example.com")
+	require.NoError(t, err)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Alpha", "test@example.com")
+	require.NoError(t, err)
+
+	content := "[Beta](/wiki/beta) [Alpha](/wiki/alpha) [Missing](/wiki/missing)"
+	err = db.updateArticleLinks(ctx, db.DB, index.Id, content)
+	require.NoError(t, err)
+
+	linked, err := db.GetForwardLinks(ctx, index.Id)
+	require.NoError(t, err)
+	require.Len(t, linked, 2)
+	assert.Equal(t, "Alpha", linked[0].Title)
+	assert.Equal(t, "beta", linked[1].Slug)
+
+	linked, err = db.GetForwardLinks(ctx, linked[0].Id)
+	require.NoError(t, err)
+	assert.Empty(t, linked)
+}
+
+func TestGetLinkCounts(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	hub, _, err := db.CreateArticleWithDraft(ctx, "Hub", "test@example.com")
+	require.NoError(t, err)
+
+	spoke, _, err := db.CreateArticleWithDraft(ctx, "Spoke", "test@example.com")
+	require.NoError(t, err)
+
+	leaf, _, err := db.CreateArticleWithDraft(ctx, "Leaf", "test@example.com")
+	require.NoError(t, err)
+
+	isolated, _, err := db.CreateArticleWithDraft(ctx, "Isolated", "test@example.com")
+	require.NoError(t, err)
+
+	err = db.updateArticleLinks(ctx, db.DB, hub.Id, "[Spoke](/wiki/spoke) [Leaf](/wiki/leaf)")
+	require.NoError(t, err)
+
+	err = db.updateArticleLinks(ctx, db.DB, spoke.Id, "[Hub](/wiki/hub) {{include:leaf}}")
+	require.NoError(t, err)
+
+	counts, err := db.GetLinkCounts(ctx, []int{hub.Id, spoke.Id, leaf.Id, isolated.Id})
+	require.NoError(t, err)
+
+	assert.Equal(t, LinkCounts{Inbound: 1, Outbound: 2}, counts[hub.Id])
+	assert.Equal(t, LinkCounts{Inbound: 1, Outbound: 2}, counts[spoke.Id])
+	assert.Equal(t, LinkCounts{Inbound: 2, Outbound: 0}, counts[leaf.Id])
+	assert.NotContains(t, counts, isolated.Id)
+
+	counts, err = db.GetLinkCounts(ctx, []int{leaf.Id})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]LinkCounts{leaf.Id: {Inbound: 2}}, counts, "only the requested articles are counted")
+
+	counts, err = db.GetLinkCounts(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
@@ -7,6 +7,7 @@ import (
 )
 
 // CreateLogEntry pushes a log entry to the worker pool.
+// Entries created after the database is closed are dropped.
 func (d *DB) CreateLogEntry(
 	ctx context.Context,
 	level models.LogLevel,
@@ -21,12 +22,9 @@ func (d *DB) CreateLogEntry(
 		CreatedAt: time.Now(),
 	}
 
-	select {
-	case d.logChan <- logEntry:
-		return nil
-	default:
-		return nil
-	}
+	d.logs.push(logEntry)
+
+	return nil
 }
 
 // GetLogs fetches logs with optional filtering.
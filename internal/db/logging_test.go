@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestCreateLogEntry_AfterClose(t *testing.T) {
+	db := newTestDB(t)
+
+	require.NoError(t, db.CreateLogEntry(context.Background(), models.LevelInfo, "TEST", "open", ""))
+
+	db.logs.close()
+	db.logWg.Wait()
+
+	assert.NotPanics(t, func() {
+		_ = db.CreateLogEntry(context.Background(), models.LevelInfo, "TEST", "closed", "")
+	})
+}
+
+func TestLogQueue_CountsDroppedEntries(t *testing.T) {
+	// A queue that no worker drains saturates after two entries.
+	db := &DB{logs: newLogQueue(2)}
+
+	for range 5 {
+		db.logs.push(&models.SystemLog{Level: models.LevelInfo, Source: "TEST"})
+	}
+
+	assert.Equal(t, int64(3), db.DroppedLogs())
+
+	<-db.logs.entries
+	db.logs.push(&models.SystemLog{Level: models.LevelInfo, Source: "TEST"})
+
+	assert.Equal(t, int64(3), db.DroppedLogs(), "entries that fit are not counted")
+}
+
+func TestNew_SQLLogging(t *testing.T) {
+	tests := []struct {
+		mode       SQLLogMode
+		wantSQL    int
+		wantErrors int
+	}{
+		{SQLLogAll, 1, 1},
+		{SQLLogErrors, 0, 1},
+		{SQLLogOff, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			dir := t.TempDir()
+
+			db, err := New("file:"+dir+"/wiki.db", "file:"+dir+"/logs.db", WithSQLLogging(tt.mode))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = db.Close()
+			})
+
+			ctx := context.Background()
+			since := time.Now()
+
+			_, err = db.CountArticles(ctx)
+			require.NoError(t, err)
+
+			_, err = db.NewRaw("SELECT * FROM missing_table").Exec(ctx)
+			require.Error(t, err)
+
+			// Flush the queued entries to the log database.
+			db.logs.close()
+			db.logWg.Wait()
+
+			sqlLogs, err := db.CountLogsSince(ctx, since, models.LevelSQL)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, sqlLogs)
+
+			errorLogs, err := db.CountLogsSince(ctx, since, models.LevelSQLError)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantErrors, errorLogs)
+		})
+	}
+}
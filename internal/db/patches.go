@@ -0,0 +1,44 @@
+package db
+
+import (
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const (
+	// fuzzyMatchThreshold loosens how closely a patch context must match (default 0.5).
+	fuzzyMatchThreshold = 0.8
+	// fuzzyMatchDistance widens how far from its expected location a patch may apply.
+	fuzzyMatchDistance = 10000
+	// fuzzyDeleteThreshold loosens how closely deleted text must match (default 0.5).
+	fuzzyDeleteThreshold = 0.8
+)
+
+// applyPatches applies patches to text. If any patch fails, the patches are re-applied
+// to the original text with looser matching and the result is flagged as degraded.
+// It returns the resulting text, whether it is degraded and how many patches failed
+// to apply cleanly.
+func applyPatches(patches []diffmatchpatch.Patch, text string) (string, bool, int) {
+	dmp := diffmatchpatch.New()
+
+	result, applied := dmp.PatchApply(patches, text)
+
+	failed := 0
+	for _, success := range applied {
+		if !success {
+			failed++
+		}
+	}
+
+	if failed == 0 {
+		return result, false, 0
+	}
+
+	fuzzy := diffmatchpatch.New()
+	fuzzy.MatchThreshold = fuzzyMatchThreshold
+	fuzzy.MatchDistance = fuzzyMatchDistance
+	fuzzy.PatchDeleteThreshold = fuzzyDeleteThreshold
+
+	result, _ = fuzzy.PatchApply(patches, text)
+
+	return result, true, failed
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"wikilite/pkg/models"
+)
+
+// minSearchTermLength is the shortest query term matched; shorter terms match too much.
+const minSearchTermLength = 2
+
+// searchTerms splits a query, such as a slug or a title, into its lowercase words.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]struct{}, len(words))
+
+	for _, word := range words {
+		if len(word) < minSearchTermLength {
+			continue
+		}
+
+		if _, ok := seen[word]; ok {
+			continue
+		}
+
+		seen[word] = struct{}{}
+		terms = append(terms, word)
+	}
+
+	return terms
+}
+
+// SearchArticles returns up to limit published articles whose title or slug contains any
+// word of the query, best matches first: articles matching more words rank higher, ties are
+// ordered by title. Only the id, title and slug of each article are loaded.
+func (d *DB) SearchArticles(ctx context.Context, query string, limit int) ([]*models.Article, error) {
+	var articles []*models.Article
+
+	terms := searchTerms(query)
+	if len(terms) == 0 || limit <= 0 {
+		return articles, nil
+	}
+
+	// Terms hold only letters and digits, so they contain no LIKE wildcards.
+	clauses := make([]string, len(terms))
+	args := make([]any, 0, 2*len(terms))
+
+	for i, term := range terms {
+		clauses[i] = "(CASE WHEN slug LIKE ? OR LOWER(title) LIKE ? THEN 1 ELSE 0 END)"
+		pattern := "%" + term + "%"
+		args = append(args, pattern, pattern)
+	}
+
+	score := strings.Join(clauses, " + ")
+
+	err := d.NewSelect().
+		Model(&articles).
+		Column("id", "title", "slug").
+		Where("version > 0").
+		Where("("+score+") > 0", args...).
+		OrderExpr("("+score+") DESC", args...).
+		OrderExpr("title COLLATE NOCASE ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
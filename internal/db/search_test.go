@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchArticles(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	for _, title := range []string{"Quantum Physics", "Physics Basics", "Quantum Computing", "Cooking"} {
+		article, _, err := db.CreateArticleWithDraft(ctx, title, "test@example.com")
+		require.NoError(t, err)
+		publishVersions(t, db, article.Id, 1)
+	}
+
+	_, _, err := db.CreateArticleWithDraft(ctx, "Quantum Draft", "test@example.com")
+	require.NoError(t, err)
+
+	articles, err := db.SearchArticles(ctx, "quantum-physic", 10)
+	require.NoError(t, err)
+
+	titles := make([]string, len(articles))
+	for i, a := range articles {
+		titles[i] = a.Title
+	}
+
+	assert.Equal(t, []string{"Quantum Physics", "Physics Basics", "Quantum Computing"}, titles,
+		"articles matching both words rank first and unpublished articles are skipped")
+
+	articles, err = db.SearchArticles(ctx, "quantum physics", 1)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "quantum-physics", articles[0].Slug)
+
+	articles, err = db.SearchArticles(ctx, "a - %", 10)
+	require.NoError(t, err)
+	assert.Empty(t, articles, "short terms and wildcards do not match everything")
+}
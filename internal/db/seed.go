@@ -8,24 +8,83 @@ import (
 	"log"
 	"time"
 	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/uptrace/bun"
 )
 
+// homeSlug is the slug of the wiki's landing page.
+const homeSlug = "home"
+
+// SeedArticle is an article imported into an empty database during seeding.
+type SeedArticle struct {
+	Title   string
+	Slug    string
+	Content string
+}
+
 // IsSeeded checks if the database has already been populated.
 func (d *DB) IsSeeded(ctx context.Context) (bool, error) {
 	return d.NewSelect().Model((*models.User)(nil)).Exists(ctx)
 }
 
-// Seed initializes the database with a default Admin user and a Home page.
-func (d *DB) Seed(ctx context.Context, adminUser *models.User, homeTitle string) error {
-	seeded, err := d.IsSeeded(ctx)
+// IsEmpty reports whether the database has neither users nor articles.
+func (d *DB) IsEmpty(ctx context.Context) (bool, error) {
+	hasUsers, err := d.NewSelect().Model((*models.User)(nil)).Exists(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if hasUsers {
+		return false, nil
+	}
+
+	hasArticles, err := d.NewSelect().Model((*models.Article)(nil)).Exists(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return !hasArticles, nil
+}
+
+// BootstrapAdmin creates adminUser as the first admin when the database holds no users, and
+// reports whether it did. Existing users are never touched, so it is safe to call on every start.
+func (d *DB) BootstrapAdmin(ctx context.Context, adminUser *models.User) (bool, error) {
+	count, err := d.CountUsers(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	if count > 0 {
+		return false, nil
+	}
+
+	adminUser.Role = models.ADMIN
+
+	err = d.CreateUser(ctx, adminUser)
+	if err != nil {
+		return false, fmt.Errorf("failed to create bootstrap admin: %w", err)
+	}
+
+	return true, nil
+}
+
+// Seed initializes an empty database with an Admin user, the given articles, and a Home page.
+// A default Home page is only created when none of the seed articles uses the "home" slug.
+// Seeding is skipped if the database already holds users or articles.
+func (d *DB) Seed(
+	ctx context.Context,
+	adminUser *models.User,
+	homeTitle string,
+	articles ...SeedArticle,
+) error {
+	empty, err := d.IsEmpty(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check seed status: %w", err)
 	}
 
-	if seeded {
+	if !empty {
 		return nil
 	}
 
@@ -41,6 +100,7 @@ func (d *DB) Seed(ctx context.Context, adminUser *models.User, homeTitle string)
 		}
 	}(tx)
 
+	adminUser.Email = utils.NormalizeEmail(adminUser.Email)
 	adminUser.CreatedAt = time.Now()
 	adminUser.UpdatedAt = time.Now()
 
@@ -53,43 +113,90 @@ func (d *DB) Seed(ctx context.Context, adminUser *models.User, homeTitle string)
 		return fmt.Errorf("failed to seed admin user: %w", err)
 	}
 
-	initialContent := fmt.Sprintf(
-		"# Welcome to your %s\n\nThis is the home page of your new wiki.",
-		homeTitle,
-	)
-
 	adminIDStr := fmt.Sprintf("%d", adminUser.Id)
 
+	hasHome := false
+	for _, a := range articles {
+		if a.Slug == homeSlug {
+			hasHome = true
+			break
+		}
+	}
+
+	if !hasHome {
+		articles = append(articles, SeedArticle{
+			Title: homeTitle,
+			Slug:  homeSlug,
+			Content: fmt.Sprintf(
+				"# Welcome to your %s\n\nThis is the home page of your new wiki.",
+				homeTitle,
+			),
+		})
+	}
+
+	seeded := make([]*models.Article, 0, len(articles))
+	for _, a := range articles {
+		article, err := d.seedArticle(ctx, tx, a, adminIDStr)
+		if err != nil {
+			return err
+		}
+
+		seeded = append(seeded, article)
+	}
+
+	for _, article := range seeded {
+		err = d.updateArticleLinks(ctx, tx, article.Id, article.Data)
+		if err != nil {
+			return fmt.Errorf("failed to seed links for %s: %w", article.Slug, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// seedArticle inserts a version 0 article and its initial history entry.
+func (d *DB) seedArticle(
+	ctx context.Context,
+	tx bun.Tx,
+	seed SeedArticle,
+	createdBy string,
+) (*models.Article, error) {
 	dmp := diffmatchpatch.New()
-	diffs := dmp.DiffMain("", initialContent, false)
+	diffs := dmp.DiffMain("", seed.Content, false)
 	patches := dmp.PatchMake("", diffs)
-	patchText := dmp.PatchToText(patches)
+	patchText, compressed, err := d.encodeData(dmp.PatchToText(patches))
+	if err != nil {
+		return nil, err
+	}
 
 	article := &models.Article{
-		Title:     homeTitle,
-		Slug:      "home",
+		Title:     seed.Title,
+		Slug:      seed.Slug,
 		Version:   0,
-		Data:      initialContent,
-		CreatedBy: adminIDStr,
+		Data:      seed.Content,
+		CreatedBy: createdBy,
 		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
 	_, err = tx.NewInsert().Model(article).Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to seed home article: %w", err)
+		return nil, fmt.Errorf("failed to seed article %s: %w", seed.Slug, err)
 	}
 
 	history := &models.History{
-		ArticleId: article.Id,
-		Version:   0,
-		Data:      patchText,
-		CreatedAt: time.Now(),
+		ArticleId:  article.Id,
+		Version:    0,
+		Data:       patchText,
+		Compressed: compressed,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
 	}
 
 	_, err = tx.NewInsert().Model(history).Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to seed home history: %w", err)
+		return nil, fmt.Errorf("failed to seed history for %s: %w", seed.Slug, err)
 	}
 
-	return tx.Commit()
+	return article, nil
 }
@@ -52,3 +52,115 @@ func TestSeed_Basic(t *testing.T) {
 	assert.Equal(t, 0, article.Version)
 	assert.Contains(t, article.Data, "Welcome to your My Wiki")
 }
+
+func TestIsEmpty(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	empty, err := db.IsEmpty(ctx)
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, "Loose Article", "user@example.com")
+	require.NoError(t, err)
+
+	empty, err = db.IsEmpty(ctx)
+	require.NoError(t, err)
+	assert.False(t, empty, "a database with articles but no users is not empty")
+}
+
+func TestSeed_WithArticles(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	adminUser := &models.User{
+		Name:  "Admin User",
+		Email: "Admin@Example.com",
+		Role:  models.ADMIN,
+	}
+	articles := []SeedArticle{
+		{
+			Title:   "Getting Started",
+			Slug:    "getting-started",
+			Content: "# Getting Started\n\nSee [FAQ](/wiki/faq).",
+		},
+		{Title: "FAQ", Slug: "faq", Content: "# FAQ"},
+	}
+
+	err := db.Seed(ctx, adminUser, "My Wiki", articles...)
+	require.NoError(t, err)
+
+	user, err := db.GetUserByEmail(ctx, "admin@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+
+	for _, slug := range []string{"getting-started", "faq", "home"} {
+		article, err := db.GetArticleBySlug(ctx, slug)
+		require.NoError(t, err)
+		require.NotNil(t, article, slug)
+	}
+
+	orphans, err := db.GetOrphanedArticles(ctx)
+	require.NoError(t, err)
+	for _, orphan := range orphans {
+		assert.NotEqual(t, "faq", orphan.Slug, "links between seeded articles should be recorded")
+	}
+
+	err = db.Seed(ctx, &models.User{Email: "second@example.com"}, "Other")
+	require.NoError(t, err)
+
+	second, err := db.GetUserByEmail(ctx, "second@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, second, "seeding should only run once")
+}
+
+func TestSeed_CustomHome(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	adminUser := &models.User{Email: "admin@example.com", Role: models.ADMIN}
+	articles := []SeedArticle{
+		{Title: "Start Here", Slug: "home", Content: "# Start Here"},
+	}
+
+	err := db.Seed(ctx, adminUser, "My Wiki", articles...)
+	require.NoError(t, err)
+
+	article, err := db.GetArticleBySlug(ctx, "home")
+	require.NoError(t, err)
+	require.NotNil(t, article)
+	assert.Equal(t, "Start Here", article.Title)
+	assert.Equal(t, "# Start Here", article.Data)
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	count, err := db.CountUsers(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	admin := &models.User{Name: "System Admin", Email: "Owner@Example.com", Hash: "hash"}
+	created, err := db.BootstrapAdmin(ctx, admin)
+	require.NoError(t, err)
+	assert.True(t, created, "the admin is created on an empty database")
+
+	stored, err := db.GetUserByEmail(ctx, "owner@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, models.ADMIN, stored.Role)
+
+	second := &models.User{Name: "Another Admin", Email: "second@example.com", Hash: "hash"}
+	created, err = db.BootstrapAdmin(ctx, second)
+	require.NoError(t, err)
+	assert.False(t, created, "the bootstrap is skipped once users exist")
+
+	missing, err := db.GetUserByEmail(ctx, "second@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	count, err = db.CountUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrArticleExists is returned when a new article would have the same title as an existing one.
+var ErrArticleExists error = &kindError{msg: "an article with this title already exists", kind: ErrConflict}
+
+// SetMaxSlugLength sets the maximum length of the slugs of new articles. Longer slugs are
+// truncated at a word boundary. Zero or less does not limit the length.
+func (d *DB) SetMaxSlugLength(length int) {
+	d.maxSlugLength = length
+}
+
+// articleSlug picks the slug of a new article. When the slug is taken by an article with a
+// different title, as happens when long titles are truncated to the same slug, a numeric
+// suffix is appended. ErrArticleExists is returned when an article has the same title.
+func (d *DB) articleSlug(ctx context.Context, tx bun.IDB, title string) (string, error) {
+	fullSlug := utils.ToKebabCase(title)
+	slug := utils.TruncateSlug(fullSlug, d.maxSlugLength)
+	candidate := slug
+
+	for n := 2; ; n++ {
+		var existing models.Article
+
+		err := tx.NewSelect().
+			Model(&existing).
+			Column("title").
+			Where("slug = ?", candidate).
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if utils.ToKebabCase(existing.Title) == fullSlug {
+			return "", ErrArticleExists
+		}
+
+		suffix := "-" + strconv.Itoa(n)
+		candidate = utils.TruncateSlug(slug, d.maxSlugLength-len(suffix)) + suffix
+	}
+}
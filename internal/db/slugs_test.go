@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateArticle_LongTitle(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	prefix := strings.Repeat("Deployment Runbook ", 5)
+
+	first, _, err := db.CreateArticleWithDraft(ctx, prefix+"for Production", "test@example.com")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(first.Slug), DefaultMaxSlugLength)
+	assert.Equal(t, "deployment-runbook-deployment-runbook-deployment-runbook-deployment-runbook", first.Slug)
+
+	second, _, err := db.CreateArticleWithDraft(ctx, prefix+"for Staging", "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, first.Slug+"-2", second.Slug, "a truncated slug collision gets a suffix")
+	assert.LessOrEqual(t, len(second.Slug), DefaultMaxSlugLength)
+
+	third, _, err := db.CreateArticleWithDraft(ctx, prefix+"for Testing", "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, first.Slug+"-3", third.Slug)
+
+	stored, err := db.GetArticleBySlug(ctx, second.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, prefix+"for Staging", stored.Title)
+
+	_, _, err = db.CreateArticleWithDraft(ctx, prefix+"for Staging", "test@example.com")
+	assert.ErrorIs(t, err, ErrArticleExists, "the same title is still rejected")
+}
+
+func TestCreateArticle_UnlimitedSlugLength(t *testing.T) {
+	db := newTestDB(t)
+	db.SetMaxSlugLength(0)
+	ctx := context.Background()
+
+	title := strings.Repeat("Deployment Runbook ", 10)
+
+	article, _, err := db.CreateArticleWithDraft(ctx, title, "test@example.com")
+	require.NoError(t, err)
+	assert.Len(t, article.Slug, len(title)-1)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"time"
+	"wikilite/pkg/models"
+)
+
+// CreateSuggestion records a proposed edit of a published article for review, along with
+// the client address it came from. Unlike CreateDraft, it never replaces other drafts or
+// suggestions of the article.
+func (d *DB) CreateSuggestion(
+	ctx context.Context,
+	articleID int,
+	newContent string,
+	suggestedBy string,
+	suggestedFrom string,
+) (*models.Draft, error) {
+	if len(newContent) > maxDiffContentSize {
+		return nil, ErrContentTooLarge
+	}
+
+	article := new(models.Article)
+
+	err := d.NewSelect().Model(article).Where("id = ?", articleID).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dmp := newDiffer()
+	diffs := dmp.DiffMain(article.Data, newContent, false)
+	dmp.DiffCleanupSemantic(diffs)
+	patches := dmp.PatchMake(article.Data, diffs)
+
+	patchText, compressed, err := d.encodeData(dmp.PatchToText(patches))
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &models.Draft{
+		ArticleId:      article.Id,
+		ArticleVersion: article.Version,
+		Data:           patchText,
+		Compressed:     compressed,
+		IsSuggestion:   true,
+		SuggestedBy:    suggestedBy,
+		SuggestedFrom:  suggestedFrom,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	_, err = d.NewInsert().Model(draft).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// GetSuggestions returns the suggestions awaiting review, oldest first.
+func (d *DB) GetSuggestions(ctx context.Context) ([]*models.Draft, error) {
+	var suggestions []*models.Draft
+
+	err := d.NewSelect().
+		Model(&suggestions).
+		Relation("Article").
+		Where("d.is_suggestion = ?", true).
+		Order("d.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// RejectSuggestion deletes a suggestion. It returns ErrNotFound when there is no
+// suggestion with the given ID.
+func (d *DB) RejectSuggestion(ctx context.Context, draftID int) error {
+	res, err := d.NewDelete().
+		Model((*models.Draft)(nil)).
+		Where("id = ?", draftID).
+		Where("is_suggestion = ?", true).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
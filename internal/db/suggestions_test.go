@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article, _, err := db.CreateArticleWithDraft(ctx, "Guide", "writer@example.com")
+	require.NoError(t, err)
+
+	drafts, err := db.GetDraftsByArticle(ctx, article.Id)
+	require.NoError(t, err)
+	require.NoError(t, db.PublishDraft(ctx, drafts[0].Id))
+
+	draft, err := db.CreateDraft(ctx, article.Id, "Writer edit", "writer@example.com")
+	require.NoError(t, err)
+
+	first, err := db.CreateSuggestion(ctx, article.Id, "First idea", "anonymous", "203.0.113.7")
+	require.NoError(t, err)
+
+	second, err := db.CreateSuggestion(ctx, article.Id, "Second idea", "reader@example.com", "198.51.100.2")
+	require.NoError(t, err)
+
+	suggestions, err := db.GetSuggestions(ctx)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, first.Id, suggestions[0].Id)
+	assert.Equal(t, "Guide", suggestions[0].Article.Title)
+	assert.Equal(t, "203.0.113.7", suggestions[0].SuggestedFrom)
+
+	count, err := db.CountDraftsByUser(ctx, "writer@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "suggestions leave the writer's draft alone")
+
+	_, content, err := db.GetDraftByID(ctx, second.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Second idea", content)
+
+	require.NoError(t, db.PublishDraft(ctx, first.Id))
+
+	history, err := db.GetArticleHistory(ctx, article.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "anonymous", history[0].CreatedBy)
+
+	require.NoError(t, db.RejectSuggestion(ctx, second.Id))
+	assert.ErrorIs(t, db.RejectSuggestion(ctx, second.Id), sql.ErrNoRows)
+	assert.ErrorIs(t, db.RejectSuggestion(ctx, draft.Id), sql.ErrNoRows, "regular drafts are not suggestions")
+
+	suggestions, err = db.GetSuggestions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+	"wikilite/pkg/models"
+
+	"github.com/uptrace/bun"
+)
+
+// tagFilter matches articles whose tags include the tag given as the query argument.
+const tagFilter = "EXISTS (SELECT 1 FROM json_each(a.tags) WHERE json_each.value = ?)"
+
+// ErrTagExists is returned when a tag would be renamed to a tag that articles already use.
+var ErrTagExists error = &kindError{msg: "the tag is already in use", kind: ErrConflict}
+
+// MergeTag replaces the source tag with the target tag on every article, and returns the
+// number of articles changed. Articles that had both tags keep the target tag once.
+func (d *DB) MergeTag(ctx context.Context, source, target string) (int, error) {
+	return d.replaceTag(ctx, source, target, false)
+}
+
+// RenameTag renames a tag on every article, and returns the number of articles changed.
+// ErrTagExists is returned when an article already uses the new name; use MergeTag then.
+func (d *DB) RenameTag(ctx context.Context, from, to string) (int, error) {
+	return d.replaceTag(ctx, from, to, true)
+}
+
+// replaceTag replaces source with target on every article tagged with source in a single
+// transaction. When exclusive is set, the target tag must not be in use yet.
+func (d *DB) replaceTag(ctx context.Context, source, target string, exclusive bool) (int, error) {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func(tx bun.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Println(err)
+		}
+	}(tx)
+
+	if exclusive {
+		exists, err := tx.NewSelect().
+			Model((*models.Article)(nil)).
+			Where(tagFilter, target).
+			Exists(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if exists {
+			return 0, ErrTagExists
+		}
+	}
+
+	var articles []*models.Article
+
+	err = tx.NewSelect().
+		Model(&articles).
+		Column("id", "slug", "version", "tags").
+		Where(tagFilter, source).
+		Scan(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, article := range articles {
+		tags := slices.Clone(article.Tags)
+		for i, tag := range tags {
+			if tag == source {
+				tags[i] = target
+			}
+		}
+
+		article.Tags = normalizeTags(tags)
+
+		_, err = tx.NewUpdate().
+			Model(article).
+			Column("tags").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, article := range articles {
+		d.articleCache.Delete(article.Slug)
+	}
+
+	return len(articles), nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTaggedArticle creates an article with the given tags.
+func createTaggedArticle(t *testing.T, db *DB, title string, tags ...string) *models.Article {
+	t.Helper()
+
+	article, _, err := db.CreateArticleWithDraft(context.Background(), title, "test@example.com")
+	require.NoError(t, err)
+
+	article.Tags = tags
+	_, err = db.NewUpdate().Model(article).Column("tags").WherePK().Exec(context.Background())
+	require.NoError(t, err)
+
+	return article
+}
+
+func TestMergeTag(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	intro := createTaggedArticle(t, db, "Intro", "js", "guide")
+	both := createTaggedArticle(t, db, "Both", "javascript", "js")
+	other := createTaggedArticle(t, db, "Other", "go")
+
+	// Load the article into the cache to check that merging invalidates it.
+	_, err := db.GetArticleBySlug(ctx, intro.Slug)
+	require.NoError(t, err)
+
+	count, err := db.MergeTag(ctx, "js", "javascript")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	for slug, want := range map[string][]string{
+		intro.Slug: {"javascript", "guide"},
+		both.Slug:  {"javascript"},
+		other.Slug: {"go"},
+	} {
+		article, err := db.GetArticleBySlug(ctx, slug)
+		require.NoError(t, err)
+		assert.Equal(t, want, article.Tags, slug)
+	}
+
+	count, err = db.MergeTag(ctx, "js", "javascript")
+	require.NoError(t, err)
+	assert.Zero(t, count, "no article has the source tag anymore")
+}
+
+func TestRenameTag(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	article := createTaggedArticle(t, db, "Intro", "setup", "linux")
+	createTaggedArticle(t, db, "Other", "ops")
+
+	count, err := db.RenameTag(ctx, "setup", "installation")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	stored, err := db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"installation", "linux"}, stored.Tags)
+
+	_, err = db.RenameTag(ctx, "installation", "ops")
+	assert.ErrorIs(t, err, ErrTagExists)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	stored, err = db.GetArticleBySlug(ctx, article.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"installation", "linux"}, stored.Tags, "a rejected rename changes nothing")
+}
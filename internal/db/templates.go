@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wikilite/pkg/models"
+)
+
+// CreateTemplate adds a template to the library.
+func (d *DB) CreateTemplate(ctx context.Context, tmpl *models.Template) error {
+	tmpl.CreatedAt = time.Now()
+	tmpl.UpdatedAt = time.Now()
+
+	_, err := d.NewInsert().Model(tmpl).Exec(ctx)
+
+	return err
+}
+
+// GetTemplates returns every template ordered by name.
+func (d *DB) GetTemplates(ctx context.Context) ([]*models.Template, error) {
+	var templates []*models.Template
+	err := d.NewSelect().
+		Model(&templates).
+		OrderExpr("name COLLATE NOCASE ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetTemplateByID fetches a template by its ID.
+func (d *DB) GetTemplateByID(ctx context.Context, id int) (*models.Template, error) {
+	tmpl := new(models.Template)
+	err := d.NewSelect().
+		Model(tmpl).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// GetTemplateByName fetches a template by its name.
+func (d *DB) GetTemplateByName(ctx context.Context, name string) (*models.Template, error) {
+	tmpl := new(models.Template)
+	err := d.NewSelect().
+		Model(tmpl).
+		Where("name = ?", name).
+		Scan(ctx)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// UpdateTemplate allows updating specific fields of a template.
+func (d *DB) UpdateTemplate(ctx context.Context, tmpl *models.Template, columns ...string) error {
+	tmpl.UpdatedAt = time.Now()
+
+	columns = append(columns, "updated_at")
+
+	_, err := d.NewUpdate().
+		Model(tmpl).
+		Column(columns...).
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteTemplate removes a template from the library.
+func (d *DB) DeleteTemplate(ctx context.Context, id int) error {
+	_, err := d.NewDelete().
+		Model((*models.Template)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestTemplateCRUD(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	howTo := &models.Template{Name: "How-To", Content: "# How to {{title}}"}
+	meeting := &models.Template{Name: "meeting notes", Content: "# {{title}}\n\nDate: {{date}}"}
+	require.NoError(t, db.CreateTemplate(ctx, meeting))
+	require.NoError(t, db.CreateTemplate(ctx, howTo))
+	assert.NotZero(t, howTo.Id)
+
+	templates, err := db.GetTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "How-To", templates[0].Name, "templates are ordered by name")
+
+	found, err := db.GetTemplateByName(ctx, "meeting notes")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, meeting.Id, found.Id)
+
+	found.Content = "# Minutes"
+	require.NoError(t, db.UpdateTemplate(ctx, found, "content"))
+
+	found, err = db.GetTemplateByID(ctx, meeting.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "# Minutes", found.Content)
+
+	require.NoError(t, db.DeleteTemplate(ctx, meeting.Id))
+
+	missing, err := db.GetTemplateByID(ctx, meeting.Id)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestCreateArticleWithContent(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	db.SetArticleTemplate("ignored when content is given")
+
+	_, draft, err := db.CreateArticleWithContent(
+		ctx,
+		"Weekly Sync",
+		"# Weekly Sync\n\n## Agenda\n",
+		"test@example.com",
+	)
+	require.NoError(t, err)
+
+	_, content, err := db.GetDraftByID(ctx, draft.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "# Weekly Sync\n\n## Agenda\n", content)
+}
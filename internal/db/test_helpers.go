@@ -5,17 +5,17 @@ import (
 	"database/sql"
 	"testing"
 
-	"github.com/jellydator/ttlcache/v3"
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"github.com/uptrace/bun/driver/sqliteshim"
 
+	"wikilite/internal/cache"
 	"wikilite/pkg/models"
 )
 
 // newTestDB creates a fresh in-memory database for testing
-func newTestDB(t *testing.T) *DB {
+func newTestDB(t testing.TB) *DB {
 	sqldb, err := sql.Open(sqliteshim.ShimName, ":memory:")
 	require.NoError(t, err)
 	require.NoError(t, sqldb.Ping())
@@ -30,6 +30,10 @@ func newTestDB(t *testing.T) *DB {
 		(*models.SystemLog)(nil),
 		(*models.Link)(nil),
 		(*models.BackupCode)(nil),
+		(*models.Webhook)(nil),
+		(*models.Template)(nil),
+		(*models.Watch)(nil),
+		(*models.ApiToken)(nil),
 	}
 
 	for _, model := range modelsToCreate {
@@ -37,24 +41,26 @@ func newTestDB(t *testing.T) *DB {
 		require.NoError(t, err)
 	}
 
-	cache := ttlcache.New[string, *models.Article](
-		ttlcache.WithTTL[string, *models.Article](cacheTtl),
-		ttlcache.WithCapacity[string, *models.Article](cacheSize),
-	)
-	go cache.Start()
+	articleCache := cache.New[string, *models.Article](cache.Default)
+	go articleCache.Start()
 
-	logChan := make(chan *models.SystemLog, 100)
+	logs := newLogQueue(100)
 
-	bunDB.WithQueryHook(&dbLogger{logChan: logChan})
+	bunDB.WithQueryHook(&dbLogger{logs: logs})
 
 	db := &DB{
-		DB:           bunDB,
-		logDB:        bunDB,
-		articleCache: cache,
-		logChan:      logChan,
+		DB:               bunDB,
+		logDB:            bunDB,
+		articleCache:     articleCache,
+		statsCache:       newStatsCache(),
+		logs:             logs,
+		views:            newViewCounter(),
+		snapshotInterval: DefaultSnapshotInterval,
+		maxSlugLength:    DefaultMaxSlugLength,
 	}
 
 	db.startLogWorkers(1)
+	db.startViewFlusher()
 
 	t.Cleanup(func() {
 		_ = db.Close()
@@ -5,15 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"slices"
 	"strconv"
 	"time"
 	"wikilite/pkg/models"
+	"wikilite/pkg/utils"
 
 	"github.com/uptrace/bun"
 )
 
 // CreateUser registers a new user.
 func (d *DB) CreateUser(ctx context.Context, user *models.User) error {
+	user.Email = utils.NormalizeEmail(user.Email)
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
@@ -50,7 +53,7 @@ func (d *DB) GetUserByEmail(ctx context.Context, email string) (*models.User, er
 	user := new(models.User)
 	err := d.NewSelect().
 		Model(user).
-		Where("email = ?", email).
+		Where("lower(email) = ?", utils.NormalizeEmail(email)).
 		Scan(ctx)
 
 	if err != nil {
@@ -64,10 +67,39 @@ func (d *DB) GetUserByEmail(ctx context.Context, email string) (*models.User, er
 	return user, nil
 }
 
-// UpdateUser allows updating specific fields of a user.
+// GetUsers returns a page of users ordered by email and the total number of users.
+func (d *DB) GetUsers(ctx context.Context, limit, offset int) ([]*models.User, int64, error) {
+	var users []*models.User
+	count, err := d.NewSelect().
+		Model(&users).
+		Order("email ASC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, int64(count), nil
+}
+
+// CountUsers returns the number of users.
+func (d *DB) CountUsers(ctx context.Context) (int, error) {
+	return d.NewSelect().Model((*models.User)(nil)).Count(ctx)
+}
+
+// UpdateUser allows updating specific fields of a user. Changing the hash or disabling the
+// user also bumps the token version, so older sessions stay revoked even after re-enabling.
 func (d *DB) UpdateUser(ctx context.Context, user *models.User, columns ...string) error {
 	user.UpdatedAt = time.Now()
 
+	disabling := user.Disabled && slices.Contains(columns, "disabled")
+	if disabling || slices.Contains(columns, "hash") {
+		user.TokenVersion++
+		columns = append(columns, "token_version")
+	}
+
 	columns = append(columns, "updated_at")
 
 	_, err := d.NewUpdate().
@@ -79,6 +111,21 @@ func (d *DB) UpdateUser(ctx context.Context, user *models.User, columns ...strin
 	return err
 }
 
+// RevokeUserTokens bumps the user's token version, invalidating every token issued so far.
+func (d *DB) RevokeUserTokens(ctx context.Context, user *models.User) error {
+	user.UpdatedAt = time.Now()
+
+	_, err := d.NewUpdate().
+		Model(user).
+		Set("token_version = token_version + 1").
+		Set("updated_at = ?", user.UpdatedAt).
+		WherePK().
+		Returning("token_version").
+		Exec(ctx, &user.TokenVersion)
+
+	return err
+}
+
 // DeleteUser performs a "Safe Delete".
 func (d *DB) DeleteUser(ctx context.Context, id int) error {
 	tx, err := d.BeginTx(ctx, nil)
@@ -103,6 +150,22 @@ func (d *DB) DeleteUser(ctx context.Context, id int) error {
 		return err
 	}
 
+	_, err = tx.NewDelete().
+		Model((*models.Watch)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.ApiToken)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.NewDelete().
 		Model((*models.User)(nil)).
 		Where("id = ?", id).
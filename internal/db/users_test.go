@@ -77,6 +77,59 @@ func TestGetUserByEmail_NotFound(t *testing.T) {
 	assert.Nil(t, found)
 }
 
+func TestGetUsers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	for _, email := range []string{"carol@example.com", "alice@example.com", "bob@example.com"} {
+		require.NoError(t, db.CreateUser(ctx, &models.User{Name: "User", Email: email, Role: models.READ}))
+	}
+
+	users, total, err := db.GetUsers(ctx, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice@example.com", users[0].Email)
+	assert.Equal(t, "bob@example.com", users[1].Email)
+
+	users, total, err = db.GetUsers(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "carol@example.com", users[0].Email)
+}
+
+func TestTokenVersion(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	user := &models.User{Name: "Test User", Email: "test@example.com", Role: models.WRITE}
+	require.NoError(t, db.CreateUser(ctx, user))
+
+	user.Name = "Renamed"
+	require.NoError(t, db.UpdateUser(ctx, user, "name"))
+	assert.Equal(t, 0, user.TokenVersion)
+
+	user.Hash = "new-hash"
+	require.NoError(t, db.UpdateUser(ctx, user, "hash"))
+	assert.Equal(t, 1, user.TokenVersion)
+
+	require.NoError(t, db.RevokeUserTokens(ctx, user))
+	assert.Equal(t, 2, user.TokenVersion)
+
+	user.Disabled = true
+	require.NoError(t, db.UpdateUser(ctx, user, "disabled"))
+	assert.Equal(t, 3, user.TokenVersion)
+
+	user.Disabled = false
+	require.NoError(t, db.UpdateUser(ctx, user, "disabled"))
+	assert.Equal(t, 3, user.TokenVersion)
+
+	stored, err := db.GetUserByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.TokenVersion)
+}
+
 func TestTypedErrors(t *testing.T) {
 	assert.NotNil(t, ErrCannotEditDraft)
 	assert.NotNil(t, ErrCannotDiscardDraft)
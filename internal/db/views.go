@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"wikilite/pkg/models"
+)
+
+// viewFlushInterval is how often the buffered article views are written to the database.
+const viewFlushInterval = 30 * time.Second
+
+// viewCounter buffers article views in memory, so that reading an article does not write
+// to the database.
+type viewCounter struct {
+	mu      sync.Mutex
+	pending map[int]int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newViewCounter() *viewCounter {
+	return &viewCounter{
+		pending: make(map[int]int),
+		stop:    make(chan struct{}),
+	}
+}
+
+// RecordView counts a view of an article. It is written to the database by the next flush.
+func (d *DB) RecordView(articleID int) {
+	d.views.mu.Lock()
+	d.views.pending[articleID]++
+	d.views.mu.Unlock()
+}
+
+// FlushViews writes the buffered article views to the database in one transaction. Views
+// that could not be written are kept for the next flush.
+func (d *DB) FlushViews(ctx context.Context) error {
+	d.views.mu.Lock()
+	pending := d.views.pending
+	d.views.pending = make(map[int]int)
+	d.views.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := d.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for articleID, count := range pending {
+			_, err := tx.NewUpdate().
+				Model((*models.Article)(nil)).
+				Set("view_count = view_count + ?", count).
+				Where("id = ?", articleID).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		d.views.mu.Lock()
+		for articleID, count := range pending {
+			d.views.pending[articleID] += count
+		}
+		d.views.mu.Unlock()
+	}
+
+	return err
+}
+
+// startViewFlusher spins up a background goroutine flushing the buffered views every
+// viewFlushInterval until the database is closed.
+func (d *DB) startViewFlusher() {
+	d.views.wg.Go(func() {
+		ticker := time.NewTicker(viewFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = d.FlushViews(context.Background())
+			case <-d.views.stop:
+				return
+			}
+		}
+	})
+}
+
+// stopViewFlusher stops the background flusher and writes the views still buffered.
+func (d *DB) stopViewFlusher() {
+	close(d.views.stop)
+	d.views.wg.Wait()
+
+	_ = d.FlushViews(context.Background())
+}
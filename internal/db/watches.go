@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"wikilite/pkg/models"
+)
+
+// WatchArticle subscribes a user to changes of an article. Watching an article twice is a no-op.
+func (d *DB) WatchArticle(ctx context.Context, userID, articleID int) error {
+	watch := &models.Watch{UserId: userID, ArticleId: articleID}
+
+	_, err := d.NewInsert().
+		Model(watch).
+		On("CONFLICT DO NOTHING").
+		Exec(ctx)
+
+	return err
+}
+
+// UnwatchArticle removes a user's subscription to an article, if any.
+func (d *DB) UnwatchArticle(ctx context.Context, userID, articleID int) error {
+	_, err := d.NewDelete().
+		Model((*models.Watch)(nil)).
+		Where("user_id = ?", userID).
+		Where("article_id = ?", articleID).
+		Exec(ctx)
+
+	return err
+}
+
+// IsWatching reports whether a user is subscribed to an article.
+func (d *DB) IsWatching(ctx context.Context, userID, articleID int) (bool, error) {
+	return d.NewSelect().
+		Model((*models.Watch)(nil)).
+		Where("user_id = ?", userID).
+		Where("article_id = ?", articleID).
+		Exists(ctx)
+}
+
+// GetWatchedArticles returns the articles a user is subscribed to, ordered by title.
+func (d *DB) GetWatchedArticles(ctx context.Context, userID int) ([]*models.Article, error) {
+	var articles []*models.Article
+
+	subquery := d.NewSelect().
+		Model((*models.Watch)(nil)).
+		Column("article_id").
+		Where("user_id = ?", userID)
+
+	err := d.NewSelect().
+		Model(&articles).
+		Where("id IN (?)", subquery).
+		Order("title ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleWatchers returns the active users subscribed to an article.
+func (d *DB) GetArticleWatchers(ctx context.Context, articleID int) ([]*models.User, error) {
+	var users []*models.User
+
+	subquery := d.NewSelect().
+		Model((*models.Watch)(nil)).
+		Column("user_id").
+		Where("article_id = ?", articleID)
+
+	err := d.NewSelect().
+		Model(&users).
+		Where("id IN (?)", subquery).
+		Where("disabled = ?", false).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestWatches(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	watcher := &models.User{Name: "Watcher", Email: "watcher@example.com", Role: models.READ}
+	require.NoError(t, db.CreateUser(ctx, watcher))
+
+	disabled := &models.User{
+		Name:     "Disabled",
+		Email:    "disabled@example.com",
+		Role:     models.READ,
+		Disabled: true,
+	}
+	require.NoError(t, db.CreateUser(ctx, disabled))
+
+	zebra, _, err := db.CreateArticleWithDraft(ctx, "Zebra", "author@example.com")
+	require.NoError(t, err)
+
+	apple, _, err := db.CreateArticleWithDraft(ctx, "Apple", "author@example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, db.WatchArticle(ctx, watcher.Id, zebra.Id))
+	require.NoError(t, db.WatchArticle(ctx, watcher.Id, zebra.Id), "watching twice is a no-op")
+	require.NoError(t, db.WatchArticle(ctx, watcher.Id, apple.Id))
+	require.NoError(t, db.WatchArticle(ctx, disabled.Id, zebra.Id))
+
+	watching, err := db.IsWatching(ctx, watcher.Id, zebra.Id)
+	require.NoError(t, err)
+	assert.True(t, watching)
+
+	articles, err := db.GetWatchedArticles(ctx, watcher.Id)
+	require.NoError(t, err)
+	require.Len(t, articles, 2)
+	assert.Equal(t, "Apple", articles[0].Title)
+	assert.Equal(t, "Zebra", articles[1].Title)
+
+	watchers, err := db.GetArticleWatchers(ctx, zebra.Id)
+	require.NoError(t, err)
+	require.Len(t, watchers, 1, "disabled users are not notified")
+	assert.Equal(t, watcher.Email, watchers[0].Email)
+
+	require.NoError(t, db.UnwatchArticle(ctx, watcher.Id, zebra.Id))
+
+	watching, err = db.IsWatching(ctx, watcher.Id, zebra.Id)
+	require.NoError(t, err)
+	assert.False(t, watching)
+
+	require.NoError(t, db.DeleteArticle(ctx, apple.Id))
+
+	articles, err = db.GetWatchedArticles(ctx, watcher.Id)
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+
+	count, err := db.NewSelect().Model((*models.Watch)(nil)).Where("article_id = ?", apple.Id).Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count, "deleting an article removes its watches")
+}
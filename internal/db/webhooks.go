@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wikilite/pkg/models"
+)
+
+// CreateWebhook registers a new outbound webhook.
+func (d *DB) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	_, err := d.NewInsert().Model(webhook).Exec(ctx)
+
+	return err
+}
+
+// GetWebhooks returns all configured webhooks.
+func (d *DB) GetWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := d.NewSelect().
+		Model(&webhooks).
+		Order("id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhookByID fetches a webhook by its ID.
+func (d *DB) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	webhook := new(models.Webhook)
+	err := d.NewSelect().
+		Model(webhook).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetActiveWebhooksForEvent returns the active webhooks subscribed to an event.
+func (d *DB) GetActiveWebhooksForEvent(
+	ctx context.Context,
+	event models.WebhookEvent,
+) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := d.NewSelect().
+		Model(&webhooks).
+		Where("active = ?", true).
+		Order("id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed := make([]*models.Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		if w.Subscribes(event) {
+			subscribed = append(subscribed, w)
+		}
+	}
+
+	return subscribed, nil
+}
+
+// UpdateWebhook allows updating specific fields of a webhook.
+func (d *DB) UpdateWebhook(ctx context.Context, webhook *models.Webhook, columns ...string) error {
+	webhook.UpdatedAt = time.Now()
+
+	columns = append(columns, "updated_at")
+
+	_, err := d.NewUpdate().
+		Model(webhook).
+		Column(columns...).
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteWebhook removes a webhook.
+func (d *DB) DeleteWebhook(ctx context.Context, id int) error {
+	_, err := d.NewDelete().
+		Model((*models.Webhook)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wikilite/pkg/models"
+)
+
+func TestCreateAndGetWebhook(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	webhook := &models.Webhook{
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+		Events: []models.WebhookEvent{models.EventArticlePublished},
+		Active: true,
+	}
+	err := db.CreateWebhook(ctx, webhook)
+	require.NoError(t, err)
+	assert.NotZero(t, webhook.Id)
+
+	found, err := db.GetWebhookByID(ctx, webhook.Id)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, webhook.URL, found.URL)
+	assert.Equal(t, "secret", found.Secret)
+	assert.Equal(t, []models.WebhookEvent{models.EventArticlePublished}, found.Events)
+
+	missing, err := db.GetWebhookByID(ctx, 999)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestGetActiveWebhooksForEvent(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	all := &models.Webhook{URL: "https://example.com/all", Active: true}
+	publishOnly := &models.Webhook{
+		URL:    "https://example.com/publish",
+		Events: []models.WebhookEvent{models.EventArticlePublished},
+		Active: true,
+	}
+	inactive := &models.Webhook{URL: "https://example.com/off", Active: false}
+
+	require.NoError(t, db.CreateWebhook(ctx, all))
+	require.NoError(t, db.CreateWebhook(ctx, publishOnly))
+	require.NoError(t, db.CreateWebhook(ctx, inactive))
+
+	webhooks, err := db.GetActiveWebhooksForEvent(ctx, models.EventArticlePublished)
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 2)
+
+	webhooks, err = db.GetActiveWebhooksForEvent(ctx, models.EventUserDeleted)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, all.Id, webhooks[0].Id)
+}
+
+func TestUpdateAndDeleteWebhook(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	webhook := &models.Webhook{URL: "https://example.com/hook", Active: true}
+	require.NoError(t, db.CreateWebhook(ctx, webhook))
+
+	webhook.Active = false
+	require.NoError(t, db.UpdateWebhook(ctx, webhook, "active"))
+
+	found, err := db.GetWebhookByID(ctx, webhook.Id)
+	require.NoError(t, err)
+	assert.False(t, found.Active)
+
+	require.NoError(t, db.DeleteWebhook(ctx, webhook.Id))
+
+	found, err = db.GetWebhookByID(ctx, webhook.Id)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
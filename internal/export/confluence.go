@@ -0,0 +1,309 @@
+package export
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"wikilite/pkg/utils"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// xmlEscaper escapes text and attribute values for XHTML output.
+var xmlEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `>`, "&gt;", `"`, "&quot;")
+
+// markdownParser parses the github flavored markdown the wiki renders.
+var markdownParser = goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+
+// Confluence converts markdown to Confluence storage format, the XHTML dialect Confluence
+// stores pages in. Links to wiki articles whose slug is a key of titles become links to the
+// Confluence page with that title; other links are kept as they are. Fenced code blocks become
+// code macros, task lists become Confluence tasks and HTML blocks are wrapped in an html macro.
+// Inline HTML tags are dropped, keeping the text between them, so the result stays valid XHTML.
+func Confluence(content string, titles map[string]string) []byte {
+	c := &confluenceWriter{source: []byte(content), titles: titles}
+	doc := markdownParser.Parse(text.NewReader(c.source))
+
+	_ = ast.Walk(doc, c.walk)
+
+	return c.buf.Bytes()
+}
+
+// confluenceWriter accumulates the storage format of a markdown document.
+type confluenceWriter struct {
+	buf    bytes.Buffer
+	source []byte
+	titles map[string]string
+}
+
+// walk writes the opening markup of a node on entering it and the closing markup on leaving it.
+func (c *confluenceWriter) walk(n ast.Node, entering bool) (ast.WalkStatus, error) {
+	switch node := n.(type) {
+	case *ast.Heading:
+		c.tag("h"+strconv.Itoa(node.Level), entering, true)
+	case *ast.Paragraph:
+		c.tag("p", entering, true)
+	case *ast.Blockquote:
+		c.tag("blockquote", entering, true)
+	case *ast.ThematicBreak:
+		if entering {
+			c.buf.WriteString("<hr />\n")
+		}
+	case *ast.List:
+		c.list(node, entering)
+	case *ast.ListItem:
+		c.listItem(node, entering)
+	case *east.TaskCheckBox:
+		// The status is written by the enclosing task.
+	case *ast.FencedCodeBlock:
+		if entering {
+			c.plainTextMacro("code", string(node.Language(c.source)), c.lines(node))
+		}
+
+		return ast.WalkSkipChildren, nil
+	case *ast.CodeBlock:
+		if entering {
+			c.plainTextMacro("code", "", c.lines(node))
+		}
+
+		return ast.WalkSkipChildren, nil
+	case *ast.HTMLBlock:
+		if entering {
+			c.plainTextMacro("html", "", c.lines(node))
+		}
+
+		return ast.WalkSkipChildren, nil
+	case *ast.Text:
+		if entering {
+			c.escape(node.Segment.Value(c.source))
+
+			if node.HardLineBreak() {
+				c.buf.WriteString("<br />")
+			} else if node.SoftLineBreak() {
+				c.buf.WriteByte('\n')
+			}
+		}
+	case *ast.String:
+		if entering {
+			c.escape(node.Value)
+		}
+	case *ast.CodeSpan:
+		c.tag("code", entering, false)
+	case *ast.Emphasis:
+		if node.Level == 2 {
+			c.tag("strong", entering, false)
+		} else {
+			c.tag("em", entering, false)
+		}
+	case *east.Strikethrough:
+		c.tag("s", entering, false)
+	case *ast.Link:
+		c.link(node, entering)
+	case *ast.AutoLink:
+		if entering {
+			url := string(node.URL(c.source))
+			if node.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(url, "mailto:") {
+				url = "mailto:" + url
+			}
+
+			c.buf.WriteString(`<a href="` + xmlEscaper.Replace(url) + `">`)
+			c.escape(node.Label(c.source))
+			c.buf.WriteString("</a>")
+		}
+	case *ast.Image:
+		if entering {
+			c.buf.WriteString(`<ac:image ac:alt="` + xmlEscaper.Replace(c.plainText(node)) + `">`)
+			c.buf.WriteString(`<ri:url ri:value="` + xmlEscaper.Replace(string(node.Destination)) + `" />`)
+			c.buf.WriteString("</ac:image>")
+		}
+
+		return ast.WalkSkipChildren, nil
+	case *ast.RawHTML:
+		return ast.WalkSkipChildren, nil
+	case *east.Table:
+		if entering {
+			c.buf.WriteString("<table><tbody>\n")
+		} else {
+			c.buf.WriteString("</tbody></table>\n")
+		}
+	case *east.TableHeader, *east.TableRow:
+		c.tag("tr", entering, true)
+	case *east.TableCell:
+		cell := "td"
+		if _, ok := node.Parent().(*east.TableHeader); ok {
+			cell = "th"
+		}
+
+		c.tag(cell, entering, false)
+	}
+
+	return ast.WalkContinue, nil
+}
+
+// tag writes the opening or closing tag of an element. Block elements end with a newline.
+func (c *confluenceWriter) tag(name string, entering, block bool) {
+	if entering {
+		c.buf.WriteString("<" + name + ">")
+
+		return
+	}
+
+	c.buf.WriteString("</" + name + ">")
+	if block {
+		c.buf.WriteByte('\n')
+	}
+}
+
+// list writes a bullet, numbered or task list.
+func (c *confluenceWriter) list(node *ast.List, entering bool) {
+	switch {
+	case isTaskList(node):
+		c.tag("ac:task-list", entering, true)
+	case !node.IsOrdered():
+		c.tag("ul", entering, true)
+	case entering && node.Start > 1:
+		c.buf.WriteString(`<ol start="` + strconv.Itoa(node.Start) + `">`)
+	default:
+		c.tag("ol", entering, true)
+	}
+}
+
+// listItem writes a list item, or a task with its status when the list is a task list.
+func (c *confluenceWriter) listItem(node *ast.ListItem, entering bool) {
+	list, ok := node.Parent().(*ast.List)
+	if !ok || !isTaskList(list) {
+		c.tag("li", entering, true)
+
+		return
+	}
+
+	if !entering {
+		c.buf.WriteString("</ac:task-body></ac:task>\n")
+
+		return
+	}
+
+	status := "incomplete"
+	if taskCheckBox(node).IsChecked {
+		status = "complete"
+	}
+
+	c.buf.WriteString("<ac:task><ac:task-status>" + status + "</ac:task-status><ac:task-body>")
+}
+
+// link writes a link to the Confluence page of a known wiki article, or a plain anchor otherwise.
+func (c *confluenceWriter) link(node *ast.Link, entering bool) {
+	destination := string(node.Destination)
+	title, ok := c.titles[utils.NormalizeLinkSlug(destination)]
+
+	if !ok {
+		if !entering {
+			c.buf.WriteString("</a>")
+
+			return
+		}
+
+		c.buf.WriteString(`<a href="` + xmlEscaper.Replace(destination) + `"`)
+		if len(node.Title) > 0 {
+			c.buf.WriteString(` title="` + xmlEscaper.Replace(string(node.Title)) + `"`)
+		}
+
+		c.buf.WriteString(">")
+
+		return
+	}
+
+	if !entering {
+		c.buf.WriteString("</ac:link-body></ac:link>")
+
+		return
+	}
+
+	c.buf.WriteString("<ac:link")
+	if _, anchor, found := strings.Cut(destination, "#"); found && anchor != "" {
+		c.buf.WriteString(` ac:anchor="` + xmlEscaper.Replace(anchor) + `"`)
+	}
+
+	c.buf.WriteString(`><ri:page ri:content-title="` + xmlEscaper.Replace(title) + `" /><ac:link-body>`)
+}
+
+// plainTextMacro writes a macro whose body is the literal text body, such as a code block
+// with an optional language.
+func (c *confluenceWriter) plainTextMacro(name, language, body string) {
+	c.buf.WriteString(`<ac:structured-macro ac:name="` + name + `">`)
+
+	if language != "" {
+		c.buf.WriteString(`<ac:parameter ac:name="language">` + xmlEscaper.Replace(language) + "</ac:parameter>")
+	}
+
+	c.buf.WriteString("<ac:plain-text-body><![CDATA[")
+	c.buf.WriteString(strings.ReplaceAll(strings.TrimSuffix(body, "\n"), "]]>", "]]]]><![CDATA[>"))
+	c.buf.WriteString("]]></ac:plain-text-body></ac:structured-macro>\n")
+}
+
+// lines returns the source lines of a block node.
+func (c *confluenceWriter) lines(node ast.Node) string {
+	var b strings.Builder
+
+	lines := node.Lines()
+	for i := range lines.Len() {
+		line := lines.At(i)
+		b.Write(line.Value(c.source))
+	}
+
+	return b.String()
+}
+
+// plainText returns the text of the inline children of node, without formatting.
+func (c *confluenceWriter) plainText(node ast.Node) string {
+	var b strings.Builder
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch child := n.(type) {
+		case *ast.Text:
+			b.Write(child.Segment.Value(c.source))
+		case *ast.String:
+			b.Write(child.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return b.String()
+}
+
+// escape writes text with the XML special characters escaped.
+func (c *confluenceWriter) escape(value []byte) {
+	c.buf.WriteString(xmlEscaper.Replace(string(value)))
+}
+
+// isTaskList reports whether every item of a list starts with a task checkbox.
+func isTaskList(list *ast.List) bool {
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		if taskCheckBox(item) == nil {
+			return false
+		}
+	}
+
+	return list.HasChildren()
+}
+
+// taskCheckBox returns the checkbox a GFM task list item starts with, or nil.
+func taskCheckBox(item ast.Node) *east.TaskCheckBox {
+	block := item.FirstChild()
+	if block == nil {
+		return nil
+	}
+
+	checkBox, _ := block.FirstChild().(*east.TaskCheckBox)
+
+	return checkBox
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wellFormed reports whether the storage format parses as XML once wrapped in a root element.
+func wellFormed(t *testing.T, storage []byte) {
+	t.Helper()
+
+	doc := `<root xmlns:ac="ac" xmlns:ri="ri">` + string(storage) + `</root>`
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+
+		require.NoError(t, err, doc)
+	}
+}
+
+func TestConfluence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"headings",
+			"# Title\n\n### Section <b>",
+			"<h1>Title</h1>\n<h3>Section </h3>\n",
+		},
+		{
+			"inline formatting",
+			"Some **bold**, *italic*, ~~struck~~ and `a < b` text.",
+			"<p>Some <strong>bold</strong>, <em>italic</em>, <s>struck</s> and <code>a &lt; b</code> text.</p>\n",
+		},
+		{
+			"bullet list",
+			"- one\n- two\n  - nested",
+			"<ul><li>one</li>\n<li>two<ul><li>nested</li>\n</ul>\n</li>\n</ul>\n",
+		},
+		{
+			"numbered list",
+			"3. three\n4. four",
+			"<ol start=\"3\"><li>three</li>\n<li>four</li>\n</ol>\n",
+		},
+		{
+			"task list",
+			"- [x] done\n- [ ] todo",
+			"<ac:task-list><ac:task><ac:task-status>complete</ac:task-status><ac:task-body>done</ac:task-body></ac:task>\n" +
+				"<ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>todo</ac:task-body></ac:task>\n" +
+				"</ac:task-list>\n",
+		},
+		{
+			"fenced code block",
+			"```go\nfmt.Println(\"]]>\")\n```",
+			`<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">go</ac:parameter>` +
+				`<ac:plain-text-body><![CDATA[fmt.Println("]]]]><![CDATA[>")]]></ac:plain-text-body></ac:structured-macro>` + "\n",
+		},
+		{
+			"indented code block",
+			"    x := 1",
+			`<ac:structured-macro ac:name="code"><ac:plain-text-body><![CDATA[x := 1]]></ac:plain-text-body></ac:structured-macro>` + "\n",
+		},
+		{
+			"links",
+			"See [Home](/wiki/home#intro), [missing](/wiki/missing) and [Go](https://go.dev \"The Go site\").",
+			`<p>See <ac:link ac:anchor="intro"><ri:page ri:content-title="Home Page" /><ac:link-body>Home</ac:link-body></ac:link>, ` +
+				`<a href="/wiki/missing">missing</a> and <a href="https://go.dev" title="The Go site">Go</a>.</p>` + "\n",
+		},
+		{
+			"autolinks and images",
+			"<https://example.com?a=1&b=2> ![A \"logo\"](/logo.png)",
+			`<p><a href="https://example.com?a=1&amp;b=2">https://example.com?a=1&amp;b=2</a> ` +
+				`<ac:image ac:alt="A &quot;logo&quot;"><ri:url ri:value="/logo.png" /></ac:image></p>` + "\n",
+		},
+		{
+			"table",
+			"| Name | Role |\n| --- | --- |\n| Ada | admin |",
+			"<table><tbody>\n<tr><th>Name</th><th>Role</th></tr>\n<tr><td>Ada</td><td>admin</td></tr>\n</tbody></table>\n",
+		},
+		{
+			"quote and rule",
+			"> quoted\n\n---",
+			"<blockquote><p>quoted</p>\n</blockquote>\n<hr />\n",
+		},
+	}
+
+	titles := map[string]string{"home": "Home Page"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Confluence(tt.content, titles)
+			assert.Equal(t, tt.want, string(got))
+			wellFormed(t, got)
+		})
+	}
+}
+
+func TestConfluence_HTMLBlock(t *testing.T) {
+	got := Confluence("<div class=\"note\">\n<p>Raw</p>\n</div>", nil)
+
+	assert.Equal(t,
+		`<ac:structured-macro ac:name="html"><ac:plain-text-body><![CDATA[<div class="note">`+
+			"\n<p>Raw</p>\n</div>]]></ac:plain-text-body></ac:structured-macro>\n",
+		string(got),
+	)
+	wellFormed(t, got)
+}
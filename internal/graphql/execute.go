@@ -0,0 +1,652 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Request is a GraphQL request as sent in the body of a POST.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Result is the response to a GraphQL request.
+type Result struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error is a request or field error. Path locates the field that failed in the response data.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Execute parses and runs a query against the schema. Syntax errors, unknown operations and
+// invalid variables are reported in Result.Errors without any data. Field errors null the
+// failing field (or its nearest nullable parent) and are reported alongside the data.
+func Execute(ctx context.Context, schema *Schema, req Request) *Result {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	op, err := doc.selectOperation(req.OperationName)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	if op.kind != "query" {
+		return errorResult(fmt.Errorf("%s operations are not supported; this endpoint is read-only", op.kind))
+	}
+
+	variables, err := coerceVariables(op.variables, req.Variables)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	e := &executor{ctx: ctx, schema: schema, doc: doc, variables: variables}
+
+	if schema.MaxCost > 0 {
+		cost := e.selectionCost(schema.Query, op.selectionSet, schema.MaxCost, map[string]bool{})
+		if cost > schema.MaxCost {
+			return errorResult(fmt.Errorf("query is too complex: its cost exceeds the limit of %d", schema.MaxCost))
+		}
+	}
+
+	data, ok := e.executeSelectionSet(schema.Query, nil, op.selectionSet, nil)
+
+	result := &Result{Errors: e.errors}
+	if ok {
+		result.Data = data
+	}
+
+	return result
+}
+
+// errorResult returns a result holding a single request error.
+func errorResult(err error) *Result {
+	return &Result{Errors: []*Error{{Message: err.Error()}}}
+}
+
+// selectOperation picks the operation to run. The name may only be omitted if the
+// document contains exactly one operation.
+func (d *document) selectOperation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains multiple operations")
+		}
+
+		return d.operations[0], nil
+	}
+
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown operation named %q", name)
+}
+
+// coerceVariables resolves the declared variables of an operation against the provided values.
+// Undeclared values are ignored; values are type checked when they are used as arguments.
+func coerceVariables(defs []*variableDefinition, provided map[string]any) (map[string]any, error) {
+	variables := make(map[string]any, len(defs))
+
+	for _, def := range defs {
+		value, ok := provided[def.name]
+
+		switch {
+		case ok && value == nil && def.typ.nonNull:
+			return nil, fmt.Errorf("variable $%s of non-null type %s must not be null", def.name, def.typ)
+		case ok:
+			variables[def.name] = value
+		case def.hasDefault:
+			variables[def.name] = def.defaultValue
+		case def.typ.nonNull:
+			return nil, fmt.Errorf("variable $%s of required type %s was not provided", def.name, def.typ)
+		}
+	}
+
+	return variables, nil
+}
+
+// executor holds the state of a single query execution.
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	doc       *document
+	variables map[string]any
+	errors    []*Error
+}
+
+// addError records a field error at path.
+func (e *executor) addError(path []any, format string, args ...any) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: path})
+}
+
+// collectedField is a response key and every field node selected under it.
+type collectedField struct {
+	key   string
+	nodes []*field
+}
+
+// collectFields flattens fragments and applies @skip/@include, grouping fields by response key.
+func (e *executor) collectFields(
+	obj *Object,
+	set []selection,
+	fields []*collectedField,
+	visited map[string]bool,
+	path []any,
+) []*collectedField {
+	for _, sel := range set {
+		include, err := e.shouldInclude(sel.selectionDirectives())
+		if err != nil {
+			e.addError(path, "%s", err)
+			continue
+		}
+
+		if !include {
+			continue
+		}
+
+		switch sel := sel.(type) {
+		case *field:
+			key := sel.responseKey()
+
+			idx := slices.IndexFunc(fields, func(f *collectedField) bool { return f.key == key })
+			if idx >= 0 {
+				fields[idx].nodes = append(fields[idx].nodes, sel)
+			} else {
+				fields = append(fields, &collectedField{key: key, nodes: []*field{sel}})
+			}
+		case *fragmentSpread:
+			if visited[sel.name] {
+				continue
+			}
+
+			visited[sel.name] = true
+
+			frag, ok := e.doc.fragments[sel.name]
+			if !ok {
+				e.addError(path, "unknown fragment %q", sel.name)
+				continue
+			}
+
+			if frag.typeCondition == obj.Name {
+				fields = e.collectFields(obj, frag.selectionSet, fields, visited, path)
+			}
+		case *inlineFragment:
+			if sel.typeCondition == "" || sel.typeCondition == obj.Name {
+				fields = e.collectFields(obj, sel.selectionSet, fields, visited, path)
+			}
+		}
+	}
+
+	return fields
+}
+
+// shouldInclude evaluates the @skip and @include directives of a selection.
+func (e *executor) shouldInclude(directives []*directive) (bool, error) {
+	for _, dir := range directives {
+		if dir.name != "skip" && dir.name != "include" {
+			continue
+		}
+
+		args, err := e.coerceArguments(
+			map[string]*Argument{"if": {Type: &NonNull{OfType: Boolean}}},
+			dir.arguments,
+		)
+		if err != nil {
+			return false, fmt.Errorf("@%s: %w", dir.name, err)
+		}
+
+		if args["if"].(bool) == (dir.name == "skip") {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// executeSelectionSet resolves the selected fields of obj. It returns false if a non-null
+// field failed, nulling the object itself.
+func (e *executor) executeSelectionSet(
+	obj *Object,
+	source any,
+	set []selection,
+	path []any,
+) (*orderedMap, bool) {
+	result := &orderedMap{values: make(map[string]any)}
+
+	for _, collected := range e.collectFields(obj, set, nil, map[string]bool{}, path) {
+		node := collected.nodes[0]
+		fieldPath := append(slices.Clip(path), collected.key)
+
+		if node.name == "__typename" {
+			result.set(collected.key, obj.Name)
+			continue
+		}
+
+		def, ok := e.fieldDefinition(obj, node.name)
+		if !ok {
+			e.addError(fieldPath, "cannot query field %q on type %q", node.name, obj.Name)
+			result.set(collected.key, nil)
+
+			continue
+		}
+
+		value, ok := e.resolveField(def, source, collected.nodes, fieldPath)
+		if !ok {
+			return nil, false
+		}
+
+		result.set(collected.key, value)
+	}
+
+	return result, true
+}
+
+// fieldDefinition looks up a field of obj. The query type also has the __schema and
+// __type introspection fields.
+func (e *executor) fieldDefinition(obj *Object, name string) (*Field, bool) {
+	if def, ok := obj.Fields[name]; ok {
+		return def, true
+	}
+
+	if obj == e.schema.Query {
+		def, ok := e.schema.introspectionFields()[name]
+		return def, ok
+	}
+
+	return nil, false
+}
+
+// selectionCost adds up the cost of the fields selected by set, expanding fragments each
+// time they are spread. It stops counting once the cost exceeds limit, so that documents
+// spreading fragments exponentially are rejected without being walked in full.
+func (e *executor) selectionCost(obj *Object, set []selection, limit int, spreading map[string]bool) int {
+	cost := 0
+
+	for _, sel := range set {
+		if cost > limit {
+			break
+		}
+
+		switch sel := sel.(type) {
+		case *field:
+			def, ok := e.fieldDefinition(obj, sel.name)
+			if !ok {
+				cost++
+				continue
+			}
+
+			cost += max(def.Cost, 1)
+
+			if child, ok := namedType(def.Type).(*Object); ok {
+				cost += e.selectionCost(child, sel.selectionSet, limit-cost, spreading)
+			}
+		case *fragmentSpread:
+			frag, ok := e.doc.fragments[sel.name]
+			if !ok || spreading[sel.name] {
+				continue
+			}
+
+			spreading[sel.name] = true
+			cost += e.selectionCost(obj, frag.selectionSet, limit-cost, spreading)
+			delete(spreading, sel.name)
+		case *inlineFragment:
+			cost += e.selectionCost(obj, sel.selectionSet, limit-cost, spreading)
+		}
+	}
+
+	return cost
+}
+
+// resolveField runs the resolver of a field and completes its value.
+func (e *executor) resolveField(def *Field, source any, nodes []*field, path []any) (any, bool) {
+	args, err := e.coerceArguments(def.Args, nodes[0].arguments)
+	if err != nil {
+		e.addError(path, "%s", err)
+		return nil, !isNonNull(def.Type)
+	}
+
+	var value any
+
+	if def.Resolve != nil {
+		value, err = def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+	} else {
+		value = defaultResolve(source, nodes[0].name)
+	}
+
+	if err != nil {
+		e.addError(path, "%s", err)
+		return nil, !isNonNull(def.Type)
+	}
+
+	return e.completeValue(def.Type, nodes, value, path)
+}
+
+// completeValue converts a resolved value to its response representation. It returns false
+// if the value is null because of an error and the null must propagate to the parent.
+func (e *executor) completeValue(typ Type, nodes []*field, value any, path []any) (any, bool) {
+	nonNull, ok := typ.(*NonNull)
+	if !ok {
+		completed, ok := e.completeNullable(typ, nodes, value, path)
+		if !ok {
+			return nil, true
+		}
+
+		return completed, true
+	}
+
+	completed, ok := e.completeNullable(nonNull.OfType, nodes, value, path)
+	if !ok {
+		return nil, false
+	}
+
+	if completed == nil {
+		e.addError(path, "cannot return null for non-null field")
+		return nil, false
+	}
+
+	return completed, true
+}
+
+// completeNullable completes a value of a nullable type. It returns false if completion failed.
+func (e *executor) completeNullable(typ Type, nodes []*field, value any, path []any) (any, bool) {
+	leaf, ok := deref(value)
+	if !ok {
+		return nil, true
+	}
+
+	switch typ := typ.(type) {
+	case *Scalar:
+		serialized, err := typ.Serialize(leaf)
+		if err != nil {
+			e.addError(path, "%s", err)
+			return nil, false
+		}
+
+		return serialized, true
+	case *Object:
+		var set []selection
+		for _, node := range nodes {
+			set = append(set, node.selectionSet...)
+		}
+
+		if len(set) == 0 {
+			e.addError(path, "field %q of type %q must have a selection of subfields", nodes[0].name, typ.Name)
+			return nil, false
+		}
+
+		// Resolvers of the fields see the value exactly as returned by the parent resolver.
+		return e.executeSelectionSet(typ, value, set, path)
+	case *List:
+		items := reflect.ValueOf(leaf)
+		if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+			e.addError(path, "expected a list, got %T", leaf)
+			return nil, false
+		}
+
+		list := make([]any, items.Len())
+		for i := range list {
+			item, ok := e.completeValue(typ.OfType, nodes, items.Index(i).Interface(), append(slices.Clip(path), i))
+			if !ok {
+				return nil, false
+			}
+
+			list[i] = item
+		}
+
+		return list, true
+	}
+
+	e.addError(path, "unsupported type %s", typ)
+
+	return nil, false
+}
+
+// coerceArguments resolves the arguments given to a field against its declared arguments.
+func (e *executor) coerceArguments(defs map[string]*Argument, given []*argument) (map[string]any, error) {
+	args := make(map[string]any, len(defs))
+
+	for _, arg := range given {
+		if _, ok := defs[arg.name]; !ok {
+			return nil, fmt.Errorf("unknown argument %q", arg.name)
+		}
+	}
+
+	for name, def := range defs {
+		idx := slices.IndexFunc(given, func(a *argument) bool { return a.name == name })
+
+		var (
+			value   any
+			present bool
+		)
+
+		if idx >= 0 {
+			value, present = e.valueOf(given[idx].value)
+		}
+
+		if !present {
+			if def.DefaultValue == nil {
+				if isNonNull(def.Type) {
+					return nil, fmt.Errorf("argument %q of required type %s was not provided", name, def.Type)
+				}
+
+				continue
+			}
+
+			value = def.DefaultValue
+		}
+
+		coerced, err := coerceInput(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+
+		args[name] = coerced
+	}
+
+	return args, nil
+}
+
+// valueOf substitutes variables in an argument value. It returns false if the value is a
+// variable that was not provided.
+func (e *executor) valueOf(value any) (any, bool) {
+	switch v := value.(type) {
+	case variable:
+		resolved, ok := e.variables[string(v)]
+		return resolved, ok
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i], _ = e.valueOf(item)
+		}
+
+		return list, true
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			if resolved, ok := e.valueOf(item); ok {
+				object[key] = resolved
+			}
+		}
+
+		return object, true
+	}
+
+	return value, true
+}
+
+// coerceInput converts an input value to the Go representation of typ.
+func coerceInput(typ Type, value any) (any, error) {
+	if nonNull, ok := typ.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected a non-null %s", nonNull.OfType)
+		}
+
+		return coerceInput(nonNull.OfType, value)
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	switch typ := typ.(type) {
+	case *Scalar:
+		return typ.Parse(value)
+	case *List:
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+
+		list := make([]any, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(typ.OfType, item)
+			if err != nil {
+				return nil, err
+			}
+
+			list[i] = coerced
+		}
+
+		return list, nil
+	}
+
+	return nil, fmt.Errorf("%s cannot be used as an input type", typ)
+}
+
+// defaultResolve reads a field from a map or struct source.
+func defaultResolve(source any, name string) any {
+	if m, ok := source.(map[string]any); ok {
+		return m[name]
+	}
+
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == name || (jsonName == "" && sf.Name == name) {
+			return v.Field(i).Interface()
+		}
+	}
+
+	return nil
+}
+
+// deref follows pointers and interfaces. It returns false if value is nil.
+func deref(value any) (any, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+
+		v = v.Elem()
+	}
+
+	return v.Interface(), true
+}
+
+// namedType unwraps list and non-null types.
+func namedType(typ Type) Type {
+	for {
+		switch t := typ.(type) {
+		case *List:
+			typ = t.OfType
+		case *NonNull:
+			typ = t.OfType
+		default:
+			return typ
+		}
+	}
+}
+
+// isNonNull reports whether typ is a non-null type.
+func isNonNull(typ Type) bool {
+	_, ok := typ.(*NonNull)
+	return ok
+}
+
+// orderedMap is a response object that keeps its keys in selection order.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// set stores a value, keeping the position of keys that already exist.
+func (m *orderedMap) set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// MarshalJSON writes the object with its keys in selection order.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
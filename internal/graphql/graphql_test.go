@@ -0,0 +1,393 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBook struct {
+	Title  string  `json:"title"`
+	Pages  int     `json:"pages"`
+	Author *string `json:"author,omitempty"`
+	secret string
+}
+
+// newTestSchema returns a schema with `book(title)`, `books(limit)` and `broken` root fields.
+func newTestSchema() *Schema {
+	author := "Ada"
+	books := []*testBook{
+		{Title: "Go", Pages: 300, Author: &author, secret: "s"},
+		{Title: "Rust", Pages: 500},
+		{Title: "Zig", Pages: 150},
+	}
+
+	book := &Object{
+		Name: "Book",
+		Fields: map[string]*Field{
+			"title":  {Type: &NonNull{OfType: String}},
+			"pages":  {Type: Int},
+			"author": {Type: String},
+			"secret": {Type: String},
+			"summary": {
+				Type: &NonNull{OfType: String},
+				Resolve: func(p ResolveParams) (any, error) {
+					return nil, errors.New("summary unavailable")
+				},
+			},
+		},
+	}
+
+	return &Schema{Query: &Object{
+		Name: "Query",
+		Fields: map[string]*Field{
+			"book": {
+				Type: book,
+				Args: map[string]*Argument{"title": {Type: &NonNull{OfType: String}}},
+				Resolve: func(p ResolveParams) (any, error) {
+					for _, b := range books {
+						if b.Title == p.Args["title"] {
+							return b, nil
+						}
+					}
+
+					return nil, nil
+				},
+			},
+			"books": {
+				Type: &NonNull{OfType: &List{OfType: &NonNull{OfType: book}}},
+				Args: map[string]*Argument{"limit": {Type: Int, DefaultValue: 10}},
+				Resolve: func(p ResolveParams) (any, error) {
+					return books[:min(p.Args["limit"].(int), len(books))], nil
+				},
+			},
+			"broken": {
+				Type: String,
+				Resolve: func(p ResolveParams) (any, error) {
+					return nil, errors.New("boom")
+				},
+			},
+		},
+	}}
+}
+
+// execute runs a query against the test schema and returns the JSON encoded result.
+func execute(t *testing.T, req Request) string {
+	t.Helper()
+
+	body, err := json.Marshal(Execute(context.Background(), newTestSchema(), req))
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{
+			"fields in selection order",
+			Request{Query: `{ book(title: "Go") { pages title author } }`},
+			`{"data":{"book":{"pages":300,"title":"Go","author":"Ada"}}}`,
+		},
+		{
+			"aliases and typename",
+			Request{Query: `{ first: book(title: "Go") { __typename name: title } missing: book(title: "C") { title } }`},
+			`{"data":{"first":{"__typename":"Book","name":"Go"},"missing":null}}`,
+		},
+		{
+			"list with default argument",
+			Request{Query: `{ books { title } }`},
+			`{"data":{"books":[{"title":"Go"},{"title":"Rust"},{"title":"Zig"}]}}`,
+		},
+		{
+			"variables",
+			Request{
+				Query:     `query Books($limit: Int = 1, $title: String!) { books(limit: $limit) { title } book(title: $title) { pages } }`,
+				Variables: map[string]any{"title": "Zig"},
+			},
+			`{"data":{"books":[{"title":"Go"}],"book":{"pages":150}}}`,
+		},
+		{
+			"json numbers as int variables",
+			Request{Query: `query ($limit: Int) { books(limit: $limit) { title } }`, Variables: map[string]any{"limit": 2.0}},
+			`{"data":{"books":[{"title":"Go"},{"title":"Rust"}]}}`,
+		},
+		{
+			"fragments",
+			Request{Query: `
+				query { books(limit: 2) { ...Basics ... on Book { pages } } }
+				fragment Basics on Book { title }
+			`},
+			`{"data":{"books":[{"title":"Go","pages":300},{"title":"Rust","pages":500}]}}`,
+		},
+		{
+			"skip and include",
+			Request{
+				Query:     `query ($full: Boolean!) { book(title: "Go") { title pages @include(if: $full) author @skip(if: true) } }`,
+				Variables: map[string]any{"full": false},
+			},
+			`{"data":{"book":{"title":"Go"}}}`,
+		},
+		{
+			"unexported fields are never resolved",
+			Request{Query: `{ book(title: "Go") { secret } }`},
+			`{"data":{"book":{"secret":null}}}`,
+		},
+		{
+			"selected operation",
+			Request{Query: `query A { broken } query B { book(title: "Zig") { title } }`, OperationName: "B"},
+			`{"data":{"book":{"title":"Zig"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.JSONEq(t, tt.want, execute(t, tt.req))
+		})
+	}
+}
+
+func TestExecute_FieldErrors(t *testing.T) {
+	got := execute(t, Request{Query: `{ broken book(title: "Go") { title } }`})
+	assert.JSONEq(t, `{
+		"data": {"broken": null, "book": {"title": "Go"}},
+		"errors": [{"message": "boom", "path": ["broken"]}]
+	}`, got)
+
+	got = execute(t, Request{Query: `{ book(title: "Go") { title summary } }`})
+	assert.JSONEq(t, `{
+		"data": {"book": null},
+		"errors": [{"message": "summary unavailable", "path": ["book", "summary"]}]
+	}`, got, "a failing non-null field nulls its nullable parent")
+
+	got = execute(t, Request{Query: `{ books { summary } }`})
+	assert.JSONEq(t, `{
+		"errors": [{"message": "summary unavailable", "path": ["books", 0, "summary"]}]
+	}`, got, "nulls propagate through non-null lists up to the root")
+}
+
+func TestExecute_RequestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{"syntax error", Request{Query: `{ book(title: "Go") { title }`}, "syntax error at 1:30: unexpected end of document"},
+		{"mutation", Request{Query: `mutation { book(title: "Go") { title } }`}, "mutation operations are not supported"},
+		{"missing variable", Request{Query: `query ($t: String!) { book(title: $t) { title } }`}, "was not provided"},
+		{"ambiguous operation", Request{Query: `query A { broken } query B { broken }`}, "operationName is required"},
+		{"unknown operation", Request{Query: `query A { broken }`, OperationName: "C"}, `unknown operation named "C"`},
+		{"empty document", Request{Query: `  # nothing here`}, "does not contain an operation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Execute(context.Background(), newTestSchema(), tt.req)
+			assert.Nil(t, result.Data)
+			require.Len(t, result.Errors, 1)
+			assert.Contains(t, result.Errors[0].Message, tt.want)
+		})
+	}
+}
+
+func TestExecute_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"unknown field", `{ book(title: "Go") { isbn } }`, `cannot query field "isbn" on type "Book"`},
+		{"unknown argument", `{ books(first: 1) { title } }`, `unknown argument "first"`},
+		{"missing argument", `{ book { title } }`, `argument "title" of required type String! was not provided`},
+		{"wrong argument type", `{ books(limit: "two") { title } }`, `argument "limit": cannot represent two as Int`},
+		{"missing subselection", `{ book(title: "Go") }`, `must have a selection of subfields`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Execute(context.Background(), newTestSchema(), Request{Query: tt.query})
+			require.NotEmpty(t, result.Errors)
+			assert.Contains(t, result.Errors[0].Message, tt.want)
+		})
+	}
+}
+
+func TestParse_Values(t *testing.T) {
+	doc, err := parse(`{
+		f(a: -12, b: 1.5e3, c: "tab\tquote\" é", d: true, e: null, f: ASC, g: [1, [2]], h: {x: $v},
+		  i: """
+		    Block
+		      indented
+		  """)
+	}`)
+	require.NoError(t, err)
+
+	args := map[string]any{}
+	for _, arg := range doc.operations[0].selectionSet[0].(*field).arguments {
+		args[arg.name] = arg.value
+	}
+
+	assert.Equal(t, -12, args["a"])
+	assert.Equal(t, 1500.0, args["b"])
+	assert.Equal(t, "tab\tquote\" é", args["c"])
+	assert.Equal(t, true, args["d"])
+	assert.Nil(t, args["e"])
+	assert.Equal(t, enumValue("ASC"), args["f"])
+	assert.Equal(t, []any{1, []any{2}}, args["g"])
+	assert.Equal(t, map[string]any{"x": variable("v")}, args["h"])
+	assert.Equal(t, "Block\n  indented", args["i"])
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []string{
+		`{ }`,
+		`{ f(a: ) }`,
+		`{ f(a: 01x) }`,
+		`{ f(a: "unterminated) }`,
+		`{ f(a: "bad \q escape") }`,
+		`query ($v: Int = $w) { f }`,
+		`fragment on on X { f }`,
+		`{ f } fragment A on X { f } fragment A on X { g }`,
+		`{ f % }`,
+	}
+
+	for _, query := range tests {
+		t.Run(query, func(t *testing.T) {
+			_, err := parse(query)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// introspectionQuery is the query GraphiQL and most client generators send to load a schema.
+const introspectionQuery = `
+	query IntrospectionQuery {
+		__schema {
+			queryType { name }
+			mutationType { name }
+			subscriptionType { name }
+			types { ...FullType }
+			directives { name description locations args { ...InputValue } }
+		}
+	}
+
+	fragment FullType on __Type {
+		kind name description
+		fields(includeDeprecated: true) {
+			name description
+			args { ...InputValue }
+			type { ...TypeRef }
+			isDeprecated deprecationReason
+		}
+		inputFields { ...InputValue }
+		interfaces { ...TypeRef }
+		enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+		possibleTypes { ...TypeRef }
+	}
+
+	fragment InputValue on __InputValue {
+		name description
+		type { ...TypeRef }
+		defaultValue
+	}
+
+	fragment TypeRef on __Type {
+		kind name
+		ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+	}
+`
+
+func TestExecute_Introspection(t *testing.T) {
+	got := execute(t, Request{Query: `{
+		__type(name: "Query") {
+			kind name
+			fields { name args { name defaultValue type { kind name ofType { kind name } } } }
+		}
+		missing: __type(name: "Author") { name }
+	}`})
+	assert.JSONEq(t, `{"data": {
+		"__type": {"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "book", "args": [
+				{"name": "title", "defaultValue": null, "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String"}}}
+			]},
+			{"name": "books", "args": [
+				{"name": "limit", "defaultValue": "10", "type": {"kind": "SCALAR", "name": "Int", "ofType": null}}
+			]},
+			{"name": "broken", "args": []}
+		]},
+		"missing": null
+	}}`, got)
+
+	var result struct {
+		Data struct {
+			Schema struct {
+				QueryType  struct{ Name string }
+				Types      []struct{ Name string }
+				Directives []struct{ Name string }
+			} `json:"__schema"`
+		}
+		Errors []*Error
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(execute(t, Request{Query: introspectionQuery})), &result))
+	require.Empty(t, result.Errors)
+
+	var types []string
+	for _, typ := range result.Data.Schema.Types {
+		types = append(types, typ.Name)
+	}
+
+	assert.Equal(t, "Query", result.Data.Schema.QueryType.Name)
+	assert.Subset(t, types, []string{"Book", "Boolean", "Int", "Query", "String", "__Schema", "__Type"})
+	assert.Len(t, result.Data.Schema.Directives, 2)
+}
+
+func TestExecute_MaxCost(t *testing.T) {
+	schema := newTestSchema()
+	schema.MaxCost = 10
+	schema.Query.Fields["books"].Cost = 5
+
+	result := Execute(context.Background(), schema, Request{Query: `{ books { title pages author } }`})
+	assert.Empty(t, result.Errors, "5 for books and 1 per book field")
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"aliases", `{ a: books { title } b: books { title } }`},
+		{"repeated fields", `{ book(title: "Go") { title title title title title title title title title title } }`},
+		{"fragments", `
+			{ book(title: "Go") { ...A ...A } }
+			fragment A on Book { ...B ...B }
+			fragment B on Book { title pages author }
+		`},
+		{"exponential fragments", `
+			{ book(title: "Go") { ...A } }
+			fragment A on Book { ...B ...B ...B ...B ...B ...B ...B ...B }
+			fragment B on Book { ...C ...C ...C ...C ...C ...C ...C ...C }
+			fragment C on Book { ...D ...D ...D ...D ...D ...D ...D ...D }
+			fragment D on Book { title title title title title title title title }
+		`},
+		{"introspection", `{
+			a: __schema { types { name fields { name } } }
+			b: __schema { types { name fields { name } } }
+			c: __schema { types { name fields { name } } }
+		}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Execute(context.Background(), schema, Request{Query: tt.query})
+			assert.Nil(t, result.Data)
+			require.Len(t, result.Errors, 1)
+			assert.Equal(t, "query is too complex: its cost exceeds the limit of 10", result.Errors[0].Message)
+		})
+	}
+}
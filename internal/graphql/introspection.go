@@ -0,0 +1,301 @@
+package graphql
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// introspectionField is a field of an object type as seen by introspection.
+type introspectionField struct {
+	name  string
+	field *Field
+}
+
+// introspectionInputValue is an argument of a field or directive as seen by introspection.
+type introspectionInputValue struct {
+	name string
+	arg  *Argument
+}
+
+// introspectionDirective is a directive supported by the executor.
+type introspectionDirective struct {
+	name        string
+	description string
+	locations   []string
+	args        map[string]*Argument
+}
+
+// supportedDirectives are the directives the executor evaluates.
+var supportedDirectives = []*introspectionDirective{
+	{
+		name:        "include",
+		description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		args:        map[string]*Argument{"if": {Type: &NonNull{OfType: Boolean}}},
+	},
+	{
+		name:        "skip",
+		description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		args:        map[string]*Argument{"if": {Type: &NonNull{OfType: Boolean}}},
+	},
+}
+
+// introspectionString is a string valued scalar standing in for the enums of the
+// introspection schema, such as __TypeKind.
+func introspectionString(name string) *Scalar {
+	return &Scalar{Name: name, Serialize: String.Serialize, Parse: String.Parse}
+}
+
+// The types of the introspection schema. Their fields are filled in by init, since the
+// types refer to each other.
+var (
+	schemaType      = &Object{Name: "__Schema"}
+	typeType        = &Object{Name: "__Type"}
+	fieldType       = &Object{Name: "__Field"}
+	inputValueType  = &Object{Name: "__InputValue"}
+	enumValueType   = &Object{Name: "__EnumValue"}
+	directiveType   = &Object{Name: "__Directive"}
+	typeKindType    = introspectionString("__TypeKind")
+	dirLocationType = introspectionString("__DirectiveLocation")
+)
+
+func init() {
+	nonNull := func(t Type) Type {
+		return &NonNull{OfType: t}
+	}
+
+	listOf := func(t Type) Type {
+		return &List{OfType: nonNull(t)}
+	}
+
+	null := func(ResolveParams) (any, error) {
+		return nil, nil
+	}
+
+	alwaysFalse := func(ResolveParams) (any, error) {
+		return false, nil
+	}
+
+	deprecatedArgs := map[string]*Argument{"includeDeprecated": {Type: Boolean, DefaultValue: false}}
+
+	schemaType.Fields = map[string]*Field{
+		"description": {Type: String, Resolve: null},
+		"types": {Type: nonNull(listOf(typeType)), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Schema).types(), nil
+		}},
+		"queryType": {Type: nonNull(typeType), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Schema).Query, nil
+		}},
+		"mutationType":     {Type: typeType, Resolve: null},
+		"subscriptionType": {Type: typeType, Resolve: null},
+		"directives": {Type: nonNull(listOf(directiveType)), Resolve: func(ResolveParams) (any, error) {
+			return supportedDirectives, nil
+		}},
+	}
+
+	typeType.Fields = map[string]*Field{
+		"kind": {Type: nonNull(typeKindType), Resolve: func(p ResolveParams) (any, error) {
+			switch p.Source.(type) {
+			case *Object:
+				return "OBJECT", nil
+			case *List:
+				return "LIST", nil
+			case *NonNull:
+				return "NON_NULL", nil
+			default:
+				return "SCALAR", nil
+			}
+		}},
+		"name": {Type: String, Resolve: func(p ResolveParams) (any, error) {
+			switch t := p.Source.(type) {
+			case *Object:
+				return t.Name, nil
+			case *Scalar:
+				return t.Name, nil
+			}
+
+			return nil, nil
+		}},
+		"description": {Type: String, Resolve: null},
+		"fields": {Type: listOf(fieldType), Args: deprecatedArgs, Resolve: func(p ResolveParams) (any, error) {
+			obj, ok := p.Source.(*Object)
+			if !ok {
+				return nil, nil
+			}
+
+			var fields []*introspectionField
+			for _, name := range slices.Sorted(maps.Keys(obj.Fields)) {
+				fields = append(fields, &introspectionField{name: name, field: obj.Fields[name]})
+			}
+
+			return fields, nil
+		}},
+		"interfaces": {Type: listOf(typeType), Resolve: func(p ResolveParams) (any, error) {
+			if _, ok := p.Source.(*Object); ok {
+				return []Type{}, nil
+			}
+
+			return nil, nil
+		}},
+		"possibleTypes":  {Type: listOf(typeType), Resolve: null},
+		"enumValues":     {Type: listOf(enumValueType), Args: deprecatedArgs, Resolve: null},
+		"inputFields":    {Type: listOf(inputValueType), Resolve: null},
+		"specifiedByURL": {Type: String, Resolve: null},
+		"ofType": {Type: typeType, Resolve: func(p ResolveParams) (any, error) {
+			switch t := p.Source.(type) {
+			case *List:
+				return t.OfType, nil
+			case *NonNull:
+				return t.OfType, nil
+			}
+
+			return nil, nil
+		}},
+	}
+
+	fieldType.Fields = map[string]*Field{
+		"name": {Type: nonNull(String), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionField).name, nil
+		}},
+		"description": {Type: String, Resolve: null},
+		"args": {Type: nonNull(listOf(inputValueType)), Resolve: func(p ResolveParams) (any, error) {
+			return inputValues(p.Source.(*introspectionField).field.Args), nil
+		}},
+		"type": {Type: nonNull(typeType), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionField).field.Type, nil
+		}},
+		"isDeprecated":      {Type: nonNull(Boolean), Resolve: alwaysFalse},
+		"deprecationReason": {Type: String, Resolve: null},
+	}
+
+	inputValueType.Fields = map[string]*Field{
+		"name": {Type: nonNull(String), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionInputValue).name, nil
+		}},
+		"description": {Type: String, Resolve: null},
+		"type": {Type: nonNull(typeType), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionInputValue).arg.Type, nil
+		}},
+		"defaultValue": {Type: String, Resolve: func(p ResolveParams) (any, error) {
+			value := p.Source.(*introspectionInputValue).arg.DefaultValue
+			if value == nil {
+				return nil, nil
+			}
+
+			// JSON encodes the scalar defaults used in schemas as GraphQL literals.
+			literal, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+
+			return string(literal), nil
+		}},
+		"isDeprecated":      {Type: nonNull(Boolean), Resolve: alwaysFalse},
+		"deprecationReason": {Type: String, Resolve: null},
+	}
+
+	enumValueType.Fields = map[string]*Field{
+		"name":              {Type: nonNull(String)},
+		"description":       {Type: String, Resolve: null},
+		"isDeprecated":      {Type: nonNull(Boolean), Resolve: alwaysFalse},
+		"deprecationReason": {Type: String, Resolve: null},
+	}
+
+	directiveType.Fields = map[string]*Field{
+		"name": {Type: nonNull(String), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionDirective).name, nil
+		}},
+		"description": {Type: String, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionDirective).description, nil
+		}},
+		"locations": {Type: nonNull(listOf(dirLocationType)), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*introspectionDirective).locations, nil
+		}},
+		"args": {Type: nonNull(listOf(inputValueType)), Resolve: func(p ResolveParams) (any, error) {
+			return inputValues(p.Source.(*introspectionDirective).args), nil
+		}},
+		"isRepeatable": {Type: nonNull(Boolean), Resolve: alwaysFalse},
+	}
+}
+
+// inputValues lists arguments sorted by name.
+func inputValues(args map[string]*Argument) []*introspectionInputValue {
+	values := []*introspectionInputValue{}
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		values = append(values, &introspectionInputValue{name: name, arg: args[name]})
+	}
+
+	return values
+}
+
+// introspectionFields returns the __schema and __type meta fields available on the query type.
+func (s *Schema) introspectionFields() map[string]*Field {
+	return map[string]*Field{
+		"__schema": {
+			Type: &NonNull{OfType: schemaType},
+			Resolve: func(ResolveParams) (any, error) {
+				return s, nil
+			},
+		},
+		"__type": {
+			Type: typeType,
+			Args: map[string]*Argument{"name": {Type: &NonNull{OfType: String}}},
+			Resolve: func(p ResolveParams) (any, error) {
+				name := p.Args["name"].(string)
+
+				types := s.types()
+
+				idx := slices.IndexFunc(types, func(t Type) bool { return t.String() == name })
+				if idx < 0 {
+					return nil, nil
+				}
+
+				return types[idx], nil
+			},
+		},
+	}
+}
+
+// types returns every named type reachable from the query type, including the built-in
+// scalars and the introspection types, sorted by name.
+func (s *Schema) types() []Type {
+	seen := map[string]Type{}
+
+	var visit func(t Type)
+	visit = func(t Type) {
+		switch t := t.(type) {
+		case *List:
+			visit(t.OfType)
+		case *NonNull:
+			visit(t.OfType)
+		case *Scalar:
+			seen[t.Name] = t
+		case *Object:
+			if _, ok := seen[t.Name]; ok {
+				return
+			}
+
+			seen[t.Name] = t
+
+			for _, f := range t.Fields {
+				visit(f.Type)
+
+				for _, arg := range f.Args {
+					visit(arg.Type)
+				}
+			}
+		}
+	}
+
+	visit(String)
+	visit(Boolean)
+	visit(schemaType)
+	visit(s.Query)
+
+	return slices.SortedFunc(maps.Values(seen), func(a, b Type) int {
+		return strings.Compare(a.String(), b.String())
+	})
+}
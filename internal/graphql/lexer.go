@@ -0,0 +1,301 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind identifies the lexical class of a token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// byteOrderMark is ignored wherever it appears between tokens.
+const byteOrderMark = "\uFEFF"
+
+// punctuators are the single character punctuators of the GraphQL grammar.
+const punctuators = "!$&()=:@[]{}|"
+
+// token is a lexical token of a GraphQL document.
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lexer splits a GraphQL document into tokens.
+type lexer struct {
+	src string
+	pos int
+}
+
+// next returns the next token, skipping whitespace, commas and comments.
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	start := l.pos
+	if start >= len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.src[start]
+
+	switch {
+	case strings.IndexByte(punctuators, c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[start:], "...") {
+			l.pos += 3
+			return token{kind: tokenPunct, value: "...", pos: start}, nil
+		}
+	case isNameStart(c):
+		for l.pos < len(l.src) && isNameContinue(l.src[l.pos]) {
+			l.pos++
+		}
+
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		if strings.HasPrefix(l.src[start:], `"""`) {
+			return l.blockString()
+		}
+
+		return l.string()
+	}
+
+	return token{}, l.errorf(start, "unexpected character %q", c)
+}
+
+// skipIgnored advances past insignificant characters.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case ' ', '\t', '\n', '\r', ',':
+			l.pos++
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			if strings.HasPrefix(l.src[l.pos:], byteOrderMark) {
+				l.pos += len(byteOrderMark)
+				continue
+			}
+
+			return
+		}
+	}
+}
+
+// number scans an Int or Float literal.
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+
+	if !l.digits() {
+		return token{}, l.errorf(start, "invalid number")
+	}
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+
+		if !l.digits() {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+
+		if !l.digits() {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+
+	if l.pos < len(l.src) && (isNameStart(l.src[l.pos]) || l.src[l.pos] == '.') {
+		return token{}, l.errorf(start, "invalid number")
+	}
+
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+// digits consumes a run of digits and reports whether there was at least one.
+func (l *lexer) digits() bool {
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+
+	return l.pos > start
+}
+
+// string scans a quoted string literal and decodes its escape sequences.
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+
+	var sb strings.Builder
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: sb.String(), pos: start}, nil
+		case '\n', '\r':
+			return token{}, l.errorf(start, "unterminated string")
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, l.errorf(start, "unterminated string")
+			}
+
+			escaped := l.src[l.pos+1]
+			l.pos += 2
+
+			switch escaped {
+			case '"', '\\', '/':
+				sb.WriteByte(escaped)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+
+				sb.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, l.errorf(l.pos-2, "invalid escape sequence \\%c", escaped)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			sb.WriteRune(r)
+			l.pos += size
+		}
+	}
+
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+// blockString scans a """block string""", removing its common indentation.
+func (l *lexer) blockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+
+	var sb strings.Builder
+
+	for l.pos < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[l.pos:], `\"""`):
+			sb.WriteString(`"""`)
+			l.pos += 4
+		case strings.HasPrefix(l.src[l.pos:], `"""`):
+			l.pos += 3
+			return token{kind: tokenString, value: blockStringValue(sb.String()), pos: start}, nil
+		default:
+			sb.WriteByte(l.src[l.pos])
+			l.pos++
+		}
+	}
+
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+// blockStringValue removes the common indentation and surrounding blank lines of a block string.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// errorf returns a syntax error located at the given byte offset.
+func (l *lexer) errorf(pos int, format string, args ...any) error {
+	line, column := 1, 1
+	for _, r := range l.src[:pos] {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return fmt.Errorf("syntax error at %d:%d: %s", line, column, fmt.Sprintf(format, args...))
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,709 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// document is a parsed GraphQL request document.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is an operation definition such as `query Name($id: ID!) { ... }`.
+type operation struct {
+	kind         string
+	name         string
+	variables    []*variableDefinition
+	selectionSet []selection
+}
+
+// variableDefinition declares an operation variable and its optional default value.
+type variableDefinition struct {
+	name         string
+	typ          *typeRef
+	defaultValue any
+	hasDefault   bool
+}
+
+// typeRef is a type reference in a variable definition, e.g. `[String!]!`.
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+// String formats the type reference as it is written in a document.
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+
+	if t.nonNull {
+		s += "!"
+	}
+
+	return s
+}
+
+// fragment is a named fragment definition.
+type fragment struct {
+	name          string
+	typeCondition string
+	selectionSet  []selection
+}
+
+// selection is a field, fragment spread or inline fragment.
+type selection interface {
+	selectionDirectives() []*directive
+}
+
+// field selects a field, optionally aliased, with arguments and subfields.
+type field struct {
+	alias        string
+	name         string
+	arguments    []*argument
+	directives   []*directive
+	selectionSet []selection
+}
+
+// responseKey returns the key the field is written under in the response.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+
+	return f.name
+}
+
+// fragmentSpread includes a named fragment, e.g. `...ArticleFields`.
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+}
+
+// inlineFragment is an anonymous fragment, e.g. `... on Article { slug }`.
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selectionSet  []selection
+}
+
+func (f *field) selectionDirectives() []*directive          { return f.directives }
+func (f *fragmentSpread) selectionDirectives() []*directive { return f.directives }
+func (f *inlineFragment) selectionDirectives() []*directive { return f.directives }
+
+// argument is a name and value pair passed to a field or directive.
+type argument struct {
+	name  string
+	value any
+}
+
+// directive is a directive applied to a selection, e.g. `@include(if: $withHistory)`.
+type directive struct {
+	name      string
+	arguments []*argument
+}
+
+// variable references an operation variable inside a value.
+type variable string
+
+// enumValue is an unquoted enum literal.
+type enumValue string
+
+// parser builds a document from the tokens of a lexer.
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// parse parses a GraphQL document.
+func parse(src string) (*document, error) {
+	p := &parser{lex: &lexer{src: src}}
+
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: make(map[string]*fragment)}
+
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			doc.operations = append(doc.operations, &operation{kind: "query", selectionSet: set})
+		case p.tok.kind == tokenName && p.tok.value == "fragment":
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, fmt.Errorf("there can be only one fragment named %q", frag.name)
+			}
+
+			doc.fragments[frag.name] = frag
+		case p.tok.kind == tokenName:
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+
+			doc.operations = append(doc.operations, op)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document does not contain an operation")
+	}
+
+	return doc, nil
+}
+
+// advance moves to the next token.
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+
+	return nil
+}
+
+// peek reports whether the current token is the given punctuator.
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+// skip consumes the current token if it is the given punctuator.
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+
+	return true, p.advance()
+}
+
+// expect consumes the given punctuator or fails.
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.unexpected()
+	}
+
+	return p.advance()
+}
+
+// expectName consumes a name token and returns its value.
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+
+	name := p.tok.value
+
+	return name, p.advance()
+}
+
+// expectKeyword consumes the given name token or fails.
+func (p *parser) expectKeyword(keyword string) error {
+	if p.tok.kind != tokenName || p.tok.value != keyword {
+		return p.unexpected()
+	}
+
+	return p.advance()
+}
+
+// unexpected returns a syntax error for the current token.
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return p.lex.errorf(p.tok.pos, "unexpected end of document")
+	}
+
+	return p.lex.errorf(p.tok.pos, "unexpected %q", p.tok.value)
+}
+
+// parseOperation parses `query|mutation|subscription Name? VariableDefinitions? Directives? SelectionSet`.
+func (p *parser) parseOperation() (*operation, error) {
+	kind := p.tok.value
+	if kind != "query" && kind != "mutation" && kind != "subscription" {
+		return nil, p.unexpected()
+	}
+
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	op := &operation{kind: kind}
+
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("(") {
+		op.variables, err = p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	op.selectionSet, err = p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// parseVariableDefinitions parses `($name: Type = default, ...)`.
+func (p *parser) parseVariableDefinitions() ([]*variableDefinition, error) {
+	err := p.expect("(")
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []*variableDefinition
+
+	for !p.peek(")") {
+		err = p.expect("$")
+		if err != nil {
+			return nil, err
+		}
+
+		def := &variableDefinition{}
+
+		def.name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+
+		def.typ, err = p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+
+		hasDefault, err := p.skip("=")
+		if err != nil {
+			return nil, err
+		}
+
+		if hasDefault {
+			def.hasDefault = true
+
+			def.defaultValue, err = p.parseValue(true)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+
+		defs = append(defs, def)
+	}
+
+	return defs, p.advance()
+}
+
+// parseTypeRef parses a named, list or non-null type reference.
+func (p *parser) parseTypeRef() (*typeRef, error) {
+	ref := &typeRef{}
+
+	isList, err := p.skip("[")
+	if err != nil {
+		return nil, err
+	}
+
+	if isList {
+		ref.elem, err = p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expect("]")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ref.name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ref.nonNull, err = p.skip("!")
+	if err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// parseFragment parses `fragment Name on Type Directives? SelectionSet`.
+func (p *parser) parseFragment() (*fragment, error) {
+	err := p.expectKeyword("fragment")
+	if err != nil {
+		return nil, err
+	}
+
+	frag := &fragment{}
+
+	frag.name, err = p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	if frag.name == "on" {
+		return nil, p.lex.errorf(p.tok.pos, "fragment cannot be named \"on\"")
+	}
+
+	err = p.expectKeyword("on")
+	if err != nil {
+		return nil, err
+	}
+
+	frag.typeCondition, err = p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	frag.selectionSet, err = p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return frag, nil
+}
+
+// parseSelectionSet parses `{ Selection+ }`.
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	err := p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+
+	var set []selection
+
+	for !p.peek("}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+
+		set = append(set, sel)
+	}
+
+	if len(set) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return set, p.advance()
+}
+
+// parseSelection parses a field, fragment spread or inline fragment.
+func (p *parser) parseSelection() (selection, error) {
+	isFragment, err := p.skip("...")
+	if err != nil {
+		return nil, err
+	}
+
+	if !isFragment {
+		return p.parseField()
+	}
+
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		spread := &fragmentSpread{name: p.tok.value}
+
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		spread.directives, err = p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+
+		return spread, nil
+	}
+
+	inline := &inlineFragment{}
+
+	if p.tok.kind == tokenName {
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		inline.typeCondition, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inline.directives, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	inline.selectionSet, err = p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return inline, nil
+}
+
+// parseField parses `Alias? Name Arguments? Directives? SelectionSet?`.
+func (p *parser) parseField() (*field, error) {
+	f := &field{}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	isAlias, err := p.skip(":")
+	if err != nil {
+		return nil, err
+	}
+
+	if isAlias {
+		f.alias = name
+
+		name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f.name = name
+
+	f.arguments, err = p.parseArguments(false)
+	if err != nil {
+		return nil, err
+	}
+
+	f.directives, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek("{") {
+		f.selectionSet, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// parseArguments parses an optional `(name: value, ...)` list.
+func (p *parser) parseArguments(constant bool) ([]*argument, error) {
+	hasArgs, err := p.skip("(")
+	if err != nil || !hasArgs {
+		return nil, err
+	}
+
+	var args []*argument
+
+	for !p.peek(")") {
+		arg := &argument{}
+
+		arg.name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+
+		arg.value, err = p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return args, p.advance()
+}
+
+// parseDirectives parses any number of `@name(arguments)` directives.
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+
+	for p.peek("@") {
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		dir := &directive{}
+
+		dir.name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		dir.arguments, err = p.parseArguments(false)
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, dir)
+	}
+
+	return directives, nil
+}
+
+// parseValue parses an input value. Variables are rejected when constant is set.
+func (p *parser) parseValue(constant bool) (any, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+
+			err := p.advance()
+			if err != nil {
+				return nil, err
+			}
+
+			name, err := p.expectName()
+
+			return variable(name), err
+		case "[":
+			return p.parseList(constant)
+		case "{":
+			return p.parseObject(constant)
+		}
+	case tokenInt:
+		n, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, p.lex.errorf(tok.pos, "integer %s is out of range", tok.value)
+		}
+
+		return n, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.lex.errorf(tok.pos, "invalid float %s", tok.value)
+		}
+
+		return f, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		var value any
+
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = enumValue(tok.value)
+		}
+
+		return value, p.advance()
+	}
+
+	return nil, p.unexpected()
+}
+
+// parseList parses `[value, ...]`.
+func (p *parser) parseList(constant bool) (any, error) {
+	err := p.expect("[")
+	if err != nil {
+		return nil, err
+	}
+
+	list := []any{}
+
+	for !p.peek("]") {
+		value, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, value)
+	}
+
+	return list, p.advance()
+}
+
+// parseObject parses `{name: value, ...}`.
+func (p *parser) parseObject(constant bool) (any, error) {
+	err := p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+
+	object := map[string]any{}
+
+	for !p.peek("}") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+
+		object[name], err = p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return object, p.advance()
+}
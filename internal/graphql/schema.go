@@ -0,0 +1,260 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Type is a GraphQL output or input type: a *Scalar, *Object, *List or *NonNull.
+type Type interface {
+	String() string
+}
+
+// Schema is an executable GraphQL schema. Only query operations are supported.
+type Schema struct {
+	Query *Object
+	// MaxCost rejects queries whose cost exceeds it before they run; zero means no limit.
+	// Every selected field costs its Field.Cost, counting each alias and fragment use.
+	MaxCost int
+}
+
+// Object is an object type with a fixed set of fields.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// String returns the type name.
+func (o *Object) String() string {
+	return o.Name
+}
+
+// Field is a field of an object type.
+type Field struct {
+	Type Type
+	Args map[string]*Argument
+	// Resolve produces the field value. When nil, the value is read from the source:
+	// a map entry with the field name, or the struct field whose json name matches.
+	Resolve ResolveFunc
+	// Cost is what selecting the field adds to the query cost; zero counts as 1.
+	Cost int
+}
+
+// Argument declares an argument accepted by a field.
+type Argument struct {
+	Type         Type
+	DefaultValue any
+}
+
+// ResolveParams are passed to a field resolver.
+type ResolveParams struct {
+	Context context.Context
+	// Source is the value of the parent object; nil for root query fields.
+	Source any
+	// Args holds the coerced arguments. Arguments that were not given and have no default are absent.
+	Args map[string]any
+}
+
+// ResolveFunc resolves the value of a field.
+type ResolveFunc func(p ResolveParams) (any, error)
+
+// List is a list of values of another type.
+type List struct {
+	OfType Type
+}
+
+// String formats the type as it is written in a schema.
+func (l *List) String() string {
+	return "[" + l.OfType.String() + "]"
+}
+
+// NonNull marks another type as never being null.
+type NonNull struct {
+	OfType Type
+}
+
+// String formats the type as it is written in a schema.
+func (n *NonNull) String() string {
+	return n.OfType.String() + "!"
+}
+
+// Scalar is a leaf type.
+type Scalar struct {
+	Name string
+	// Serialize converts a resolved value to its JSON representation.
+	Serialize func(value any) (any, error)
+	// Parse converts an input value (a literal or a JSON decoded variable) to its Go representation.
+	Parse func(value any) (any, error)
+}
+
+// String returns the type name.
+func (s *Scalar) String() string {
+	return s.Name
+}
+
+// String is the built-in String scalar.
+var String = &Scalar{
+	Name: "String",
+	Serialize: func(value any) (any, error) {
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+
+		if s, ok := value.(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+
+		return nil, fmt.Errorf("cannot represent %v as String", value)
+	},
+	Parse: func(value any) (any, error) {
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("cannot represent %v as String", value)
+	},
+}
+
+// Int is the built-in 32-bit Int scalar.
+var Int = &Scalar{
+	Name: "Int",
+	Serialize: func(value any) (any, error) {
+		n, ok := toInt(value)
+		if !ok {
+			return nil, fmt.Errorf("cannot represent %v as Int", value)
+		}
+
+		return n, nil
+	},
+	Parse: func(value any) (any, error) {
+		n, ok := toInt(value)
+		if !ok {
+			return nil, fmt.Errorf("cannot represent %v as Int", value)
+		}
+
+		return n, nil
+	},
+}
+
+// Float is the built-in Float scalar.
+var Float = &Scalar{
+	Name: "Float",
+	Serialize: func(value any) (any, error) {
+		f, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("cannot represent %v as Float", value)
+		}
+
+		return f, nil
+	},
+	Parse: func(value any) (any, error) {
+		f, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("cannot represent %v as Float", value)
+		}
+
+		return f, nil
+	},
+}
+
+// Boolean is the built-in Boolean scalar.
+var Boolean = &Scalar{
+	Name: "Boolean",
+	Serialize: func(value any) (any, error) {
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+
+		return nil, fmt.Errorf("cannot represent %v as Boolean", value)
+	},
+	Parse: func(value any) (any, error) {
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+
+		return nil, fmt.Errorf("cannot represent %v as Boolean", value)
+	},
+}
+
+// ID is the built-in ID scalar, serialized as a string.
+var ID = &Scalar{
+	Name: "ID",
+	Serialize: func(value any) (any, error) {
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+
+		if n, ok := toInt(value); ok {
+			return strconv.Itoa(n), nil
+		}
+
+		return nil, fmt.Errorf("cannot represent %v as ID", value)
+	},
+	Parse: func(value any) (any, error) {
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+
+		if n, ok := toInt(value); ok {
+			return strconv.Itoa(n), nil
+		}
+
+		return nil, fmt.Errorf("cannot represent %v as ID", value)
+	},
+}
+
+// toInt converts integral numbers that fit in 32 bits to an int.
+func toInt(value any) (int, bool) {
+	v := reflect.ValueOf(value)
+
+	var n int64
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt32 {
+			return 0, false
+		}
+
+		n = int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != math.Trunc(f) {
+			return 0, false
+		}
+
+		if f < math.MinInt32 || f > math.MaxInt32 {
+			return 0, false
+		}
+
+		n = int64(f)
+	default:
+		return 0, false
+	}
+
+	if n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, false
+	}
+
+	return int(n), true
+}
+
+// toFloat converts any number to a float64.
+func toFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
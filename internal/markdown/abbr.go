@@ -0,0 +1,247 @@
+package markdown
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// abbreviationDefinition matches a `*[HTML]: HyperText Markup Language` line.
+var abbreviationDefinition = regexp.MustCompile(`^\*\[([^\]]+)\]:[ \t]*(.*?)\s*$`)
+
+// abbreviationsKey stores the abbreviations defined in a document on the parser context.
+var abbreviationsKey = parser.NewContextKey()
+
+// kindAbbreviation is the node kind of an abbreviation occurrence.
+var kindAbbreviation = ast.NewNodeKind("Abbreviation")
+
+// abbreviationNode is an inline node wrapping an abbreviated term with its expansion.
+type abbreviationNode struct {
+	ast.BaseInline
+	title []byte
+}
+
+// Kind implements ast.Node.
+func (n *abbreviationNode) Kind() ast.NodeKind {
+	return kindAbbreviation
+}
+
+// Dump implements ast.Node.
+func (n *abbreviationNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Title": string(n.title)}, nil)
+}
+
+// abbreviationExtension collects `*[TERM]: expansion` definitions and wraps every
+// whole-word occurrence of TERM in an <abbr> element. Definition lines are removed
+// from the output; code spans and code blocks are left untouched.
+type abbreviationExtension struct{}
+
+// Extend registers the definition, occurrence and rendering hooks with the markdown processor.
+func (e *abbreviationExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithParagraphTransformers(
+			util.Prioritized(&abbreviationParagraphTransformer{}, 150),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(&abbreviationASTTransformer{}, 999),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&abbreviationRenderer{}, 500),
+	))
+}
+
+// abbreviationParagraphTransformer strips abbreviation definitions out of paragraphs.
+type abbreviationParagraphTransformer struct{}
+
+// Transform records each definition line on the parser context and removes it from the paragraph.
+func (t *abbreviationParagraphTransformer) Transform(
+	node *ast.Paragraph,
+	reader text.Reader,
+	pc parser.Context,
+) {
+	lines := node.Lines()
+	kept := text.NewSegments()
+
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+
+		match := abbreviationDefinition.FindSubmatch(segment.Value(reader.Source()))
+		if match == nil {
+			kept.Append(segment)
+			continue
+		}
+
+		term := strings.TrimSpace(string(match[1]))
+		if term == "" {
+			kept.Append(segment)
+			continue
+		}
+
+		definitions, _ := pc.Get(abbreviationsKey).(map[string]string)
+		if definitions == nil {
+			definitions = make(map[string]string)
+			pc.Set(abbreviationsKey, definitions)
+		}
+
+		definitions[term] = string(match[2])
+	}
+
+	if kept.Len() == lines.Len() {
+		return
+	}
+
+	if kept.Len() == 0 {
+		node.Parent().RemoveChild(node.Parent(), node)
+		return
+	}
+
+	node.SetLines(kept)
+}
+
+// abbreviationASTTransformer wraps occurrences of defined abbreviations in abbreviation nodes.
+type abbreviationASTTransformer struct{}
+
+// Transform splits text nodes around every defined term once the whole document has been parsed.
+func (t *abbreviationASTTransformer) Transform(
+	doc *ast.Document,
+	reader text.Reader,
+	pc parser.Context,
+) {
+	definitions, _ := pc.Get(abbreviationsKey).(map[string]string)
+	if len(definitions) == 0 {
+		return
+	}
+
+	terms := make([]string, 0, len(definitions))
+	for term := range definitions {
+		terms = append(terms, regexp.QuoteMeta(term))
+	}
+
+	// Longer terms first so that "HTML5" wins over "HTML".
+	sort.Slice(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+
+	pattern := regexp.MustCompile(strings.Join(terms, "|"))
+	source := reader.Source()
+
+	var texts []*ast.Text
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch v := n.(type) {
+		case *ast.CodeSpan, *ast.RawHTML, *ast.AutoLink:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, v)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, node := range texts {
+		splitAbbreviations(node, source, pattern, definitions)
+	}
+}
+
+// splitAbbreviations replaces whole-word matches in node with abbreviation nodes.
+// The text before each match is inserted ahead of it and node keeps the remainder,
+// so line break flags stay on the last segment of the line.
+func splitAbbreviations(
+	node *ast.Text,
+	source []byte,
+	pattern *regexp.Regexp,
+	definitions map[string]string,
+) {
+	segment := node.Segment
+	value := segment.Value(source)
+	parent := node.Parent()
+
+	start := 0
+	for _, loc := range pattern.FindAllIndex(value, -1) {
+		if !isWordBoundary(value, loc[0], loc[1]) {
+			continue
+		}
+
+		if loc[0] > start {
+			before := ast.NewTextSegment(text.NewSegment(segment.Start+start, segment.Start+loc[0]))
+			parent.InsertBefore(parent, node, before)
+		}
+
+		abbr := &abbreviationNode{title: []byte(definitions[string(value[loc[0]:loc[1]])])}
+		abbr.AppendChild(abbr, ast.NewTextSegment(
+			text.NewSegment(segment.Start+loc[0], segment.Start+loc[1]),
+		))
+		parent.InsertBefore(parent, node, abbr)
+
+		start = loc[1]
+	}
+
+	if start > 0 {
+		node.Segment = segment.WithStart(segment.Start + start)
+	}
+}
+
+// isWordBoundary reports whether value[start:end] is not joined to a letter or digit on either side.
+func isWordBoundary(value []byte, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRune(value[:start])
+		if isWordRune(r) {
+			return false
+		}
+	}
+
+	if end < len(value) {
+		r, _ := utf8.DecodeRune(value[end:])
+		if isWordRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isWordRune reports whether r continues a word.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// abbreviationRenderer renders abbreviation nodes as <abbr title="...">.
+type abbreviationRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *abbreviationRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindAbbreviation, r.renderAbbreviation)
+}
+
+// renderAbbreviation writes the opening and closing <abbr> tags around the term.
+func (r *abbreviationRenderer) renderAbbreviation(
+	w util.BufWriter,
+	_ []byte,
+	node ast.Node,
+	entering bool,
+) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</abbr>")
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.WriteString(`<abbr title="`)
+	_, _ = w.Write(util.EscapeHTML(node.(*abbreviationNode).title))
+	_, _ = w.WriteString(`">`)
+
+	return ast.WalkContinue, nil
+}
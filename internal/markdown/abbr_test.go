@@ -0,0 +1,86 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_RenderHTML_Abbreviations(t *testing.T) {
+	renderer := NewRenderer(WithAbbreviations(true))
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"occurrence",
+			"Written in HTML.\n\n*[HTML]: HyperText Markup Language",
+			`<p>Written in <abbr title="HyperText Markup Language">HTML</abbr>.</p>`,
+		},
+		{
+			"every occurrence",
+			"*[API]: Application Programming Interface\n\nThe API docs list each API route.",
+			`<p>The <abbr title="Application Programming Interface">API</abbr> docs list each ` +
+				`<abbr title="Application Programming Interface">API</abbr> route.</p>`,
+		},
+		{
+			"whole words only",
+			"HTMLish and XHTML\n\n*[HTML]: HyperText Markup Language",
+			"<p>HTMLish and XHTML</p>",
+		},
+		{
+			"longest term wins",
+			"HTML5 and HTML\n*[HTML]: HyperText Markup Language\n*[HTML5]: HyperText Markup Language 5",
+			`<p><abbr title="HyperText Markup Language 5">HTML5</abbr> and ` +
+				`<abbr title="HyperText Markup Language">HTML</abbr></p>`,
+		},
+		{
+			"inside emphasis",
+			"**HTML** rocks\n\n*[HTML]: HyperText Markup Language",
+			`<p><strong><abbr title="HyperText Markup Language">HTML</abbr></strong> rocks</p>`,
+		},
+		{
+			"code span",
+			"`HTML`\n\n*[HTML]: HyperText Markup Language",
+			"<p><code>HTML</code></p>",
+		},
+		{
+			"escaped title",
+			"R&D\n\n*[R&D]: Research & \"Development\"",
+			`<abbr title="Research &amp; &#34;Development&#34;">R&amp;D</abbr>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := renderer.RenderHTML(context.Background(), &buf, tt.content)
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), tt.want)
+			assert.NotContains(t, buf.String(), "*[")
+		})
+	}
+}
+
+func TestRenderer_RenderHTML_AbbreviationsDisabled(t *testing.T) {
+	renderer := NewRenderer()
+
+	var buf bytes.Buffer
+
+	err := renderer.RenderHTML(context.Background(), &buf, "HTML\n\n*[HTML]: HyperText Markup Language")
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "<abbr")
+}
+
+func TestRenderer_PlainText_Abbreviations(t *testing.T) {
+	renderer := NewRenderer(WithAbbreviations(true))
+
+	content := "# Intro\n\nWritten in HTML.\n\n*[HTML]: HyperText Markup Language"
+	assert.Equal(t, "Intro\nWritten in HTML.", renderer.PlainText(content))
+}
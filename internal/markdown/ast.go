@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Node is a markdown syntax tree node in a form that can be serialized to JSON, so clients
+// can render documents with their own components.
+type Node struct {
+	Type       string         `json:"type"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Text       string         `json:"text,omitempty"`
+	Children   []*Node        `json:"children,omitempty"`
+}
+
+// AST parses markdown content and returns its syntax tree, rooted at a "Document" node.
+// The tree reflects the same sanitization as RenderHTML: raw HTML is sanitized and link
+// and image destinations with disallowed URL schemes are dropped.
+func (r *Renderer) AST(content string) *Node {
+	source := []byte(content)
+	doc := r.md.Parser().Parse(text.NewReader(source))
+
+	return r.astNode(doc, source)
+}
+
+// astNode converts a goldmark node and its children.
+func (r *Renderer) astNode(node ast.Node, source []byte) *Node {
+	n := &Node{Type: node.Kind().String()}
+
+	for _, attr := range node.Attributes() {
+		switch v := attr.Value.(type) {
+		case []byte:
+			n.setAttribute(string(attr.Name), string(v))
+		default:
+			n.setAttribute(string(attr.Name), v)
+		}
+	}
+
+	switch v := node.(type) {
+	case *ast.Heading:
+		n.setAttribute("level", v.Level)
+	case *ast.Emphasis:
+		n.setAttribute("level", v.Level)
+	case *ast.Link:
+		n.setOptionalAttribute("destination", safeURL(string(v.Destination)))
+		n.setOptionalAttribute("title", string(v.Title))
+	case *ast.Image:
+		n.setOptionalAttribute("destination", safeURL(string(v.Destination)))
+		n.setOptionalAttribute("title", string(v.Title))
+	case *ast.AutoLink:
+		n.setOptionalAttribute("destination", safeURL(string(v.URL(source))))
+		n.Text = string(v.Label(source))
+	case *ast.List:
+		n.setAttribute("ordered", v.IsOrdered())
+		if v.IsOrdered() {
+			n.setAttribute("start", v.Start)
+		}
+	case *ast.FencedCodeBlock:
+		n.setOptionalAttribute("language", string(v.Language(source)))
+		n.Text = string(v.Lines().Value(source))
+	case *ast.CodeBlock:
+		n.Text = string(v.Lines().Value(source))
+	case *ast.HTMLBlock:
+		html := v.Lines().Value(source)
+		if v.HasClosure() {
+			html = append(html, v.ClosureLine.Value(source)...)
+		}
+		n.Text = r.sanitizer.Sanitize(string(html))
+	case *ast.RawHTML:
+		n.Text = r.sanitizer.Sanitize(string(v.Segments.Value(source)))
+	case *ast.Text:
+		n.Text = string(v.Segment.Value(source))
+		if v.SoftLineBreak() {
+			n.setAttribute("softLineBreak", true)
+		}
+		if v.HardLineBreak() {
+			n.setAttribute("hardLineBreak", true)
+		}
+	case *ast.String:
+		n.Text = string(v.Value)
+	case *east.TableCell:
+		if v.Alignment != east.AlignNone {
+			n.setAttribute("align", v.Alignment.String())
+		}
+	case *east.TaskCheckBox:
+		n.setAttribute("checked", v.IsChecked)
+	case *calloutNode:
+		n.setAttribute("calloutType", v.calloutType)
+	}
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		c := r.astNode(child, source)
+
+		// The parser splits text at characters that might start inline syntax; clients
+		// expect one node per run of text, so plain neighbours are joined back together.
+		last := len(n.Children) - 1
+		if last >= 0 && c.isPlainText() && n.Children[last].isPlainText() {
+			n.Children[last].Text += c.Text
+			continue
+		}
+
+		n.Children = append(n.Children, c)
+	}
+
+	return n
+}
+
+// isPlainText reports whether the node is text without line breaks.
+func (n *Node) isPlainText() bool {
+	return n.Type == ast.KindText.String() && len(n.Attributes) == 0
+}
+
+// setAttribute sets an attribute of the node.
+func (n *Node) setAttribute(name string, value any) {
+	if n.Attributes == nil {
+		n.Attributes = make(map[string]any)
+	}
+
+	n.Attributes[name] = value
+}
+
+// setOptionalAttribute sets a string attribute of the node unless it is empty.
+func (n *Node) setOptionalAttribute(name string, value string) {
+	if value != "" {
+		n.setAttribute(name, value)
+	}
+}
+
+// safeURL returns dest when the sanitizer would keep it in a link, or "" otherwise. Like the
+// UGC policy, it allows relative URLs and the http, https and mailto schemes.
+func safeURL(dest string) string {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return ""
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return dest
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,64 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_AST(t *testing.T) {
+	renderer := NewRenderer()
+
+	doc := renderer.AST("# Getting Started\n\nSee [the FAQ](/wiki/faq \"Questions\") and " +
+		"[bad](javascript:alert(1)).\n\n<script>alert(1)</script>\n")
+
+	assert.Equal(t, "Document", doc.Type)
+	require.Len(t, doc.Children, 3)
+
+	heading := doc.Children[0]
+	assert.Equal(t, "Heading", heading.Type)
+	assert.Equal(t, 1, heading.Attributes["level"])
+	assert.Equal(t, "getting-started", heading.Attributes["id"])
+	require.Len(t, heading.Children, 1)
+	assert.Equal(t, "Getting Started", heading.Children[0].Text)
+
+	paragraph := doc.Children[1]
+	require.Len(t, paragraph.Children, 5)
+
+	link := paragraph.Children[1]
+	assert.Equal(t, "Link", link.Type)
+	assert.Equal(t, "/wiki/faq", link.Attributes["destination"])
+	assert.Equal(t, "Questions", link.Attributes["title"])
+	assert.Equal(t, "the FAQ", link.Children[0].Text)
+
+	unsafe := paragraph.Children[3]
+	assert.Equal(t, "Link", unsafe.Type)
+	assert.Empty(t, unsafe.Attributes["destination"], "disallowed schemes are dropped")
+
+	html := doc.Children[2]
+	assert.Equal(t, "HTMLBlock", html.Type)
+	assert.NotContains(t, html.Text, "<script>")
+}
+
+func TestRenderer_ASTLists(t *testing.T) {
+	renderer := NewRenderer()
+
+	doc := renderer.AST("3. one\n4. two\n\n- [x] done\n\n```go\nfmt.Println()\n```\n")
+	require.Len(t, doc.Children, 3)
+
+	ordered := doc.Children[0]
+	assert.Equal(t, "List", ordered.Type)
+	assert.Equal(t, true, ordered.Attributes["ordered"])
+	assert.Equal(t, 3, ordered.Attributes["start"])
+	assert.Len(t, ordered.Children, 2)
+
+	task := doc.Children[1].Children[0].Children[0].Children[0]
+	assert.Equal(t, "TaskCheckBox", task.Type)
+	assert.Equal(t, true, task.Attributes["checked"])
+
+	code := doc.Children[2]
+	assert.Equal(t, "FencedCodeBlock", code.Type)
+	assert.Equal(t, "go", code.Attributes["language"])
+	assert.Equal(t, "fmt.Println()\n", code.Text)
+}
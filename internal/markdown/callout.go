@@ -0,0 +1,159 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// calloutMarker matches the `[!NOTE]` line that starts a GitHub-style callout.
+var calloutMarker = regexp.MustCompile(`(?i)^[ \t]*\[!(note|tip|important|warning|caution)\]\s*$`)
+
+// calloutClassPattern matches the class attributes written by the callout renderer.
+var calloutClassPattern = regexp.MustCompile(`^(callout callout-(note|tip|important|warning|caution)|callout-title)$`)
+
+// kindCallout is the node kind of a callout.
+var kindCallout = ast.NewNodeKind("Callout")
+
+// calloutNode is a blockquote that starts with a callout marker. Its children are the
+// blockquote content after the marker.
+type calloutNode struct {
+	ast.BaseBlock
+	calloutType string
+}
+
+// Kind implements ast.Node.
+func (n *calloutNode) Kind() ast.NodeKind {
+	return kindCallout
+}
+
+// Dump implements ast.Node.
+func (n *calloutNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.calloutType}, nil)
+}
+
+// calloutExtension renders blockquotes whose first line is `[!NOTE]`, `[!TIP]`,
+// `[!IMPORTANT]`, `[!WARNING]` or `[!CAUTION]` as titled callouts, like GitHub does.
+type calloutExtension struct{}
+
+// Extend registers the blockquote transformer and the callout renderer with the markdown processor.
+func (e *calloutExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&calloutTransformer{}, 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&calloutRenderer{}, 500),
+	))
+}
+
+// calloutTransformer replaces marked blockquotes with callout nodes.
+type calloutTransformer struct{}
+
+// Transform converts every top-level or nested blockquote that starts with a callout marker.
+func (t *calloutTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	var quotes []*ast.Blockquote
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Kind() == ast.KindBlockquote {
+			quotes = append(quotes, n.(*ast.Blockquote))
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, quote := range quotes {
+		calloutType := stripCalloutMarker(quote, reader.Source())
+		if calloutType == "" {
+			continue
+		}
+
+		callout := &calloutNode{calloutType: calloutType}
+		for child := quote.FirstChild(); child != nil; child = quote.FirstChild() {
+			callout.AppendChild(callout, child)
+		}
+
+		quote.Parent().ReplaceChild(quote.Parent(), quote, callout)
+	}
+}
+
+// stripCalloutMarker removes the marker line from the first paragraph of quote and returns
+// the lower-case callout type, or returns "" when the blockquote is not a callout.
+func stripCalloutMarker(quote *ast.Blockquote, source []byte) string {
+	paragraph, ok := quote.FirstChild().(*ast.Paragraph)
+	if !ok || paragraph.Lines().Len() == 0 {
+		return ""
+	}
+
+	lines := paragraph.Lines()
+	first := lines.At(0)
+
+	match := calloutMarker.FindSubmatch(first.Value(source))
+	if match == nil {
+		return ""
+	}
+
+	// The marker is parsed as plain text, possibly split into several nodes; anything
+	// else on the line (such as a link to a `[!note]: ...` reference) is not a callout.
+	var marker []ast.Node
+	for child := paragraph.FirstChild(); child != nil; child = child.NextSibling() {
+		textNode, ok := child.(*ast.Text)
+		if !ok {
+			return ""
+		}
+
+		marker = append(marker, child)
+		if textNode.Segment.Stop >= first.Stop || textNode.SoftLineBreak() || textNode.HardLineBreak() {
+			break
+		}
+	}
+
+	for _, child := range marker {
+		paragraph.RemoveChild(paragraph, child)
+	}
+
+	if paragraph.ChildCount() == 0 {
+		quote.RemoveChild(quote, paragraph)
+	} else {
+		rest := text.NewSegments()
+		for i := 1; i < lines.Len(); i++ {
+			rest.Append(lines.At(i))
+		}
+		paragraph.SetLines(rest)
+	}
+
+	return strings.ToLower(string(match[1]))
+}
+
+// calloutRenderer renders callout nodes as a <div> with a title paragraph.
+type calloutRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *calloutRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindCallout, r.renderCallout)
+}
+
+// renderCallout writes the callout container and title around the callout body.
+func (r *calloutRenderer) renderCallout(
+	w util.BufWriter,
+	_ []byte,
+	node ast.Node,
+	entering bool,
+) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</div>\n")
+		return ast.WalkContinue, nil
+	}
+
+	calloutType := node.(*calloutNode).calloutType
+
+	_, _ = w.WriteString(`<div class="callout callout-` + calloutType + `">` + "\n")
+	_, _ = w.WriteString(`<p class="callout-title">` + strings.ToUpper(calloutType[:1]) + calloutType[1:] + "</p>\n")
+
+	return ast.WalkContinue, nil
+}
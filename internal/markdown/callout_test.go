@@ -0,0 +1,84 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_RenderHTML_Callouts(t *testing.T) {
+	renderer := NewRenderer()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"note",
+			"> [!NOTE]\n> Back up the database first.",
+			"<div class=\"callout callout-note\">\n<p class=\"callout-title\">Note</p>\n" +
+				"<p>Back up the database first.</p>\n</div>",
+		},
+		{
+			"warning",
+			"> [!WARNING]\n> This deletes **every** draft.\n>\n> There is no undo.",
+			"<div class=\"callout callout-warning\">\n<p class=\"callout-title\">Warning</p>\n" +
+				"<p>This deletes <strong>every</strong> draft.</p>\n<p>There is no undo.</p>\n</div>",
+		},
+		{
+			"lower-case marker",
+			"> [!tip]\n> Press `?` for shortcuts.",
+			"<div class=\"callout callout-tip\">\n<p class=\"callout-title\">Tip</p>\n" +
+				"<p>Press <code>?</code> for shortcuts.</p>\n</div>",
+		},
+		{
+			"marker in its own paragraph",
+			"> [!IMPORTANT]\n>\n> Read this.",
+			"<div class=\"callout callout-important\">\n<p class=\"callout-title\">Important</p>\n" +
+				"<p>Read this.</p>\n</div>",
+		},
+		{
+			"unknown type",
+			"> [!TODO]\n> Not a callout.",
+			"<blockquote>\n<p>[!TODO]\nNot a callout.</p>\n</blockquote>",
+		},
+		{
+			"marker not on its own line",
+			"> [!NOTE] inline\n> text",
+			"<blockquote>\n<p>[!NOTE] inline\ntext</p>\n</blockquote>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := renderer.RenderHTML(context.Background(), &buf, tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(bytes.TrimSpace(buf.Bytes())))
+		})
+	}
+}
+
+func TestRenderer_RenderHTML_CalloutClassesSanitized(t *testing.T) {
+	renderer := NewRenderer()
+
+	var buf bytes.Buffer
+	err := renderer.RenderHTML(context.Background(), &buf, `<div class="callout callout-note evil">Hi</div>`)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "class=")
+}
+
+func TestRenderer_AST_Callout(t *testing.T) {
+	doc := NewRenderer().AST("> [!CAUTION]\n> Hot surface.")
+
+	require.Len(t, doc.Children, 1)
+	callout := doc.Children[0]
+	assert.Equal(t, "Callout", callout.Type)
+	assert.Equal(t, "caution", callout.Attributes["calloutType"])
+	require.Len(t, callout.Children, 1)
+	assert.Equal(t, "Paragraph", callout.Children[0].Type)
+}
@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// maxShortcodeLength bounds how far the parser scans for a closing colon.
+const maxShortcodeLength = 40
+
+// emojiExtension replaces :shortcode: sequences with the matching Unicode emoji.
+// Unknown shortcodes, code spans and code blocks are left untouched.
+type emojiExtension struct{}
+
+// Extend registers the shortcode inline parser with the markdown parser.
+func (e *emojiExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(&emojiParser{}, 999),
+	))
+}
+
+// emojiParser is an inline parser triggered by ':'.
+type emojiParser struct{}
+
+// Trigger returns the characters that start a shortcode.
+func (p *emojiParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+// Parse consumes a known :shortcode: and returns its emoji as a text node.
+func (p *emojiParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if len(line) < 3 {
+		return nil
+	}
+
+	end := 1
+	for end < len(line) && end <= maxShortcodeLength && isShortcodeChar(line[end]) {
+		end++
+	}
+
+	if end == 1 || end >= len(line) || line[end] != ':' {
+		return nil
+	}
+
+	emoji, ok := emojiShortcodes[string(line[1:end])]
+	if !ok {
+		return nil
+	}
+
+	block.Advance(end + 1)
+
+	return ast.NewString([]byte(emoji))
+}
+
+// isShortcodeChar reports whether c may appear between the colons of a shortcode.
+func isShortcodeChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' || c == '+' || c == '-'
+}
@@ -0,0 +1,403 @@
+package markdown
+
+// emojiShortcodes maps GitHub-style shortcodes to their Unicode emoji.
+var emojiShortcodes = map[string]string{
+	// Faces
+	"smile":                        "😄",
+	"smiley":                       "😃",
+	"grinning":                     "😀",
+	"grin":                         "😁",
+	"laughing":                     "😆",
+	"satisfied":                    "😆",
+	"sweat_smile":                  "😅",
+	"joy":                          "😂",
+	"rofl":                         "🤣",
+	"slightly_smiling_face":        "🙂",
+	"upside_down_face":             "🙃",
+	"wink":                         "😉",
+	"blush":                        "😊",
+	"innocent":                     "😇",
+	"heart_eyes":                   "😍",
+	"star_struck":                  "🤩",
+	"kissing_heart":                "😘",
+	"yum":                          "😋",
+	"stuck_out_tongue":             "😛",
+	"stuck_out_tongue_winking_eye": "😜",
+	"thinking":                     "🤔",
+	"face_with_raised_eyebrow":     "🤨",
+	"neutral_face":                 "😐",
+	"expressionless":               "😑",
+	"no_mouth":                     "😶",
+	"smirk":                        "😏",
+	"unamused":                     "😒",
+	"roll_eyes":                    "🙄",
+	"grimacing":                    "😬",
+	"relieved":                     "😌",
+	"pensive":                      "😔",
+	"sleepy":                       "😪",
+	"sleeping":                     "😴",
+	"mask":                         "😷",
+	"nerd_face":                    "🤓",
+	"sunglasses":                   "😎",
+	"confused":                     "😕",
+	"worried":                      "😟",
+	"frowning_face":                "☹️",
+	"open_mouth":                   "😮",
+	"astonished":                   "😲",
+	"flushed":                      "😳",
+	"pleading_face":                "🥺",
+	"cry":                          "😢",
+	"sob":                          "😭",
+	"scream":                       "😱",
+	"confounded":                   "😖",
+	"disappointed":                 "😞",
+	"sweat":                        "😓",
+	"weary":                        "😩",
+	"tired_face":                   "😫",
+	"yawning_face":                 "🥱",
+	"triumph":                      "😤",
+	"rage":                         "😡",
+	"angry":                        "😠",
+	"exploding_head":               "🤯",
+	"partying_face":                "🥳",
+	"skull":                        "💀",
+	"poop":                         "💩",
+	"clown_face":                   "🤡",
+	"ghost":                        "👻",
+	"alien":                        "👽",
+	"robot":                        "🤖",
+
+	// Gestures and people
+	"wave":            "👋",
+	"raised_hand":     "✋",
+	"ok_hand":         "👌",
+	"v":               "✌️",
+	"crossed_fingers": "🤞",
+	"+1":              "👍",
+	"thumbsup":        "👍",
+	"-1":              "👎",
+	"thumbsdown":      "👎",
+	"fist":            "✊",
+	"punch":           "👊",
+	"clap":            "👏",
+	"raised_hands":    "🙌",
+	"open_hands":      "👐",
+	"handshake":       "🤝",
+	"pray":            "🙏",
+	"point_up":        "☝️",
+	"point_down":      "👇",
+	"point_left":      "👈",
+	"point_right":     "👉",
+	"muscle":          "💪",
+	"eyes":            "👀",
+	"brain":           "🧠",
+	"bow":             "🙇",
+	"facepalm":        "🤦",
+	"shrug":           "🤷",
+
+	// Hearts and symbols
+	"heart":                       "❤️",
+	"orange_heart":                "🧡",
+	"yellow_heart":                "💛",
+	"green_heart":                 "💚",
+	"blue_heart":                  "💙",
+	"purple_heart":                "💜",
+	"black_heart":                 "🖤",
+	"broken_heart":                "💔",
+	"sparkling_heart":             "💖",
+	"100":                         "💯",
+	"boom":                        "💥",
+	"collision":                   "💥",
+	"dizzy":                       "💫",
+	"zzz":                         "💤",
+	"speech_balloon":              "💬",
+	"thought_balloon":             "💭",
+	"white_check_mark":            "✅",
+	"heavy_check_mark":            "✔️",
+	"ballot_box_with_check":       "☑️",
+	"x":                           "❌",
+	"negative_squared_cross_mark": "❎",
+	"heavy_plus_sign":             "➕",
+	"heavy_minus_sign":            "➖",
+	"question":                    "❓",
+	"grey_question":               "❔",
+	"exclamation":                 "❗",
+	"grey_exclamation":            "❕",
+	"bangbang":                    "‼️",
+	"warning":                     "⚠️",
+	"no_entry":                    "⛔",
+	"no_entry_sign":               "🚫",
+	"stop_sign":                   "🛑",
+	"construction":                "🚧",
+	"recycle":                     "♻️",
+	"infinity":                    "♾️",
+	"information_source":          "ℹ️",
+	"new":                         "🆕",
+	"free":                        "🆓",
+	"up":                          "🆙",
+	"cool":                        "🆒",
+	"ok":                          "🆗",
+	"sos":                         "🆘",
+	"red_circle":                  "🔴",
+	"large_orange_circle":         "🟠",
+	"yellow_circle":               "🟡",
+	"green_circle":                "🟢",
+	"large_blue_circle":           "🔵",
+	"white_circle":                "⚪",
+	"black_circle":                "⚫",
+	"arrow_up":                    "⬆️",
+	"arrow_down":                  "⬇️",
+	"arrow_left":                  "⬅️",
+	"arrow_right":                 "➡️",
+	"arrows_counterclockwise":     "🔄",
+
+	// Objects and activities
+	"tada":                       "🎉",
+	"confetti_ball":              "🎊",
+	"balloon":                    "🎈",
+	"gift":                       "🎁",
+	"trophy":                     "🏆",
+	"medal_sports":               "🏅",
+	"1st_place_medal":            "🥇",
+	"dart":                       "🎯",
+	"game_die":                   "🎲",
+	"art":                        "🎨",
+	"musical_note":               "🎵",
+	"headphones":                 "🎧",
+	"microphone":                 "🎤",
+	"movie_camera":               "🎥",
+	"camera":                     "📷",
+	"iphone":                     "📱",
+	"computer":                   "💻",
+	"keyboard":                   "⌨️",
+	"desktop_computer":           "🖥️",
+	"printer":                    "🖨️",
+	"floppy_disk":                "💾",
+	"cd":                         "💿",
+	"battery":                    "🔋",
+	"electric_plug":              "🔌",
+	"bulb":                       "💡",
+	"flashlight":                 "🔦",
+	"wrench":                     "🔧",
+	"hammer":                     "🔨",
+	"hammer_and_wrench":          "🛠️",
+	"gear":                       "⚙️",
+	"nut_and_bolt":               "🔩",
+	"link":                       "🔗",
+	"paperclip":                  "📎",
+	"pushpin":                    "📌",
+	"round_pushpin":              "📍",
+	"scissors":                   "✂️",
+	"pencil":                     "📝",
+	"memo":                       "📝",
+	"pencil2":                    "✏️",
+	"black_nib":                  "✒️",
+	"book":                       "📖",
+	"open_book":                  "📖",
+	"books":                      "📚",
+	"notebook":                   "📓",
+	"bookmark":                   "🔖",
+	"clipboard":                  "📋",
+	"calendar":                   "📆",
+	"date":                       "📅",
+	"card_index":                 "📇",
+	"chart_with_upwards_trend":   "📈",
+	"chart_with_downwards_trend": "📉",
+	"bar_chart":                  "📊",
+	"file_folder":                "📁",
+	"open_file_folder":           "📂",
+	"package":                    "📦",
+	"email":                      "📧",
+	"envelope":                   "✉️",
+	"inbox_tray":                 "📥",
+	"outbox_tray":                "📤",
+	"mailbox":                    "📫",
+	"bell":                       "🔔",
+	"no_bell":                    "🔕",
+	"mega":                       "📣",
+	"loudspeaker":                "📢",
+	"mag":                        "🔍",
+	"mag_right":                  "🔎",
+	"lock":                       "🔒",
+	"unlock":                     "🔓",
+	"key":                        "🔑",
+	"shield":                     "🛡️",
+	"hourglass":                  "⌛",
+	"hourglass_flowing_sand":     "⏳",
+	"watch":                      "⌚",
+	"alarm_clock":                "⏰",
+	"stopwatch":                  "⏱️",
+	"moneybag":                   "💰",
+	"dollar":                     "💵",
+	"credit_card":                "💳",
+	"gem":                        "💎",
+	"label":                      "🏷️",
+	"bookmark_tabs":              "📑",
+	"triangular_flag_on_post":    "🚩",
+	"checkered_flag":             "🏁",
+	"white_flag":                 "🏳️",
+	"rocket":                     "🚀",
+	"airplane":                   "✈️",
+	"car":                        "🚗",
+	"bike":                       "🚲",
+	"ship":                       "🚢",
+	"house":                      "🏠",
+	"office":                     "🏢",
+	"hospital":                   "🏥",
+	"bank":                       "🏦",
+	"school":                     "🏫",
+	"hotel":                      "🏨",
+	"world_map":                  "🗺️",
+	"earth_americas":             "🌎",
+	"earth_africa":               "🌍",
+	"earth_asia":                 "🌏",
+	"globe_with_meridians":       "🌐",
+	"pill":                       "💊",
+	"syringe":                    "💉",
+	"dna":                        "🧬",
+	"microscope":                 "🔬",
+	"telescope":                  "🔭",
+	"test_tube":                  "🧪",
+	"magnet":                     "🧲",
+	"bomb":                       "💣",
+	"crystal_ball":               "🔮",
+	"lipstick":                   "💄",
+	"ring":                       "💍",
+	"crown":                      "👑",
+	"tophat":                     "🎩",
+	"mortar_board":               "🎓",
+	"briefcase":                  "💼",
+	"eyeglasses":                 "👓",
+	"necktie":                    "👔",
+	"shirt":                      "👕",
+	"jeans":                      "👖",
+	"running_shoe":               "👟",
+
+	// Nature and weather
+	"sunny":            "☀️",
+	"cloud":            "☁️",
+	"umbrella":         "☔",
+	"zap":              "⚡",
+	"snowflake":        "❄️",
+	"snowman":          "⛄",
+	"fire":             "🔥",
+	"droplet":          "💧",
+	"ocean":            "🌊",
+	"rainbow":          "🌈",
+	"star":             "⭐",
+	"star2":            "🌟",
+	"sparkles":         "✨",
+	"crescent_moon":    "🌙",
+	"full_moon":        "🌕",
+	"new_moon":         "🌑",
+	"seedling":         "🌱",
+	"evergreen_tree":   "🌲",
+	"deciduous_tree":   "🌳",
+	"palm_tree":        "🌴",
+	"cactus":           "🌵",
+	"four_leaf_clover": "🍀",
+	"maple_leaf":       "🍁",
+	"fallen_leaf":      "🍂",
+	"herb":             "🌿",
+	"rose":             "🌹",
+	"sunflower":        "🌻",
+	"tulip":            "🌷",
+	"cherry_blossom":   "🌸",
+	"mushroom":         "🍄",
+	"bug":              "🐛",
+	"beetle":           "🪲",
+	"bee":              "🐝",
+	"butterfly":        "🦋",
+	"snail":            "🐌",
+	"spider":           "🕷️",
+	"turtle":           "🐢",
+	"snake":            "🐍",
+	"octopus":          "🐙",
+	"fish":             "🐟",
+	"whale":            "🐳",
+	"dolphin":          "🐬",
+	"crab":             "🦀",
+	"penguin":          "🐧",
+	"bird":             "🐦",
+	"eagle":            "🦅",
+	"owl":              "🦉",
+	"chicken":          "🐔",
+	"hatching_chick":   "🐣",
+	"dog":              "🐶",
+	"cat":              "🐱",
+	"mouse":            "🐭",
+	"rabbit":           "🐰",
+	"fox_face":         "🦊",
+	"bear":             "🐻",
+	"panda_face":       "🐼",
+	"koala":            "🐨",
+	"tiger":            "🐯",
+	"lion":             "🦁",
+	"cow":              "🐮",
+	"pig":              "🐷",
+	"frog":             "🐸",
+	"monkey":           "🐒",
+	"see_no_evil":      "🙈",
+	"hear_no_evil":     "🙉",
+	"speak_no_evil":    "🙊",
+	"horse":            "🐴",
+	"unicorn":          "🦄",
+	"elephant":         "🐘",
+	"camel":            "🐫",
+	"giraffe":          "🦒",
+	"sloth":            "🦥",
+	"dragon":           "🐉",
+	"t-rex":            "🦖",
+	"paw_prints":       "🐾",
+
+	// Food and drink
+	"apple":            "🍎",
+	"green_apple":      "🍏",
+	"banana":           "🍌",
+	"cherries":         "🍒",
+	"grapes":           "🍇",
+	"lemon":            "🍋",
+	"strawberry":       "🍓",
+	"watermelon":       "🍉",
+	"peach":            "🍑",
+	"pineapple":        "🍍",
+	"avocado":          "🥑",
+	"tomato":           "🍅",
+	"carrot":           "🥕",
+	"corn":             "🌽",
+	"hot_pepper":       "🌶️",
+	"bread":            "🍞",
+	"cheese":           "🧀",
+	"egg":              "🥚",
+	"bacon":            "🥓",
+	"hamburger":        "🍔",
+	"fries":            "🍟",
+	"pizza":            "🍕",
+	"hotdog":           "🌭",
+	"taco":             "🌮",
+	"burrito":          "🌯",
+	"ramen":            "🍜",
+	"spaghetti":        "🍝",
+	"sushi":            "🍣",
+	"rice":             "🍚",
+	"popcorn":          "🍿",
+	"doughnut":         "🍩",
+	"cookie":           "🍪",
+	"cake":             "🍰",
+	"birthday":         "🎂",
+	"chocolate_bar":    "🍫",
+	"candy":            "🍬",
+	"lollipop":         "🍭",
+	"ice_cream":        "🍨",
+	"coffee":           "☕",
+	"tea":              "🍵",
+	"beer":             "🍺",
+	"beers":            "🍻",
+	"wine_glass":       "🍷",
+	"cocktail":         "🍸",
+	"tropical_drink":   "🍹",
+	"champagne":        "🍾",
+	"clinking_glasses": "🥂",
+	"milk_glass":       "🥛",
+	"cup_with_straw":   "🥤",
+}
@@ -0,0 +1,52 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_RenderHTML_Emoji(t *testing.T) {
+	renderer := NewRenderer(WithEmoji(true))
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"shortcode", "Hello :smile:", "<p>Hello 😄</p>"},
+		{"adjacent", ":tada::+1:", "<p>🎉👍</p>"},
+		{"unknown shortcode", "Meet at :not_an_emoji: 10:30:45", "<p>Meet at :not_an_emoji: 10:30:45</p>"},
+		{"code span", "`:smile:`", "<p><code>:smile:</code></p>"},
+		{"code block", "```\n:smile:\n```", "<pre><code>:smile:\n</code></pre>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := renderer.RenderHTML(context.Background(), &buf, tt.content)
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), tt.want)
+		})
+	}
+}
+
+func TestRenderer_RenderHTML_EmojiDisabled(t *testing.T) {
+	renderer := NewRenderer()
+
+	var buf bytes.Buffer
+
+	err := renderer.RenderHTML(context.Background(), &buf, "Hello :smile:")
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Hello :smile:")
+}
+
+func TestRenderer_PlainText_Emoji(t *testing.T) {
+	renderer := NewRenderer(WithEmoji(true))
+
+	assert.Equal(t, "Launch day 🚀", renderer.PlainText("# Launch day :rocket:"))
+}
@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// lazyImageExtension marks every markdown image with loading="lazy", so browsers defer
+// fetching images until they are about to scroll into view.
+type lazyImageExtension struct{}
+
+// Extend registers the image transformer with the markdown parser.
+func (e *lazyImageExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&lazyImageTransformer{}, 999),
+	))
+}
+
+// lazyImageTransformer adds the loading attribute to image nodes.
+type lazyImageTransformer struct{}
+
+// Transform sets loading="lazy" on images that do not already specify a loading mode.
+func (t *lazyImageTransformer) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Kind() != ast.KindImage {
+			return ast.WalkContinue, nil
+		}
+
+		_, ok := n.AttributeString("loading")
+		if !ok {
+			n.SetAttributeString("loading", []byte("lazy"))
+		}
+
+		return ast.WalkSkipChildren, nil
+	})
+}
@@ -3,53 +3,183 @@ package markdown
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"regexp"
+	"time"
 
+	"github.com/jellydator/ttlcache/v3"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 )
 
+const (
+	// renderCacheTTL is how long rendered HTML is kept for a given markdown input.
+	renderCacheTTL = time.Hour
+	// renderCacheSize is the maximum number of rendered documents kept in memory.
+	renderCacheSize = 500
+)
+
+// imageLoadingPattern matches the values of the img loading attribute.
+var imageLoadingPattern = regexp.MustCompile(`^(lazy|eager)$`)
+
 // Renderer handles the conversion of markdown to other formats.
 type Renderer struct {
 	md        goldmark.Markdown
 	sanitizer *bluemonday.Policy
+	cache     *ttlcache.Cache[string, []byte]
+}
+
+// rendererOptions holds the optional features of a Renderer.
+type rendererOptions struct {
+	emoji           bool
+	definitionLists bool
+	abbreviations   bool
+	hardWraps       bool
+	lazyImages      bool
+}
+
+// Option configures optional Renderer features.
+type Option func(*rendererOptions)
+
+// WithEmoji toggles replacing :shortcode: sequences such as :tada: with Unicode emoji.
+func WithEmoji(enabled bool) Option {
+	return func(o *rendererOptions) {
+		o.emoji = enabled
+	}
+}
+
+// WithDefinitionLists toggles "Term\n: definition" definition lists.
+func WithDefinitionLists(enabled bool) Option {
+	return func(o *rendererOptions) {
+		o.definitionLists = enabled
+	}
+}
+
+// WithAbbreviations toggles "*[HTML]: HyperText Markup Language" abbreviation definitions,
+// which wrap each occurrence of the term in an <abbr> element.
+func WithAbbreviations(enabled bool) Option {
+	return func(o *rendererOptions) {
+		o.abbreviations = enabled
+	}
+}
+
+// WithHardWraps toggles rendering single newlines inside a paragraph as <br> line breaks,
+// as GitHub does, instead of the CommonMark default of a space.
+func WithHardWraps(enabled bool) Option {
+	return func(o *rendererOptions) {
+		o.hardWraps = enabled
+	}
+}
+
+// WithLazyImages toggles adding loading="lazy" to rendered images, so offscreen images
+// are only fetched when the reader scrolls to them.
+func WithLazyImages(enabled bool) Option {
+	return func(o *rendererOptions) {
+		o.lazyImages = enabled
+	}
 }
 
 // NewRenderer creates a new instance of the Markdown Renderer.
-func NewRenderer() *Renderer {
+func NewRenderer(opts ...Option) *Renderer {
+	var options rendererOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	extensions := []goldmark.Extender{extension.GFM, &calloutExtension{}}
+	if options.emoji {
+		extensions = append(extensions, &emojiExtension{})
+	}
+
+	if options.definitionLists {
+		extensions = append(extensions, extension.DefinitionList)
+	}
+
+	if options.abbreviations {
+		extensions = append(extensions, &abbreviationExtension{})
+	}
+
+	if options.lazyImages {
+		extensions = append(extensions, &lazyImageExtension{})
+	}
+
+	rendererOpts := []renderer.Option{html.WithUnsafe()}
+	if options.hardWraps {
+		rendererOpts = append(rendererOpts, html.WithHardWraps())
+	}
+
 	md := goldmark.New(
-		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithExtensions(extensions...),
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(),
 		),
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
+		goldmark.WithRendererOptions(rendererOpts...),
 	)
 
+	// UGCPolicy already permits <dl>, <dt> and <dd>; abbreviations also need their title.
 	sanitizer := bluemonday.UGCPolicy()
+	if options.abbreviations {
+		sanitizer.AllowAttrs("title").OnElements("abbr")
+	}
+
+	// Callouts are styled by class, so only the classes the callout renderer writes are kept.
+	sanitizer.AllowAttrs("class").Matching(calloutClassPattern).OnElements("div", "p")
+
+	// Numeric width and height are kept on images by UGCPolicy; the loading hint is not.
+	sanitizer.AllowAttrs("loading").Matching(imageLoadingPattern).OnElements("img")
+
+	// Expired entries are dropped lazily on access, so the cache needs no cleanup goroutine.
+	cache := ttlcache.New[string, []byte](
+		ttlcache.WithTTL[string, []byte](renderCacheTTL),
+		ttlcache.WithCapacity[string, []byte](renderCacheSize),
+	)
 
 	return &Renderer{
 		md:        md,
 		sanitizer: sanitizer,
+		cache:     cache,
 	}
 }
 
 // RenderHTML converts markdown content to HTML, sanitizes it, and writes it to the writer.
+// Output is cached by a hash of the content, so identical markdown is only rendered once.
 func (r *Renderer) RenderHTML(ctx context.Context, w io.Writer, content string) error {
-	var buf bytes.Buffer
+	sum := sha256.Sum256([]byte(content))
+	key := hex.EncodeToString(sum[:])
 
-	err := r.md.Convert([]byte(content), &buf)
+	item := r.cache.Get(key)
+	if item != nil {
+		_, err := w.Write(item.Value())
+
+		return err
+	}
+
+	safeHTML, err := r.render(content)
 	if err != nil {
 		return err
 	}
 
-	safeHTML := r.sanitizer.SanitizeBytes(buf.Bytes())
+	r.cache.Set(key, safeHTML, ttlcache.DefaultTTL)
 
 	_, err = w.Write(safeHTML)
 
 	return err
 }
+
+// render converts markdown content to sanitized HTML without consulting the cache.
+func (r *Renderer) render(content string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := r.md.Convert([]byte(content), &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.sanitizer.SanitizeBytes(buf.Bytes()), nil
+}
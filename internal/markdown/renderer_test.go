@@ -3,6 +3,7 @@ package markdown
 import (
 	"bytes"
 	"context"
+	"io"
 	"strconv"
 	"strings"
 	"testing"
@@ -295,3 +296,115 @@ func TestRenderer_RenderHTML_ContextCancellation(t *testing.T) {
 	err := renderer.RenderHTML(ctx, &buf, content)
 	require.NoError(t, err)
 }
+
+func TestRenderer_RenderHTML_Cached(t *testing.T) {
+	renderer := NewRenderer()
+	ctx := context.Background()
+
+	var first, second bytes.Buffer
+	require.NoError(t, renderer.RenderHTML(ctx, &first, "# Cached"))
+	assert.Equal(t, 1, renderer.cache.Len())
+
+	require.NoError(t, renderer.RenderHTML(ctx, &second, "# Cached"))
+	assert.Equal(t, 1, renderer.cache.Len(), "identical content should reuse the cached render")
+	assert.Equal(t, first.String(), second.String())
+
+	var other bytes.Buffer
+	require.NoError(t, renderer.RenderHTML(ctx, &other, "# Different"))
+	assert.Equal(t, 2, renderer.cache.Len())
+	assert.Contains(t, other.String(), "Different")
+}
+
+func BenchmarkRenderHTML(b *testing.B) {
+	var content strings.Builder
+	content.WriteString("# Long Document\n\n")
+
+	for i := range 200 {
+		content.WriteString("## Section " + strconv.Itoa(i) + "\n\n")
+		content.WriteString("Some **bold** and *italic* text with a [link](/wiki/page).\n\n")
+		content.WriteString("- item one\n- item two\n\n")
+	}
+
+	markdown := content.String()
+	renderer := NewRenderer()
+	ctx := context.Background()
+
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			_, err := renderer.render(markdown)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			err := renderer.RenderHTML(ctx, io.Discard, markdown)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func TestRenderer_RenderHTML_DefinitionLists(t *testing.T) {
+	renderer := NewRenderer(WithDefinitionLists(true))
+	ctx := context.Background()
+	var buf bytes.Buffer
+
+	content := "Wiki\n: A site edited by its readers.\n: Hawaiian for quick.\n\nSlug\n: The URL-safe name of an article."
+	err := renderer.RenderHTML(ctx, &buf, content)
+	require.NoError(t, err)
+
+	result := buf.String()
+	assert.Contains(t, result, "<dl>")
+	assert.Contains(t, result, "<dt>Wiki</dt>")
+	assert.Contains(t, result, "<dd>A site edited by its readers.</dd>")
+	assert.Contains(t, result, "<dd>Hawaiian for quick.</dd>")
+	assert.Contains(t, result, "<dt>Slug</dt>")
+	assert.Contains(t, result, "</dl>")
+}
+
+func TestRenderer_RenderHTML_HardWraps(t *testing.T) {
+	ctx := context.Background()
+	content := "First line\nsecond line"
+
+	var buf bytes.Buffer
+	err := NewRenderer(WithHardWraps(true)).RenderHTML(ctx, &buf, content)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "First line<br>\nsecond line")
+
+	buf.Reset()
+	err = NewRenderer().RenderHTML(ctx, &buf, content)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "<p>First line\nsecond line</p>")
+	assert.NotContains(t, buf.String(), "<br")
+}
+
+func TestRenderer_RenderHTML_LazyImages(t *testing.T) {
+	ctx := context.Background()
+	content := "![Logo](/logo.png)\n\n<img src=\"/raw.png\" width=\"640\" height=\"480\" loading=\"eager\">"
+
+	var buf bytes.Buffer
+	err := NewRenderer(WithLazyImages(true)).RenderHTML(ctx, &buf, content)
+	require.NoError(t, err)
+
+	result := buf.String()
+	assert.Contains(t, result, `<img src="/logo.png" alt="Logo" loading="lazy">`)
+	assert.Contains(t, result, `<img src="/raw.png" width="640" height="480" loading="eager">`)
+
+	buf.Reset()
+	err = NewRenderer().RenderHTML(ctx, &buf, "![Logo](/logo.png)")
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "loading=")
+}
+
+func TestRenderer_RenderHTML_DefinitionListsDisabled(t *testing.T) {
+	renderer := NewRenderer()
+	ctx := context.Background()
+	var buf bytes.Buffer
+
+	err := renderer.RenderHTML(ctx, &buf, "Wiki\n: A site edited by its readers.")
+	require.NoError(t, err)
+
+	result := buf.String()
+	assert.NotContains(t, result, "<dl>")
+	assert.Contains(t, result, ": A site edited by its readers.")
+}
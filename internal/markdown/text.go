@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// wordsPerMinute is the average reading speed used to estimate reading time.
+const wordsPerMinute = 200
+
+// PlainText strips markdown formatting from content and returns the readable text.
+// Block level elements are separated by newlines. Code blocks are omitted.
+func (r *Renderer) PlainText(content string) string {
+	source := []byte(content)
+	doc := r.md.Parser().Parse(text.NewReader(source))
+
+	var blocks []string
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		blocks = appendBlockText(blocks, node, source)
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+// Excerpt returns the plain text of the first paragraph in content, truncated to at most
+// maxRunes characters on a word boundary. A maxRunes of zero or less disables truncation.
+func (r *Renderer) Excerpt(content string, maxRunes int) string {
+	source := []byte(content)
+	doc := r.md.Parser().Parse(text.NewReader(source))
+
+	var excerpt string
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		if node.Kind() == ast.KindParagraph {
+			excerpt = inlineText(node, source)
+			if excerpt != "" {
+				return ast.WalkStop, nil
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return truncateWords(excerpt, maxRunes)
+}
+
+// WordCount returns the number of words in the readable text of content.
+func (r *Renderer) WordCount(content string) int {
+	return len(strings.Fields(r.PlainText(content)))
+}
+
+// ReadingTime estimates the number of minutes needed to read content.
+// Any non-empty content takes at least one minute.
+func (r *Renderer) ReadingTime(content string) int {
+	words := r.WordCount(content)
+	if words == 0 {
+		return 0
+	}
+
+	return int(math.Ceil(float64(words) / wordsPerMinute))
+}
+
+// appendBlockText collects the text of a block node and its children.
+func appendBlockText(blocks []string, node ast.Node, source []byte) []string {
+	switch node.Kind() {
+	case ast.KindFencedCodeBlock, ast.KindCodeBlock, ast.KindHTMLBlock, ast.KindThematicBreak:
+		return blocks
+	case ast.KindParagraph, ast.KindHeading, ast.KindTextBlock:
+		if t := inlineText(node, source); t != "" {
+			blocks = append(blocks, t)
+		}
+		return blocks
+	}
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if child.Type() == ast.TypeBlock {
+			blocks = appendBlockText(blocks, child, source)
+		} else if t := inlineText(child, source); t != "" {
+			blocks = append(blocks, t)
+		}
+	}
+
+	return blocks
+}
+
+// inlineText concatenates the text segments beneath an inline container node.
+func inlineText(node ast.Node, source []byte) string {
+	var sb strings.Builder
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch v := n.(type) {
+		case *ast.Text:
+			sb.Write(v.Segment.Value(source))
+			if v.SoftLineBreak() || v.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(v.Value)
+		case *ast.AutoLink:
+			sb.Write(v.Label(source))
+		case *ast.RawHTML, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// truncateWords shortens s to at most maxRunes characters, cutting at the last word boundary.
+func truncateWords(s string, maxRunes int) string {
+	if maxRunes <= 0 || utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+
+	runes := []rune(s)
+	cut := string(runes[:maxRunes])
+
+	if idx := strings.LastIndex(cut, " "); idx > 0 {
+		cut = cut[:idx]
+	}
+
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
@@ -0,0 +1,77 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderer_PlainText(t *testing.T) {
+	renderer := NewRenderer()
+
+	content := "# Title\n\nSome **bold** and [a link](/wiki/page).\n\n" +
+		"```go\nfmt.Println(\"hidden\")\n```\n\n- First item\n- Second `code`"
+
+	result := renderer.PlainText(content)
+	assert.Equal(t, "Title\nSome bold and a link.\nFirst item\nSecond code", result)
+}
+
+func TestRenderer_Excerpt(t *testing.T) {
+	renderer := NewRenderer()
+
+	testCases := []struct {
+		name     string
+		content  string
+		maxRunes int
+		expected string
+	}{
+		{
+			name:     "skips heading",
+			content:  "# Title\n\nThe *first* paragraph.\n\nThe second paragraph.",
+			expected: "The first paragraph.",
+		},
+		{
+			name:     "joins wrapped lines",
+			content:  "Line one\nline two",
+			expected: "Line one line two",
+		},
+		{
+			name:     "truncates on word boundary",
+			content:  "The quick brown fox jumps over the lazy dog",
+			maxRunes: 18,
+			expected: "The quick brown…",
+		},
+		{
+			name:     "no paragraphs",
+			content:  "# Only a heading",
+			expected: "",
+		},
+		{
+			name:     "empty content",
+			content:  "",
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, renderer.Excerpt(tc.content, tc.maxRunes))
+		})
+	}
+}
+
+func TestRenderer_WordCount(t *testing.T) {
+	renderer := NewRenderer()
+
+	assert.Equal(t, 0, renderer.WordCount(""))
+	assert.Equal(t, 4, renderer.WordCount("# Title\n\nSome **bold** text\n\n```\ncode is not counted\n```"))
+}
+
+func TestRenderer_ReadingTime(t *testing.T) {
+	renderer := NewRenderer()
+
+	assert.Equal(t, 0, renderer.ReadingTime(""))
+	assert.Equal(t, 1, renderer.ReadingTime("A few words"))
+	assert.Equal(t, 2, renderer.ReadingTime(strings.Repeat("word ", 201)))
+}
@@ -0,0 +1,125 @@
+package mediawiki
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// MainNamespace is the namespace of regular articles. Talk, user, template and other
+// pages live in other namespaces.
+const MainNamespace = 0
+
+// Page is a page of a MediaWiki XML dump with its revisions, oldest first.
+type Page struct {
+	Title string
+	// Redirect is the title of the page this one redirects to, if it is a redirect.
+	Redirect  string
+	Revisions []Revision
+	Namespace int
+}
+
+// Revision is a single revision of a page.
+type Revision struct {
+	Timestamp time.Time
+	// Contributor is the user name of the author, or their IP address for anonymous edits.
+	Contributor string
+	Comment     string
+	Text        string
+}
+
+// xmlPage mirrors the page element of the export format (https://www.mediawiki.org/xml/export-0.11.xsd).
+type xmlPage struct {
+	Redirect *struct {
+		Title string `xml:"title,attr"`
+	} `xml:"redirect"`
+	Title     string        `xml:"title"`
+	Revisions []xmlRevision `xml:"revision"`
+	Namespace int           `xml:"ns"`
+}
+
+// xmlRevision mirrors the revision element of the export format.
+type xmlRevision struct {
+	Timestamp   time.Time `xml:"timestamp"`
+	Contributor struct {
+		Username string `xml:"username"`
+		IP       string `xml:"ip"`
+	} `xml:"contributor"`
+	Comment string `xml:"comment"`
+	Text    string `xml:"text"`
+}
+
+// Reader reads the pages of a MediaWiki XML dump one at a time, so dumps with a full
+// history need not fit in memory.
+type Reader struct {
+	decoder *xml.Decoder
+}
+
+// NewReader returns a Reader for the dump read from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{decoder: xml.NewDecoder(r)}
+}
+
+// Next returns the next page of the dump. It returns io.EOF when there are no more pages.
+func (r *Reader) Next() (*Page, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+
+			return nil, fmt.Errorf("invalid MediaWiki dump: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "page" {
+			continue
+		}
+
+		var raw xmlPage
+
+		err = r.decoder.DecodeElement(&raw, &start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MediaWiki dump: %w", err)
+		}
+
+		return newPage(&raw), nil
+	}
+}
+
+// newPage converts a decoded page element, ordering its revisions by time.
+func newPage(raw *xmlPage) *Page {
+	page := &Page{
+		Title:     raw.Title,
+		Namespace: raw.Namespace,
+		Revisions: make([]Revision, 0, len(raw.Revisions)),
+	}
+
+	if raw.Redirect != nil {
+		page.Redirect = raw.Redirect.Title
+	}
+
+	for _, rev := range raw.Revisions {
+		contributor := rev.Contributor.Username
+		if contributor == "" {
+			contributor = rev.Contributor.IP
+		}
+
+		page.Revisions = append(page.Revisions, Revision{
+			Timestamp:   rev.Timestamp,
+			Contributor: contributor,
+			Comment:     rev.Comment,
+			Text:        rev.Text,
+		})
+	}
+
+	slices.SortStableFunc(page.Revisions, func(a, b Revision) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return page
+}
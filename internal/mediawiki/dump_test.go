@@ -0,0 +1,101 @@
+package mediawiki
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleDump is a trimmed export of two articles, a redirect and a talk page.
+const sampleDump = `<mediawiki xmlns="http://www.mediawiki.org/xml/export-0.11/" version="0.11" xml:lang="en">
+  <siteinfo>
+    <sitename>Sample</sitename>
+  </siteinfo>
+  <page>
+    <title>Getting Started</title>
+    <ns>0</ns>
+    <id>1</id>
+    <revision>
+      <id>12</id>
+      <parentid>11</parentid>
+      <timestamp>2021-03-02T10:00:00Z</timestamp>
+      <contributor><username>Bob</username><id>2</id></contributor>
+      <comment>Add install steps</comment>
+      <model>wikitext</model>
+      <format>text/x-wiki</format>
+      <text bytes="60" xml:space="preserve">== Install ==
+# Download
+# Run '''setup''' &amp; see [[FAQ]]</text>
+    </revision>
+    <revision>
+      <id>11</id>
+      <timestamp>2021-03-01T09:00:00Z</timestamp>
+      <contributor><ip>192.0.2.7</ip></contributor>
+      <text bytes="20" xml:space="preserve">== Install ==</text>
+    </revision>
+  </page>
+  <page>
+    <title>Start</title>
+    <ns>0</ns>
+    <id>2</id>
+    <redirect title="Getting Started" />
+    <revision>
+      <timestamp>2021-03-03T08:00:00Z</timestamp>
+      <contributor><username>Ann</username></contributor>
+      <text xml:space="preserve">#REDIRECT [[Getting Started]]</text>
+    </revision>
+  </page>
+  <page>
+    <title>Talk:Getting Started</title>
+    <ns>1</ns>
+    <id>3</id>
+    <revision>
+      <timestamp>2021-03-04T08:00:00Z</timestamp>
+      <contributor><username>Ann</username></contributor>
+      <text xml:space="preserve">Looks good.</text>
+    </revision>
+  </page>
+</mediawiki>`
+
+func TestReader_Next(t *testing.T) {
+	reader := NewReader(strings.NewReader(sampleDump))
+
+	page, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Getting Started", page.Title)
+	assert.Equal(t, MainNamespace, page.Namespace)
+	assert.Empty(t, page.Redirect)
+	require.Len(t, page.Revisions, 2)
+
+	assert.Equal(t, time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC), page.Revisions[0].Timestamp.UTC(), "revisions are ordered oldest first")
+	assert.Equal(t, "192.0.2.7", page.Revisions[0].Contributor)
+	assert.Equal(t, "Bob", page.Revisions[1].Contributor)
+	assert.Equal(t, "Add install steps", page.Revisions[1].Comment)
+	assert.Equal(t, "== Install ==\n# Download\n# Run '''setup''' & see [[FAQ]]", page.Revisions[1].Text)
+
+	page, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Start", page.Title)
+	assert.Equal(t, "Getting Started", page.Redirect)
+
+	page, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Talk:Getting Started", page.Title)
+	assert.Equal(t, 1, page.Namespace)
+
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReader_Next_Invalid(t *testing.T) {
+	reader := NewReader(strings.NewReader(`<mediawiki><page><title>Broken</title><revision>`))
+
+	_, err := reader.Next()
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, io.EOF)
+	assert.Contains(t, err.Error(), "invalid MediaWiki dump")
+}
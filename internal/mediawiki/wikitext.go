@@ -0,0 +1,191 @@
+package mediawiki
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"wikilite/pkg/utils"
+)
+
+var (
+	headingRegex     = regexp.MustCompile(`^(={1,6})\s*(.+?)\s*={1,6}\s*$`)
+	ruleRegex        = regexp.MustCompile(`^-{4,}\s*$`)
+	listRegex        = regexp.MustCompile(`^([*#:;]+)\s*(.*)$`)
+	commentRegex     = regexp.MustCompile(`(?s)<!--.*?-->`)
+	nowikiRegex      = regexp.MustCompile(`(?s)<nowiki>(.*?)</nowiki>|<nowiki\s*/>`)
+	placeholderRegex = regexp.MustCompile("\x00([0-9]+)\x00")
+	boldItalicRegex  = regexp.MustCompile(`'''''(.+?)'''''`)
+	boldRegex        = regexp.MustCompile(`'''(.+?)'''`)
+	italicRegex      = regexp.MustCompile(`''(.+?)''`)
+	wikiLinkRegex    = regexp.MustCompile(`\[\[([^\[\]]+)\]\]`)
+	externalRegex    = regexp.MustCompile(`\[((?:https?|ftp)://[^\s\]]+)(?:\s+([^\]]*))?\]`)
+)
+
+// ToMarkdown converts MediaWiki wikitext to markdown. The conversion is best effort: it
+// covers headings, bold and italic text, bullet, numbered and definition lists, indented
+// text, horizontal rules, internal and external links and images. Templates, categories
+// and comments are dropped; tables and HTML tags are kept as they are. The content of
+// <nowiki> tags is kept literally.
+func ToMarkdown(wikitext string) string {
+	text := strings.ReplaceAll(wikitext, "\r\n", "\n")
+
+	// Literal text is swapped for NUL delimited placeholders until the conversion is done.
+	var literals []string
+
+	text = nowikiRegex.ReplaceAllStringFunc(text, func(nowiki string) string {
+		literals = append(literals, nowikiRegex.FindStringSubmatch(nowiki)[1])
+
+		return "\x00" + strconv.Itoa(len(literals)-1) + "\x00"
+	})
+
+	text = commentRegex.ReplaceAllString(text, "")
+	text = removeTemplates(text)
+
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		converted := convertLine(line)
+
+		// Text right below an indented (quoted) line would continue the quote.
+		quoted := strings.HasPrefix(converted, ">")
+		if len(out) > 0 && strings.HasPrefix(out[len(out)-1], ">") && !quoted && converted != "" {
+			out = append(out, "")
+		}
+
+		out = append(out, converted)
+	}
+
+	markdown := placeholderRegex.ReplaceAllStringFunc(strings.Join(out, "\n"), func(placeholder string) string {
+		index, _ := strconv.Atoi(placeholderRegex.FindStringSubmatch(placeholder)[1])
+
+		return literals[index]
+	})
+
+	return strings.TrimSpace(markdown) + "\n"
+}
+
+// convertLine converts a single line of wikitext.
+func convertLine(line string) string {
+	heading := headingRegex.FindStringSubmatch(line)
+	if heading != nil {
+		return strings.Repeat("#", len(heading[1])) + " " + convertInline(heading[2])
+	}
+
+	if ruleRegex.MatchString(line) {
+		// A dash rule right below a paragraph would turn it into a heading.
+		return "***"
+	}
+
+	match := listRegex.FindStringSubmatch(line)
+	if match == nil {
+		return convertInline(line)
+	}
+
+	prefix, content := match[1], convertInline(match[2])
+
+	switch {
+	case strings.Trim(prefix, ":") == "":
+		return strings.Repeat("> ", len(prefix)) + content
+	case prefix == ";":
+		term, definition, found := strings.Cut(content, " : ")
+		if found {
+			return "**" + strings.TrimSpace(term) + "**: " + strings.TrimSpace(definition)
+		}
+
+		return "**" + content + "**"
+	}
+
+	var indent strings.Builder
+	for _, marker := range prefix[:len(prefix)-1] {
+		if marker == '#' {
+			indent.WriteString("   ")
+		} else {
+			indent.WriteString("  ")
+		}
+	}
+
+	bullet := "- "
+	if prefix[len(prefix)-1] == '#' {
+		bullet = "1. "
+	}
+
+	return indent.String() + bullet + content
+}
+
+// convertInline converts the formatting and links within a line.
+func convertInline(text string) string {
+	text = boldItalicRegex.ReplaceAllString(text, "***$1***")
+	text = boldRegex.ReplaceAllString(text, "**$1**")
+	text = italicRegex.ReplaceAllString(text, "*$1*")
+
+	text = wikiLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		return convertWikiLink(wikiLinkRegex.FindStringSubmatch(link)[1])
+	})
+
+	return externalRegex.ReplaceAllStringFunc(text, func(link string) string {
+		match := externalRegex.FindStringSubmatch(link)
+		if strings.TrimSpace(match[2]) == "" {
+			return "<" + match[1] + ">"
+		}
+
+		return "[" + strings.TrimSpace(match[2]) + "](" + match[1] + ")"
+	})
+}
+
+// convertWikiLink converts the inside of a [[target|label]] link.
+func convertWikiLink(inner string) string {
+	parts := strings.Split(inner, "|")
+	target := strings.TrimSpace(parts[0])
+	label := strings.TrimSpace(parts[len(parts)-1])
+
+	namespace, name, _ := strings.Cut(target, ":")
+	switch strings.ToLower(strings.TrimSpace(namespace)) {
+	case "category":
+		return ""
+	case "file", "image":
+		if len(parts) == 1 {
+			label = ""
+		}
+
+		return "![" + label + "](" + strings.ReplaceAll(strings.TrimSpace(name), " ", "_") + ")"
+	}
+
+	if strings.HasPrefix(target, ":") {
+		return strings.TrimPrefix(label, ":")
+	}
+
+	page, anchor, _ := strings.Cut(target, "#")
+
+	href := ""
+	if page != "" {
+		href = "/wiki/" + utils.ToKebabCase(page)
+	}
+
+	if anchor != "" {
+		href += "#" + utils.ToKebabCase(anchor)
+	}
+
+	return "[" + label + "](" + href + ")"
+}
+
+// removeTemplates drops {{template}} invocations, including nested ones.
+func removeTemplates(text string) string {
+	var b strings.Builder
+
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "{{"):
+			depth++
+			i++
+		case depth > 0 && strings.HasPrefix(text[i:], "}}"):
+			depth--
+			i++
+		case depth == 0:
+			b.WriteByte(text[i])
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package mediawiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		wikitext string
+		want     string
+	}{
+		{
+			"headings",
+			"= Title =\n== Section ==\n=== Sub ''section'' ===",
+			"# Title\n## Section\n### Sub *section*\n",
+		},
+		{
+			"bold and italic",
+			"Some '''bold''', ''italic'' and '''''both''''' text.",
+			"Some **bold**, *italic* and ***both*** text.\n",
+		},
+		{
+			"lists",
+			"* one\n** nested\n* two\n# first\n#* mixed\n# second",
+			"- one\n  - nested\n- two\n1. first\n   - mixed\n1. second\n",
+		},
+		{
+			"definitions and indents",
+			"; Term : Definition\n; Alone\n: indented\n:: deeper\nAfter",
+			"**Term**: Definition\n**Alone**\n> indented\n> > deeper\n\nAfter\n",
+		},
+		{
+			"internal links",
+			"See [[Main Page]], [[Install Guide#Linux Setup|Linux]] and [[#Notes|below]].",
+			"See [Main Page](/wiki/main-page), [Linux](/wiki/install-guide#linux-setup) and [below](#notes).\n",
+		},
+		{
+			"external links",
+			"Visit [https://example.com Example] or [https://go.dev].",
+			"Visit [Example](https://example.com) or <https://go.dev>.\n",
+		},
+		{
+			"files and categories",
+			"[[File:Team photo.jpg|thumb|200px|The team]]\n[[Category:People]]\n[[:Category:People]]",
+			"![The team](Team_photo.jpg)\n\nCategory:People\n",
+		},
+		{
+			"templates and comments",
+			"{{Infobox|name={{PAGENAME}}}}Intro<!-- hidden -->text.\n----\n<nowiki>''raw''</nowiki>",
+			"Introtext.\n***\n''raw''\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToMarkdown(tt.wikitext))
+		})
+	}
+}
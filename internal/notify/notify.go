@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"wikilite/pkg/models"
+)
+
+const queueSize = 100
+
+// Notification is a message addressed to a single user.
+type Notification struct {
+	Recipient string
+	Subject   string
+	Message   string
+	Link      string
+}
+
+// Notifier delivers notifications to users, e.g. by email or chat.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier records notifications in the system logs instead of delivering them.
+// It is the default when no other Notifier is configured.
+type LogNotifier struct {
+	logger models.Logger
+}
+
+// NewLogNotifier creates a notifier that writes to logger.
+func NewLogNotifier(logger models.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify writes the notification to the log.
+func (l *LogNotifier) Notify(ctx context.Context, n Notification) error {
+	if l.logger == nil {
+		return nil
+	}
+
+	return l.logger(
+		ctx,
+		models.LevelInfo,
+		"NOTIFY",
+		n.Subject,
+		fmt.Sprintf("To: %s | Link: %s | %s", n.Recipient, n.Link, n.Message),
+	)
+}
+
+// Queue delivers notifications through a Notifier from a background worker,
+// so slow deliveries never hold up a request.
+type Queue struct {
+	notifier Notifier
+	logger   models.Logger
+
+	queue chan Notification
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewQueue creates a queue and starts its worker.
+func NewQueue(notifier Notifier, logger models.Logger) *Queue {
+	q := &Queue{
+		notifier: notifier,
+		logger:   logger,
+		queue:    make(chan Notification, queueSize),
+	}
+
+	q.wg.Go(q.workerLoop)
+
+	return q
+}
+
+// Enqueue queues a notification without blocking the caller.
+// Notifications are dropped if the queue is full or closed.
+func (q *Queue) Enqueue(n Notification) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return
+	}
+
+	select {
+	case q.queue <- n:
+	default:
+		q.log(models.LevelWarning, "Notification queue full, notification dropped", n.Recipient)
+	}
+}
+
+// Close stops accepting notifications and waits for the queued ones to be delivered.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+// workerLoop delivers queued notifications until the queue is closed.
+func (q *Queue) workerLoop() {
+	for n := range q.queue {
+		err := q.notifier.Notify(context.Background(), n)
+		if err != nil {
+			q.log(
+				models.LevelError,
+				"Notification delivery failed",
+				fmt.Sprintf("To: %s | Error: %v", n.Recipient, err),
+			)
+		}
+	}
+}
+
+// log writes to the configured logger, if any.
+func (q *Queue) log(level models.LogLevel, message, data string) {
+	if q.logger != nil {
+		_ = q.logger(context.Background(), level, "NOTIFY", message, data)
+	}
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingNotifier keeps every notification it is asked to deliver.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []Notification
+	err  error
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sent = append(r.sent, n)
+
+	return r.err
+}
+
+func TestQueue_DeliversBeforeClose(t *testing.T) {
+	notifier := &recordingNotifier{}
+	q := NewQueue(notifier, nil)
+
+	q.Enqueue(Notification{Recipient: "a@example.com"})
+	q.Enqueue(Notification{Recipient: "b@example.com"})
+	q.Close()
+
+	assert.Len(t, notifier.sent, 2)
+	assert.Equal(t, "a@example.com", notifier.sent[0].Recipient)
+
+	q.Enqueue(Notification{Recipient: "c@example.com"})
+	q.Close()
+	assert.Len(t, notifier.sent, 2, "notifications after Close are dropped")
+}
+
+func TestQueue_LogsFailures(t *testing.T) {
+	var logged []string
+	logger := func(_ context.Context, level models.LogLevel, _, message, data string) error {
+		logged = append(logged, string(level)+": "+message+" | "+data)
+		return nil
+	}
+
+	q := NewQueue(&recordingNotifier{err: errors.New("smtp down")}, logger)
+	q.Enqueue(Notification{Recipient: "a@example.com"})
+	q.Close()
+
+	assert.Equal(t, []string{
+		"ERROR: Notification delivery failed | To: a@example.com | Error: smtp down",
+	}, logged)
+}
+
+func TestLogNotifier(t *testing.T) {
+	var message, data string
+	logger := func(_ context.Context, _ models.LogLevel, _, m, d string) error {
+		message, data = m, d
+		return nil
+	}
+
+	err := NewLogNotifier(logger).Notify(context.Background(), Notification{
+		Recipient: "a@example.com",
+		Subject:   "Home was updated",
+		Message:   "b@example.com published version 2.",
+		Link:      "/wiki/home",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Home was updated", message)
+	assert.Equal(t, "To: a@example.com | Link: /wiki/home | b@example.com published version 2.", data)
+}
@@ -0,0 +1,22 @@
+package plugin
+
+// DefaultMemoryLimit is the default maximum heap size of a plugin VM in bytes (64 MiB).
+const DefaultMemoryLimit = 64 << 20
+
+// Limits bounds the resources available to each plugin VM. Zero values select the defaults.
+//
+// The QuickJS binding does not expose JS_SetMaxStackSize, so call depth cannot be capped here.
+type Limits struct {
+	// MemoryLimit is the maximum heap size of a VM in bytes. Allocations beyond it throw an
+	// "out of memory" error inside the plugin instead of growing the host process.
+	MemoryLimit int
+}
+
+// memoryLimit returns the configured memory limit, falling back to the default.
+func (l Limits) memoryLimit() int {
+	if l.MemoryLimit <= 0 {
+		return DefaultMemoryLimit
+	}
+
+	return l.MemoryLimit
+}
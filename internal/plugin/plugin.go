@@ -17,6 +17,20 @@ type Plugin struct {
 	ID     string
 	Script string
 	Order  int
+	Scope  Scope
+}
+
+// Scope limits the articles a plugin's pipeline hooks run for. A plugin with an empty
+// scope runs for every article; otherwise it runs when any of the lists match.
+type Scope struct {
+	Slugs      []string `json:"slugs,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// IsEmpty reports whether the scope places no restriction on the plugin.
+func (s Scope) IsEmpty() bool {
+	return len(s.Slugs) == 0 && len(s.Tags) == 0 && len(s.Namespaces) == 0
 }
 
 //go:embed types.d.ts
@@ -62,6 +76,7 @@ func loadFromDirectory(dir string) ([]Plugin, error) {
 			ID:     id,
 			Order:  order,
 			Script: string(content),
+			Scope:  parseManifest(string(content)),
 		})
 	}
 
@@ -72,6 +87,54 @@ func loadFromDirectory(dir string) ([]Plugin, error) {
 	return plugins, nil
 }
 
+// parseManifest reads the scope declared in the comments at the top of a plugin script,
+// given as "@slugs", "@tags" and "@namespaces" lines with comma or space separated values:
+//
+//	// @tags status, draft
+//	// @namespaces docs
+//
+// Parsing stops at the first line that is not a comment.
+func parseManifest(script string) Scope {
+	var scope Scope
+
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var ok bool
+		for _, marker := range []string{"//", "/**", "/*", "*/", "*"} {
+			if strings.HasPrefix(line, marker) {
+				line, ok = strings.TrimSpace(strings.TrimPrefix(line, marker)), true
+				break
+			}
+		}
+
+		if !ok {
+			break
+		}
+
+		line = strings.TrimSpace(strings.TrimSuffix(line, "*/"))
+
+		key, value, _ := strings.Cut(line, " ")
+		values := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+
+		switch key {
+		case "@slugs":
+			scope.Slugs = append(scope.Slugs, values...)
+		case "@tags":
+			scope.Tags = append(scope.Tags, values...)
+		case "@namespaces":
+			scope.Namespaces = append(scope.Namespaces, values...)
+		}
+	}
+
+	return scope
+}
+
 // ensureTypeDefinitions checks for the existence of types.d.ts and creates it if missing.
 func ensureTypeDefinitions(dir string) error {
 	path := filepath.Join(dir, "types.d.ts")
@@ -64,3 +64,22 @@ func TestEnsureTypeDefinitions(t *testing.T) {
 	err = ensureTypeDefinitions(tmpDir)
 	require.NoError(t, err, "Calling ensureTypeDefinitions again should not produce an error")
 }
+
+func TestParseManifest(t *testing.T) {
+	script := `/**
+ * status-badge.js
+ * @tags status, draft
+ * @namespaces docs
+ */
+// @slugs home
+function onArticleRender(html, ctx) { return html; }
+// @slugs ignored
+`
+
+	scope := parseManifest(script)
+	assert.Equal(t, []string{"home"}, scope.Slugs)
+	assert.Equal(t, []string{"status", "draft"}, scope.Tags)
+	assert.Equal(t, []string{"docs"}, scope.Namespaces)
+
+	assert.True(t, parseManifest("function onArticleRender(html) { return html; }").IsEmpty())
+}
@@ -3,6 +3,7 @@
 package plugin
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
@@ -12,7 +13,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
-	"time"
+	"wikilite/internal/cache"
 	"wikilite/pkg/models"
 
 	"github.com/jellydator/ttlcache/v3"
@@ -20,16 +21,16 @@ import (
 	"modernc.org/quickjs"
 )
 
-const (
-	cacheTtl  = 30 * time.Minute
-	cacheSize = 1000
-)
+// consoleLogSource is the log source used for plugin console output.
+const consoleLogSource = "plugin-console"
 
 // Manager manages a set of fixed workers that own QuickJS VMs.
 type Manager struct {
 	Store Store
 
 	sanitizer *bluemonday.Policy
+	logger    models.Logger
+	limits    Limits
 
 	jobQueue chan jobRequest
 	stopChan chan struct{}
@@ -74,7 +75,16 @@ type jobResponse struct {
 }
 
 // NewManager creates a new plugin manager with a fixed worker pool.
-func NewManager(dbPath string, pluginDir string, jsPkgsPath string) (*Manager, error) {
+// Rendered output is cached as set by cacheConfig, with unset fields taken from cache.Default.
+// Plugin console output is written to logger, or to stdout when logger is nil.
+func NewManager(
+	dbPath string,
+	pluginDir string,
+	jsPkgsPath string,
+	limits Limits,
+	cacheConfig cache.Config,
+	logger models.Logger,
+) (*Manager, error) {
 	store, err := newBoltStore(dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize storage: %w", err)
@@ -93,11 +103,8 @@ func NewManager(dbPath string, pluginDir string, jsPkgsPath string) (*Manager, e
 
 	workerCount := max(runtime.NumCPU(), 4)
 
-	cache := ttlcache.New[string, string](
-		ttlcache.WithTTL[string, string](cacheTtl),
-		ttlcache.WithCapacity[string, string](cacheSize),
-	)
-	go cache.Start()
+	outputCache := cache.New[string, string](cacheConfig.Or(cache.Default))
+	go outputCache.Start()
 
 	m := &Manager{
 		Store:      store,
@@ -107,7 +114,9 @@ func NewManager(dbPath string, pluginDir string, jsPkgsPath string) (*Manager, e
 		jobQueue:   make(chan jobRequest, workerCount*10),
 		stopChan:   make(chan struct{}),
 		sanitizer:  bluemonday.UGCPolicy(),
-		cache:      cache,
+		logger:     logger,
+		limits:     limits,
+		cache:      outputCache,
 	}
 
 	for i := 0; i < workerCount; i++ {
@@ -145,6 +154,7 @@ func (m *Manager) ExecutePipeline(
 	contextData map[string]any,
 ) (string, []Error, error) {
 	var slug string
+	var tags []string
 	var role int
 
 	if contextData != nil {
@@ -153,6 +163,11 @@ func (m *Manager) ExecutePipeline(
 			slug = s
 		}
 
+		t, ok := contextData["Tags"].([]string)
+		if ok {
+			tags = t
+		}
+
 		u, ok := contextData["User"].(*models.User)
 		if ok && u != nil {
 			role = int(u.Role)
@@ -163,11 +178,12 @@ func (m *Manager) ExecutePipeline(
 	if slug != "" && hookName == "onArticleRender" {
 		hash := md5.Sum([]byte(initialInput))
 		cacheKey = fmt.Sprintf(
-			"pipeline:%s:%s:%s:%d",
+			"pipeline:%s:%s:%s:%d:%s",
 			hookName,
 			slug,
 			hex.EncodeToString(hash[:]),
 			role,
+			strings.Join(tags, ","),
 		)
 
 		if item := m.cache.Get(cacheKey); item != nil {
@@ -264,6 +280,8 @@ func (m *Manager) workerLoop(id int) {
 		}
 	}(vm)
 
+	vm.SetMemoryLimit(uintptr(m.limits.memoryLimit()))
+
 	err = m.initVM(vm)
 	if err != nil {
 		fmt.Printf("Worker %d failed to load environment: %v\n", id, err)
@@ -406,6 +424,7 @@ func (m *Manager) initVM(vm *quickjs.VM) error {
 	for _, p := range m.Plugins {
 		safeID := fmt.Sprintf("PLUGIN_%s", p.ID)
 		wrapper := fmt.Sprintf(`
+			globalThis.__CURRENT_PLUGIN_ID = '%[3]s';
 			globalThis['%[1]s'] = (function() {
 				// --- User Code Start ---
 				%[2]s
@@ -415,7 +434,7 @@ func (m *Manager) initVM(vm *quickjs.VM) error {
 				if (typeof onAction === 'function') exports.onAction = onAction;
 				return exports;
 			})();
-		`, safeID, p.Script)
+		`, safeID, p.Script, p.ID)
 
 		_, err = vm.Eval(wrapper, quickjs.EvalGlobal)
 		if err != nil {
@@ -488,18 +507,20 @@ func (m *Manager) injectHostAPI(vm *quickjs.VM) error {
 
 	consoleLogShim := `
 		globalThis.console = {
-		  log: function (...args) {
+		  write: function (level, args) {
 			const msg = args.map((arg) => {
 			  if (typeof arg === "object") {
 				try { return JSON.stringify(arg); } catch (e) { return String(arg); }
 			  }
 			  return String(arg);
 			}).join(" ");
-			if (typeof __console_log === "function") __console_log(msg);
+			var id = globalThis.__CURRENT_PLUGIN_ID || "";
+			if (typeof __console_log === "function") __console_log(id, level, msg);
 		  },
-		  error: function (...args) { this.log("ERROR:", ...args); },
-		  warn: function (...args) { this.log("WARN:", ...args); },
-		  info: function (...args) { this.log("INFO:", ...args); },
+		  log: function (...args) { this.write("INFO", args); },
+		  error: function (...args) { this.write("ERROR", args); },
+		  warn: function (...args) { this.write("WARNING", args); },
+		  info: function (...args) { this.write("INFO", args); },
 		};
 	`
 	_, err = vm.Eval(consoleLogShim, quickjs.EvalGlobal)
@@ -507,9 +528,7 @@ func (m *Manager) injectHostAPI(vm *quickjs.VM) error {
 		return err
 	}
 
-	err = vm.RegisterFunc("__console_log", func(msg string) {
-		fmt.Println("[PLUGIN]", msg)
-	}, false)
+	err = vm.RegisterFunc("__console_log", m.consoleLog, false)
 	if err != nil {
 		return err
 	}
@@ -547,6 +566,25 @@ func (m *Manager) injectHostAPI(vm *quickjs.VM) error {
 	return err
 }
 
+// consoleLog records a console message written by a plugin.
+func (m *Manager) consoleLog(pluginID string, level string, msg string) {
+	if m.logger == nil {
+		fmt.Println("[PLUGIN]", pluginID, msg)
+		return
+	}
+
+	err := m.logger(
+		context.Background(),
+		models.LogLevel(level),
+		consoleLogSource,
+		fmt.Sprintf("[%s] %s", pluginID, msg),
+		pluginID,
+	)
+	if err != nil {
+		log.Printf("failed to record plugin console output: %v", err)
+	}
+}
+
 // injectPipelineExecutor creates a batched pipeline runner in JS.
 func (m *Manager) injectPipelineExecutor(vm *quickjs.VM) error {
 	var pluginIDsJS strings.Builder
@@ -561,14 +599,49 @@ func (m *Manager) injectPipelineExecutor(vm *quickjs.VM) error {
 	}
 	pluginIDsJS.WriteString("]")
 
+	scopes := make(map[string]Scope)
+	for _, p := range m.Plugins {
+		if !p.Scope.IsEmpty() {
+			scopes[p.ID] = p.Scope
+		}
+	}
+
+	scopesJS, err := json.Marshal(scopes)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin scopes: %w", err)
+	}
+
 	pipelineJS := fmt.Sprintf(`
+		function __in_scope(scope, ctx) {
+			if (!scope) return true;
+			if (!ctx || !ctx.Slug) return false;
+
+			var slug = ctx.Slug;
+			if ((scope.slugs || []).indexOf(slug) !== -1) return true;
+
+			var tags = ctx.Tags || [];
+			for (var i = 0; i < tags.length; i++) {
+				if ((scope.tags || []).indexOf(tags[i]) !== -1) return true;
+			}
+
+			var namespaces = scope.namespaces || [];
+			for (var i = 0; i < namespaces.length; i++) {
+				if (slug.indexOf(namespaces[i] + '/') === 0) return true;
+			}
+
+			return false;
+		}
+
 		function __run_pipeline(hook, content, ctx) {
 			var plugins = %s;
+			var scopes = %s;
 			var current = content;
 			var errors = [];
 			
 			for (var i = 0; i < plugins.length; i++) {
 				var pid = plugins[i];
+				if (!__in_scope(scopes[pid], ctx)) continue;
+
 				globalThis.__CURRENT_PLUGIN_ID = pid;
 				
 				var p = globalThis['PLUGIN_' + pid];
@@ -614,9 +687,9 @@ func (m *Manager) injectPipelineExecutor(vm *quickjs.VM) error {
 			var result = __run_plugin_action(pluginId, action, payloadStr, ctx);
 			return JSON.stringify(result);
 		}
-	`, pluginIDsJS.String())
+	`, pluginIDsJS.String(), scopesJS)
 
-	_, err := vm.Eval(pipelineJS, quickjs.EvalGlobal)
+	_, err = vm.Eval(pipelineJS, quickjs.EvalGlobal)
 	return err
 }
 
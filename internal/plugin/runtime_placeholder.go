@@ -2,10 +2,22 @@
 
 package plugin
 
+import (
+	"wikilite/internal/cache"
+	"wikilite/pkg/models"
+)
+
 type Manager struct{}
 
 // NewManager is a placeholder function for when the plugin system is not built.
-func NewManager(_ string, _ string, _ string) (*Manager, error) {
+func NewManager(
+	_ string,
+	_ string,
+	_ string,
+	_ Limits,
+	_ cache.Config,
+	_ models.Logger,
+) (*Manager, error) {
 	return nil, nil
 }
 
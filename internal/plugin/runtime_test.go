@@ -3,9 +3,13 @@
 package plugin
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"wikilite/internal/cache"
+	"wikilite/pkg/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,7 +35,7 @@ func TestNewManager_Success(t *testing.T) {
 		os.WriteFile(filepath.Join(pluginDir, pluginFile), []byte(pluginContent), 0644),
 	)
 
-	manager, err := NewManager(dbPath, pluginDir, "")
+	manager, err := NewManager(dbPath, pluginDir, "", Limits{}, cache.Config{}, nil)
 	require.NoError(t, err)
 	require.NotNil(t, manager)
 
@@ -67,7 +71,7 @@ func TestExecutePipeline_Success(t *testing.T) {
 		os.WriteFile(filepath.Join(pluginDir, pluginFile), []byte(pluginContent), 0644),
 	)
 
-	manager, err := NewManager(dbPath, pluginDir, "")
+	manager, err := NewManager(dbPath, pluginDir, "", Limits{}, cache.Config{}, nil)
 	require.NoError(t, err)
 	defer func(manager *Manager) {
 		_ = manager.Close()
@@ -106,7 +110,7 @@ func TestExecutePluginAction_Success(t *testing.T) {
 		os.WriteFile(filepath.Join(pluginDir, pluginFile), []byte(pluginContent), 0644),
 	)
 
-	manager, err := NewManager(dbPath, pluginDir, "")
+	manager, err := NewManager(dbPath, pluginDir, "", Limits{}, cache.Config{}, nil)
 	require.NoError(t, err)
 	defer func(manager *Manager) {
 		_ = manager.Close()
@@ -146,7 +150,7 @@ func TestExecutePipeline_WithCache(t *testing.T) {
 		os.WriteFile(filepath.Join(pluginDir, pluginFile), []byte(pluginContent), 0644),
 	)
 
-	manager, err := NewManager(dbPath, pluginDir, "")
+	manager, err := NewManager(dbPath, pluginDir, "", Limits{}, cache.Config{}, nil)
 	require.NoError(t, err)
 	defer func(manager *Manager) {
 		_ = manager.Close()
@@ -167,3 +171,145 @@ func TestExecutePipeline_WithCache(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEqual(t, result1, result3)
 }
+
+func TestConsoleLog_WritesToLogger(t *testing.T) {
+	pluginDir := t.TempDir()
+	dbPath := filepath.Join(t.TempDir(), "plugindb")
+
+	pluginFile := "10-chatty.js"
+	pluginContent := `
+		function onAction(action, payload, ctx) {
+			console.log("handling", action, {count: 2});
+			console.error("something broke");
+			return {ok: true};
+		}
+	`
+	require.NoError(
+		t,
+		os.WriteFile(filepath.Join(pluginDir, pluginFile), []byte(pluginContent), 0644),
+	)
+
+	type entry struct {
+		level   models.LogLevel
+		source  string
+		message string
+		data    string
+	}
+
+	var mu sync.Mutex
+	var entries []entry
+	logger := func(_ context.Context, level models.LogLevel, source, message, data string) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		entries = append(entries, entry{level, source, message, data})
+		return nil
+	}
+
+	manager, err := NewManager(dbPath, pluginDir, "", Limits{}, cache.Config{}, logger)
+	require.NoError(t, err)
+	defer func(manager *Manager) {
+		_ = manager.Close()
+	}(manager)
+
+	_, err = manager.ExecutePluginAction("chatty", "sync", "{}", nil)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, entry{
+		level:   models.LevelInfo,
+		source:  "plugin-console",
+		message: `[chatty] handling sync {"count":2}`,
+		data:    "chatty",
+	}, entries[0])
+	assert.Equal(t, models.LevelError, entries[1].level)
+	assert.Equal(t, "[chatty] something broke", entries[1].message)
+}
+
+func TestMemoryLimit_GracefulError(t *testing.T) {
+	pluginDir := t.TempDir()
+	dbPath := filepath.Join(t.TempDir(), "plugindb")
+
+	pluginFile := "10-hungry.js"
+	pluginContent := `
+		function hog() {
+			var chunks = [];
+			for (;;) chunks.push(new Array(100000).fill(1));
+		}
+
+		function onArticleRender(content, ctx) {
+			hog();
+			return content + " changed";
+		}
+
+		function onAction(action, payload, ctx) {
+			if (action === "hog") hog();
+			return {ok: true};
+		}
+	`
+	require.NoError(
+		t,
+		os.WriteFile(filepath.Join(pluginDir, pluginFile), []byte(pluginContent), 0644),
+	)
+
+	manager, err := NewManager(dbPath, pluginDir, "", Limits{MemoryLimit: 32 << 20}, cache.Config{}, nil)
+	require.NoError(t, err)
+	defer func(manager *Manager) {
+		_ = manager.Close()
+	}(manager)
+
+	result, pluginErrs, err := manager.ExecutePipeline("onArticleRender", "content", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "content", result)
+	require.Len(t, pluginErrs, 1)
+	assert.Equal(t, "hungry", pluginErrs[0].PluginID)
+	assert.Contains(t, pluginErrs[0].Error, "out of memory")
+
+	_, err = manager.ExecutePluginAction("hungry", "hog", "{}", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of memory")
+
+	res, err := manager.ExecutePluginAction("hungry", "noop", "{}", nil)
+	require.NoError(t, err, "the VM should remain usable after hitting the limit")
+	assert.JSONEq(t, `{"ok":true}`, res)
+}
+
+func TestExecutePipeline_Scoped(t *testing.T) {
+	pluginDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "10-status-badge.js"), []byte(`
+		// @tags status
+		function onArticleRender(content, ctx) { return content + " [status]"; }
+	`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "20-docs-banner.js"), []byte(`
+		// @namespaces docs
+		function onArticleRender(content, ctx) { return content + " [docs]"; }
+	`), 0644))
+
+	manager, err := NewManager(t.TempDir(), pluginDir, "", Limits{}, cache.Config{}, nil)
+	require.NoError(t, err)
+	defer func(manager *Manager) {
+		_ = manager.Close()
+	}(manager)
+
+	result, errors, err := manager.ExecutePipeline("onArticleRender", "content", map[string]any{
+		"Slug": "release-notes",
+		"Tags": []string{"status"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, errors)
+	assert.Equal(t, "content [status]", result)
+
+	result, _, err = manager.ExecutePipeline("onArticleRender", "content", map[string]any{
+		"Slug": "docs/install",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "content [docs]", result)
+
+	result, _, err = manager.ExecutePipeline("onArticleRender", "content", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "content", result, "scoped plugins need an article context")
+}
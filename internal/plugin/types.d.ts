@@ -69,6 +69,7 @@ interface Context {
         [key: string]: any;
     };
     Slug?: string;
+    Tags?: string[];
     RequestID?: string;
     QueryParams?: Record<string, string[]>;
 
@@ -0,0 +1,12 @@
+// Package version holds the build information of the binary, injected at build time with
+// -ldflags "-X wikilite/internal/version.Version=... -X ...Commit=... -X ...BuildTime=...".
+package version
+
+var (
+	// Version is the release version of the application.
+	Version = "dev"
+	// Commit is the git commit the binary was built from.
+	Commit = "unknown"
+	// BuildTime is when the binary was built, in RFC 3339 format.
+	BuildTime = "unknown"
+)
@@ -0,0 +1,207 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"wikilite/pkg/models"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of the request body.
+	SignatureHeader = "X-Wikilite-Signature"
+	// EventHeader carries the event type of the delivery.
+	EventHeader = "X-Wikilite-Event"
+
+	queueSize      = 100
+	workerCount    = 2
+	maxAttempts    = 4
+	initialBackoff = 1 * time.Second
+	requestTimeout = 10 * time.Second
+)
+
+// Store provides the webhooks subscribed to an event.
+type Store interface {
+	GetActiveWebhooksForEvent(
+		ctx context.Context,
+		event models.WebhookEvent,
+	) ([]*models.Webhook, error)
+}
+
+// Payload is the JSON body delivered to webhook endpoints.
+type Payload struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Data      any                 `json:"data"`
+	Event     models.WebhookEvent `json:"event"`
+}
+
+// Dispatcher delivers events to webhooks from a background worker pool,
+// retrying failed deliveries with exponential backoff.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	logger models.Logger
+
+	queue    chan Payload
+	stopChan chan struct{}
+
+	initialBackoff time.Duration
+	wg             sync.WaitGroup
+	closeOnce      sync.Once
+}
+
+// NewDispatcher creates a dispatcher and starts its workers.
+func NewDispatcher(store Store, logger models.Logger) *Dispatcher {
+	d := &Dispatcher{
+		store:          store,
+		client:         &http.Client{Timeout: requestTimeout},
+		logger:         logger,
+		queue:          make(chan Payload, queueSize),
+		stopChan:       make(chan struct{}),
+		initialBackoff: initialBackoff,
+	}
+
+	for range workerCount {
+		d.wg.Go(d.workerLoop)
+	}
+
+	return d
+}
+
+// Dispatch queues an event for delivery without blocking the caller.
+// Events are dropped if the queue is full.
+func (d *Dispatcher) Dispatch(event models.WebhookEvent, data any) {
+	payload := Payload{
+		Event:     event,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+
+	select {
+	case <-d.stopChan:
+	case d.queue <- payload:
+	default:
+		d.log(models.LevelWarning, "Webhook queue full, event dropped", string(event))
+	}
+}
+
+// Close stops the workers, abandoning any pending retries.
+func (d *Dispatcher) Close() {
+	d.closeOnce.Do(func() {
+		close(d.stopChan)
+		d.wg.Wait()
+	})
+}
+
+// workerLoop processes queued events until the dispatcher is closed.
+func (d *Dispatcher) workerLoop() {
+	for {
+		select {
+		case <-d.stopChan:
+			return
+		case payload := <-d.queue:
+			d.process(payload)
+		}
+	}
+}
+
+// process delivers a payload to every webhook subscribed to its event.
+func (d *Dispatcher) process(payload Payload) {
+	webhooks, err := d.store.GetActiveWebhooksForEvent(context.Background(), payload.Event)
+	if err != nil {
+		d.log(models.LevelError, "Failed to load webhooks", err.Error())
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.log(models.LevelError, "Failed to encode webhook payload", err.Error())
+		return
+	}
+
+	for _, w := range webhooks {
+		d.deliver(w, payload.Event, body)
+	}
+}
+
+// deliver posts the body to a single webhook, retrying with backoff on failure.
+func (d *Dispatcher) deliver(w *models.Webhook, event models.WebhookEvent, body []byte) {
+	backoff := d.initialBackoff
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err := d.send(w, event, body)
+		if err == nil {
+			return
+		}
+
+		if attempt == maxAttempts {
+			d.log(
+				models.LevelError,
+				fmt.Sprintf("Webhook delivery failed after %d attempts", attempt),
+				fmt.Sprintf("Webhook ID: %d | URL: %s | Error: %v", w.Id, w.URL, err),
+			)
+			return
+		}
+
+		select {
+		case <-d.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// send performs a single signed delivery attempt.
+func (d *Dispatcher) send(w *models.Webhook, event models.WebhookEvent, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event))
+
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// log writes to the configured logger, if any.
+func (d *Dispatcher) log(level models.LogLevel, message, data string) {
+	if d.logger != nil {
+		_ = d.logger(context.Background(), level, "WEBHOOK", message, data)
+	}
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wikilite/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore returns a fixed set of webhooks filtered by subscription.
+type fakeStore struct {
+	webhooks []*models.Webhook
+}
+
+func (f *fakeStore) GetActiveWebhooksForEvent(
+	_ context.Context,
+	event models.WebhookEvent,
+) ([]*models.Webhook, error) {
+	var result []*models.Webhook
+	for _, w := range f.webhooks {
+		if w.Active && w.Subscribes(event) {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{webhooks: []*models.Webhook{
+		{Id: 1, URL: srv.URL, Secret: "shh", Active: true},
+	}}
+
+	d := NewDispatcher(store, nil)
+	defer d.Close()
+
+	d.Dispatch(models.EventArticlePublished, map[string]any{"slug": "home"})
+
+	select {
+	case r := <-received:
+		body := <-bodies
+		assert.Equal(t, string(models.EventArticlePublished), r.Header.Get(EventHeader))
+		assert.Equal(t, "sha256="+Sign("shh", body), r.Header.Get(SignatureHeader))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, models.EventArticlePublished, payload.Event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+}
+
+func TestDispatcher_RetriesOnFailure(t *testing.T) {
+	var attempts atomic.Int32
+	done := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		close(done)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{webhooks: []*models.Webhook{
+		{Id: 1, URL: srv.URL, Active: true},
+	}}
+
+	d := NewDispatcher(store, nil)
+	d.initialBackoff = 10 * time.Millisecond
+	defer d.Close()
+
+	d.Dispatch(models.EventUserCreated, nil)
+
+	select {
+	case <-done:
+		assert.Equal(t, int32(3), attempts.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook delivery was not retried")
+	}
+}
+
+func TestDispatcher_SkipsUnsubscribedWebhooks(t *testing.T) {
+	var hits atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{webhooks: []*models.Webhook{
+		{Id: 1, URL: srv.URL, Events: []models.WebhookEvent{models.EventUserDeleted}, Active: true},
+		{Id: 2, URL: srv.URL, Active: false},
+	}}
+
+	d := NewDispatcher(store, nil)
+	d.Dispatch(models.EventArticleDeleted, nil)
+
+	time.Sleep(100 * time.Millisecond)
+	d.Close()
+
+	assert.Equal(t, int32(0), hits.Load())
+}
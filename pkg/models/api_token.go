@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ApiTokenScope limits what a personal access token may be used for.
+type ApiTokenScope string
+
+const (
+	// ScopeRead allows read-only requests (GET, HEAD and OPTIONS).
+	ScopeRead ApiTokenScope = "read"
+	// ScopeWrite allows every request the owner may make. It implies ScopeRead.
+	ScopeWrite ApiTokenScope = "write"
+)
+
+// ApiTokenScopes lists every scope a personal access token can be granted.
+var ApiTokenScopes = []ApiTokenScope{
+	ScopeRead,
+	ScopeWrite,
+}
+
+// ApiToken represents a personal access token that authenticates scripts as its owner.
+// Only a hash of the token is stored.
+type ApiToken struct {
+	bun.BaseModel `bun:"table:api_tokens,alias:at"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt"`
+
+	ExpiresAt  *time.Time `bun:"expires_at"   json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `bun:"last_used_at" json:"lastUsedAt,omitempty"`
+
+	Name        string          `bun:"name,notnull"                json:"name"`
+	HashedToken string          `bun:"hashed_token,notnull,unique" json:"-"`
+	Scopes      []ApiTokenScope `bun:"scopes,type:text"            json:"scopes"`
+
+	Id     int `bun:"id,pk,autoincrement" json:"id"`
+	UserId int `bun:"user_id,notnull"     json:"userId"`
+}
+
+// HasScope reports whether the token was granted scope. ScopeWrite implies ScopeRead.
+func (t *ApiToken) HasScope(scope ApiTokenScope) bool {
+	if slices.Contains(t.Scopes, scope) {
+		return true
+	}
+
+	return scope == ScopeRead && slices.Contains(t.Scopes, ScopeWrite)
+}
+
+// Expired reports whether the token has an expiry date that has passed at now.
+func (t *ApiToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// AfterInsert is a Bun hook triggered after a successful insert.
+func (t *ApiToken) AfterInsert(ctx context.Context, _ *bun.InsertQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelInfo,
+			"DATABASE",
+			"API Token Created",
+			fmt.Sprintf("API Token ID: %d for User ID: %d (Name: %s)", t.Id, t.UserId, t.Name),
+		)
+	}
+	return nil
+}
+
+// AfterDelete is a Bun hook triggered after a successful delete.
+func (t *ApiToken) AfterDelete(ctx context.Context, _ *bun.DeleteQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelWarning,
+			"DATABASE",
+			"API Token Revoked",
+			fmt.Sprintf("API Token ID: %d", t.Id),
+		)
+	}
+	return nil
+}
@@ -14,17 +14,26 @@ type Article struct {
 	bun.BaseModel `bun:"table:articles,alias:a"`
 
 	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt"`
 
 	Title     string `bun:"title,notnull"       json:"title"`
 	Slug      string `bun:"slug,unique,notnull" json:"slug"`
 	Data      string `bun:"data,type:text"      json:"data"`
 	CreatedBy string `bun:"created_by"          json:"createdBy"`
 
+	Tags []string `bun:"tags,nullzero" json:"tags,omitempty"`
+
+	// CustomCSS and CustomJS are set by admins and added to the article page when custom
+	// article code is enabled.
+	CustomCSS string `bun:"custom_css,nullzero" json:"-"`
+	CustomJS  string `bun:"custom_js,nullzero"  json:"-"`
+
 	History []*History `bun:"rel:has-many,join:id=article_id" json:"history,omitempty"`
 	Drafts  []*Draft   `bun:"rel:has-many,join:id=article_id" json:"drafts,omitempty"`
 
-	Id      int `bun:"id,pk,autoincrement" json:"id"`
-	Version int `bun:"version,default:0"   json:"version"`
+	Id        int `bun:"id,pk,autoincrement"          json:"id"`
+	Version   int `bun:"version,default:0"            json:"version"`
+	ViewCount int `bun:"view_count,notnull,default:0" json:"viewCount"`
 }
 
 // BeforeAppendModel is a hook that runs before a model is inserted or updated.
@@ -87,12 +96,22 @@ type History struct {
 
 	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
 
-	Article *Article `bun:"rel:belongs-to,join:article_id=id" json:"article,omitempty"`
-	Data    string   `bun:"data,type:text"                    json:"data"`
+	Article   *Article `bun:"rel:belongs-to,join:article_id=id" json:"article,omitempty"`
+	Data      string   `bun:"data,type:text"                    json:"data"`
+	CreatedBy string   `bun:"created_by"                        json:"createdBy,omitempty"`
 
 	Id        int `bun:"id,pk,autoincrement" json:"id"`
 	ArticleId int `bun:"article_id,notnull"  json:"articleId"`
 	Version   int `bun:"version,notnull"     json:"version"`
+
+	// Snapshot marks Data as the full article content rather than a patch.
+	Snapshot bool `bun:"snapshot,notnull,default:false" json:"snapshot"`
+	// Compressed marks Data as gzip-compressed and base64-encoded.
+	Compressed bool `bun:"compressed,notnull,default:false" json:"-"`
+
+	// Added and Removed are the number of characters this version inserted and deleted.
+	Added   int `bun:"-" json:"added"`
+	Removed int `bun:"-" json:"removed"`
 }
 
 // Link represents a link between two articles.
@@ -22,6 +22,19 @@ type Draft struct {
 	Id             int `bun:"id,pk,autoincrement" json:"id"`
 	ArticleId      int `bun:"article_id,notnull"  json:"articleId"`
 	ArticleVersion int `bun:"article_version"     json:"articleVersion"`
+
+	// Compressed marks Data as gzip-compressed and base64-encoded.
+	Compressed bool `bun:"compressed,notnull,default:false" json:"-"`
+
+	// IsSuggestion marks a proposed edit awaiting review. Suggestions have no owner
+	// (CreatedBy is empty) and are credited to SuggestedBy when approved. SuggestedFrom is
+	// the IP address of the client that made the suggestion.
+	IsSuggestion  bool   `bun:"is_suggestion,notnull,default:false" json:"isSuggestion,omitempty"`
+	SuggestedBy   string `bun:"suggested_by"                         json:"suggestedBy,omitempty"`
+	SuggestedFrom string `bun:"suggested_from"                       json:"-"`
+
+	// ReconstructionDegraded is set when the content could only be approximately rebuilt.
+	ReconstructionDegraded bool `bun:"-" json:"reconstructionDegraded,omitempty"`
 }
 
 // AfterInsert is a Bun hook triggered after a successful insert.
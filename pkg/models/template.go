@@ -0,0 +1,84 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Template placeholders substituted when a template is rendered.
+const (
+	// TemplateTitle is replaced with the article title.
+	TemplateTitle = "{{title}}"
+	// TemplateDate is replaced with the current date in YYYY-MM-DD form.
+	TemplateDate = "{{date}}"
+)
+
+// Template represents reusable starting content for new articles.
+type Template struct {
+	bun.BaseModel `bun:"table:templates,alias:tp"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt"`
+
+	Name    string `bun:"name,notnull,unique" json:"name"`
+	Content string `bun:"content,notnull"     json:"content"`
+
+	Id int `bun:"id,pk,autoincrement" json:"id"`
+}
+
+// Render returns the template content with its placeholders substituted.
+func (t *Template) Render(title string, now time.Time) string {
+	return strings.NewReplacer(
+		TemplateTitle, title,
+		TemplateDate, now.Format(time.DateOnly),
+	).Replace(t.Content)
+}
+
+// AfterInsert is a Bun hook triggered after a successful insert.
+func (t *Template) AfterInsert(ctx context.Context, _ *bun.InsertQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelInfo,
+			"DATABASE",
+			"Template Created",
+			fmt.Sprintf("Template ID: %d (Name: %s)", t.Id, t.Name),
+		)
+	}
+	return nil
+}
+
+// AfterUpdate is a Bun hook triggered after a successful update.
+func (t *Template) AfterUpdate(ctx context.Context, _ *bun.UpdateQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelInfo,
+			"DATABASE",
+			"Template Updated",
+			fmt.Sprintf("Template ID: %d (Name: %s)", t.Id, t.Name),
+		)
+	}
+	return nil
+}
+
+// AfterDelete is a Bun hook triggered after a successful delete.
+func (t *Template) AfterDelete(ctx context.Context, _ *bun.DeleteQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelWarning,
+			"DATABASE",
+			"Template Deleted",
+			fmt.Sprintf("Template ID: %d", t.Id),
+		)
+	}
+	return nil
+}
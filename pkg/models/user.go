@@ -20,6 +20,16 @@ const (
 	ADMIN
 )
 
+// EditorMode is the layout of the article editor preferred by a user.
+type EditorMode string
+
+const (
+	// EditorMarkdown shows the markdown editor on its own.
+	EditorMarkdown EditorMode = "markdown"
+	// EditorSplitPreview shows a live preview of the draft next to the markdown editor.
+	EditorSplitPreview EditorMode = "split-preview"
+)
+
 // User represents a user account.
 type User struct {
 	bun.BaseModel `bun:"table:users,alias:u"`
@@ -31,10 +41,13 @@ type User struct {
 	Hash      string    `bun:"hash"                                                  json:"-"`
 	OTPSecret string    `bun:"otp_secret"                                            json:"-"`
 
-	Id         int      `bun:"id,pk,autoincrement"               json:"id"`
-	Role       UserRole `bun:"role,notnull"                      json:"role"`
-	IsExternal bool     `bun:"is_external,notnull,default:false" json:"isExternal"`
-	Disabled   bool     `bun:"disabled,default:false"            json:"disabled"`
+	EditorMode EditorMode `bun:"editor_mode,notnull,default:'markdown'" json:"editorMode"`
+
+	Id           int      `bun:"id,pk,autoincrement"               json:"id"`
+	Role         UserRole `bun:"role,notnull"                      json:"role"`
+	IsExternal   bool     `bun:"is_external,notnull,default:false" json:"isExternal"`
+	Disabled     bool     `bun:"disabled,default:false"            json:"disabled"`
+	TokenVersion int      `bun:"token_version,notnull,default:0"  json:"-"`
 }
 
 // AfterInsert is a Bun hook triggered after a successful insert.
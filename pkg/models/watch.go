@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Watch records that a user wants to be notified when an article changes.
+type Watch struct {
+	bun.BaseModel `bun:"table:watches,alias:wa"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+
+	UserId    int `bun:"user_id,pk"    json:"userId"`
+	ArticleId int `bun:"article_id,pk" json:"articleId"`
+}
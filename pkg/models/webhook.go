@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// WebhookEvent identifies an event that can be delivered to a webhook.
+type WebhookEvent string
+
+const (
+	// EventArticlePublished fires when a draft is published to an article.
+	EventArticlePublished WebhookEvent = "article.published"
+	// EventArticleDeleted fires when an article is deleted.
+	EventArticleDeleted WebhookEvent = "article.deleted"
+	// EventUserCreated fires when a user is created.
+	EventUserCreated WebhookEvent = "user.created"
+	// EventUserDeleted fires when a user is deleted.
+	EventUserDeleted WebhookEvent = "user.deleted"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	EventArticlePublished,
+	EventArticleDeleted,
+	EventUserCreated,
+	EventUserDeleted,
+}
+
+// Webhook represents an outbound HTTP endpoint that receives event notifications.
+type Webhook struct {
+	bun.BaseModel `bun:"table:webhooks,alias:w"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt"`
+
+	URL    string         `bun:"url,notnull"      json:"url"`
+	Secret string         `bun:"secret"           json:"-"`
+	Events []WebhookEvent `bun:"events,type:text" json:"events"`
+
+	Id     int  `bun:"id,pk,autoincrement" json:"id"`
+	Active bool `bun:"active,notnull"      json:"active"`
+}
+
+// Subscribes reports whether the webhook should receive the given event.
+// A webhook with no events configured receives all events.
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	return slices.Contains(w.Events, event)
+}
+
+// AfterInsert is a Bun hook triggered after a successful insert.
+func (w *Webhook) AfterInsert(ctx context.Context, _ *bun.InsertQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelInfo,
+			"DATABASE",
+			"Webhook Created",
+			fmt.Sprintf("Webhook ID: %d (URL: %s)", w.Id, w.URL),
+		)
+	}
+	return nil
+}
+
+// AfterUpdate is a Bun hook triggered after a successful update.
+func (w *Webhook) AfterUpdate(ctx context.Context, _ *bun.UpdateQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelInfo,
+			"DATABASE",
+			"Webhook Updated",
+			fmt.Sprintf("Webhook ID: %d (URL: %s)", w.Id, w.URL),
+		)
+	}
+	return nil
+}
+
+// AfterDelete is a Bun hook triggered after a successful delete.
+func (w *Webhook) AfterDelete(ctx context.Context, _ *bun.DeleteQuery) error {
+	logger := LoggerFromContext(ctx)
+	if logger != nil {
+		_ = logger(
+			ctx,
+			LevelWarning,
+			"DATABASE",
+			"Webhook Deleted",
+			fmt.Sprintf("Webhook ID: %d", w.Id),
+		)
+	}
+	return nil
+}
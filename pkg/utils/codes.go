@@ -2,10 +2,15 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 )
 
+// ApiTokenPrefix starts every personal access token, telling them apart from JWTs.
+const ApiTokenPrefix = "wlt_"
+
 // GenerateBackupCodes generates a specified number of cryptographically secure 8-character backup codes.
 func GenerateBackupCodes(count int) ([]string, error) {
 	if count <= 0 {
@@ -70,3 +75,36 @@ func ValidateBackupCodeFormat(code string) bool {
 
 	return true
 }
+
+// GenerateApiToken generates a new personal access token: ApiTokenPrefix followed by 40
+// cryptographically secure alphanumeric characters.
+func GenerateApiToken() (string, error) {
+	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	const tokenLength = 40
+
+	secret, err := generateSecureCode(charset, tokenLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+
+	return ApiTokenPrefix + secret, nil
+}
+
+// GeneratePassword generates a cryptographically secure alphanumeric password of the given length.
+func GeneratePassword(length int) (string, error) {
+	const charset = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+	password, err := generateSecureCode(charset, length)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	return password, nil
+}
+
+// HashApiToken returns the hex encoded SHA-256 hash under which a personal access token is
+// stored. Tokens are long and random, so a fast hash is sufficient.
+func HashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,9 @@
+package utils
+
+import "strings"
+
+// NormalizeEmail returns the canonical form of an email address used for storage and lookups.
+// Emails are trimmed and lowercased so that differently cased addresses resolve to the same user.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"user@example.com", "user@example.com"},
+		{"User@Example.COM", "user@example.com"},
+		{"  user@example.com\t", "user@example.com"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run("normalize_"+tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeEmail(tc.input))
+		})
+	}
+}
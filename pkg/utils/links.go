@@ -21,6 +21,26 @@ func ToKebabCase(str string) string {
 	return str
 }
 
+// TruncateSlug shortens a kebab case slug to at most maxLength characters, dropping whole
+// words where possible. A single word longer than maxLength is cut.
+// Zero or less leaves the slug unchanged.
+func TruncateSlug(slug string, maxLength int) string {
+	if maxLength <= 0 || len(slug) <= maxLength {
+		return slug
+	}
+
+	if slug[maxLength] == '-' {
+		return slug[:maxLength]
+	}
+
+	cut := slug[:maxLength]
+	if i := strings.LastIndex(cut, "-"); i > 0 {
+		return cut[:i]
+	}
+
+	return cut
+}
+
 // linkRegex is a regular expression to find Markdown links.
 var linkRegex = regexp.MustCompile(`\[.*?\]\((.*?)\)`)
 
@@ -52,3 +72,58 @@ func ExtractSlugsFromContent(content string) []string {
 
 	return result
 }
+
+// includeRegex matches {{include:slug}} transclusion tokens.
+var includeRegex = regexp.MustCompile(`\{\{\s*include:\s*([^{}\s]+)\s*\}\}`)
+
+// ExtractIncludesFromContent returns the targets of the {{include:slug}} tokens in content.
+func ExtractIncludesFromContent(content string) []string {
+	matches := includeRegex.FindAllStringSubmatch(content, -1)
+	targets := make([]string, 0, len(matches))
+
+	for _, match := range matches {
+		targets = append(targets, match[1])
+	}
+
+	return targets
+}
+
+// HasIncludes reports whether content contains any {{include:slug}} tokens.
+func HasIncludes(content string) bool {
+	return includeRegex.MatchString(content)
+}
+
+// ReplaceIncludes replaces every {{include:slug}} token in content with the result of
+// calling replace with the token's target.
+func ReplaceIncludes(content string, replace func(target string) string) string {
+	return includeRegex.ReplaceAllStringFunc(content, func(token string) string {
+		return replace(includeRegex.FindStringSubmatch(token)[1])
+	})
+}
+
+// NormalizeLinkSlug converts a raw link target into an article slug.
+// Anchors and query strings are stripped, only the final path segment is kept,
+// and the result is kebab-cased. Anchor-only, relative, and external targets
+// return an empty string.
+func NormalizeLinkSlug(target string) string {
+	target = strings.TrimSpace(target)
+
+	if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, ".") {
+		return ""
+	}
+
+	if strings.Contains(target, ":") {
+		return ""
+	}
+
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		target = target[:i]
+	}
+
+	target = strings.Trim(target, "/")
+	if i := strings.LastIndex(target, "/"); i >= 0 {
+		target = target[i+1:]
+	}
+
+	return ToKebabCase(target)
+}
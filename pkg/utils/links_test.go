@@ -143,6 +143,37 @@ func TestToKebabCase_CamelCase(t *testing.T) {
 	}
 }
 
+func TestTruncateSlug(t *testing.T) {
+	testCases := []struct {
+		name      string
+		slug      string
+		maxLength int
+		expected  string
+	}{
+		{"short slug", "install-guide", 80, "install-guide"},
+		{"cut at word boundary", "install-guide-for-linux", 16, "install-guide"},
+		{"limit ends on a word", "install-guide-for-linux", 13, "install-guide"},
+		{"single long word", "supercalifragilistic", 10, "supercalif"},
+		{"no limit", "install-guide-for-linux", 0, "install-guide-for-linux"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TruncateSlug(tc.slug, tc.maxLength))
+		})
+	}
+}
+
+func TestTruncateSlug_LongTitle(t *testing.T) {
+	title := strings.Repeat("Very Long Title ", 20)
+
+	slug := TruncateSlug(ToKebabCase(title), 80)
+	assert.LessOrEqual(t, len(slug), 80)
+	assert.True(t, strings.HasPrefix(slug, "very-long-title-"))
+	assert.False(t, strings.HasSuffix(slug, "-"))
+	assert.Contains(t, []string{"very", "long", "title"}, slug[strings.LastIndex(slug, "-")+1:])
+}
+
 func TestExtractSlugsFromContent_BasicLinks(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -315,3 +346,45 @@ func TestExtractSlugsFromContent_LargeContent(t *testing.T) {
 	assert.ElementsMatch(t, expected, result)
 	assert.Len(t, result, len(expected))
 }
+
+func TestNormalizeLinkSlug(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"home", "home"},
+		{"page#section", "page"},
+		{"page?version=2", "page"},
+		{"nested/path", "path"},
+		{"with spaces", "with-spaces"},
+		{"Mixed_Case", "mixed-case"},
+		{"trailing/", "trailing"},
+		{"#section", ""},
+		{"../relative", ""},
+		{"./sibling", ""},
+		{"mailto:someone@example.com", ""},
+		{"ftp://example.com/file", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run("normalize_"+tc.input, func(t *testing.T) {
+			result := NormalizeLinkSlug(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestExtractIncludesFromContent(t *testing.T) {
+	content := "{{include:intro}}\n\nText with {{ include: Shared-Notes }} inline.\n\n{{include:}} {{title}}"
+
+	assert.Equal(t, []string{"intro", "Shared-Notes"}, ExtractIncludesFromContent(content))
+	assert.True(t, HasIncludes(content))
+	assert.False(t, HasIncludes("No includes, just {{title}} and {{date}}."))
+}
+
+func TestReplaceIncludes(t *testing.T) {
+	result := ReplaceIncludes("a {{include:one}} b {{include:two}}", strings.ToUpper)
+
+	assert.Equal(t, "a ONE b TWO", result)
+}
@@ -1,6 +1,13 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 // HashPassword takes a plaintext password and returns the bcrypt hash.
 func HashPassword(password string) (string, error) {
@@ -16,3 +23,66 @@ func CheckPassword(password, hash string) bool {
 
 	return err == nil
 }
+
+// DefaultPasswordMinLength is the minimum password length used when a policy does not set one.
+const DefaultPasswordMinLength = 8
+
+// PasswordPolicy describes the requirements a new password must satisfy.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// ValidatePassword checks a password against the policy.
+// It returns nil if the password is acceptable, or an error describing every unmet requirement.
+func ValidatePassword(password string, policy PasswordPolicy) error {
+	minLength := policy.MinLength
+	if minLength <= 0 {
+		minLength = DefaultPasswordMinLength
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	var problems []string
+
+	if utf8.RuneCountInString(password) < minLength {
+		problems = append(problems, fmt.Sprintf("be at least %d characters long", minLength))
+	}
+
+	if policy.RequireUpper && !hasUpper {
+		problems = append(problems, "contain an uppercase letter")
+	}
+
+	if policy.RequireLower && !hasLower {
+		problems = append(problems, "contain a lowercase letter")
+	}
+
+	if policy.RequireDigit && !hasDigit {
+		problems = append(problems, "contain a digit")
+	}
+
+	if policy.RequireSymbol && !hasSymbol {
+		problems = append(problems, "contain a symbol")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("password must %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
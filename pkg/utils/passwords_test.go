@@ -136,3 +136,42 @@ func TestPasswordHashing_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePassword(t *testing.T) {
+	strict := PasswordPolicy{
+		MinLength:     10,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	testCases := []struct {
+		name     string
+		password string
+		policy   PasswordPolicy
+		problem  string
+	}{
+		{name: "default length ok", password: "password123", policy: PasswordPolicy{}},
+		{name: "default too short", password: "short", policy: PasswordPolicy{}, problem: "at least 8"},
+		{name: "custom length", password: "abcdef", policy: PasswordPolicy{MinLength: 6}},
+		{name: "complex ok", password: "Sup3r-Secret", policy: strict},
+		{name: "missing upper", password: "sup3r-secret", policy: strict, problem: "uppercase"},
+		{name: "missing lower", password: "SUP3R-SECRET", policy: strict, problem: "lowercase"},
+		{name: "missing digit", password: "Super-Secret", policy: strict, problem: "digit"},
+		{name: "missing symbol", password: "Sup3rSecret1", policy: strict, problem: "symbol"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePassword(tc.password, tc.policy)
+			if tc.problem == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.problem)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "fmt"
+
+// FormatBytes formats a size in bytes using binary units, e.g. "512 B", "1.5 KiB" or "20.0 MiB".
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeAgo describes how long before now t was, e.g. "just now", "5 minutes ago" or "2 days ago".
+// Times older than a year are formatted as a date instead.
+func TimeAgo(t, now time.Time) string {
+	elapsed := now.Sub(t)
+
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return plural(int(elapsed/time.Minute), "minute") + " ago"
+	case elapsed < 24*time.Hour:
+		return plural(int(elapsed/time.Hour), "hour") + " ago"
+	case elapsed < 30*24*time.Hour:
+		return plural(int(elapsed/(24*time.Hour)), "day") + " ago"
+	case elapsed < 365*24*time.Hour:
+		return plural(int(elapsed/(30*24*time.Hour)), "month") + " ago"
+	default:
+		return "on " + t.Format("Jan 02, 2006")
+	}
+}
+
+// plural formats a count with its unit, adding an "s" unless the count is one.
+func plural(count int, unit string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+
+	return fmt.Sprintf("%d %ss", count, unit)
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		elapsed  time.Duration
+		expected string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{49 * time.Hour, "2 days ago"},
+		{65 * 24 * time.Hour, "2 months ago"},
+		{400 * 24 * time.Hour, "on May 12, 2023"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TimeAgo(now.Add(-tc.elapsed), now))
+		})
+	}
+}